@@ -0,0 +1,10 @@
+//go:build !js
+
+package main
+
+import "os"
+
+// commandLineArgs возвращает аргументы командной строки без имени программы
+func commandLineArgs() []string {
+	return os.Args[1:]
+}
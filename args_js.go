@@ -0,0 +1,26 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"net/url"
+	"strings"
+	"syscall/js"
+)
+
+// commandLineArgs переводит параметры адреса страницы во флаги: например,
+// index.html?mode=client&addr=ws://192.168.0.5:4001/ запускает игру клиентом
+func commandLineArgs() []string {
+	search := js.Global().Get("location").Get("search").String()
+	query, err := url.ParseQuery(strings.TrimPrefix(search, "?"))
+	if err != nil {
+		return nil
+	}
+	args := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			args = append(args, "-"+name+"="+value)
+		}
+	}
+	return args
+}
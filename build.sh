@@ -1,7 +1,7 @@
 #!/bin/bash
 
 # Cross-platform build script for Go game
-# Usage: ./build.sh [clean|all|windows|linux|mac]
+# Usage: ./build.sh [clean|all|windows|linux|mac|wasm|relay|matchmaking]
 
 set -e  # Exit on any error
 
@@ -9,7 +9,8 @@ set -e  # Exit on any error
 PROJECT_NAME="simple_platformer"
 VERSION="1.0.0"
 DIST_DIR="dist"
-MAIN_FILE="main.go"
+MAIN_PACKAGE="."  # Package main with platform-specific files (args*.go)
+TAGS="${TAGS:-}"  # Build tags, e.g. TAGS=debug ./build.sh linux for a build with the debug overlay on
 
 # Colors for output
 RED='\033[0;31m'
@@ -88,7 +89,7 @@ build_single() {
     print_info "Building for ${os}/${arch}..."
     
     # Build command
-    if GOOS="$os" GOARCH="$arch" go build -ldflags="-s -w -X main.Version=$VERSION" -o "$output_name" "$MAIN_FILE"; then
+    if GOOS="$os" GOARCH="$arch" go build -tags "$TAGS" -ldflags="-s -w -X main.Version=$VERSION" -o "$output_name" "$MAIN_PACKAGE"; then
         print_success "Built: $(basename "$output_name")"
         
         # Compress with UPX if available
@@ -130,6 +131,65 @@ build_mac() {
     build_single "darwin" "arm64" ""
 }
 
+build_wasm() {
+    print_info "Building browser (WebAssembly) version..."
+
+    local output_dir="$DIST_DIR/${PROJECT_NAME}-wasm"
+    mkdir -p "$output_dir"
+
+    if ! GOOS=js GOARCH=wasm go build -tags "$TAGS" -ldflags="-s -w -X main.Version=$VERSION" -o "$output_dir/game.wasm" "$MAIN_PACKAGE"; then
+        print_error "Failed to build for js/wasm"
+        return 1
+    fi
+
+    # wasm_exec.js lives in misc/wasm before Go 1.24 and in lib/wasm since then
+    local goroot=$(go env GOROOT)
+    local wasm_exec="$goroot/lib/wasm/wasm_exec.js"
+    [ -f "$wasm_exec" ] || wasm_exec="$goroot/misc/wasm/wasm_exec.js"
+    cp "$wasm_exec" "$output_dir/"
+
+    # Flags are passed as page parameters, e.g. index.html?mode=client&addr=ws://192.168.0.5:4001/
+    cat > "$output_dir/index.html" <<'HTML'
+<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Simple Platformer</title></head>
+<body style="margin:0;background:#000">
+<script src="wasm_exec.js"></script>
+<script>
+const go = new Go();
+WebAssembly.instantiateStreaming(fetch("game.wasm"), go.importObject).then((result) => go.run(result.instance));
+</script>
+</body>
+</html>
+HTML
+
+    print_success "Built: $(basename "$output_dir")/ (serve it over HTTP and host with -ws to play over the network)"
+}
+
+# Builds a server from cmd/<name> for the current platform
+build_tool() {
+    local name=$1
+    print_info "Building $name server for $(go env GOOS)/$(go env GOARCH)..."
+
+    local output_name="$DIST_DIR/${PROJECT_NAME}-${name}$(go env GOEXE)"
+    if ! go build -ldflags="-s -w" -o "$output_name" "./cmd/$name"; then
+        print_error "Failed to build the $name server"
+        return 1
+    fi
+
+    print_success "Built: $(basename "$output_name")"
+}
+
+build_relay() {
+    # Host with -addr relay://<relay>:4100/<room>, join with the same address
+    build_tool "relay"
+}
+
+build_matchmaking() {
+    # Host with -matchmaking http://<server>:4200, join with -room <code> or from the menu
+    build_tool "matchmaking"
+}
+
 build_all() {
     print_info "Starting cross-platform build for $PROJECT_NAME v$VERSION"
     
@@ -223,11 +283,24 @@ case "${1:-all}" in
         build_all
         create_archive
         ;;
+    "wasm")
+        check_dependencies
+        create_dist_dir
+        build_wasm
+        ;;
+    "relay")
+        create_dist_dir
+        build_relay
+        ;;
+    "matchmaking")
+        create_dist_dir
+        build_matchmaking
+        ;;
     "summary")
         show_summary
         ;;
     *)
-        echo "Usage: $0 [clean|all|windows|linux|mac|summary]"
+        echo "Usage: $0 [clean|all|windows|linux|mac|wasm|relay|matchmaking|summary]"
         echo ""
         echo "Commands:"
         echo "  clean     - Remove build artifacts"
@@ -235,6 +308,9 @@ case "${1:-all}" in
         echo "  windows   - Build only Windows versions"
         echo "  linux     - Build only Linux versions"
         echo "  mac       - Build only macOS versions"
+        echo "  wasm      - Build the browser version (joins hosts over WebSocket)"
+        echo "  relay     - Build the relay server for playing through NAT"
+        echo "  matchmaking - Build the matchmaking server for joining by room code"
         echo "  summary   - Show build summary"
         exit 1
         ;;
@@ -0,0 +1,19 @@
+// Команда matchmaking запускает сервер подбора игр: хосты регистрируют
+// на нем комнаты, а клиенты подключаются к ним по коду комнаты.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"platformer/internal/matchmaking"
+)
+
+func main() {
+	addrFlag := flag.String("addr", ":4200", "Address the matchmaking server listens on")
+	flag.Parse()
+
+	log.Printf("matchmaking: listening on %s", *addrFlag)
+	log.Fatal(http.ListenAndServe(*addrFlag, matchmaking.NewServer()))
+}
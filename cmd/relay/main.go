@@ -0,0 +1,23 @@
+// Команда relay запускает посредника для сетевой игры через NAT:
+// хост и клиент подключаются к нему с адресом relay://<адрес посредника>/<комната>.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+
+	"platformer/internal/relay"
+)
+
+func main() {
+	addrFlag := flag.String("addr", ":4100", "Address the relay listens on")
+	flag.Parse()
+
+	listener, err := net.Listen("tcp", *addrFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("relay: listening on %s", listener.Addr())
+	log.Fatal(relay.NewServer().Serve(listener))
+}
@@ -0,0 +1,163 @@
+package assets
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"image"
+	"image/png"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// files — ресурсы, встроенные в исполняемый файл:
+//   - sprites/<имя>.png - спрайты;
+//   - sounds/effects/<имя>.wav|.ogg - звуковые эффекты, sounds/music - музыка;
+//   - fonts/<имя>.ttf - шрифты (шрифты Go, лицензия в fonts/LICENSE);
+//   - levels/<имя>.json - уровни.
+//
+//go:embed sprites sounds fonts levels
+var files embed.FS
+
+// source — откуда читаются ресурсы: встроенные файлы или каталог на диске (режим -dev)
+var source fs.FS = files
+
+// soundExtensions — поддерживаемые форматы звука в порядке предпочтения
+var soundExtensions = []string{".ogg", ".wav"}
+
+// Sound — звуковой файл: данные и формат (расширение файла, например ".wav")
+type Sound struct {
+	Name   string
+	Format string
+	Data   []byte
+}
+
+// Кэш загруженных ресурсов. Ресурсы загружаются и из фоновой загрузки,
+// и из игрового цикла, поэтому доступ к кэшу защищен мьютексом.
+var (
+	mu     sync.Mutex
+	images = make(map[string]image.Image)
+	sounds = make(map[string]Sound)
+	fonts  = make(map[string][]byte)
+)
+
+// GetImage возвращает спрайт name (без расширения). Изображение декодируется
+// один раз, повторные вызовы возвращают то же изображение.
+func GetImage(name string) (image.Image, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if img, ok := images[name]; ok {
+		return img, nil
+	}
+
+	img, err := decodeImage(name)
+	if err != nil {
+		return nil, err
+	}
+	images[name] = img
+	return img, nil
+}
+
+// decodeImage читает и декодирует спрайт name
+func decodeImage(name string) (image.Image, error) {
+	data, err := readFile(imagePath(name))
+	if err != nil {
+		return nil, fmt.Errorf("assets: image %q: %w", name, err)
+	}
+	decoded, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("assets: decode image %q: %w", name, err)
+	}
+	return decoded, nil
+}
+
+// imagePath возвращает путь к файлу спрайта name
+func imagePath(name string) string {
+	return path.Join("sprites", name+".png")
+}
+
+// GetSound возвращает звук name - путь внутри sounds без расширения (например, "effects/jump")
+func GetSound(name string) (Sound, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if sound, ok := sounds[name]; ok {
+		return sound, nil
+	}
+
+	for _, ext := range soundExtensions {
+		data, err := readFile(path.Join("sounds", name+ext))
+		if err != nil {
+			continue
+		}
+		sound := Sound{Name: name, Format: ext, Data: data}
+		sounds[name] = sound
+		return sound, nil
+	}
+	return Sound{}, fmt.Errorf("assets: sound %q: %w", name, fs.ErrNotExist)
+}
+
+// Sounds возвращает имена звуков каталога dir внутри sounds (без расширений, по алфавиту)
+func Sounds(dir string) ([]string, error) {
+	return names(path.Join("sounds", dir), soundExtensions)
+}
+
+// GetFont возвращает данные TrueType-шрифта name (без расширения)
+func GetFont(name string) ([]byte, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if data, ok := fonts[name]; ok {
+		return data, nil
+	}
+	data, err := readFile(path.Join("fonts", name+".ttf"))
+	if err != nil {
+		return nil, fmt.Errorf("assets: font %q: %w", name, err)
+	}
+	fonts[name] = data
+	return data, nil
+}
+
+// GetLevel возвращает JSON уровня name (без расширения)
+func GetLevel(name string) ([]byte, error) {
+	data, err := readFile(path.Join("levels", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("assets: level %q: %w", name, err)
+	}
+	return data, nil
+}
+
+// Levels возвращает имена встроенных уровней по алфавиту
+func Levels() ([]string, error) {
+	return names("levels", []string{".json"})
+}
+
+// names возвращает имена файлов каталога dir с одним из расширений exts (без расширений)
+func names(dir string, exts []string) ([]string, error) {
+	entries, err := fs.ReadDir(source, dir)
+	if err != nil {
+		return nil, fmt.Errorf("assets: %w", err)
+	}
+
+	seen := make(map[string]bool, len(entries))
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		known := false
+		for _, e := range exts {
+			known = known || e == ext
+		}
+		name := strings.TrimSuffix(entry.Name(), ext)
+		if entry.IsDir() || !known || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,98 @@
+package assets
+
+import (
+	"fmt"
+	"image"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// watched — время изменения прочитанных с диска файлов по их путям (только в режиме -dev)
+var watched map[string]time.Time
+
+// UseDirectory переключает загрузку ресурсов с встроенных файлов на каталог dir
+// с той же структурой (sprites, sounds, fonts, levels). Используется в режиме разработки,
+// чтобы изменения файлов подхватывались без пересборки.
+func UseDirectory(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("assets: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("assets: %s is not a directory", dir)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	source = os.DirFS(dir)
+	watched = make(map[string]time.Time)
+	images = make(map[string]image.Image)
+	sounds = make(map[string]Sound)
+	fonts = make(map[string][]byte)
+	return nil
+}
+
+// readFile читает файл ресурса и в режиме разработки запоминает время его изменения
+func readFile(name string) ([]byte, error) {
+	data, err := fs.ReadFile(source, name)
+	if err != nil {
+		return nil, err
+	}
+	if watched != nil {
+		if info, err := fs.Stat(source, name); err == nil {
+			watched[name] = info.ModTime()
+		}
+	}
+	return data, nil
+}
+
+// Changes — виды ресурсов, файлы которых изменились с прошлой проверки
+type Changes struct {
+	Images bool // Спрайты обновлены; их нужно запросить заново
+	Sounds bool // Звуки нужно запросить заново
+}
+
+// Poll проверяет, не изменились ли на диске уже загруженные файлы, и обновляет кэш.
+// Вызывается из игрового цикла; без UseDirectory ничего не делает.
+func Poll() (Changes, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var changes Changes
+	var firstErr error
+	for name, modTime := range watched {
+		info, err := fs.Stat(source, name)
+		if err != nil || info.ModTime().Equal(modTime) {
+			continue
+		}
+		watched[name] = info.ModTime()
+
+		switch dir, file := path.Split(name); {
+		case dir == "sprites/":
+			if err := reloadImage(strings.TrimSuffix(file, path.Ext(file))); err != nil && firstErr == nil {
+				firstErr = err
+			}
+			changes.Images = true
+		case strings.HasPrefix(dir, "sounds/"):
+			delete(sounds, strings.TrimSuffix(strings.TrimPrefix(name, "sounds/"), path.Ext(file)))
+			changes.Sounds = true
+		case dir == "fonts/":
+			delete(fonts, strings.TrimSuffix(file, path.Ext(file)))
+		}
+	}
+	return changes, firstErr
+}
+
+// reloadImage заново декодирует спрайт name. Если изменившийся файл не читается
+// (например, редактор еще не дописал его), в кэше остается прежнее изображение.
+func reloadImage(name string) error {
+	img, err := decodeImage(name)
+	if err != nil {
+		return err
+	}
+	images[name] = img
+	return nil
+}
@@ -0,0 +1,94 @@
+package budget
+
+// Kind — вид динамического содержимого, количество которого ограничено
+type Kind string
+
+const (
+	KindBullets   Kind = "bullets"
+	KindParticles Kind = "particles" // Частицы погоды
+	KindCorpses   Kind = "corpses"
+	KindEffects   Kind = "effects" // Всплывающие числа урона, отметки попаданий и следы выстрелов
+)
+
+// kindsOrder задает порядок вывода статистики
+var kindsOrder = []Kind{KindBullets, KindParticles, KindCorpses, KindEffects}
+
+// Caps — максимальное количество объектов каждого вида (0 - без ограничения)
+type Caps map[Kind]int
+
+// Stat — статистика по одному виду содержимого
+type Stat struct {
+	Kind    Kind `json:"kind"`
+	Live    int  `json:"live"`    // Сколько объектов существует сейчас
+	Cap     int  `json:"cap"`     // Ограничение (0 - без ограничения)
+	Evicted int  `json:"evicted"` // Сколько объектов вытеснено за все время
+}
+
+// Tracker следит за ограничениями и считает вытесненные объекты.
+// При превышении ограничения удаляются самые старые объекты, поэтому
+// долгие сессии на больших уровнях не наращивают память неограниченно.
+type Tracker struct {
+	caps    Caps
+	live    map[Kind]int
+	evicted map[Kind]int
+}
+
+// NewTracker создает трекер с заданными ограничениями
+func NewTracker(caps Caps) *Tracker {
+	return &Tracker{
+		caps:    caps,
+		live:    make(map[Kind]int),
+		evicted: make(map[Kind]int),
+	}
+}
+
+// Stats возвращает статистику по всем видам содержимого
+func (t *Tracker) Stats() []Stat {
+	stats := make([]Stat, 0, len(kindsOrder))
+	for _, kind := range kindsOrder {
+		stats = append(stats, Stat{
+			Kind:    kind,
+			Live:    t.live[kind],
+			Cap:     t.caps[kind],
+			Evicted: t.evicted[kind],
+		})
+	}
+	return stats
+}
+
+// Cap возвращает ограничение для вида содержимого (0 - без ограничения)
+func (t *Tracker) Cap(kind Kind) int {
+	return t.caps[kind]
+}
+
+// Observe записывает, сколько объектов вида kind существует и сколько из них только что вытеснено.
+// Нужен контейнерам, которые вытесняют объекты сами, а не через Apply.
+func (t *Tracker) Observe(kind Kind, live, evicted int) {
+	t.evicted[kind] += evicted
+	t.live[kind] = live
+}
+
+// Apply применяет ограничение к списку, в котором старые объекты идут первыми,
+// и возвращает список без вытесненных объектов
+func Apply[T any](t *Tracker, kind Kind, items []T) []T {
+	items, evicted := Evict(items, t.caps[kind])
+	t.Observe(kind, len(items), evicted)
+	return items
+}
+
+// Evict удаляет самые старые объекты (из начала списка), чтобы осталось не больше max.
+// Возвращает укороченный список и количество удаленных объектов. При max <= 0 список не меняется.
+func Evict[T any](items []T, max int) ([]T, int) {
+	if max <= 0 || len(items) <= max {
+		return items, 0
+	}
+
+	evicted := len(items) - max
+	// Сдвигаем оставшиеся объекты в начало, чтобы переиспользовать массив
+	kept := copy(items, items[evicted:])
+	var zero T
+	for i := kept; i < len(items); i++ {
+		items[i] = zero // Освобождаем ссылки на вытесненные объекты
+	}
+	return items[:kept], evicted
+}
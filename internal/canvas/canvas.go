@@ -0,0 +1,98 @@
+package canvas
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	"platformer/internal/entities"
+	"platformer/internal/leaderboard"
+	"platformer/internal/level"
+	"platformer/internal/speedrun"
+	"platformer/internal/transform"
+	"platformer/internal/weather"
+)
+
+// Canvas — кадр, на котором игра рисует мир и интерфейс.
+// Игра описывает кадр только через этот интерфейс и не знает, чем он рисуется,
+// поэтому симуляция и сборка кадра не зависят от окна и графической библиотеки.
+// Закешированные изображения (карта освещения, миникарта, половины экрана)
+// хранит сам кадр.
+type Canvas interface {
+	// DrawBackground заливает кадр фоном текущего бэкенда отрисовки
+	DrawBackground()
+
+	// Сущности рисуют себя сами (entities.Entity.Draw); view переводит
+	// мировые координаты в экранные
+	entities.Canvas
+
+	// Прочие объекты мира
+	DrawExit(x, y, width, height float64, view transform.View)
+	DrawHealthBar(player *entities.Player, view transform.View)
+	DrawBase(x, y, width, height float64, team int, view transform.View)
+	DrawFlag(x, y, width, height float64, team int, view transform.View)
+
+	// Подписи и эффекты, привязанные к точкам мира
+	DrawSpeech(text string, x, y float64, view transform.View)
+	DrawNameTag(name string, player *entities.Player, view transform.View, clr color.Color)
+	DrawEmoteBubble(text string, x, y float64, view transform.View, progress float64)
+	DrawWeather(system *weather.System, view transform.View)
+	DrawPhysicsDebug(view transform.View, debug PhysicsDebug)
+
+	// DrawLighting затемняет кадр до яркости ambient (от 0 до 1) и высветляет
+	// пятна источников света lights, заданных в координатах мира
+	DrawLighting(view transform.View, ambient float64, lights []level.Light)
+
+	// DrawMinimap рисует миникарту области bounds с платформами platforms, рамкой
+	// вида view и отметками markers. Неподвижные платформы рисуются в изображение
+	// карты один раз и перерисовываются, только когда меняется revision;
+	// платформы moving рисуются каждый кадр.
+	DrawMinimap(revision int, bounds level.Rect, platforms, moving []*entities.Platform, view transform.View, markers []MinimapMarker)
+
+	// Интерфейс поверх мира
+	DrawBanner(text string)
+	DrawCollapseFront()
+	DrawConsole(lines []string, input string)
+	DrawHUD(player *entities.Player)
+	DrawWeaponInfo(name string)
+	DrawDebugPage(title string, lines []string)
+	DrawNetworkStatus(text string)
+	DrawConnectionInfo(pingMs, lossPercent, ageMs int, quality ConnectionQuality)
+	DrawMatchInfo(localScore, remoteScore, secondsLeft int, isHost bool)
+	DrawCoopInfo(stage, stages int)
+	DrawDeathmatchInfo(round, localKills, remoteKills, limit int)
+	DrawCTFInfo(red, blue, limit, secondsLeft int)
+	DrawScoreboard(title string, rows []ScoreboardRow)
+	DrawSpeedrun(elapsed time.Duration, splits []speedrun.Split, finished bool)
+	DrawSplitDivider()
+
+	// Экраны вне игры
+	DrawLoading(progress float64, current string)
+	DrawMenu(title string, items []string, selected int)
+	DrawMenuWithHint(title string, items []string, selected int, hint string)
+	DrawLeaderboard(sections []LeaderboardSection, status string)
+	DrawLobby(players []LobbyPlayer, rules, status, hint string)
+
+	// DrawHalf рисует draw в половину index (0 - левая, 1 - правая) разделенного
+	// экрана: половина очищается, а затем накладывается на кадр
+	DrawHalf(index int, draw DrawFunc)
+
+	// ReadPixels копирует кадр в dst; кадр уменьшается до размера dst,
+	// если тот меньше (кадры клипов)
+	ReadPixels(dst *image.RGBA)
+}
+
+// ConnectionQuality — качество сетевого соединения для индикатора
+type ConnectionQuality int
+
+const (
+	ConnectionGood ConnectionQuality = iota // Задержка незаметна
+	ConnectionFair                          // Задержка заметна, но играть можно
+	ConnectionPoor                          // Игра дергается из-за сети
+)
+
+// LeaderboardSection — одна таблица рекордов для отображения
+type LeaderboardSection struct {
+	Title   string
+	Entries []leaderboard.Entry
+}
@@ -0,0 +1,34 @@
+package canvas
+
+import "platformer/internal/entities"
+
+// DebugBoxKind определяет цвет хитбокса в отладочном слое физики
+type DebugBoxKind int
+
+const (
+	DebugPlatform DebugBoxKind = iota
+	DebugPlayer
+	DebugNPC
+	DebugBullet
+	DebugTrigger
+)
+
+// DebugBox — хитбокс объекта со скоростью
+type DebugBox struct {
+	Box                  entities.AABB
+	Kind                 DebugBoxKind
+	VelocityX, VelocityY float64
+	Label                string // Подпись (например, вид триггера)
+}
+
+// DebugContact — точка контакта и нормаль поверхности, от которой вытолкнули объект
+type DebugContact struct {
+	X, Y             float64
+	NormalX, NormalY float64
+}
+
+// PhysicsDebug — все, что рисует отладочный слой физики за кадр
+type PhysicsDebug struct {
+	Boxes    []DebugBox
+	Contacts []DebugContact
+}
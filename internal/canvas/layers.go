@@ -0,0 +1,68 @@
+package canvas
+
+import "sort"
+
+// Layer — слой отрисовки кадра. Слои рисуются по возрастанию: фон снизу, интерфейс сверху.
+type Layer int
+
+const (
+	LayerBackground  Layer = iota // Фон
+	LayerTerrain                  // Платформы и выходы
+	LayerEntities                 // Персонажи и NPC
+	LayerProjectiles              // Пули и следы выстрелов
+	LayerParticles                // Всплывающие надписи, отметки попаданий и прочие эффекты
+	LayerLighting                 // Карта освещения темных уровней поверх мира
+	LayerUI                       // Интерфейс поверх мира
+)
+
+// DrawFunc рисует что-то на кадре
+type DrawFunc func(screen Canvas)
+
+// drawCall — отложенный вызов отрисовки
+type drawCall struct {
+	layer Layer
+	depth float64
+	draw  DrawFunc
+}
+
+// DrawQueue собирает вызовы отрисовки кадра и выполняет их по слоям. Внутри слоя вызовы
+// упорядочиваются по глубине (больше - ближе к зрителю), а при равной глубине -
+// по порядку добавления, поэтому порядок не зависит от порядка кода в Game.Draw.
+type DrawQueue struct {
+	calls []drawCall
+}
+
+// Add добавляет вызов отрисовки в слой layer с глубиной depth
+func (q *DrawQueue) Add(layer Layer, depth float64, draw DrawFunc) {
+	q.calls = append(q.calls, drawCall{layer: layer, depth: depth, draw: draw})
+}
+
+// Flush выполняет собранные вызовы в порядке слоев и очищает очередь.
+// Память очереди переиспользуется в следующих кадрах.
+func (q *DrawQueue) Flush(screen Canvas) {
+	q.FlushBelow(screen, LayerUI+1)
+}
+
+// FlushBelow выполняет только вызовы слоев ниже layer и убирает их из очереди;
+// остальные вызовы остаются до следующего Flush. Так можно снять кадр без интерфейса.
+func (q *DrawQueue) FlushBelow(screen Canvas, layer Layer) {
+	sort.SliceStable(q.calls, func(i, j int) bool {
+		if q.calls[i].layer != q.calls[j].layer {
+			return q.calls[i].layer < q.calls[j].layer
+		}
+		return q.calls[i].depth < q.calls[j].depth
+	})
+	done := 0
+	for _, call := range q.calls {
+		if call.layer >= layer {
+			break
+		}
+		call.draw(screen)
+		done++
+	}
+	rest := copy(q.calls, q.calls[done:])
+	for i := rest; i < len(q.calls); i++ {
+		q.calls[i] = drawCall{}
+	}
+	q.calls = q.calls[:rest]
+}
@@ -0,0 +1,53 @@
+package canvas
+
+import "image/color"
+
+// PlayerColors — цвета, которые игроки выбирают в лобби
+var PlayerColors = []color.RGBA{
+	{R: 80, G: 160, B: 255, A: 255},
+	{R: 240, G: 90, B: 80, A: 255},
+	{R: 90, G: 210, B: 110, A: 255},
+	{R: 245, G: 200, B: 70, A: 255},
+	{R: 190, G: 110, B: 240, A: 255},
+	{R: 250, G: 150, B: 60, A: 255},
+}
+
+// PlayerColor возвращает цвет игрока с номером index (по кругу)
+func PlayerColor(index int) color.RGBA {
+	n := len(PlayerColors)
+	return PlayerColors[(index%n+n)%n]
+}
+
+// TeamColors — цвета команд: красные и синие
+var TeamColors = []color.RGBA{
+	{R: 230, G: 70, B: 60, A: 255},
+	{R: 70, G: 130, B: 240, A: 255},
+}
+
+// TeamColor возвращает цвет команды team
+func TeamColor(team int) color.RGBA {
+	n := len(TeamColors)
+	return TeamColors[(team%n+n)%n]
+}
+
+// LobbyPlayer — строка игрока в лобби
+type LobbyPlayer struct {
+	Name     string
+	Color    int    // Индекс в PlayerColors
+	Team     int    // Индекс в TeamColors
+	TeamName string // Название команды
+	Ready    bool   // Игрок готов к началу матча
+	Host     bool   // Игрок - хост
+	Local    bool   // Это игрок за этим экраном
+	Present  bool   // Игрок подключен (иначе - пустое место)
+}
+
+// ScoreboardRow — строка игрока в таблице счета
+type ScoreboardRow struct {
+	Name   string
+	Color  int  // Индекс в PlayerColors
+	Kills  int  // Убийства в текущем раунде
+	Deaths int  // Гибели в текущем раунде
+	Rounds int  // Выигранные раунды
+	Local  bool // Это игрок за этим экраном
+}
@@ -0,0 +1,16 @@
+package canvas
+
+// MarkerKind — вид отметки сущности на миникарте
+type MarkerKind int
+
+const (
+	MarkerPlayer MarkerKind = iota // Персонаж игрока
+	MarkerRemote                   // Персонаж второго игрока
+	MarkerNPC                      // NPC
+)
+
+// MinimapMarker — отметка сущности на миникарте; X, Y - центр сущности в мире
+type MinimapMarker struct {
+	X, Y float64
+	Kind MarkerKind
+}
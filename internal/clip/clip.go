@@ -0,0 +1,88 @@
+package clip
+
+import (
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"os"
+	"path/filepath"
+)
+
+// Recorder хранит последние кадры игры в кольцевом буфере, чтобы по запросу
+// сохранить несколько последних секунд в анимированный GIF
+type Recorder struct {
+	width, height int
+	frames        []*image.RGBA // Кольцевой буфер кадров
+	next          int           // Индекс, в который попадет следующий кадр
+	count         int           // Сколько кадров в буфере заполнено
+}
+
+// NewRecorder создает буфер на capacity кадров размером width на height.
+// Память под кадры выделяется по мере записи.
+func NewRecorder(capacity, width, height int) *Recorder {
+	return &Recorder{width: width, height: height, frames: make([]*image.RGBA, capacity)}
+}
+
+// Size возвращает размер кадров буфера
+func (r *Recorder) Size() (int, int) {
+	return r.width, r.height
+}
+
+// Next возвращает изображение, в которое нужно записать следующий кадр.
+// Самый старый кадр переиспользуется, когда буфер заполнен.
+func (r *Recorder) Next() *image.RGBA {
+	frame := r.frames[r.next]
+	if frame == nil {
+		frame = image.NewRGBA(image.Rect(0, 0, r.width, r.height))
+		r.frames[r.next] = frame
+	}
+	r.next = (r.next + 1) % len(r.frames)
+	r.count = min(r.count+1, len(r.frames))
+	return frame
+}
+
+// Take забирает записанные кадры от старого к новому и очищает буфер.
+// Забранные кадры больше не переиспользуются, поэтому их можно кодировать в фоне.
+func (r *Recorder) Take() []*image.RGBA {
+	frames := make([]*image.RGBA, 0, r.count)
+	start := (r.next - r.count + len(r.frames)) % len(r.frames)
+	for i := 0; i < r.count; i++ {
+		index := (start + i) % len(r.frames)
+		frames = append(frames, r.frames[index])
+		r.frames[index] = nil
+	}
+	r.next, r.count = 0, 0
+	return frames
+}
+
+// WriteGIF записывает кадры в анимированный GIF-файл path; delay - пауза между
+// кадрами в сотых долях секунды. Каталог файла создается при необходимости.
+func WriteGIF(path string, frames []*image.RGBA, delay int) error {
+	if len(frames) == 0 {
+		return fmt.Errorf("clip: no frames")
+	}
+
+	anim := &gif.GIF{}
+	for _, frame := range frames {
+		// Общая палитра на все кадры; рассеивание скрывает ступеньки градиентов
+		paletted := image.NewPaletted(frame.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, frame.Bounds(), frame, image.Point{})
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, delay)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("clip: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("clip: %w", err)
+	}
+	if err := gif.EncodeAll(file, anim); err != nil {
+		file.Close()
+		return fmt.Errorf("clip: encode %s: %w", path, err)
+	}
+	return file.Close()
+}
@@ -8,21 +8,247 @@ const (
 
 	// Размеры игрового мира (карта больше экрана)
 	WorldWidth  = 5000 // Ширина игрового мира
-	WorldHeight = 800  // Высота игрового мира (равна высоте экрана)
+	WorldHeight = 800  // Высота игрового мира по умолчанию (уровень задает свою)
 
 	// Размеры персонажа
 	PlayerWidth  = 40
 	PlayerHeight = 40
 
+	// Приседание
+	PlayerCrouchHeight    = 24  // Высота хитбокса присевшего персонажа
+	CrouchSpeedMultiplier = 0.5 // Во сколько раз замедляется движение в приседе
+
 	// Физические константы
 	Gravity      = 0.5   // Сила гравитации (ускорение вниз)
-	JumpStrength = -15.0 // Сила прыжка (отрицательное значение, так как Y растет вниз)
+	JumpStrength = -15.0 // Сила прыжка при удержании клавиши (отрицательное значение, так как Y растет вниз)
 	MoveSpeed    = 5.0   // Скорость горизонтального движения
 	MaxFallSpeed = 15.0  // Максимальная скорость падения
 	Friction     = 0.8   // Коэффициент трения при движении
 
+	// Трение на платформах из особых материалов (заменяет Friction и SprintFriction, пока персонаж стоит на них)
+	IceFriction = 0.98 // Лед: персонаж долго скользит после отпускания клавиш
+	MudFriction = 0.4  // Грязь: персонаж останавливается почти сразу
+
+	// Минимальная сила прыжка: при раннем отпускании клавиши скорость вверх
+	// обрезается до этого значения, и получается короткий подскок
+	MinJumpStrength = -6.0
+
+	// Двойной прыжок
+	MaxAirJumps     = 1     // Сколько дополнительных прыжков доступно в воздухе
+	AirJumpStrength = -12.0 // Сила прыжка в воздухе
+
+	// Бег (удержание Shift во время движения)
+	SprintSpeed        = 8.5  // Максимальная скорость бега
+	SprintAcceleration = 0.35 // Прирост скорости за кадр при разгоне до бега
+	SprintFriction     = 0.93 // Трение при торможении после бега (скольжение дольше обычного)
+
+	// Рывок (Shift)
+	DashSpeed          = 14.0 // Горизонтальная скорость во время рывка
+	DashFrames         = 10   // Длительность рывка в кадрах (гравитация в это время не действует)
+	DashCooldownFrames = 45   // Перезарядка рывка в кадрах
+	DashInvulnerable   = true // Неуязвим ли персонаж во время рывка
+
+	// "Время койота": сколько кадров после схода с платформы еще можно прыгнуть
+	CoyoteTimeFrames = 6
+
 	// Константы для стрельбы
 	BulletSpeed  = 10.0 // Скорость полета пули
 	BulletWidth  = 8.0  // Ширина пули
 	BulletHeight = 40.0 // Высота пули
+
+	// Сколько раз пуля бластера отскакивает от платформ, прежде чем исчезнуть (0 - исчезает при первом касании)
+	BulletRicochets = 2
+
+	// Пробивание: пуля пролетает сквозь BulletPierce NPC, и с каждым пробитым
+	// урон умножается на BulletPierceFalloff. Платформы пуля не пробивает.
+	BulletPierce        = 1
+	BulletPierceFalloff = 0.5
+
+	// Константы задания сопровождения
+	NPCFollowSpeed      = 3.5  // Скорость NPC, следующего за игроком
+	NPCFollowDistance   = 60.0 // На каком расстоянии от игрока NPC останавливается
+	NPCInteractDistance = 80.0 // На каком расстоянии можно заговорить с NPC
+	EscortExitX         = 4800 // Позиция выхода с уровня по X
+	EscortExitWidth     = 80   // Ширина зоны выхода
+	EscortExitHeight    = 120  // Высота зоны выхода
+	BulletDamage        = 25   // Урон от одной пули
+
+	// Отбрасывание и оглушение при получении урона
+	PlayerMaxHealth = 100  // Здоровье персонажа
+	KnockbackForce  = 7.0  // Горизонтальная скорость отбрасывания
+	KnockbackLift   = -5.0 // Вертикальная скорость отбрасывания (вверх)
+	HitstunFrames   = 18   // Сколько кадров цель не управляется после удара
+
+	// Длительность перехода камеры между комнатами в кадрах
+	RoomTransitionFrames = 40
+
+	// Длительность сетевого матча в кадрах (3 минуты)
+	VersusMatchFrames = 3 * 60 * 60
+
+	// Через сколько кадров после прохождения уровня кооперативной кампании начинается следующий (3 секунды)
+	CoopAdvanceFrames = 3 * 60
+
+	// Сколько убийств нужно для победы в раунде дезматча
+	DeathmatchScoreLimit = 10
+	// Пауза между раундами дезматча в кадрах (5 секунд)
+	RoundRestartFrames = 5 * 60
+
+	// Как часто хост отправляет клиенту изменения уровня, даже если их нет (2 секунды)
+	WorldSyncFrames = 2 * 60
+
+	// Сколько кадров погибший в сетевой игре персонаж ждет появления (3 секунды)
+	RespawnFrames = 3 * 60
+
+	// Размеры флага в захвате флага
+	FlagWidth  = 20
+	FlagHeight = 40
+	// Через сколько кадров упавший флаг сам возвращается на базу (10 секунд)
+	FlagReturnFrames = 10 * 60
+	// Сколько захватов флага нужно для победы в матче
+	CTFCaptureLimit = 3
+
+	// Сколько вводов клиента хост держит в очереди; лишние (старые) отбрасываются,
+	// чтобы задержка управления клиента не росла
+	OpponentInputBuffer = 4
+
+	// Интерполяция персонажа хоста у клиента
+	InterpolationDelay     = 6.0  // На сколько шагов хоста (~100 мс) показ отстает от последнего снимка
+	InterpolationBuffer    = 32   // Сколько последних состояний хранится
+	InterpolationCatchUp   = 0.05 // Доля расхождения часов показа, выравниваемая за шаг
+	InterpolationSnapSteps = 30.0 // При расхождении больше стольких шагов часы переставляются сразу
+	InterpolationTeleport  = 32.0 // Сдвиг за шаг хоста (пикселей), который считается телепортом
+
+	// Сколько неподтвержденных хостом вводов помнит клиент для повтора после снимка (~2 с)
+	PredictionHistory = 120
+
+	// Компенсация задержки выстрелов клиента
+	LagCompensationHistory  = 60 // Сколько снимков (~1 с) хост помнит положение своего персонажа
+	LagCompensationMaxSteps = 30 // Насколько шагов (~500 мс) в прошлое можно вернуть персонажа
+
+	// Пороги индикатора качества соединения: заметная и мешающая играть задержка
+	NetPingFairMs  = 100  // Пинг, мс
+	NetPingPoorMs  = 250  // Пинг, мс
+	NetLossFair    = 0.02 // Доля потерь
+	NetLossPoor    = 0.10 // Доля потерь
+	NetStaleFairMs = 100  // Время с последнего обновления, мс
+	NetStalePoorMs = 500  // Время с последнего обновления, мс
+
+	// Наклон стика геймпада, с которого он считается нажатым (от 0 до 1)
+	GamepadDeadZone = 0.3
+
+	// Второй игрок за этим компьютером появляется правее первого на столько пикселей
+	SecondPlayerOffset = 60.0
+
+	// Бот (персонаж, которым управляет программа)
+	BotKeepDistance   = 120.0 // Ближе к цели бот не подходит и только стреляет
+	BotShootRange     = 500.0 // Дальше цели бот не стреляет
+	BotJumpRise       = 60.0  // Насколько цель должна быть выше, чтобы бот прыгал к ней
+	BotChaseJumpRange = 200.0 // По горизонтали не дальше этого бот прыгает к цели наверху
+	BotProbeDistance  = 8.0   // Ширина полоски, которой бот ищет стену и яму впереди
+	BotGapDepth       = 24.0  // Глубина, на которой бот ищет опору за краем
+
+	// Эмоции игроков
+	EmoteFrames         = 120 // Сколько кадров показывается эмоция
+	EmoteCooldownFrames = 30  // Минимальный интервал между эмоциями
+
+	// Триггеры уровня
+	DamageZoneIntervalFrames = 30  // Как часто опасная зона наносит урон игроку внутри нее
+	TriggerMessageFrames     = 150 // Сколько кадров показывается сообщение триггера
+
+	// Тряска камеры при попадании
+	CameraShakeStrength = 4.0 // Амплитуда в пикселях экрана
+	CameraShakeFrames   = 12  // Длительность в кадрах
+
+	// Ограничения динамического содержимого (самые старые объекты вытесняются)
+	MaxBullets   = 64
+	MaxParticles = 1024 // Частицы погоды комнаты
+	MaxCorpses   = 32
+	MaxEffects   = 64
+
+	// Сколько лучших результатов хранит локальная таблица рекордов
+	HighScoreCount = 10
+
+	// Размеры шрифта надписей интерфейса (в пикселях)
+	FontSizeHUD      = 13 // Отладочная информация и строки состояния
+	FontSizeMenu     = 18 // Пункты меню и таблицы рекордов
+	FontSizeTitle    = 30 // Заголовки экранов
+	FontSizeDialogue = 14 // Реплики и облачка эмоций
+	FontSizeBanner   = 22 // Крупные сообщения в центре экрана
+
+	// Погода
+	WeatherMaxParticles = 400  // Частиц при наибольшей интенсивности осадков
+	RainSpeed           = 14.0 // Скорость падения капель дождя
+	SnowSpeed           = 1.5  // Скорость падения снежинок
+	WetFriction         = 0.93 // Трение обычных платформ под дождем на уровнях со скользкой погодой
+
+	// Миникарта в правом нижнем углу экрана (в пикселях)
+	MinimapWidth        = 220 // Наибольшая ширина миникарты
+	MinimapMaxHeight    = 120 // Наибольшая высота миникарты
+	MinimapMargin       = 10  // Отступ от краев экрана
+	MinimapMarkerRadius = 2.5 // Радиус отметки сущности
+
+	// Подгрузка больших уровней без комнат частями вокруг камеры и персонажей
+	ChunkSize       = 1024.0 // Сторона квадратной части мира в пикселях
+	ChunkLoadRadius = 1      // Сколько частей в каждую сторону от камеры и персонажей загружено
+	StreamMinChunks = 16     // Уровни с меньшим числом частей загружаются целиком
+
+	// Каталог для снимков экрана (F12) и клипов (F10)
+	ScreenshotDir = "screenshots"
+
+	// Клипы: последние секунды игры в уменьшенных кадрах
+	ClipSeconds = 5   // Длина клипа по умолчанию
+	ClipFPS     = 15  // Кадров клипа в секунду (делитель 60)
+	ClipScale   = 0.4 // Масштаб кадров клипа относительно экрана
+
+	// Сколько секунд по умолчанию восстанавливается оборвавшееся сетевое соединение
+	ReconnectSeconds = 30
+
+	// Сила оттенка спрайта цветом игрока в сетевой игре (0 - без оттенка, 1 - только цвет игрока)
+	PlayerTintStrength = 0.65
+
+	// Отладочная консоль (клавиша `)
+	ConsoleLines       = 14   // Строк журнала над строкой ввода
+	ConsoleSpawnOffset = 40.0 // Расстояние от персонажа до NPC, созданного командой spawn
+	ConsoleNPCSize     = 40.0 // Размер NPC, созданного командой spawn
+
+	// За сколько кадров усредняются замеры производительности (-pprof и страница отрисовки)
+	MetricsWindowFrames = 60
+
+	// Как часто в режиме -dev проверяются изменения файлов ресурсов (в кадрах)
+	AssetPollFrames = 30
+
+	// Каталог, в котором меню настроек ищет PNG-скины персонажа
+	SkinDir = "skins"
+
+	// Количество слотов профилей игроков
+	ProfileSlots = 3
+
+	// Частота дискретизации звука и громкость эффектов (от 0 до 1)
+	AudioSampleRate = 44100
+
+	// Громкость по умолчанию: общая, эффектов и музыки (от 0 до 1),
+	// и шаг ее изменения в меню настроек
+	MasterVolume  = 1.0
+	EffectsVolume = 0.5
+	MusicVolume   = 0.35
+	VolumeStep    = 0.1
+
+	// Длительность перехода между треками в кадрах и приглушение музыки во время диалогов
+	MusicCrossfadeFrames = 90
+	MusicDuckVolume      = 0.3 // Доля обычной громкости, до которой музыка приглушается
+	MusicDuckFrames      = 20  // За сколько кадров музыка приглушается или возвращается
+
+	// Перезарядка оружия в кадрах (60 кадров = 1 секунда)
+	ShootCooldownFrames = 10
+
+	// Точная винтовка (мгновенный луч)
+	RifleCooldownFrames = 45     // Перезарядка винтовки
+	RifleRange          = 1200.0 // Дальность луча
+	RifleDamage         = 50     // Урон от попадания
+	TracerFrames        = 10     // Сколько кадров виден след выстрела
+
+	// Всплывающие числа урона и отметки попаданий
+	DamageNumberFrames = 45  // Сколько кадров видно число урона
+	DamageNumberRise   = 0.8 // На сколько пикселей число поднимается за кадр
+	HitMarkerFrames    = 20  // Сколько кадров видна отметка попадания
 )
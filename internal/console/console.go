@@ -0,0 +1,148 @@
+package console
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+)
+
+// Command — команда консоли. Run получает аргументы без имени команды
+// и возвращает текст ответа; ошибка выводится в консоль вместо ответа.
+type Command struct {
+	Name  string
+	Usage string // Аргументы для справки (например, "<x> <y>")
+	Help  string // Ключ перевода описания команды
+	Run   func(args []string) (string, error)
+}
+
+// Console — отладочная консоль: реестр команд, строка ввода, история команд и журнал ответов
+type Console struct {
+	commands map[string]Command
+	open     bool
+	input    []rune
+	lines    []string // Журнал: введенные команды и ответы, не длиннее config.ConsoleLines
+	history  []string // Выполненные команды для перелистывания стрелками
+	browse   int      // Позиция в истории при перелистывании (len(history) - новая строка)
+}
+
+// New создает консоль со встроенной командой help
+func New() *Console {
+	c := &Console{commands: make(map[string]Command)}
+	c.Register(Command{Name: "help", Help: "console.help", Run: c.help})
+	return c
+}
+
+// Register добавляет команду; команда с тем же именем заменяется
+func (c *Console) Register(cmd Command) {
+	c.commands[cmd.Name] = cmd
+}
+
+// Open сообщает, открыта ли консоль
+func (c *Console) Open() bool {
+	return c.open
+}
+
+// Toggle открывает или закрывает консоль
+func (c *Console) Toggle() {
+	c.open = !c.open
+}
+
+// Input возвращает набранную строку
+func (c *Console) Input() string {
+	return string(c.input)
+}
+
+// Lines возвращает журнал консоли от старых строк к новым
+func (c *Console) Lines() []string {
+	return c.lines
+}
+
+// Type добавляет набранные символы в строку ввода
+func (c *Console) Type(chars []rune) {
+	c.input = append(c.input, chars...)
+}
+
+// Backspace стирает последний символ строки ввода
+func (c *Console) Backspace() {
+	if len(c.input) > 0 {
+		c.input = c.input[:len(c.input)-1]
+	}
+}
+
+// Browse перелистывает историю команд: delta = -1 - предыдущая команда, 1 - следующая
+func (c *Console) Browse(delta int) {
+	if len(c.history) == 0 {
+		return
+	}
+	c.browse = max(0, min(len(c.history), c.browse+delta))
+	if c.browse == len(c.history) {
+		c.input = c.input[:0]
+		return
+	}
+	c.input = []rune(c.history[c.browse])
+}
+
+// Submit выполняет набранную строку и очищает ввод
+func (c *Console) Submit() {
+	line := strings.TrimSpace(string(c.input))
+	c.input = c.input[:0]
+	if line == "" {
+		return
+	}
+	c.history = append(c.history, line)
+	c.browse = len(c.history)
+	c.Execute(line)
+}
+
+// Execute выполняет командную строку line и записывает ее и ответ в журнал
+func (c *Console) Execute(line string) {
+	c.print("> " + line)
+	if out := c.Run(line); out != "" {
+		c.print(out)
+	}
+}
+
+// Run выполняет командную строку line и возвращает ответ, не записывая его в журнал
+// (так команды выполняет удаленное управление). Ошибка команды возвращается текстом ответа.
+func (c *Console) Run(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	cmd, ok := c.commands[strings.ToLower(fields[0])]
+	if !ok {
+		return i18n.T("console.unknown", fields[0])
+	}
+	out, err := cmd.Run(fields[1:])
+	if err != nil {
+		return i18n.T("console.error", err)
+	}
+	return out
+}
+
+// print добавляет в журнал строки текста, вытесняя самые старые
+func (c *Console) print(text string) {
+	c.lines = append(c.lines, strings.Split(text, "\n")...)
+	if extra := len(c.lines) - config.ConsoleLines; extra > 0 {
+		c.lines = append(c.lines[:0], c.lines[extra:]...)
+	}
+}
+
+// help выводит список команд с описанием
+func (c *Console) help(args []string) (string, error) {
+	names := make([]string, 0, len(c.commands))
+	for name := range c.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		cmd := c.commands[name]
+		lines = append(lines, fmt.Sprintf("%-24s %s", strings.TrimSpace(cmd.Name+" "+cmd.Usage), i18n.T(cmd.Help)))
+	}
+	return strings.Join(lines, "\n"), nil
+}
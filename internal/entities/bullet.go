@@ -1,24 +1,73 @@
 package entities
 
+import (
+	"platformer/internal/config"
+	"platformer/internal/transform"
+)
+
 // Bullet представляет пулю, выпущенную персонажем
 type Bullet struct {
-	X, Y          float64 // Позиция пули на экране
-	VelocityX     float64 // Скорость пули по горизонтали (положительная = вправо, отрицательная = влево)
-	Width, Height float64 // Размеры пули
+	ID        ID      `json:"-"` // Стабильный идентификатор
+	AABB              // Позиция и размеры пули
+	VelocityX float64 // Скорость пули по горизонтали (положительная = вправо, отрицательная = влево)
+	VelocityY float64 // Скорость пули по вертикали (после рикошета от пола или потолка)
+	Bounces   int     // Сколько раз пуля еще может отскочить от платформы, прежде чем исчезнуть
+	Pierce    int     // Сквозь сколько NPC пуля пролетает, прежде чем застрять в следующем
 }
 
 // NewBullet создает новую пулю
 func NewBullet(x, y, velocityX, width, height float64) *Bullet {
 	return &Bullet{
-		X:         x,
-		Y:         y,
+		ID:        NextID(),
+		AABB:      AABB{X: x, Y: y, Width: width, Height: height},
 		VelocityX: velocityX,
-		Width:     width,
-		Height:    height,
 	}
 }
 
-// Update обновляет позицию пули
-func (b *Bullet) Update() {
+// Update продвигает пулю на шаг. Быстрая пуля может проскочить тонкую платформу
+// за один шаг, поэтому сначала проверяется весь ее путь: пуля с запасом отскоков
+// долетает до поверхности и отражается от нее, остальные исчезают при первом касании.
+// Пуля исчезает и за границами уровня, а также застряв в платформе.
+func (b *Bullet) Update(ctx Context) bool {
+	if time, normalX, normalY, hit := ctx.SweepPlatforms(b, b.VelocityX, b.VelocityY); hit {
+		b.X += b.VelocityX * time
+		b.Y += b.VelocityY * time
+		return b.Ricochet(normalX, normalY)
+	}
+
 	b.X += b.VelocityX
+	b.Y += b.VelocityY
+
+	width, height := ctx.LevelSize()
+	if b.X <= -config.BulletWidth || b.X >= width+config.BulletWidth ||
+		b.Y <= -config.BulletHeight || b.Y >= height+config.BulletHeight {
+		return false
+	}
+	for _, platform := range ctx.PlatformsNear(b.X, b.Y, b.Width, b.Height) {
+		if b.Bounds().Intersects(platform.Bounds()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Draw рисует пулю
+func (b *Bullet) Draw(screen Canvas, view transform.View) {
+	screen.DrawBullet(b, view)
+}
+
+// Ricochet отражает пулю от поверхности с нормалью (normalX, normalY)
+// и расходует один отскок. Возвращает false, если отскоков не осталось и пуля должна исчезнуть.
+func (b *Bullet) Ricochet(normalX, normalY float64) bool {
+	if b.Bounces <= 0 {
+		return false
+	}
+	b.Bounces--
+	if normalX != 0 {
+		b.VelocityX = -b.VelocityX
+	}
+	if normalY != 0 {
+		b.VelocityY = -b.VelocityY
+	}
+	return true
 }
@@ -0,0 +1,52 @@
+package entities
+
+// AABB — прямоугольник, выровненный по осям (Axis-Aligned Bounding Box).
+// Встраивается во все сущности как их хитбокс, поэтому поля X, Y, Width, Height
+// доступны напрямую (player.X), а физика работает с любыми сущностями одинаково.
+type AABB struct {
+	X, Y          float64 // Левый верхний угол в мировых координатах
+	Width, Height float64 // Размеры
+}
+
+// Collider — любая сущность с хитбоксом
+type Collider interface {
+	Bounds() AABB
+}
+
+// Bounds возвращает хитбокс; благодаря этому методу каждая сущность со встроенным AABB — Collider
+func (b AABB) Bounds() AABB {
+	return b
+}
+
+// Left возвращает координату левой стороны
+func (b AABB) Left() float64 { return b.X }
+
+// Right возвращает координату правой стороны
+func (b AABB) Right() float64 { return b.X + b.Width }
+
+// Top возвращает координату верхней стороны
+func (b AABB) Top() float64 { return b.Y }
+
+// Bottom возвращает координату нижней стороны
+func (b AABB) Bottom() float64 { return b.Y + b.Height }
+
+// Center возвращает центр прямоугольника
+func (b AABB) Center() (float64, float64) {
+	return b.X + b.Width/2, b.Y + b.Height/2
+}
+
+// Intersects проверяет, пересекаются ли прямоугольники.
+// Касание сторонами пересечением не считается.
+func (b AABB) Intersects(other AABB) bool {
+	return b.X < other.X+other.Width &&
+		b.X+b.Width > other.X &&
+		b.Y < other.Y+other.Height &&
+		b.Y+b.Height > other.Y
+}
+
+// Moved возвращает прямоугольник, сдвинутый на (dx, dy)
+func (b AABB) Moved(dx, dy float64) AABB {
+	b.X += dx
+	b.Y += dy
+	return b
+}
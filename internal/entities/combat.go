@@ -0,0 +1,73 @@
+package entities
+
+import "platformer/internal/config"
+
+// Hit описывает удар по сущности: урон, отбрасывание и оглушение
+type Hit struct {
+	Damage     int     // Урон
+	DirectionX float64 // Направление отбрасывания по горизонтали (-1 влево, 1 вправо)
+	Force      float64 // Горизонтальная скорость отбрасывания
+	Lift       float64 // Вертикальная скорость отбрасывания (отрицательная - вверх)
+	Stun       int     // Сколько кадров цель оглушена и не управляется
+}
+
+// NewHit создает удар со стандартным отбрасыванием от источника в точке sourceX
+// по цели, центр которой находится в targetX
+func NewHit(damage int, sourceX, targetX float64) Hit {
+	return Hit{
+		Damage:     damage,
+		DirectionX: KnockbackDirection(sourceX, targetX),
+		Force:      config.KnockbackForce,
+		Lift:       config.KnockbackLift,
+		Stun:       config.HitstunFrames,
+	}
+}
+
+// KnockbackDirection возвращает направление от источника к цели (-1 или 1)
+func KnockbackDirection(sourceX, targetX float64) float64 {
+	if targetX < sourceX {
+		return -1
+	}
+	return 1
+}
+
+// TakeHit наносит персонажу удар. Во время кадров неуязвимости удар игнорируется.
+// Возвращает true, если удар прошел.
+func (p *Player) TakeHit(hit Hit) bool {
+	if p.IsInvulnerable() {
+		return false
+	}
+
+	p.Health -= hit.Damage
+	if p.Health < 0 {
+		p.Health = 0
+	}
+
+	p.VelocityX = hit.DirectionX * hit.Force
+	p.VelocityY = hit.Lift
+	p.OnGround = false
+	p.Jumping = false
+	p.DashFrames = 0
+	p.StunFrames = hit.Stun
+	return true
+}
+
+// IsStunned сообщает, оглушен ли персонаж (ввод в это время игнорируется)
+func (p *Player) IsStunned() bool {
+	return p.StunFrames > 0
+}
+
+// TakeHit наносит NPC удар с уроном и отбрасыванием
+func (n *NPC) TakeHit(hit Hit) {
+	n.TakeDamage(hit.Damage)
+
+	n.VelocityX = hit.DirectionX * hit.Force
+	n.VelocityY = hit.Lift
+	n.OnGround = false
+	n.StunFrames = hit.Stun
+}
+
+// IsStunned сообщает, оглушен ли NPC
+func (n *NPC) IsStunned() bool {
+	return n.StunFrames > 0
+}
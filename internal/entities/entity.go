@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"image/color"
+
+	"platformer/internal/transform"
+)
+
+// Entity — общий интерфейс всех сущностей: стабильный идентификатор, тип, хитбокс,
+// шаг симуляции и отрисовка. По ID на сущность ссылаются сетевые сообщения
+// и сохранения, а через Update и Draw игра и мир (world.Registry) обновляют
+// и рисуют сущности общими циклами, не разбирая их типы.
+type Entity interface {
+	Serializable
+	Collider
+
+	// Update продвигает сущность на один шаг симуляции; false - сущность исчезла
+	Update(ctx Context) bool
+	// Draw рисует сущность на кадре screen; view переводит мировые координаты в экранные
+	Draw(screen Canvas, view transform.View)
+}
+
+// Проверяем, что все типы сущностей реализуют общий интерфейс
+var (
+	_ Entity = (*Player)(nil)
+	_ Entity = (*NPC)(nil)
+	_ Entity = (*Bullet)(nil)
+	_ Entity = (*Platform)(nil)
+)
+
+// Context — уровень, в котором сущности делают шаг. Его дает игра: сущность
+// сама двигается, а о платформах рядом и размерах уровня спрашивает контекст.
+type Context interface {
+	// PlatformsNear возвращает платформы рядом с прямоугольником.
+	// Результат действителен до следующего запроса.
+	PlatformsNear(x, y, width, height float64) []*Platform
+
+	// SweepPlatforms ищет первое касание платформы хитбоксом body, который
+	// за шаг сдвигается на (dx, dy): долю пути до касания и нормаль поверхности
+	SweepPlatforms(body Collider, dx, dy float64) (time, normalX, normalY float64, hit bool)
+
+	// LevelSize возвращает ширину и высоту уровня
+	LevelSize() (width, height float64)
+
+	// Friction возвращает трение для объекта, стоящего на поверхности из материала ground
+	Friction(ground string) float64
+
+	// MovePlayer применяет к персонажу гравитацию, скорость и столкновения
+	// с платформами. Они зависят от правил уровня (сила гравитации, зоны гибели,
+	// контрольные точки), поэтому физику персонажа выполняет игра.
+	MovePlayer(player *Player)
+}
+
+// Canvas — методы кадра, которыми сущности рисуют себя. Кадр игры
+// (canvas.Canvas) включает их, поэтому пакет entities не зависит от отрисовки.
+type Canvas interface {
+	DrawPlatform(platform *Platform, view transform.View)
+	// tint - оттенок персонажа (nil - персонаж рисуется без оттенка)
+	DrawPlayer(player *Player, view transform.View, tint color.Color)
+	DrawBullet(bullet *Bullet, view transform.View)
+	DrawNPC(npc *NPC, view transform.View)
+
+	// Эффекты объектов мира (world.Object); alpha - непрозрачность от 0 до 1
+	DrawTracer(view transform.View, x1, y1, x2, y2, alpha float64)
+	DrawFloatingText(view transform.View, text string, x, y, alpha float64)
+	DrawHitMarker(view transform.View, x, y, alpha float64)
+}
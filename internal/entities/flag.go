@@ -0,0 +1,24 @@
+package entities
+
+// Flag представляет флаг команды в режиме захвата флага
+type Flag struct {
+	AABB     // Позиция и размеры флага
+	Team int // Команда, которой принадлежит флаг
+
+	HomeX, HomeY float64 // Место флага на базе команды
+}
+
+// NewFlag создает флаг команды team, стоящий на базе в точке (homeX, homeY)
+func NewFlag(team int, homeX, homeY, width, height float64) *Flag {
+	return &Flag{
+		AABB:  AABB{X: homeX, Y: homeY, Width: width, Height: height},
+		Team:  team,
+		HomeX: homeX,
+		HomeY: homeY,
+	}
+}
+
+// ReturnHome возвращает флаг на базу
+func (f *Flag) ReturnHome() {
+	f.X, f.Y = f.HomeX, f.HomeY
+}
@@ -0,0 +1,32 @@
+package entities
+
+import "sync/atomic"
+
+// ID — стабильный идентификатор сущности.
+// Идентификатор назначается при создании и сохраняется при сериализации,
+// поэтому по нему можно ссылаться на сущность в сохранениях и сетевых сообщениях.
+type ID uint64
+
+// lastID хранит последний выданный идентификатор
+var lastID atomic.Uint64
+
+// NextID выдает новый уникальный идентификатор сущности
+func NextID() ID {
+	return ID(lastID.Add(1))
+}
+
+// ReserveID гарантирует, что NextID не выдаст идентификатор, меньший или равный id.
+// Используется при восстановлении сущностей из сохранений и снимков.
+func ReserveID(id ID) {
+	for {
+		current := lastID.Load()
+		if uint64(id) <= current || lastID.CompareAndSwap(current, uint64(id)) {
+			return
+		}
+	}
+}
+
+// ResetIDs сбрасывает счетчик идентификаторов (например, при загрузке нового уровня)
+func ResetIDs() {
+	lastID.Store(0)
+}
@@ -1,12 +1,33 @@
 package entities
 
+import (
+	"platformer/internal/config"
+	"platformer/internal/transform"
+)
+
+// NPCMaxHealth — здоровье NPC по умолчанию
+const NPCMaxHealth = 100
+
 // NPC представляет неигрового персонажа
 type NPC struct {
-	// Позиция NPC на экране
-	X, Y float64
+	// Стабильный идентификатор (хранится отдельно от данных при сериализации)
+	ID ID `json:"-"`
+
+	// Позиция и размеры NPC
+	AABB
+
+	// Скорость NPC (для физики)
+	VelocityX, VelocityY float64
 
-	// Размеры NPC
-	Width, Height float64
+	// Находится ли NPC на платформе и из какого материала была последняя платформа
+	OnGround bool
+	Ground   string
+
+	// Здоровье NPC
+	Health, MaxHealth int
+
+	// Сколько кадров NPC еще оглушен после удара
+	StunFrames int
 
 	// Направление взгляда NPC
 	// true = смотрит вправо, false = смотрит влево
@@ -16,10 +37,93 @@ type NPC struct {
 // NewNPC создает нового NPC с заданными параметрами
 func NewNPC(x, y, width, height float64) *NPC {
 	return &NPC{
-		X:           x,
-		Y:           y,
-		Width:       width,
-		Height:      height,
+		ID:          NextID(),
+		AABB:        AABB{X: x, Y: y, Width: width, Height: height},
 		FacingRight: true, // По умолчанию смотрит вправо
+		Health:      NPCMaxHealth,
+		MaxHealth:   NPCMaxHealth,
+	}
+}
+
+// TakeDamage наносит NPC урон. Здоровье не опускается ниже нуля.
+func (n *NPC) TakeDamage(amount int) {
+	n.Health -= amount
+	if n.Health < 0 {
+		n.Health = 0
+	}
+}
+
+// IsDead сообщает, погиб ли NPC
+func (n *NPC) IsDead() bool {
+	return n.Health <= 0
+}
+
+// Update применяет к NPC оглушение, гравитацию, движение и столкновения
+// с платформами; поведение задания (например, сопровождения) задает игра.
+// Погибший NPC не двигается, и Update возвращает false.
+func (n *NPC) Update(ctx Context) bool {
+	if n.IsDead() {
+		return false
 	}
+
+	// Отсчитываем оглушение; пока NPC оглушен, на земле его тормозит трение
+	if n.StunFrames > 0 {
+		n.StunFrames--
+		if n.OnGround {
+			n.VelocityX *= ctx.Friction(n.Ground)
+		}
+		if n.StunFrames == 0 {
+			// Оглушение прошло - NPC останавливается, дальше им управляет его логика
+			n.VelocityX = 0
+		}
+	}
+
+	// Гравитация действует так же, как на персонажа
+	if !n.OnGround {
+		n.VelocityY += config.Gravity
+		if n.VelocityY > config.MaxFallSpeed {
+			n.VelocityY = config.MaxFallSpeed
+		}
+	}
+
+	n.X += n.VelocityX
+	n.Y += n.VelocityY
+
+	// NPC не может выйти за границы мира по горизонтали
+	width, _ := ctx.LevelSize()
+	if n.X < 0 {
+		n.X = 0
+	} else if n.X+n.Width > width {
+		n.X = width - n.Width
+	}
+
+	n.OnGround = false
+	for _, platform := range ctx.PlatformsNear(n.X, n.Y, n.Width, n.Height) {
+		if !n.Bounds().Intersects(platform.Bounds()) {
+			continue
+		}
+
+		// NPC приземляется на платформу, если падал на нее сверху
+		if n.VelocityY >= 0 && n.Y+n.Height-n.VelocityY <= platform.Y {
+			n.Y = platform.Y - n.Height
+			n.VelocityY = 0
+			n.OnGround = true
+			n.Ground = platform.Material
+			continue
+		}
+
+		// Иначе упираемся в платформу сбоку
+		if n.X+n.Width/2 < platform.X+platform.Width/2 {
+			n.X = platform.X - n.Width
+		} else {
+			n.X = platform.X + platform.Width
+		}
+		n.VelocityX = 0
+	}
+	return true
+}
+
+// Draw рисует NPC
+func (n *NPC) Draw(screen Canvas, view transform.View) {
+	screen.DrawNPC(n, view)
 }
@@ -1,17 +1,48 @@
 package entities
 
+import "platformer/internal/transform"
+
 // Platform представляет платформу в игре
 type Platform struct {
-	X, Y          float64 // Позиция платформы
-	Width, Height float64 // Размеры платформы
+	ID   ID `json:"-"` // Стабильный идентификатор
+	AABB    // Позиция и размеры платформы
+
+	Material string // Материал поверхности из данных уровня (пустой - обычная поверхность)
+
+	// Смещение движущейся платформы за последний кадр
+	VelocityX, VelocityY float64
+
+	// Скорость ленты конвейера: стоящие на платформе объекты сдвигаются на нее каждый кадр
+	Conveyor float64
+
+	// Через сколько кадров рушащаяся платформа исчезнет (0 - платформа не рушится)
+	CrumbleFrames int
+}
+
+// Crumbling сообщает, рушится ли платформа
+func (p *Platform) Crumbling() bool {
+	return p.CrumbleFrames > 0
+}
+
+// Update отсчитывает обрушение платформы; false - платформа рухнула.
+// Движущиеся платформы двигают движения уровня, а не сама платформа.
+func (p *Platform) Update(ctx Context) bool {
+	if !p.Crumbling() {
+		return true
+	}
+	p.CrumbleFrames--
+	return p.Crumbling()
+}
+
+// Draw рисует платформу
+func (p *Platform) Draw(screen Canvas, view transform.View) {
+	screen.DrawPlatform(p, view)
 }
 
 // NewPlatform создает новую платформу
 func NewPlatform(x, y, width, height float64) *Platform {
 	return &Platform{
-		X:      x,
-		Y:      y,
-		Width:  width,
-		Height: height,
+		ID:   NextID(),
+		AABB: AABB{X: x, Y: y, Width: width, Height: height},
 	}
 }
@@ -1,26 +1,170 @@
 package entities
 
+import (
+	"image/color"
+
+	"platformer/internal/config"
+	"platformer/internal/transform"
+)
+
 // Player представляет игрового персонажа
 type Player struct {
-	// Позиция персонажа на экране
-	X, Y float64
+	// Стабильный идентификатор (хранится отдельно от данных при сериализации)
+	ID ID `json:"-"`
+
+	// Позиция и размеры хитбокса персонажа (размеры меняются при приседании)
+	AABB
+
+	// Присел ли персонаж
+	Crouching bool
 
 	// Скорость персонажа (для физики)
 	VelocityX, VelocityY float64
 
 	// Состояние персонажа
-	OnGround bool // Находится ли персонаж на платформе
+	OnGround bool      // Находится ли персонаж на платформе
+	Ground   string    // Материал платформы, на которой персонаж стоял последней
+	Support  *Platform `json:"-"` // Платформа, на которой персонаж стоит сейчас (nil - в воздухе)
+
+	// Персонаж находится в прыжке, который еще можно укоротить, отпустив клавишу
+	Jumping bool
+
+	// Прыжки в воздухе: сколько осталось и сколько восстанавливается при приземлении
+	AirJumps, MaxAirJumps int
+
+	// Сколько кадров еще разрешен прыжок после схода с платформы ("время койота")
+	CoyoteFrames int
+
+	// Бежит ли персонаж (удерживает клавишу бега во время движения)
+	Sprinting bool
+
+	// Сколько кадров еще длится рывок
+	DashFrames int
+
+	// Здоровье персонажа
+	Health, MaxHealth int
+
+	// Сколько кадров персонаж еще оглушен после удара
+	StunFrames int
+
+	// Сколько кадров персонаж еще неуязвим (кадры неуязвимости)
+	InvulnerableFrames int
 
 	// Направление взгляда персонажа (для стрельбы)
 	// true = смотрит вправо, false = смотрит влево
 	FacingRight bool
+
+	// Гравитация перевернута: персонаж падает вверх и стоит на нижних сторонах платформ
+	GravityFlipped bool
+
+	// Оттенок персонажа: цвет игрока в сетевой игре или второго игрока за этим
+	// компьютером (nil - без оттенка). Задается игрой перед отрисовкой.
+	Tint color.Color `json:"-"`
 }
 
 // NewPlayer создает нового персонажа с начальными параметрами
 func NewPlayer(x, y float64) *Player {
 	return &Player{
-		X:           x,
-		Y:           y,
+		ID:          NextID(),
+		AABB:        AABB{X: x, Y: y, Width: config.PlayerWidth, Height: config.PlayerHeight},
+		Health:      config.PlayerMaxHealth,
+		MaxHealth:   config.PlayerMaxHealth,
 		FacingRight: true, // По умолчанию персонаж смотрит вправо
 	}
 }
+
+// CanJump сообщает, может ли персонаж прыгнуть с земли:
+// он стоит на платформе или только что сошел с ее края
+func (p *Player) CanJump() bool {
+	return p.OnGround || p.CoyoteFrames > 0
+}
+
+// CanAirJump сообщает, остались ли у персонажа прыжки в воздухе
+func (p *Player) CanAirJump() bool {
+	return !p.OnGround && p.AirJumps > 0
+}
+
+// GrantAirJumps задает, сколько прыжков в воздухе доступно персонажу
+func (p *Player) GrantAirJumps(count int) {
+	p.MaxAirJumps = count
+	p.AirJumps = count
+}
+
+// Land восстанавливает прыжки в воздухе при приземлении
+func (p *Player) Land() {
+	p.AirJumps = p.MaxAirJumps
+}
+
+// IsDashing сообщает, выполняет ли персонаж рывок
+func (p *Player) IsDashing() bool {
+	return p.DashFrames > 0
+}
+
+// IsInvulnerable сообщает, действуют ли на персонажа кадры неуязвимости
+func (p *Player) IsInvulnerable() bool {
+	return p.InvulnerableFrames > 0
+}
+
+// GravityDirection возвращает направление гравитации по Y: 1 - вниз, -1 - вверх
+func (p *Player) GravityDirection() float64 {
+	if p.GravityFlipped {
+		return -1
+	}
+	return 1
+}
+
+// FlipGravity переворачивает гравитацию. Персонаж отрывается от опоры и начинает падать в другую сторону.
+func (p *Player) FlipGravity() {
+	p.Stand()
+	p.GravityFlipped = !p.GravityFlipped
+	p.OnGround = false
+	p.CoyoteFrames = 0
+	p.Jumping = false
+}
+
+// Crouch приседает: хитбокс становится ниже, а ноги остаются на месте
+func (p *Player) Crouch() {
+	if p.Crouching {
+		return
+	}
+	// При перевернутой гравитации ноги сверху, поэтому верх хитбокса не двигается
+	if !p.GravityFlipped {
+		p.Y += p.Height - config.PlayerCrouchHeight
+	}
+	p.Height = config.PlayerCrouchHeight
+	p.Crouching = true
+}
+
+// Stand выпрямляет персонажа после приседания
+func (p *Player) Stand() {
+	if !p.Crouching {
+		return
+	}
+	if !p.GravityFlipped {
+		p.Y -= config.PlayerHeight - p.Height
+	}
+	p.Height = config.PlayerHeight
+	p.Crouching = false
+}
+
+// SetCrouching приседает или встает в зависимости от crouching
+func (p *Player) SetCrouching(crouching bool) {
+	if crouching {
+		p.Crouch()
+	} else {
+		p.Stand()
+	}
+}
+
+// Update выполняет физику шага персонажа. Управление уже применено к его скорости,
+// а гравитацию, движение и столкновения выполняет контекст (Context.MovePlayer).
+// Персонаж не исчезает с уровня, поэтому Update всегда возвращает true.
+func (p *Player) Update(ctx Context) bool {
+	ctx.MovePlayer(p)
+	return true
+}
+
+// Draw рисует персонажа с его оттенком
+func (p *Player) Draw(screen Canvas, view transform.View) {
+	screen.DrawPlayer(p, view, p.Tint)
+}
@@ -0,0 +1,145 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Kind определяет тип сущности в сериализованном виде
+type Kind string
+
+const (
+	KindPlayer   Kind = "player"
+	KindNPC      Kind = "npc"
+	KindBullet   Kind = "bullet"
+	KindPlatform Kind = "platform"
+)
+
+// Serializable — сущность, которую можно сохранить и восстановить.
+// Все типы сущностей реализуют этот интерфейс, поэтому сохранения
+// записывают любые сущности в одной кодировке (Record). Сетевые снимки идут
+// каждый шаг и передаются компактным двоичным форматом с дельта-сжатием
+// (network/wire.go, network/delta.go), а не этими записями.
+type Serializable interface {
+	EntityID() ID
+	EntityKind() Kind
+}
+
+// Record — сериализованное представление одной сущности:
+// стабильный идентификатор, тип и данные этого типа
+type Record struct {
+	ID      ID              `json:"id"`
+	Kind    Kind            `json:"kind"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// factories создает пустые сущности по их типу при десериализации
+var factories = map[Kind]func() Serializable{
+	KindPlayer:   func() Serializable { return &Player{} },
+	KindNPC:      func() Serializable { return &NPC{} },
+	KindBullet:   func() Serializable { return &Bullet{} },
+	KindPlatform: func() Serializable { return &Platform{} },
+}
+
+// RegisterKind регистрирует новый тип сущности для десериализации.
+// factory должна возвращать указатель на пустую сущность.
+func RegisterKind(kind Kind, factory func() Serializable) {
+	if _, exists := factories[kind]; exists {
+		panic(fmt.Sprintf("entities: kind %q already registered", kind))
+	}
+	factories[kind] = factory
+}
+
+// Encode сериализует сущность в Record
+func Encode(entity Serializable) (Record, error) {
+	payload, err := json.Marshal(entity)
+	if err != nil {
+		return Record{}, fmt.Errorf("encode %s %d: %w", entity.EntityKind(), entity.EntityID(), err)
+	}
+
+	return Record{
+		ID:      entity.EntityID(),
+		Kind:    entity.EntityKind(),
+		Payload: payload,
+	}, nil
+}
+
+// Decode восстанавливает сущность из Record.
+// Идентификатор резервируется, чтобы новые сущности не получили такой же ID.
+func Decode(record Record) (Serializable, error) {
+	factory, ok := factories[record.Kind]
+	if !ok {
+		return nil, fmt.Errorf("decode entity %d: unknown kind %q", record.ID, record.Kind)
+	}
+
+	entity := factory()
+	if err := json.Unmarshal(record.Payload, entity); err != nil {
+		return nil, fmt.Errorf("decode %s %d: %w", record.Kind, record.ID, err)
+	}
+
+	// ID хранится в Record, а не в данных, поэтому восстанавливаем его отдельно
+	if withID, ok := entity.(interface{ setID(ID) }); ok {
+		withID.setID(record.ID)
+	}
+	ReserveID(record.ID)
+
+	return entity, nil
+}
+
+// EncodeAll сериализует список сущностей
+func EncodeAll(list []Serializable) ([]Record, error) {
+	records := make([]Record, 0, len(list))
+	for _, entity := range list {
+		record, err := Encode(entity)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// DecodeAll восстанавливает список сущностей
+func DecodeAll(records []Record) ([]Serializable, error) {
+	list := make([]Serializable, 0, len(records))
+	for _, record := range records {
+		entity, err := Decode(record)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, entity)
+	}
+	return list, nil
+}
+
+// EntityID возвращает идентификатор персонажа
+func (p *Player) EntityID() ID { return p.ID }
+
+// EntityKind возвращает тип сущности
+func (p *Player) EntityKind() Kind { return KindPlayer }
+
+func (p *Player) setID(id ID) { p.ID = id }
+
+// EntityID возвращает идентификатор NPC
+func (n *NPC) EntityID() ID { return n.ID }
+
+// EntityKind возвращает тип сущности
+func (n *NPC) EntityKind() Kind { return KindNPC }
+
+func (n *NPC) setID(id ID) { n.ID = id }
+
+// EntityID возвращает идентификатор пули
+func (b *Bullet) EntityID() ID { return b.ID }
+
+// EntityKind возвращает тип сущности
+func (b *Bullet) EntityKind() Kind { return KindBullet }
+
+func (b *Bullet) setID(id ID) { b.ID = id }
+
+// EntityID возвращает идентификатор платформы
+func (p *Platform) EntityID() ID { return p.ID }
+
+// EntityKind возвращает тип сущности
+func (p *Platform) EntityKind() Kind { return KindPlatform }
+
+func (p *Platform) setID(id ID) { p.ID = id }
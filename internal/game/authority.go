@@ -0,0 +1,234 @@
+package game
+
+import (
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/network"
+)
+
+// Сетевая игра идет по схеме авторитетного хоста: клиент каждый шаг отправляет
+// только свой ввод, хост применяет его к персонажу клиента вместе со своим,
+// определяет все попадания и рассылает снимок мира, который клиент показывает
+// (свое движение клиент предсказывает, см. prediction.go).
+
+// stepOpponent на хосте продвигает персонажа клиента на один шаг по его вводу
+func (g *Game) stepOpponent() {
+	if g.opponent == nil || !g.net.Connected() {
+		return
+	}
+
+	p := g.opponent
+	p.input = g.nextOpponentInput(p.input)
+	if g.isDead(p) {
+		// Погибший персонаж клиента ждет появления
+		p.input = NoInput
+	}
+	g.withPilot(p, func() {
+		p.tickCooldowns()
+		g.handleInput()
+		g.handleWeaponSwitch()
+		g.carryPlayer()
+		g.player.Update(entityContext{g})
+		g.updateBullets()
+	})
+}
+
+// nextOpponentInput возвращает ввод клиента для очередного шага. Если новых вводов
+// нет (потерялись или задержались), персонаж клиента продолжает делать то же, что и раньше.
+func (g *Game) nextOpponentInput(last Input) Input {
+	g.opponentInputs = append(g.opponentInputs, g.net.PollInputs()...)
+	if len(g.opponentInputs) == 0 {
+		return last
+	}
+	// Накопившиеся старые вводы отбрасываем, чтобы управление клиента не отставало
+	if extra := len(g.opponentInputs) - config.OpponentInputBuffer; extra > 0 {
+		g.opponentInputs = g.opponentInputs[:copy(g.opponentInputs, g.opponentInputs[extra:])]
+	}
+
+	next := g.opponentInputs[0]
+	g.opponentInputs = g.opponentInputs[:copy(g.opponentInputs, g.opponentInputs[1:])]
+	g.opponentInputSeq = next.Seq
+	g.opponentViewTick = next.ViewTick
+	return inputFromMessage(next)
+}
+
+// inputMessage переводит ввод шага в сообщение для хоста
+func inputMessage(input Input) network.InputMessage {
+	return network.InputMessage{
+		Left:         input.Left,
+		Right:        input.Right,
+		Jump:         input.Jump,
+		Crouch:       input.Crouch,
+		Sprint:       input.Sprint,
+		Dash:         input.Dash,
+		Shoot:        input.Shoot,
+		Interact:     input.Interact,
+		SwitchWeapon: input.SwitchWeapon,
+	}
+}
+
+// inputFromMessage восстанавливает ввод клиента из сообщения.
+// Эмоции и голосование идут отдельными событиями, поэтому в сообщение не входят.
+func inputFromMessage(msg network.InputMessage) Input {
+	input := NoInput
+	input.Left = msg.Left
+	input.Right = msg.Right
+	input.Jump = msg.Jump
+	input.Crouch = msg.Crouch
+	input.Sprint = msg.Sprint
+	input.Dash = msg.Dash
+	input.Shoot = msg.Shoot
+	input.Interact = msg.Interact
+	input.SwitchWeapon = msg.SwitchWeapon
+	return input
+}
+
+// buildSnapshot собирает на хосте снимок мира для клиента
+func (g *Game) buildSnapshot() network.SnapshotMessage {
+	snapshot := network.SnapshotMessage{
+		Tick:          g.simTick,
+		Host:          playerState(g.local.player, g.local.weapon),
+		Client:        playerState(g.opponent.player, g.opponent.weapon),
+		HostBullets:   bulletStates(g.local.bullets),
+		ClientBullets: bulletStates(g.opponent.bullets),
+		NPCs:          make([]network.NPCState, 0, len(g.npcs)),
+		HostScore:     g.match.localScore,
+		ClientScore:   g.match.remoteScore,
+		InputSeq:      g.opponentInputSeq,
+	}
+	for _, npc := range g.npcs {
+		if npc.IsDead() {
+			continue
+		}
+		snapshot.NPCs = append(snapshot.NPCs, network.NPCState{
+			ID:          uint64(npc.ID),
+			X:           npc.X,
+			Y:           npc.Y,
+			Width:       npc.Width,
+			Height:      npc.Height,
+			VelocityX:   npc.VelocityX,
+			VelocityY:   npc.VelocityY,
+			FacingRight: npc.FacingRight,
+			Health:      npc.Health,
+		})
+	}
+	return snapshot
+}
+
+// applySnapshot показывает у клиента мир из снимка хоста: персонажа клиента
+// (с повтором неподтвержденных вводов), пули, NPC и счет матча.
+// Персонажа хоста показывает updateRemote.
+func (g *Game) applySnapshot(snapshot network.SnapshotMessage) {
+	if g.isPlayback() {
+		// В записи персонажа клиента двигал и подтверждал хост: повторять нечего
+		snapshot.InputSeq = g.prediction.seq
+	}
+	g.reconcile(snapshot.Client, snapshot.InputSeq)
+	g.weapon = weapon(snapshot.Client.Weapon)
+
+	g.bullets = bulletsFromStates(g.bullets[:0], snapshot.ClientBullets)
+	g.enemyFire = bulletsFromStates(g.enemyFire[:0], snapshot.HostBullets)
+
+	// NPC сохраняются между снимками по ID, чтобы эффекты оставались привязаны к ним
+	known := make(map[entities.ID]*entities.NPC, len(g.npcs))
+	for _, npc := range g.npcs {
+		known[npc.ID] = npc
+	}
+	npcs := make([]*entities.NPC, 0, len(snapshot.NPCs))
+	for _, state := range snapshot.NPCs {
+		id := entities.ID(state.ID)
+		npc, ok := known[id]
+		if !ok {
+			npc = entities.NewNPC(state.X, state.Y, state.Width, state.Height)
+			npc.ID = id
+		}
+		npc.X, npc.Y = state.X, state.Y
+		npc.VelocityX, npc.VelocityY = state.VelocityX, state.VelocityY
+		npc.FacingRight = state.FacingRight
+		npc.Health = state.Health
+		npcs = append(npcs, npc)
+	}
+	g.npcs = npcs
+
+	g.match.localScore = snapshot.ClientScore
+	g.match.remoteScore = snapshot.HostScore
+}
+
+// stepClient выполняет шаг клиента: мир моделирует хост, а клиент отправляет ввод,
+// предсказывает свое движение, показывает последний снимок и обновляет только
+// свои эффекты и камеру
+func (g *Game) stepClient() error {
+	g.timers.Update()
+	g.updateEmotes()
+	g.updateStreaming()
+	// Движущиеся платформы клиент двигает сам, а хост время от времени выравнивает их (worldsync.go)
+	g.updatePlatforms()
+	g.updateKillZones()
+	g.predictInput()
+
+	if err := g.updateNetwork(); err != nil {
+		return err
+	}
+	g.updateRespawns()
+
+	g.world.Update(entityContext{g})
+	g.camera.Update(g.player.X, g.player.Y)
+	g.updateSplitScreen()
+	g.weather.Update()
+	return nil
+}
+
+// playerState переводит персонажа в состояние для снимка
+func playerState(player *entities.Player, w weapon) network.PlayerState {
+	return network.PlayerState{
+		X:           player.X,
+		Y:           player.Y,
+		VelocityX:   player.VelocityX,
+		VelocityY:   player.VelocityY,
+		OnGround:    player.OnGround,
+		FacingRight: player.FacingRight,
+		Crouching:   player.Crouching,
+		Health:      player.Health,
+		Weapon:      int(w),
+	}
+}
+
+// applyPlayerState переносит состояние из снимка на персонажа
+func applyPlayerState(player *entities.Player, state network.PlayerState) {
+	player.SetCrouching(state.Crouching)
+	player.X = state.X
+	player.Y = state.Y
+	player.VelocityX = state.VelocityX
+	player.VelocityY = state.VelocityY
+	player.OnGround = state.OnGround
+	player.FacingRight = state.FacingRight
+	player.Health = state.Health
+}
+
+// bulletStates переводит пули в состояния для снимка
+func bulletStates(bullets []*entities.Bullet) []network.BulletState {
+	states := make([]network.BulletState, 0, len(bullets))
+	for _, bullet := range bullets {
+		states = append(states, network.BulletState{
+			ID:        uint64(bullet.ID),
+			X:         bullet.X,
+			Y:         bullet.Y,
+			VelocityX: bullet.VelocityX,
+			VelocityY: bullet.VelocityY,
+			Pierce:    bullet.Pierce,
+		})
+	}
+	return states
+}
+
+// bulletsFromStates добавляет в dst пули из снимка
+func bulletsFromStates(dst []*entities.Bullet, states []network.BulletState) []*entities.Bullet {
+	for _, state := range states {
+		bullet := entities.NewBullet(state.X, state.Y, state.VelocityX, config.BulletWidth, config.BulletHeight)
+		bullet.ID = entities.ID(state.ID)
+		bullet.VelocityY = state.VelocityY
+		bullet.Pierce = state.Pierce
+		dst = append(dst, bullet)
+	}
+	return dst
+}
@@ -0,0 +1,152 @@
+package game
+
+import (
+	"math"
+
+	"platformer/internal/config"
+	"platformer/internal/entities"
+)
+
+// Бот управляет персонажем через тот же InputSource, что клавиатура и геймпад:
+// каждый шаг он смотрит на мир и "зажимает" клавиши, а шаг симуляции не отличает
+// его от человека. Бот идет к ближайшему чужому персонажу, перепрыгивает стены
+// и ямы на пути и стреляет, когда соперник стоит перед ним на линии огня.
+// Решения зависят только от состояния игры, без случайных чисел, поэтому шаг
+// остается детерминированным (determinism.go).
+//
+// Бот может быть вторым игроком за этим компьютером (-player2 bot) для тренировки
+// или управлять персонажем этого игрока (-bot): так клиент без окна занимает
+// свободное место в матче на выделенном сервере.
+
+// botInput — источник ввода бота, управляющего персонажем пилота self
+type botInput struct {
+	game      *Game
+	self      *pilot
+	shootHeld bool // Стрельба была зажата на прошлом шаге: выстрел дает только новое нажатие
+}
+
+// newBotInput создает бота, управляющего персонажем пилота self
+func newBotInput(g *Game, self *pilot) *botInput {
+	return &botInput{game: g, self: self}
+}
+
+// isBot сообщает, управляет ли пилотом p бот
+func isBot(p *pilot) bool {
+	_, ok := p.source.(*botInput)
+	return ok
+}
+
+// Input выбирает клавиши бота для очередного шага
+func (b *botInput) Input() Input {
+	input := NoInput
+	player := b.self.player
+	target := b.target()
+	if player == nil || target == nil {
+		b.shootHeld = false
+		return input
+	}
+
+	x, y := player.Center()
+	targetX, targetY := target.Center()
+	dx := targetX - x
+	// Высота цели над ботом с учетом направления гравитации
+	rise := (y - targetY) * player.GravityDirection()
+
+	// Идем к цели, пока она дальше BotKeepDistance, иначе только разворачиваемся к ней
+	direction := 1.0
+	if dx < 0 {
+		direction = -1
+	}
+	// Цель почти вплотную по горизонтали: разворот к ней только раскачивал бы бота
+	close := math.Abs(dx) < player.Width/2
+	facingTarget := close || player.FacingRight == (direction > 0)
+	if math.Abs(dx) > config.BotKeepDistance || !facingTarget {
+		input.Left = direction < 0
+		input.Right = direction > 0
+	}
+
+	// Прыгаем через стену или яму впереди и на платформу, где стоит цель
+	moving := input.Left || input.Right
+	switch {
+	case moving && b.blocked(direction):
+		input.Jump = true
+	case moving && player.OnGround && b.gapAhead(direction):
+		input.Jump = true
+	case rise > config.BotJumpRise && math.Abs(dx) < config.BotChaseJumpRange:
+		input.Jump = true
+	}
+
+	// Стреляем, когда цель перед ботом почти на той же высоте (вплотную пуля
+	// вылетает из-за цели и не попадет). Выстрел дает новое нажатие,
+	// поэтому клавиша отпускается через шаг.
+	inLine := math.Abs(targetY-y) < (player.Height+target.Height)/2
+	if facingTarget && !close && inLine && math.Abs(dx) < config.BotShootRange && b.friendlyFire() {
+		input.Shoot = !b.shootHeld
+	}
+	b.shootHeld = input.Shoot
+	return input
+}
+
+// target возвращает ближайшего к боту чужого персонажа (nil - целей нет)
+func (b *botInput) target() *entities.Player {
+	g := b.game
+	if g.options.Mode == ModeClient {
+		// Клиент моделирует только своего персонажа, а персонажа хоста видит в снимках
+		return g.remote
+	}
+	player := b.self.player
+	var nearest *entities.Player
+	best := math.Inf(1)
+	for _, p := range g.pilots() {
+		if p == b.self || p.player == nil || g.isDead(p) {
+			continue
+		}
+		distance := math.Hypot(p.player.X-player.X, p.player.Y-player.Y)
+		if distance < best {
+			nearest, best = p.player, distance
+		}
+	}
+	return nearest
+}
+
+// friendlyFire сообщает, может ли бот ранить цель: союзника он только сопровождает
+func (b *botInput) friendlyFire() bool {
+	return !b.game.playersAllied()
+}
+
+// blocked сообщает, стоит ли вплотную перед ботом стена в направлении direction
+func (b *botInput) blocked(direction float64) bool {
+	player := b.self.player
+	x := player.Right()
+	if direction < 0 {
+		x = player.Left() - config.BotProbeDistance
+	}
+	// Полоска на высоте тела без ног и макушки, чтобы не задеть пол и потолок
+	margin := player.Height / 4
+	return b.solidAt(x, player.Y+margin, config.BotProbeDistance, player.Height-2*margin)
+}
+
+// gapAhead сообщает, нет ли опоры под краем персонажа со стороны direction
+func (b *botInput) gapAhead(direction float64) bool {
+	player := b.self.player
+	x := player.Right()
+	if direction < 0 {
+		x = player.Left() - config.BotProbeDistance
+	}
+	y := player.Bottom()
+	if player.GravityFlipped {
+		y = player.Y - config.BotGapDepth
+	}
+	return !b.solidAt(x, y, config.BotProbeDistance, config.BotGapDepth)
+}
+
+// solidAt сообщает, задевает ли прямоугольник хотя бы одну платформу сетки
+func (b *botInput) solidAt(x, y, width, height float64) bool {
+	probe := entities.AABB{X: x, Y: y, Width: width, Height: height}
+	for _, platform := range b.game.platformsNear(x, y, width, height) {
+		if probe.Intersects(platform.Bounds()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,6 @@
+//go:build debug
+
+package game
+
+// debugBuild — отладочная сборка (go build -tags debug): отладочная информация видна с запуска
+const debugBuild = true
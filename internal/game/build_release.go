@@ -0,0 +1,6 @@
+//go:build !debug
+
+package game
+
+// debugBuild — обычная (release) сборка: отладочная информация скрыта, пока ее не откроют по F3
+const debugBuild = false
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"math/rand"
+
+	"platformer/internal/config"
+	"platformer/internal/level"
+	"platformer/internal/transform"
+)
+
+// Camera представляет камеру, которая следует за игроком
+type Camera struct {
+	X, Y float64 // Позиция камеры в игровом мире
+	Zoom float64 // Масштаб (0 - без увеличения)
+
+	// Границы, за которые камера не выходит (мир, границы камеры уровня или текущая комната)
+	Bounds level.Rect
+
+	// Размер области экрана, в которую выводит камера (0 - весь экран, см. splitscreen.go)
+	ScreenWidth, ScreenHeight float64
+
+	shakeStrength float64 // Амплитуда тряски в пикселях экрана
+	shakeFrames   int     // Сколько кадров тряски осталось
+	shakeTotal    int     // Длительность текущей тряски
+	shakeX        float64 // Текущее смещение тряски
+	shakeY        float64
+
+	rng *rand.Rand // Случайные числа тряски: генератор симуляции (determinism.go)
+}
+
+// View возвращает вид камеры для перевода координат мира в координаты экрана
+func (c *Camera) View() transform.View {
+	return transform.View{
+		X:      c.X,
+		Y:      c.Y,
+		Zoom:   c.Zoom,
+		ShakeX: c.shakeX,
+		ShakeY: c.shakeY,
+
+		ScreenWidth:  c.ScreenWidth,
+		ScreenHeight: c.ScreenHeight,
+	}
+}
+
+// Target вычисляет позицию камеры, при которой игрок находится в центре экрана,
+// с учетом границ камеры
+func (c *Camera) Target(playerX, playerY float64) (float64, float64) {
+	viewWidth, viewHeight := c.View().WorldSize()
+
+	// Центрируем камеру на игроке
+	// Камера должна показывать игрока в центре экрана (или немного смещена вперед)
+	targetX := playerX - viewWidth/2 + config.PlayerWidth/2
+	targetY := playerY - viewHeight/2 + config.PlayerHeight/2
+
+	// Камера не выходит за границы ни по горизонтали, ни по вертикали:
+	// на высоком уровне она прокручивается вслед за игроком, а на уровне
+	// высотой в экран стоит на месте
+	targetX = clampView(targetX, c.Bounds.X, c.Bounds.Width, viewWidth)
+	targetY = clampView(targetY, c.Bounds.Y, c.Bounds.Height, viewHeight)
+
+	return targetX, targetY
+}
+
+// clampView ограничивает начало видимой области размера view отрезком
+// границ [start, start+size]; область меньше экрана центрируется
+func clampView(target, start, size, view float64) float64 {
+	if size < view {
+		return start + (size-view)/2
+	}
+	return clamp(target, start, start+size-view)
+}
+
+// Update обновляет позицию камеры, чтобы она следовала за игроком
+func (c *Camera) Update(playerX, playerY float64) {
+	targetX, targetY := c.Target(playerX, playerY)
+
+	// Плавно перемещаем камеру к целевой позиции
+	// Это создает более плавное движение камеры
+	c.X += (targetX - c.X) * 0.1
+	c.Y = targetY
+
+	c.updateShake()
+}
+
+// Snap мгновенно перемещает камеру к игроку (например, после появления на уровне)
+func (c *Camera) Snap(playerX, playerY float64) {
+	c.X, c.Y = c.Target(playerX, playerY)
+}
+
+// Shake запускает тряску камеры с амплитудой strength пикселей на frames кадров.
+// Более слабая тряска не прерывает уже идущую более сильную.
+func (c *Camera) Shake(strength float64, frames int) {
+	if c.shakeFrames > 0 && c.shakeStrength > strength {
+		return
+	}
+	c.shakeStrength = strength
+	c.shakeFrames = frames
+	c.shakeTotal = frames
+}
+
+// updateShake обновляет смещение тряски; амплитуда затухает к концу тряски
+func (c *Camera) updateShake() {
+	if c.shakeFrames <= 0 {
+		c.shakeX, c.shakeY = 0, 0
+		return
+	}
+
+	amplitude := c.shakeStrength * float64(c.shakeFrames) / float64(c.shakeTotal)
+	c.shakeX = (c.rng.Float64()*2 - 1) * amplitude
+	c.shakeY = (c.rng.Float64()*2 - 1) * amplitude
+	c.shakeFrames--
+}
@@ -0,0 +1,310 @@
+package game
+
+import (
+	"math"
+	"sort"
+
+	"platformer/internal/config"
+	"platformer/internal/entities"
+)
+
+// Большой уровень без комнат делится на квадратные части (chunks) стороной
+// config.ChunkSize. Загружены только части вокруг камеры и персонажей:
+// платформы остальных частей убраны из сетки столкновений (движущиеся
+// продолжают путь, но сетку не трогают), а NPC лежат в своей части
+// и не обновляются.
+// Поэтому число платформ в сетке и сущностей в покадровых списках
+// ограничено числом загруженных частей, а не размером уровня.
+//
+// Платформы остаются в g.platforms (на их индексы опираются worldsync.go
+// и сохранения), а в сетку возвращаются с прежним порядком, поэтому
+// столкновения разрешаются так же, как без подгрузки. Загруженные части
+// перебираются в отсортированном порядке, чтобы шаг оставался
+// детерминированным (determinism.go). Клиент подгружает только платформы:
+// NPC ему присылает хост.
+
+// chunkKey — координаты части мира
+type chunkKey struct {
+	X, Y int
+}
+
+// less упорядочивает части по строкам
+func (k chunkKey) less(other chunkKey) bool {
+	if k.Y != other.Y {
+		return k.Y < other.Y
+	}
+	return k.X < other.X
+}
+
+// chunk — часть мира: ее платформы и выгруженные NPC
+type chunk struct {
+	platforms []*entities.Platform // Платформы, которые задевают часть (в порядке данных уровня)
+	parked    []*entities.NPC      // NPC, ждущие загрузки части
+}
+
+// worldStream подгружает части мира вокруг камеры и персонажей
+type worldStream struct {
+	enabled bool
+	chunks  map[chunkKey]*chunk
+	order   map[*entities.Platform]int // Порядок платформы в сетке: ее индекс в данных уровня
+	refs    map[*entities.Platform]int // В скольких загруженных частях лежит платформа
+	loaded  []chunkKey                 // Загруженные части, отсортированные по строкам
+	wanted  []chunkKey                 // Части, нужные на этом шаге (буфер)
+}
+
+// chunkOf возвращает часть, в которой лежит точка (x, y)
+func chunkOf(x, y float64) chunkKey {
+	return chunkKey{
+		X: int(math.Floor(x / config.ChunkSize)),
+		Y: int(math.Floor(y / config.ChunkSize)),
+	}
+}
+
+// streamed сообщает, нужна ли уровню подгрузка частями: уровни с комнатами
+// и так держат загруженной только текущую комнату (rooms.go)
+func (g *Game) streamed() bool {
+	if len(g.level.Rooms) > 0 {
+		return false
+	}
+	columns := math.Ceil(g.level.Width / config.ChunkSize)
+	rows := math.Ceil(g.level.Height / config.ChunkSize)
+	return columns*rows >= config.StreamMinChunks
+}
+
+// resetStreaming раскладывает платформы и NPC нового уровня по частям
+// и загружает части вокруг камеры и персонажей
+func (g *Game) resetStreaming() {
+	s := &g.stream
+	*s = worldStream{enabled: g.streamed()}
+	if !s.enabled {
+		return
+	}
+
+	s.chunks = make(map[chunkKey]*chunk)
+	s.order = make(map[*entities.Platform]int, len(g.platforms))
+	s.refs = make(map[*entities.Platform]int)
+	for i, platform := range g.platforms {
+		s.order[platform] = i
+		g.grid.Remove(platform)
+
+		// Движущаяся платформа лежит во всех частях, через которые проходит
+		bounds := platform.Bounds()
+		for _, mover := range g.movers {
+			if mover.platform == platform {
+				bounds = moverSweep(mover)
+				break
+			}
+		}
+		forEachChunk(bounds, func(key chunkKey) {
+			s.chunk(key).platforms = append(s.chunk(key).platforms, platform)
+		})
+	}
+
+	// Все NPC сначала выгружаются, а нужные вернутся вместе со своими частями
+	if g.options.Mode != ModeClient {
+		g.parkNPCs(func(chunkKey) bool { return true })
+	}
+	g.updateStreaming()
+}
+
+// chunk возвращает часть по координатам, создавая ее при необходимости
+func (s *worldStream) chunk(key chunkKey) *chunk {
+	c, ok := s.chunks[key]
+	if !ok {
+		c = &chunk{}
+		s.chunks[key] = c
+	}
+	return c
+}
+
+// isLoaded сообщает, загружена ли часть
+func (s *worldStream) isLoaded(key chunkKey) bool {
+	return containsKey(s.loaded, key)
+}
+
+// updateStreaming загружает части вокруг камеры и персонажей и выгружает остальные
+func (g *Game) updateStreaming() {
+	s := &g.stream
+	if !s.enabled {
+		return
+	}
+
+	s.wanted = s.wanted[:0]
+	want := func(x, y float64) {
+		center := chunkOf(x, y)
+		for dy := -config.ChunkLoadRadius; dy <= config.ChunkLoadRadius; dy++ {
+			for dx := -config.ChunkLoadRadius; dx <= config.ChunkLoadRadius; dx++ {
+				s.wanted = append(s.wanted, chunkKey{X: center.X + dx, Y: center.Y + dy})
+			}
+		}
+	}
+	want(g.camera.X+config.ScreenWidth/2, g.camera.Y+config.ScreenHeight/2)
+	for _, p := range g.pilots() {
+		if p.player != nil {
+			want(p.player.X+p.player.Width/2, p.player.Y+p.player.Height/2)
+		}
+	}
+	sort.Slice(s.wanted, func(i, j int) bool { return s.wanted[i].less(s.wanted[j]) })
+	s.wanted = compactKeys(s.wanted)
+
+	// Выгружаем ненужные части, затем загружаем новые
+	unloaded := false
+	for _, key := range s.loaded {
+		if !containsKey(s.wanted, key) {
+			g.unloadChunk(key)
+			unloaded = true
+		}
+	}
+	for _, key := range s.wanted {
+		if !s.isLoaded(key) {
+			g.loadChunk(key)
+		}
+	}
+	s.loaded, s.wanted = s.wanted, s.loaded
+
+	if unloaded && g.options.Mode != ModeClient {
+		g.parkNPCs(func(key chunkKey) bool { return !s.isLoaded(key) })
+	}
+	g.cullBullets()
+}
+
+// loadChunk возвращает платформы части в сетку, а ее NPC - в игру
+func (g *Game) loadChunk(key chunkKey) {
+	s := &g.stream
+	c, ok := s.chunks[key]
+	if !ok {
+		return
+	}
+	for _, platform := range c.platforms {
+		order, ok := s.order[platform]
+		if !ok {
+			continue // Платформа уже рухнула (escape.go)
+		}
+		s.refs[platform]++
+		if s.refs[platform] == 1 {
+			g.grid.InsertAt(platform, order)
+		}
+	}
+	g.npcs = append(g.npcs, c.parked...)
+	c.parked = nil
+}
+
+// unloadChunk убирает из сетки платформы части, которых нет в других загруженных частях
+func (g *Game) unloadChunk(key chunkKey) {
+	s := &g.stream
+	c, ok := s.chunks[key]
+	if !ok {
+		return
+	}
+	for _, platform := range c.platforms {
+		if s.refs[platform] == 0 {
+			continue
+		}
+		s.refs[platform]--
+		if s.refs[platform] == 0 {
+			delete(s.refs, platform)
+			g.grid.Remove(platform)
+		}
+	}
+}
+
+// parkNPCs переносит в части NPC, стоящих в частях, для которых unload вернул true.
+// Сопровождаемый NPC не выгружается: он следует за игроком и нужен заданию.
+func (g *Game) parkNPCs(unload func(chunkKey) bool) {
+	s := &g.stream
+	active := g.npcs[:0]
+	for _, npc := range g.npcs {
+		key := chunkOf(npc.X+npc.Width/2, npc.Y+npc.Height/2)
+		if (g.escort != nil && npc == g.escort.NPC) || !unload(key) {
+			active = append(active, npc)
+			continue
+		}
+		if !npc.IsDead() {
+			s.chunk(key).parked = append(s.chunk(key).parked, npc)
+		}
+	}
+	g.npcs = active
+}
+
+// cullBullets убирает пули, залетевшие в выгруженные части: платформ
+// там нет в сетке, и пули пролетали бы сквозь стены
+func (g *Game) cullBullets() {
+	for _, p := range g.pilots() {
+		active := p.bullets[:0]
+		for _, bullet := range p.bullets {
+			if g.stream.isLoaded(chunkOf(bullet.X+bullet.Width/2, bullet.Y+bullet.Height/2)) {
+				active = append(active, bullet)
+			}
+		}
+		p.bullets = active
+	}
+}
+
+// forgetPlatform убирает рухнувшую платформу из частей: загрузка ее не вернет
+func (s *worldStream) forgetPlatform(platform *entities.Platform) {
+	if !s.enabled {
+		return
+	}
+	delete(s.order, platform)
+	delete(s.refs, platform)
+}
+
+// allNPCs возвращает NPC игры вместе с выгруженными (для сохранений)
+func (g *Game) allNPCs() []*entities.NPC {
+	s := &g.stream
+	if !s.enabled {
+		return g.npcs
+	}
+	keys := make([]chunkKey, 0, len(s.chunks))
+	for key, c := range s.chunks {
+		if len(c.parked) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+
+	npcs := append([]*entities.NPC(nil), g.npcs...)
+	for _, key := range keys {
+		npcs = append(npcs, s.chunks[key].parked...)
+	}
+	return npcs
+}
+
+// moverSweep возвращает область, которую движущаяся платформа проходит целиком
+func moverSweep(mover *platformMover) entities.AABB {
+	platform := mover.platform
+	return entities.AABB{
+		X:      math.Min(mover.originX, mover.originX+mover.motion.DX),
+		Y:      math.Min(mover.originY, mover.originY+mover.motion.DY),
+		Width:  platform.Width + math.Abs(mover.motion.DX),
+		Height: platform.Height + math.Abs(mover.motion.DY),
+	}
+}
+
+// forEachChunk вызывает fn для каждой части, которую задевает прямоугольник
+func forEachChunk(bounds entities.AABB, fn func(chunkKey)) {
+	min := chunkOf(bounds.X, bounds.Y)
+	max := chunkOf(bounds.X+bounds.Width, bounds.Y+bounds.Height)
+	for y := min.Y; y <= max.Y; y++ {
+		for x := min.X; x <= max.X; x++ {
+			fn(chunkKey{X: x, Y: y})
+		}
+	}
+}
+
+// compactKeys убирает повторы из отсортированного списка частей
+func compactKeys(keys []chunkKey) []chunkKey {
+	out := keys[:0]
+	for i, key := range keys {
+		if i == 0 || key != keys[i-1] {
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+// containsKey ищет часть в отсортированном списке
+func containsKey(keys []chunkKey, key chunkKey) bool {
+	i := sort.Search(len(keys), func(i int) bool { return !keys[i].less(key) })
+	return i < len(keys) && keys[i] == key
+}
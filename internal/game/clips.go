@@ -0,0 +1,81 @@
+package game
+
+import (
+	"log"
+	"path/filepath"
+	"time"
+
+	"platformer/internal/canvas"
+	"platformer/internal/clip"
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+)
+
+// clipRecorder записывает уменьшенные кадры игрового процесса для клипов
+type clipRecorder struct {
+	frames  *clip.Recorder
+	counter int  // Кадров отрисовки с последней записи в буфер
+	saving  bool // Клип кодируется в фоне
+}
+
+// newClipRecorder создает запись последних seconds секунд (nil при seconds <= 0 - запись выключена)
+func newClipRecorder(seconds int) *clipRecorder {
+	if seconds <= 0 {
+		return nil
+	}
+	width := int(config.ScreenWidth * config.ClipScale)
+	height := int(config.ScreenHeight * config.ClipScale)
+	return &clipRecorder{frames: clip.NewRecorder(seconds*config.ClipFPS, width, height)}
+}
+
+// captureClipFrame добавляет кадр в буфер клипа с частотой config.ClipFPS
+func (g *Game) captureClipFrame(screen canvas.Canvas) {
+	c := g.clips
+	if c == nil {
+		return
+	}
+	c.counter++
+	if c.counter < ticksPerSecond/config.ClipFPS {
+		return
+	}
+	c.counter = 0
+
+	// Кадр буфера меньше экрана, поэтому кадр уменьшается при чтении
+	screen.ReadPixels(c.frames.Next())
+}
+
+// handleClip по F10 сохраняет последние секунды игры в GIF-файл в каталоге снимков.
+// Кодирование идет в фоне; следующий клип можно сохранить, когда закончится предыдущий.
+func (g *Game) handleClip() {
+	c := g.clips
+	if c == nil || c.saving || !g.window.JustPressed(KeyF10) {
+		return
+	}
+
+	frames := c.frames.Take()
+	if len(frames) == 0 {
+		return
+	}
+	path := filepath.Join(config.ScreenshotDir, time.Now().Format("2006-01-02_15-04-05")+".gif")
+	g.showMessage(i18n.T("clip.saving", path))
+
+	c.saving = true
+	delay := 100 / config.ClipFPS
+	go func() {
+		if err := clip.WriteGIF(path, frames, delay); err != nil {
+			log.Printf("%v", err)
+		} else {
+			log.Printf("clip saved: %s", path)
+		}
+		g.clipDone <- struct{}{}
+	}()
+}
+
+// pollClipDone отмечает окончание фонового сохранения клипа
+func (g *Game) pollClipDone() {
+	select {
+	case <-g.clipDone:
+		g.clips.saving = false
+	default:
+	}
+}
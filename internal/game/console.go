@@ -0,0 +1,168 @@
+package game
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/console"
+	"platformer/internal/entities"
+	"platformer/internal/i18n"
+)
+
+// registerConsoleCommands регистрирует отладочные команды консоли
+func (g *Game) registerConsoleCommands() {
+	g.console.Register(console.Command{Name: "spawn", Usage: "npc [x y]", Help: "console.spawn.help", Run: g.cmdSpawn})
+	g.console.Register(console.Command{Name: "give", Usage: "weapon <name>", Help: "console.give.help", Run: g.cmdGive})
+	g.console.Register(console.Command{Name: "teleport", Usage: "<x> <y>", Help: "console.teleport.help", Run: g.cmdTeleport})
+	g.console.Register(console.Command{Name: "gravity", Usage: "<scale>", Help: "console.gravity.help", Run: g.cmdGravity})
+	g.console.Register(console.Command{Name: "load", Usage: "<level>", Help: "console.load.help", Run: g.cmdLoad})
+	g.console.Register(console.Command{Name: "netstats", Help: "console.netstats.help", Run: g.cmdNetStats})
+	g.registerKickCommands()
+	g.registerServerCommands()
+}
+
+// updateConsole открывает консоль клавишей ` (тильда) и передает ей набранный текст.
+// Консоль доступна только во время игры.
+func (g *Game) updateConsole() {
+	if g.window.JustPressed(KeyBackquote) {
+		g.console.Toggle()
+		return
+	}
+	if !g.console.Open() {
+		return
+	}
+
+	// Символы клавиши консоли (в русской раскладке - ё) в строку не попадают
+	chars := g.window.AppendChars(nil)
+	typed := chars[:0]
+	for _, r := range chars {
+		if !strings.ContainsRune("`~ёЁ", r) {
+			typed = append(typed, r)
+		}
+	}
+	g.console.Type(typed)
+
+	if g.window.JustPressed(KeyBackspace) {
+		g.console.Backspace()
+	}
+	if g.window.JustPressed(KeyArrowUp) {
+		g.console.Browse(-1)
+	}
+	if g.window.JustPressed(KeyArrowDown) {
+		g.console.Browse(1)
+	}
+	if g.window.JustPressed(KeyEnter) {
+		g.console.Submit()
+	}
+	if g.window.JustPressed(KeyEscape) {
+		g.console.Toggle()
+	}
+}
+
+// drawConsole рисует открытую консоль поверх всего интерфейса
+func (g *Game) drawConsole(screen canvas.Canvas) {
+	if !g.console.Open() {
+		return
+	}
+	screen.DrawConsole(g.console.Lines(), g.console.Input())
+}
+
+// errUsage сообщает о неверных аргументах команды name
+func errUsage(name, usage string) error {
+	return errors.New(i18n.T("console.usage", name+" "+usage))
+}
+
+// parseCoords разбирает пару координат x y
+func parseCoords(args []string) (float64, float64, bool) {
+	if len(args) != 2 {
+		return 0, 0, false
+	}
+	x, errX := strconv.ParseFloat(args[0], 64)
+	y, errY := strconv.ParseFloat(args[1], 64)
+	return x, y, errX == nil && errY == nil
+}
+
+// cmdSpawn создает NPC рядом с персонажем или в указанной точке
+func (g *Game) cmdSpawn(args []string) (string, error) {
+	if len(args) == 0 || args[0] != "npc" {
+		return "", errUsage("spawn", "npc [x y]")
+	}
+	player := g.player
+	x, y := player.X+player.Width+config.ConsoleSpawnOffset, player.Y
+	if len(args) > 1 {
+		var ok bool
+		if x, y, ok = parseCoords(args[1:]); !ok {
+			return "", errUsage("spawn", "npc [x y]")
+		}
+	}
+	npc := entities.NewNPC(x, y, config.ConsoleNPCSize, config.ConsoleNPCSize)
+	g.npcs = append(g.npcs, npc)
+	return i18n.T("console.spawned", x, y), nil
+}
+
+// cmdGive выдает персонажу оружие по имени (blaster, rifle)
+func (g *Game) cmdGive(args []string) (string, error) {
+	names := make([]string, 0, len(weaponNames))
+	for w, key := range weaponNames {
+		name := strings.TrimPrefix(key, "weapon.")
+		if len(args) == 2 && args[0] == "weapon" && args[1] == name {
+			g.weapon = w
+			return i18n.T("console.given", i18n.T(key)), nil
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "", errUsage("give", "weapon <"+strings.Join(names, "|")+">")
+}
+
+// cmdTeleport переносит персонажа в точку x y и останавливает его
+func (g *Game) cmdTeleport(args []string) (string, error) {
+	x, y, ok := parseCoords(args)
+	if !ok {
+		return "", errUsage("teleport", "<x> <y>")
+	}
+	g.player.X, g.player.Y = x, y
+	g.player.VelocityX, g.player.VelocityY = 0, 0
+	return i18n.T("console.teleported", x, y), nil
+}
+
+// cmdGravity задает множитель гравитации персонажа (1 - обычная гравитация)
+func (g *Game) cmdGravity(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errUsage("gravity", "<scale>")
+	}
+	scale, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || scale < 0 {
+		return "", errUsage("gravity", "<scale>")
+	}
+	g.gravityScale = scale
+	return i18n.T("console.gravity", scale), nil
+}
+
+// cmdLoad загружает уровень из файла или встроенный уровень и начинает его заново.
+// В сетевой игре уровень у игроков должен совпадать, поэтому команда недоступна.
+func (g *Game) cmdLoad(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errUsage("load", "<level>")
+	}
+	if g.net != nil {
+		return "", errors.New(i18n.T("console.local_only"))
+	}
+	loaded, err := loadLevel(args[0])
+	if err != nil {
+		return "", err
+	}
+	g.level = loaded
+	g.options.LevelPath = args[0]
+	g.resetLevel()
+	return i18n.T("console.loaded", loaded.Name), nil
+}
+
+// cmdNetStats выводит состояние сетевого подключения
+func (g *Game) cmdNetStats(args []string) (string, error) {
+	return strings.Join(g.netStatusLines(), "\n"), nil
+}
@@ -0,0 +1,225 @@
+package game
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/i18n"
+	"platformer/internal/level"
+	"platformer/internal/network"
+	"platformer/internal/physics"
+)
+
+// В кооперативной игре (хост с -match coop) игроки вместе проходят уровни кампании
+// по порядку. NPC, как и в матче, моделирует хост и рассылает в снимках, но ранят
+// их пули обоих игроков, а друг друга игроки не ранят. Уровень пройден, только
+// когда у выхода стоят оба персонажа: тогда хост переводит обоих на следующий
+// уровень, а после последнего предлагает пройти кампанию заново.
+
+// coopLevels — уровни кампании по порядку ("" - встроенный уровень)
+var coopLevels = []string{"", "rooms", "gravity", "escape"}
+
+// События кооперативной игры
+const (
+	eventCoopExit  network.EventType = "coop_exit"  // Хост сообщает, кто из игроков стоит у выхода
+	eventCoopStage network.EventType = "coop_stage" // Хост переводит обоих игроков на уровень кампании
+)
+
+// coopExitPayload — кто из игроков стоит у выхода
+type coopExitPayload struct {
+	Host     bool `json:"host"`
+	Client   bool `json:"client"`
+	Complete bool `json:"complete"` // У выхода оба, уровень пройден
+}
+
+// coopStagePayload — уровень кампании, на котором играют оба игрока
+type coopStagePayload struct {
+	Stage  int    `json:"stage"`  // Номер уровня в кампании (с 0)
+	Stages int    `json:"stages"` // Сколько уровней в кампании
+	Level  string `json:"level"`  // Уровень ("" - встроенный)
+}
+
+// coopCampaign — состояние кооперативной кампании
+type coopCampaign struct {
+	active        bool     // Идет кооперативная игра (клиент узнает об этом от хоста)
+	levels        []string // Уровни кампании (известны только хосту)
+	stage         int      // Номер текущего уровня в кампании
+	stages        int      // Сколько уровней в кампании
+	hostAtExit    bool     // Персонаж хоста стоит у выхода
+	clientAtExit  bool     // Персонаж клиента стоит у выхода
+	advanceFrames int      // Сколько кадров осталось до перехода на следующий уровень (хост)
+}
+
+// newCoopCampaign создает кампанию хоста, которая начинается с уровня path.
+// Встроенный уровень кампании продолжается следующими по порядку, а свой
+// уровень хоста (например, из файла) - кампания из одного уровня.
+func newCoopCampaign(path string) coopCampaign {
+	name := strings.TrimSuffix(filepath.Base(path), ".json")
+	for i, stage := range coopLevels {
+		if path == stage || (path != "" && name == stage) {
+			return coopCampaign{active: true, levels: coopLevels, stage: i, stages: len(coopLevels)}
+		}
+	}
+	return coopCampaign{active: true, levels: []string{path}, stages: 1}
+}
+
+// isCoop сообщает, идет ли кооперативная сетевая игра
+func (g *Game) isCoop() bool {
+	return g.isVersus() && g.coop.active
+}
+
+// updateCoop на хосте следит, кто из игроков стоит у выхода, и после прохождения
+// уровня переводит обоих на следующий
+func (g *Game) updateCoop() {
+	if !g.isCoop() || !g.isHost() {
+		return
+	}
+	c := &g.coop
+
+	if g.levelComplete {
+		c.advanceFrames--
+		if c.advanceFrames <= 0 {
+			g.advanceCoop()
+		}
+		return
+	}
+
+	hostAtExit := !g.isDead(g.local) && g.atExit(g.local.player)
+	clientAtExit := g.opponent != nil && g.net.Connected() && !g.isDead(g.opponent) && g.atExit(g.opponent.player)
+	if hostAtExit == c.hostAtExit && clientAtExit == c.clientAtExit {
+		return
+	}
+	c.hostAtExit, c.clientAtExit = hostAtExit, clientAtExit
+	if hostAtExit && clientAtExit {
+		g.levelComplete = true
+		c.advanceFrames = config.CoopAdvanceFrames
+	}
+	if g.net.Connected() {
+		g.sendMatchEvent(eventCoopExit, coopExitPayload{Host: hostAtExit, Client: clientAtExit, Complete: g.levelComplete})
+	}
+}
+
+// advanceCoop переводит обоих игроков на следующий уровень кампании,
+// а после последнего уровня открывает голосование за повтор кампании
+func (g *Game) advanceCoop() {
+	next := g.coop.stage + 1
+	if next >= len(g.coop.levels) {
+		g.endMatch()
+		g.sendMatchEvent(eventMatchOver, matchPayload{Match: g.match.number})
+		return
+	}
+	g.enterCoopStage(next)
+}
+
+// enterCoopStage на хосте начинает уровень кампании stage у обоих игроков
+func (g *Game) enterCoopStage(stage int) {
+	g.coop.stage = stage
+	payload := g.coopStage()
+	g.sendMatchEvent(eventCoopStage, payload)
+	g.loadCoopStage(payload)
+}
+
+// sendCoopStage сообщает клиенту, на каком уровне кампании идет игра
+func (g *Game) sendCoopStage() {
+	if g.net.Connected() {
+		g.sendMatchEvent(eventCoopStage, g.coopStage())
+	}
+}
+
+// coopStage возвращает текущий уровень кампании хоста
+func (g *Game) coopStage() coopStagePayload {
+	c := g.coop
+	return coopStagePayload{Stage: c.stage, Stages: len(c.levels), Level: c.levels[c.stage]}
+}
+
+// loadCoopStage загружает уровень кампании и начинает его заново
+func (g *Game) loadCoopStage(payload coopStagePayload) {
+	lvl := level.Default()
+	if payload.Level != "" {
+		loaded, err := loadLevel(payload.Level)
+		if err != nil {
+			// Без уровня хоста клиент остается на текущем, а хост не переходит на следующий
+			log.Printf("coop: load %s: %v", payload.Level, err)
+			loaded = g.level
+		}
+		lvl = loaded
+	}
+
+	g.coop.active = true
+	g.coop.stage, g.coop.stages = payload.Stage, payload.Stages
+	g.coop.hostAtExit, g.coop.clientAtExit = false, false
+	g.coop.advanceFrames = 0
+	g.level = lvl
+	g.options.LevelPath = payload.Level
+	g.resetLevel()
+}
+
+// handleCoopEvent обрабатывает у клиента события кооперативной игры от хоста
+func (g *Game) handleCoopEvent(event network.Event) {
+	if g.isHost() {
+		return
+	}
+	switch event.Type {
+	case eventCoopExit:
+		var payload coopExitPayload
+		if err := event.Decode(&payload); err != nil {
+			return
+		}
+		g.coop.hostAtExit, g.coop.clientAtExit = payload.Host, payload.Client
+		g.levelComplete = payload.Complete
+
+	case eventCoopStage:
+		var payload coopStagePayload
+		if err := event.Decode(&payload); err != nil {
+			return
+		}
+		// Хост повторяет уровень при каждом изменении в лобби: уже загруженный не перезагружаем
+		c := g.coop
+		if c.active && c.stage == payload.Stage && g.options.LevelPath == payload.Level {
+			return
+		}
+		g.loadCoopStage(payload)
+	}
+}
+
+// atExit сообщает, стоит ли персонаж у выхода с уровня. На уровне с заданием
+// сопровождения выход открывается, только когда NPC доведен до него.
+func (g *Game) atExit(player *entities.Player) bool {
+	if g.escort != nil {
+		exit := g.escort.Exit
+		return g.escort.Completed() &&
+			physics.Overlaps(player, entities.AABB{X: exit.X, Y: exit.Y, Width: exit.Width, Height: exit.Height})
+	}
+	for _, t := range g.level.Triggers {
+		bounds := entities.AABB{X: t.Bounds.X, Y: t.Bounds.Y, Width: t.Bounds.Width, Height: t.Bounds.Height}
+		if t.Kind == level.TriggerExit && physics.Overlaps(player, bounds) {
+			return true
+		}
+	}
+	return false
+}
+
+// drawCoop выводит, кто из игроков ждет у выхода и пройден ли уровень кампании
+func (g *Game) drawCoop(screen canvas.Canvas) {
+	if !g.isCoop() || g.match.over {
+		return
+	}
+
+	c := g.coop
+	localAtExit, remoteAtExit := c.hostAtExit, c.clientAtExit
+	if !g.isHost() {
+		localAtExit, remoteAtExit = remoteAtExit, localAtExit
+	}
+	switch {
+	case g.levelComplete:
+		screen.DrawBanner(i18n.T("coop.level_complete"))
+	case localAtExit:
+		screen.DrawBanner(i18n.T("coop.waiting_partner"))
+	case remoteAtExit:
+		screen.DrawBanner(i18n.T("coop.partner_waiting"))
+	}
+}
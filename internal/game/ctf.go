@@ -0,0 +1,314 @@
+package game
+
+import (
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/i18n"
+	"platformer/internal/level"
+	"platformer/internal/network"
+	"platformer/internal/physics"
+)
+
+// В захвате флага (хост с -match ctf) у каждой команды есть база из данных уровня
+// (level.Base) со своим флагом: игроки играют за разные команды, выбранные
+// в лобби (teams.go), и появляются на своих базах. Чужой флаг подбирают
+// касанием и приносят на свою базу, пока свой флаг стоит на ней: это захват,
+// за который команда получает очко. Погибший игрок роняет флаг; свой упавший
+// флаг возвращают касанием, а иначе он сам возвращается на базу через
+// config.FlagReturnFrames. Флаги моделирует хост и рассылает событием при каждом
+// изменении, несомый флаг клиент рисует у несущего персонажа. Захваты идут
+// в снимках как счет матча, а матч кончается по времени или по Options.ScoreLimit захватов.
+
+// eventCTF — хост рассылает состояние флагов
+const eventCTF network.EventType = "ctf"
+
+// flagState — где находится флаг
+type flagState int
+
+const (
+	flagAtBase  flagState = iota // Стоит на базе своей команды
+	flagCarried                  // Его несет игрок другой команды
+	flagDropped                  // Лежит там, где погиб несший его игрок
+)
+
+// flagPayload — состояние флага команды
+type flagPayload struct {
+	State  flagState `json:"state"`
+	Client bool      `json:"client"` // Флаг несет персонаж клиента (иначе - персонаж хоста)
+	X      float64   `json:"x"`      // Где лежит упавший флаг
+	Y      float64   `json:"y"`
+}
+
+// ctfPayload — состояние флагов и последнее событие с ними
+type ctfPayload struct {
+	Limit  int                          `json:"limit"` // Сколько захватов нужно для победы
+	Flags  [level.TeamCount]flagPayload `json:"flags"`
+	Notice string                       `json:"notice,omitempty"` // Ключ перевода сообщения о событии (пустой - нет)
+	Team   int                          `json:"team"`             // Команда, о которой сообщение
+}
+
+// ctfFlag — флаг команды
+type ctfFlag struct {
+	flagPayload
+	flag         *entities.Flag
+	returnFrames int // Сколько кадров осталось до возвращения упавшего флага на базу (хост)
+}
+
+// ctfMatch — состояние захвата флага
+type ctfMatch struct {
+	active     bool // Идет захват флага (клиент узнает об этом от хоста)
+	limit      int
+	bases      [level.TeamCount]level.Rect
+	flags      [level.TeamCount]ctfFlag
+	notice     string // Событие, о котором еще не сообщили клиенту (хост)
+	noticeTeam int
+	changed    bool // Флаги изменились и еще не отправлены клиенту (хост)
+}
+
+// newCTF создает захват флага хоста до limit захватов (0 - по умолчанию)
+func newCTF(limit int) ctfMatch {
+	if limit <= 0 {
+		limit = config.CTFCaptureLimit
+	}
+	return ctfMatch{active: true, limit: limit}
+}
+
+// isCTF сообщает, идет ли сетевой захват флага
+func (g *Game) isCTF() bool {
+	return g.isVersus() && g.ctf.active
+}
+
+// ctfBases возвращает базы команд уровня. Если в уровне базы нет, команда 0
+// стоит у точки появления, а команда 1 - у противоположного края уровня.
+func ctfBases(lvl *level.Level) [level.TeamCount]level.Rect {
+	const width, height = 160, 120
+	y := lvl.Spawn.Y + config.PlayerHeight - height
+	bases := [level.TeamCount]level.Rect{
+		{X: lvl.Spawn.X - width/2, Y: y, Width: width, Height: height},
+		{X: lvl.Width - lvl.Spawn.X - width/2, Y: y, Width: width, Height: height},
+	}
+	for _, base := range lvl.Bases {
+		bases[base.Team] = base.Bounds
+	}
+	return bases
+}
+
+// resetFlags ставит флаги на базы уровня, а в захвате флага переносит персонажей
+// на базы их команд. Вызывается при каждом перезапуске уровня.
+func (g *Game) resetFlags() {
+	c := &g.ctf
+	c.bases = ctfBases(g.level)
+	for team, base := range c.bases {
+		homeX := base.X + (base.Width-config.FlagWidth)/2
+		homeY := base.Y + base.Height - config.FlagHeight
+		c.flags[team] = ctfFlag{flag: entities.NewFlag(team, homeX, homeY, config.FlagWidth, config.FlagHeight)}
+	}
+	c.notice = ""
+	c.changed = true
+
+	if !g.isCTF() {
+		return
+	}
+	g.placeAtBase(g.local)
+	if g.opponent != nil {
+		g.placeAtBase(g.opponent)
+	}
+}
+
+// placeAtBase ставит персонажа p на базу его команды
+func (g *Game) placeAtBase(p *pilot) {
+	point := g.basePoint(g.pilotTeam(p))
+	p.player.X, p.player.Y = point.X, point.Y
+}
+
+// basePoint возвращает точку появления на базе команды team
+func (g *Game) basePoint(team int) level.Point {
+	base := g.ctf.bases[team]
+	return level.Point{
+		X: base.X + (base.Width-config.PlayerWidth)/2,
+		Y: base.Y + base.Height - config.PlayerHeight,
+	}
+}
+
+// updateCTF на хосте подбирает, возвращает и засчитывает флаги, которых касаются
+// персонажи, и отправляет клиенту изменившееся состояние флагов
+func (g *Game) updateCTF() {
+	if !g.isCTF() || !g.isHost() {
+		return
+	}
+	c := &g.ctf
+
+	if !g.isDead(g.local) {
+		g.touchFlags(g.local, false)
+	}
+	if g.opponent != nil && g.net.Connected() && !g.isDead(g.opponent) {
+		g.touchFlags(g.opponent, true)
+	}
+
+	for team := range c.flags {
+		f := &c.flags[team]
+		if f.State != flagDropped {
+			continue
+		}
+		f.returnFrames--
+		if f.returnFrames <= 0 {
+			g.returnFlag(team, "ctf.returned", team)
+		}
+	}
+
+	if c.changed && g.net.Connected() {
+		c.changed = false
+		g.sendCTF()
+	}
+}
+
+// touchFlags обрабатывает касание флагов и базы персонажем p клиента (client) или хоста
+func (g *Game) touchFlags(p *pilot, client bool) {
+	c := &g.ctf
+	team := g.teamOf(client)
+	enemy := 1 - team
+	own, theirs := &c.flags[team], &c.flags[enemy]
+	player := p.player
+
+	if theirs.State != flagCarried && physics.Overlaps(player, theirs.flag) {
+		theirs.State, theirs.Client = flagCarried, client
+		g.ctfNotice("ctf.taken", team)
+	}
+	if own.State == flagDropped && physics.Overlaps(player, own.flag) {
+		g.returnFlag(team, "ctf.returned", team)
+	}
+
+	// Захват засчитывается, только пока свой флаг стоит на базе
+	base := c.bases[team]
+	carrying := theirs.State == flagCarried && theirs.Client == client
+	if !carrying || own.State != flagAtBase ||
+		!physics.Overlaps(player, entities.AABB{X: base.X, Y: base.Y, Width: base.Width, Height: base.Height}) {
+		return
+	}
+	g.returnFlag(enemy, "ctf.captured", team)
+
+	score := &g.match.localScore
+	if client {
+		score = &g.match.remoteScore
+	}
+	*score++
+	if *score >= c.limit && !g.match.over {
+		g.endMatch()
+		g.sendMatchEvent(eventMatchOver, matchPayload{Match: g.match.number})
+	}
+}
+
+// returnFlag возвращает флаг команды team на базу и сообщает игрокам notice о команде noticeTeam
+func (g *Game) returnFlag(team int, notice string, noticeTeam int) {
+	f := &g.ctf.flags[team]
+	f.State, f.Client, f.returnFrames = flagAtBase, false, 0
+	f.flag.ReturnHome()
+	g.ctfNotice(notice, noticeTeam)
+}
+
+// dropFlag на хосте роняет флаг, который нес погибший персонаж текущего шага.
+// Упавший за пределы уровня флаг сразу возвращается на базу.
+func (g *Game) dropFlag() {
+	if !g.isCTF() || !g.isHost() {
+		return
+	}
+	client := g.pilot != g.local
+	player := g.player
+	for team := range g.ctf.flags {
+		f := &g.ctf.flags[team]
+		if f.State != flagCarried || f.Client != client {
+			continue
+		}
+		centerX, centerY := player.Center()
+		if !g.level.Bounds().ContainsPoint(centerX, centerY) {
+			g.returnFlag(team, "ctf.returned", team)
+			continue
+		}
+		f.State, f.returnFrames = flagDropped, config.FlagReturnFrames
+		f.flag.X = centerX - f.flag.Width/2
+		f.flag.Y = player.Y + player.Height - f.flag.Height
+		f.X, f.Y = f.flag.X, f.flag.Y
+		g.ctfNotice("ctf.dropped", g.teamOf(client))
+	}
+}
+
+// ctfNotice показывает сообщение о событии с флагом и передает его клиенту
+func (g *Game) ctfNotice(notice string, team int) {
+	g.ctf.notice, g.ctf.noticeTeam = notice, team
+	g.ctf.changed = true
+	g.showMessage(i18n.T(notice, teamName(team)))
+}
+
+// sendCTF отправляет клиенту состояние флагов
+func (g *Game) sendCTF() {
+	if !g.net.Connected() {
+		return
+	}
+	c := &g.ctf
+	payload := ctfPayload{Limit: c.limit, Notice: c.notice, Team: c.noticeTeam}
+	for team, f := range c.flags {
+		payload.Flags[team] = f.flagPayload
+	}
+	c.notice = ""
+	g.sendMatchEvent(eventCTF, payload)
+}
+
+// handleCTFEvent принимает у клиента состояние флагов от хоста
+func (g *Game) handleCTFEvent(event network.Event) {
+	if g.isHost() {
+		return
+	}
+	var payload ctfPayload
+	if err := event.Decode(&payload); err != nil {
+		return
+	}
+	c := &g.ctf
+	if !c.active {
+		c.active = true
+		g.placeAtBase(g.local)
+	}
+	c.limit = payload.Limit
+	for team := range c.flags {
+		f := &c.flags[team]
+		f.flagPayload = payload.Flags[team]
+		if f.State == flagDropped {
+			f.flag.X, f.flag.Y = f.X, f.Y
+		} else {
+			f.flag.ReturnHome()
+		}
+	}
+	if payload.Notice != "" && payload.Team >= 0 && payload.Team < level.TeamCount {
+		g.showMessage(i18n.T(payload.Notice, teamName(payload.Team)))
+	}
+}
+
+// flagCarrier возвращает персонажа, который несет флаг f
+func (g *Game) flagCarrier(f ctfFlag) *entities.Player {
+	if f.Client != g.isHost() {
+		return g.local.player
+	}
+	return g.remote
+}
+
+// drawCTF рисует базы команд и флаги; несомый флаг развевается над несущим персонажем
+func (g *Game) drawCTF(screen canvas.Canvas) {
+	if !g.isCTF() {
+		return
+	}
+	view := g.camera.View()
+	for team, base := range g.ctf.bases {
+		screen.DrawBase(base.X, base.Y, base.Width, base.Height, team, view)
+	}
+	for team, f := range g.ctf.flags {
+		x, y := f.flag.X, f.flag.Y
+		if f.State == flagCarried {
+			carrier := g.flagCarrier(f)
+			if carrier == nil {
+				continue
+			}
+			x, y = carrier.X+carrier.Width/2, carrier.Y-f.flag.Height/2
+		}
+		screen.DrawFlag(x, y, f.flag.Width, f.flag.Height, team, view)
+	}
+}
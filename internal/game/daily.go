@@ -0,0 +1,44 @@
+package game
+
+import (
+	"errors"
+	"time"
+)
+
+// Ежедневное испытание (Options.Daily): уровень и зерно случайных чисел
+// выбираются по дню (UTC), поэтому в этот день все игроки проходят одно и то же.
+// Только результаты испытания попадают в таблицу дня (leaderboard.DailyBoard).
+
+// dailyLevels — уровни, которые по очереди становятся испытанием дня (пустой - встроенный)
+var dailyLevels = []string{"", "rooms", "gravity", "escape", "tower"}
+
+// dailyChallenge — испытание одного дня
+type dailyChallenge struct {
+	Day   time.Time // Начало дня по UTC
+	Level string    // Уровень испытания
+	Seed  int64     // Зерно случайных чисел симуляции
+}
+
+// dailyChallengeFor возвращает испытание дня, в который попадает момент now
+func dailyChallengeFor(now time.Time) dailyChallenge {
+	day := now.UTC().Truncate(24 * time.Hour)
+	number := day.Unix() / int64(24*time.Hour/time.Second)
+	return dailyChallenge{
+		Day:   day,
+		Level: dailyLevels[number%int64(len(dailyLevels))],
+		Seed:  number,
+	}
+}
+
+// applyDaily подставляет в опции уровень и зерно испытания дня
+func applyDaily(opts *Options, now time.Time) (*dailyChallenge, error) {
+	if opts.Mode != ModeLocal && opts.Mode != Mode("") {
+		return nil, errors.New("daily challenge: only available in local mode")
+	}
+	if opts.LoadPath != "" {
+		return nil, errors.New("daily challenge: cannot continue from a save")
+	}
+	challenge := dailyChallengeFor(now)
+	opts.LevelPath, opts.Level, opts.Seed = challenge.Level, nil, challenge.Seed
+	return &challenge, nil
+}
@@ -0,0 +1,190 @@
+package game
+
+import (
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+	"platformer/internal/network"
+)
+
+// В дезматче (хост с -match deathmatch) игроки сражаются раундами: за убийство
+// соперника дается очко, и раунд выигрывает тот, кто первым наберет Options.ScoreLimit.
+// Убийства и гибели (в том числе от падения и опасных зон) считает хост и
+// рассылает клиенту при каждом изменении. После конца раунда игра стоит несколько
+// секунд, а затем хост начинает следующий раунд у обоих игроков.
+
+// eventDeathmatch — хост рассылает счет и состояние раунда дезматча
+const eventDeathmatch network.EventType = "deathmatch"
+
+// frags — счет игрока в дезматче
+type frags struct {
+	Kills  int `json:"kills"`  // Убийства в текущем раунде
+	Deaths int `json:"deaths"` // Гибели в текущем раунде
+	Rounds int `json:"rounds"` // Выигранные раунды
+}
+
+// deathmatchPayload — счет и состояние раунда дезматча
+type deathmatchPayload struct {
+	Round   int   `json:"round"`    // Номер раунда (с 1)
+	Limit   int   `json:"limit"`    // Сколько убийств нужно для победы в раунде
+	Host    frags `json:"host"`     // Счет игрока хоста
+	Client  frags `json:"client"`   // Счет игрока клиента
+	Over    bool  `json:"over"`     // Раунд закончен, идет пауза перед следующим
+	HostWon bool  `json:"host_won"` // Закончившийся раунд выиграл хост
+}
+
+// deathmatchState — состояние дезматча
+type deathmatchState struct {
+	deathmatchPayload
+	active        bool // Идет дезматч (клиент узнает об этом от хоста)
+	restartFrames int  // Сколько кадров осталось до следующего раунда (хост)
+	changed       bool // Счет изменился и еще не отправлен клиенту (хост)
+}
+
+// newDeathmatch создает дезматч хоста до limit убийств в раунде (0 - по умолчанию)
+func newDeathmatch(limit int) deathmatchState {
+	if limit <= 0 {
+		limit = config.DeathmatchScoreLimit
+	}
+	return deathmatchState{active: true, deathmatchPayload: deathmatchPayload{Round: 1, Limit: limit}}
+}
+
+// isDeathmatch сообщает, идет ли сетевой дезматч
+func (g *Game) isDeathmatch() bool {
+	return g.isVersus() && g.deathmatch.active
+}
+
+// updateRound ведет паузу между раундами на хосте и отправляет клиенту изменившийся счет.
+// Возвращает true, если раунд окончен и игровой процесс остановлен.
+func (g *Game) updateRound() bool {
+	dm := &g.deathmatch
+	if g.isHost() {
+		if dm.Over {
+			dm.restartFrames--
+			if dm.restartFrames <= 0 {
+				g.startRound(dm.Round + 1)
+			}
+		}
+		if dm.changed && g.net.Connected() {
+			dm.changed = false
+			g.sendDeathmatch()
+		}
+	}
+	return dm.Over
+}
+
+// startRound начинает раунд number: счет убийств и гибелей обнуляется, а уровень
+// начинается заново. Выигранные раунды сохраняются.
+func (g *Game) startRound(number int) {
+	dm := &g.deathmatch
+	dm.Round = number
+	dm.Host.Kills, dm.Host.Deaths = 0, 0
+	dm.Client.Kills, dm.Client.Deaths = 0, 0
+	dm.Over, dm.HostWon = false, false
+	dm.changed = true
+	g.resetLevel()
+}
+
+// recordKill на хосте засчитывает убийство персонажа victim его сопернику
+// и заканчивает раунд, когда соперник набрал нужное число убийств
+func (g *Game) recordKill(victim *pilot) {
+	dm := &g.deathmatch
+	if !g.isDeathmatch() || !g.isHost() || dm.Over {
+		return
+	}
+	hostWon := victim != g.local
+	killer := &dm.Client
+	if hostWon {
+		killer = &dm.Host
+	}
+	killer.Kills++
+	dm.changed = true
+
+	if killer.Kills >= dm.Limit {
+		killer.Rounds++
+		dm.Over, dm.HostWon = true, hostWon
+		dm.restartFrames = config.RoundRestartFrames
+	}
+}
+
+// recordDeath на хосте засчитывает гибель персонажа, которым управляет текущий шаг
+func (g *Game) recordDeath() {
+	dm := &g.deathmatch
+	if !g.isDeathmatch() || !g.isHost() || dm.Over {
+		return
+	}
+	if g.pilot == g.local {
+		dm.Host.Deaths++
+	} else {
+		dm.Client.Deaths++
+	}
+	dm.changed = true
+}
+
+// sendDeathmatch отправляет клиенту счет дезматча
+func (g *Game) sendDeathmatch() {
+	if g.net.Connected() {
+		g.sendMatchEvent(eventDeathmatch, g.deathmatch.deathmatchPayload)
+	}
+}
+
+// handleDeathmatchEvent принимает у клиента счет дезматча от хоста
+func (g *Game) handleDeathmatchEvent(event network.Event) {
+	if g.isHost() {
+		return
+	}
+	var payload deathmatchPayload
+	if err := event.Decode(&payload); err != nil {
+		return
+	}
+	dm := &g.deathmatch
+	if dm.active && payload.Round != dm.Round {
+		// Хост начал новый раунд
+		g.resetLevel()
+	}
+	dm.deathmatchPayload = payload
+	dm.active = true
+}
+
+// localFrags возвращает счет этого игрока и соперника
+func (g *Game) localFrags() (local, remote frags) {
+	dm := g.deathmatch
+	if g.isHost() {
+		return dm.Host, dm.Client
+	}
+	return dm.Client, dm.Host
+}
+
+// drawDeathmatch выводит счет раунда и итог закончившегося раунда
+func (g *Game) drawDeathmatch(screen canvas.Canvas) {
+	local, remote := g.localFrags()
+	screen.DrawDeathmatchInfo(g.deathmatch.Round, local.Kills, remote.Kills, g.deathmatch.Limit)
+
+	if !g.deathmatch.Over {
+		return
+	}
+	if g.deathmatch.HostWon == g.isHost() {
+		screen.DrawBanner(i18n.T("deathmatch.round_won"))
+	} else {
+		screen.DrawBanner(i18n.T("deathmatch.round_lost"))
+	}
+}
+
+// drawScoreboard выводит таблицу счета дезматча, пока зажата клавиша Tab
+func (g *Game) drawScoreboard(screen canvas.Canvas) {
+	if !g.isDeathmatch() || !g.scoreboardOpen {
+		return
+	}
+
+	local, remote := g.localFrags()
+	hello, _ := g.net.Remote()
+	rows := []canvas.ScoreboardRow{
+		{Name: g.options.PlayerName, Color: g.lobby.local.Color, Kills: local.Kills, Deaths: local.Deaths, Rounds: local.Rounds, Local: true},
+		{Name: hello.Name, Color: g.remoteColor(), Kills: remote.Kills, Deaths: remote.Deaths, Rounds: remote.Rounds},
+	}
+	// Строки идут по числу убийств
+	if remote.Kills > local.Kills {
+		rows[0], rows[1] = rows[1], rows[0]
+	}
+	screen.DrawScoreboard(i18n.T("scoreboard.title", g.deathmatch.Round, g.deathmatch.Limit), rows)
+}
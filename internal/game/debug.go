@@ -0,0 +1,203 @@
+package game
+
+import (
+	"time"
+
+	"platformer/internal/canvas"
+	"platformer/internal/i18n"
+)
+
+// debugPage — страница отладочной информации
+type debugPage int
+
+const (
+	debugPageOff       debugPage = iota // Отладочная информация скрыта
+	debugPagePhysics                    // Персонаж, контакты и отладочный слой физики
+	debugPageNetwork                    // Состояние сетевого подключения
+	debugPageRendering                  // Бэкенд отрисовки, частота кадров и лимиты объектов
+	debugPageCount
+)
+
+// debugPageNames — имена страниц в настройках профиля
+var debugPageNames = map[debugPage]string{
+	debugPageOff:       "off",
+	debugPagePhysics:   "physics",
+	debugPageNetwork:   "network",
+	debugPageRendering: "rendering",
+}
+
+// defaultDebugPage возвращает страницу при запуске: в отладочной сборке (тег debug)
+// сразу видна физика, в обычной отладочная информация скрыта
+func defaultDebugPage() debugPage {
+	if debugBuild {
+		return debugPagePhysics
+	}
+	return debugPageOff
+}
+
+// debugPageByName возвращает страницу по имени из профиля; пустое или неизвестное
+// имя дает страницу по умолчанию
+func debugPageByName(name string) debugPage {
+	for page, pageName := range debugPageNames {
+		if pageName == name {
+			return page
+		}
+	}
+	return defaultDebugPage()
+}
+
+// handleDebugToggle листает страницы отладочной информации по клавише F3:
+// физика, сеть, отрисовка и снова скрыта
+func (g *Game) handleDebugToggle() {
+	debugKeyPressed := g.window.Pressed(KeyF3)
+
+	if debugKeyPressed && !g.prevDebugKeyPressed {
+		g.debugPage = (g.debugPage + 1) % debugPageCount
+	}
+
+	g.prevDebugKeyPressed = debugKeyPressed
+}
+
+// recordContact запоминает точку контакта с платформой и нормаль ее поверхности
+func (g *Game) recordContact(x, y, normalX, normalY float64) {
+	g.contacts = append(g.contacts, canvas.DebugContact{X: x, Y: y, NormalX: normalX, NormalY: normalY})
+}
+
+// drawPhysicsDebug рисует хитбоксы, скорости, контакты и триггеры через камеру
+// на странице физики
+func (g *Game) drawPhysicsDebug(screen canvas.Canvas) {
+	if g.debugPage != debugPagePhysics {
+		return
+	}
+
+	debug := canvas.PhysicsDebug{Contacts: g.contacts}
+
+	for _, platform := range g.platforms {
+		debug.Boxes = append(debug.Boxes, canvas.DebugBox{
+			Box:       platform.AABB,
+			Kind:      canvas.DebugPlatform,
+			VelocityX: platform.VelocityX + platform.Conveyor,
+			VelocityY: platform.VelocityY,
+		})
+	}
+	for _, volume := range g.triggers.Volumes() {
+		debug.Boxes = append(debug.Boxes, canvas.DebugBox{Box: volume.Bounds, Kind: canvas.DebugTrigger, Label: volume.Kind})
+	}
+	for _, npc := range g.npcs {
+		debug.Boxes = append(debug.Boxes, canvas.DebugBox{
+			Box: npc.AABB, Kind: canvas.DebugNPC, VelocityX: npc.VelocityX, VelocityY: npc.VelocityY,
+		})
+	}
+	for _, bullet := range g.bullets {
+		debug.Boxes = append(debug.Boxes, canvas.DebugBox{Box: bullet.AABB, Kind: canvas.DebugBullet, VelocityX: bullet.VelocityX})
+	}
+	for _, bullet := range g.enemyFire {
+		debug.Boxes = append(debug.Boxes, canvas.DebugBox{Box: bullet.AABB, Kind: canvas.DebugBullet, VelocityX: bullet.VelocityX})
+	}
+	if g.remote != nil {
+		debug.Boxes = append(debug.Boxes, canvas.DebugBox{
+			Box: g.remote.AABB, Kind: canvas.DebugPlayer, VelocityX: g.remote.VelocityX, VelocityY: g.remote.VelocityY,
+		})
+	}
+	debug.Boxes = append(debug.Boxes, canvas.DebugBox{
+		Box: g.player.AABB, Kind: canvas.DebugPlayer, VelocityX: g.player.VelocityX, VelocityY: g.player.VelocityY,
+	})
+
+	screen.DrawPhysicsDebug(g.camera.View(), debug)
+}
+
+// drawDebugInfo выводит подсказки, здоровье и оружие, а поверх них - текущую страницу
+// отладочной информации
+func (g *Game) drawDebugInfo(screen canvas.Canvas) {
+	screen.DrawHUD(g.player)
+	screen.DrawWeaponInfo(i18n.T(weaponNames[g.weapon]))
+
+	var lines []string
+	switch g.debugPage {
+	case debugPageOff:
+		return
+	case debugPagePhysics:
+		lines = g.physicsDebugLines()
+	case debugPageNetwork:
+		lines = g.netStatusLines()
+	case debugPageRendering:
+		lines = g.renderingDebugLines()
+	}
+	title := i18n.T("debug.page", i18n.T("debug.page."+debugPageNames[g.debugPage]), int(g.debugPage), int(debugPageCount-1))
+	screen.DrawDebugPage(title, lines)
+}
+
+// physicsDebugLines возвращает строки страницы физики
+func (g *Game) physicsDebugLines() []string {
+	player := g.player
+	onGround := i18n.T("hud.on_ground_no")
+	if player.OnGround {
+		onGround = i18n.T("hud.on_ground_yes")
+	}
+	return []string{
+		i18n.T("hud.position", player.X, player.Y),
+		i18n.T("hud.velocity", player.VelocityX, player.VelocityY),
+		onGround,
+		i18n.T("debug.gravity", g.gravityScale, player.GravityDirection()),
+		i18n.T("debug.contacts", len(g.contacts)),
+		i18n.T("hud.bullets", len(g.bullets)),
+	}
+}
+
+// netStatusLines возвращает строки состояния сетевого подключения
+// (страница сети и команда консоли netstats)
+func (g *Game) netStatusLines() []string {
+	if g.net == nil {
+		return []string{i18n.T("console.offline")}
+	}
+	lines := []string{
+		i18n.T("console.net.mode", g.options.Mode),
+		i18n.T("console.net.connected", g.net.Connected()),
+	}
+	if status := g.net.ListenStatus(); status.Address != "" {
+		lines = append(lines, i18n.T("console.net.listen", status.Address, status.State))
+	}
+	if stats, ok := g.net.Stats(); ok {
+		lines = append(lines, i18n.T("console.net.quality", stats.Ping.Milliseconds(), int(stats.Loss*100+0.5),
+			float64(stats.Jitter.Microseconds())/1000))
+	}
+	if traffic, ok := g.net.Traffic(); ok {
+		lines = append(lines,
+			i18n.T("console.net.frames", traffic.FramesIn, traffic.FramesOut),
+			i18n.T("console.net.bytes", traffic.BytesIn/1024, traffic.BytesOut/1024),
+			i18n.T("console.net.dropped", traffic.Dropped, traffic.DecodeErrors),
+		)
+	}
+	if g.remote != nil {
+		lines = append(lines, i18n.T("debug.remote", g.remote.X, g.remote.Y))
+	}
+	if err := g.net.Err(); err != nil {
+		lines = append(lines, i18n.T("console.error", err))
+	}
+	return lines
+}
+
+// renderingDebugLines возвращает строки страницы отрисовки
+func (g *Game) renderingDebugLines() []string {
+	fps, tps := g.window.FrameRate()
+	lines := []string{
+		i18n.T("hud.renderer", g.window.Renderer()),
+		i18n.T("debug.fps", fps, tps),
+	}
+	stats := g.metrics.Snapshot()
+	lines = append(lines,
+		i18n.T("debug.timing", milliseconds(stats.Tick.Avg), milliseconds(stats.Tick.Max),
+			milliseconds(stats.Draw.Avg), milliseconds(stats.Draw.Max)),
+		i18n.T("debug.memory", stats.Memory.HeapAlloc/1024, stats.Memory.AllocsPerFrame,
+			stats.Memory.BytesPerFrame/1024, stats.Memory.NumGC),
+	)
+	for _, stat := range g.content.Stats() {
+		lines = append(lines, i18n.T("hud.budget", stat.Kind, stat.Live, stat.Cap, stat.Evicted))
+	}
+	return lines
+}
+
+// milliseconds переводит длительность в миллисекунды для вывода
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,122 @@
+package game
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"math/rand"
+
+	"platformer/internal/entities"
+	"platformer/internal/world"
+)
+
+// Шаг симуляции детерминирован: при одинаковых уровне, зерне Options.Seed
+// и вводах на каждом шаге игра проходит одинаково на любой машине и при любом
+// TPS. Для этого step не смотрит на часы (шаг фиксирован, время считается
+// в кадрах levelFrames и simTick), случайные числа берет только из g.rng,
+// а сущности перебирает в срезах, а не в картах. Часы нужны только вне шага:
+// чтобы решить, сколько шагов выполнить (stepClock), и для интерполяции
+// снимков у клиента.
+//
+// StateHash сводит состояние симуляции к одному числу: одинаковые хэши
+// на одном шаге у двух запусков (или у хоста и повтора записи) значат, что
+// игры не разошлись, а первый шаг с разными хэшами показывает рассинхронизацию.
+
+// newRand создает генератор случайных чисел симуляции с зерном seed
+func newRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// StateHash возвращает хэш состояния симуляции на текущем шаге: счетчиков
+// шагов, персонажей, пуль, NPC, платформ и объектов мира. Идентификаторы
+// сущностей в хэш не входят: их раздает общий для процесса счетчик,
+// и у двух игр в одном процессе они разные.
+func (g *Game) StateHash() uint64 {
+	h := stateHasher{h: fnv.New64a()}
+	h.uint(uint64(g.simTick))
+	h.uint(uint64(g.levelFrames))
+	h.bool(g.levelComplete)
+
+	for _, p := range g.pilots() {
+		h.uint(uint64(p.weapon))
+		h.int(p.shootCooldown.Remaining())
+		h.int(p.rifleCooldown.Remaining())
+		h.int(p.dashCooldown.Remaining())
+		if p.player != nil {
+			h.entity(p.player)
+		}
+		for _, bullet := range p.bullets {
+			h.entity(bullet)
+		}
+	}
+	if g.remote != nil && g.pilotOf(g.remote) == nil {
+		h.entity(g.remote)
+	}
+	for _, npc := range g.npcs {
+		h.entity(npc)
+	}
+	for _, bullet := range g.enemyFire {
+		h.entity(bullet)
+	}
+	for _, platform := range g.platforms {
+		h.entity(platform)
+	}
+	g.world.Each(func(object world.Object) {
+		// Объекты мира не всегда сериализуются, поэтому учитываются вид и хитбокс
+		h.string(string(object.EntityKind()))
+		h.box(object.Bounds())
+	})
+	return h.h.Sum64()
+}
+
+// stateHasher дописывает значения состояния в хэш
+type stateHasher struct {
+	h   hash.Hash64
+	buf [8]byte
+}
+
+func (s *stateHasher) uint(v uint64) {
+	binary.LittleEndian.PutUint64(s.buf[:], v)
+	_, _ = s.h.Write(s.buf[:])
+}
+
+func (s *stateHasher) int(v int) {
+	s.uint(uint64(int64(v)))
+}
+
+func (s *stateHasher) bool(v bool) {
+	if v {
+		s.uint(1)
+	} else {
+		s.uint(0)
+	}
+}
+
+func (s *stateHasher) float(v float64) {
+	s.uint(math.Float64bits(v))
+}
+
+func (s *stateHasher) string(v string) {
+	s.int(len(v))
+	_, _ = s.h.Write([]byte(v))
+}
+
+func (s *stateHasher) box(b entities.AABB) {
+	s.float(b.X)
+	s.float(b.Y)
+	s.float(b.Width)
+	s.float(b.Height)
+}
+
+// entity дописывает сущность в той же кодировке, что и сохранения,
+// а если она не кодируется - ее вид и хитбокс
+func (s *stateHasher) entity(entity entities.Entity) {
+	record, err := entities.Encode(entity)
+	s.string(string(entity.EntityKind()))
+	if err != nil {
+		s.box(entity.Bounds())
+		return
+	}
+	_, _ = s.h.Write(record.Payload)
+}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"strconv"
+
+	"platformer/internal/budget"
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/sound"
+	"platformer/internal/transform"
+)
+
+// kindFloatingText и kindHitMarker — типы сущностей визуальных эффектов попаданий
+const (
+	kindFloatingText entities.Kind = "floating_text"
+	kindHitMarker    entities.Kind = "hit_marker"
+)
+
+// showDamage показывает над целью всплывающее число урона и проигрывает звук попадания
+func (g *Game) showDamage(target entities.Collider, damage int) {
+	if damage <= 0 {
+		return
+	}
+	g.sounds.Play(sound.EffectHit)
+	bounds := target.Bounds()
+	centerX, _ := bounds.Center()
+	g.world.Add(&floatingText{
+		id:     entities.NextID(),
+		text:   strconv.Itoa(damage),
+		x:      centerX,
+		y:      bounds.Top(),
+		frames: config.DamageNumberFrames,
+	})
+}
+
+// showHitMarker показывает отметку попадания в точке (x, y) мира
+func (g *Game) showHitMarker(x, y float64) {
+	g.world.Add(&hitMarker{id: entities.NextID(), x: x, y: y, frames: config.HitMarkerFrames})
+}
+
+// floatingText — число урона, которое всплывает над целью и гаснет
+type floatingText struct {
+	id     entities.ID
+	text   string
+	x, y   float64 // Точка мира, над которой показывается текст (поднимается со временем)
+	frames int     // Сколько кадров текст еще виден
+}
+
+// EntityID возвращает идентификатор эффекта
+func (f *floatingText) EntityID() entities.ID { return f.id }
+
+// EntityKind возвращает тип сущности
+func (f *floatingText) EntityKind() entities.Kind { return kindFloatingText }
+
+// BudgetKind относит эффект к ограниченным визуальным эффектам
+func (f *floatingText) BudgetKind() budget.Kind { return budget.KindEffects }
+
+// DrawLayer возвращает слой отрисовки всплывающей надписи
+func (f *floatingText) DrawLayer() canvas.Layer { return canvas.LayerParticles }
+
+// Bounds возвращает примерную область текста (ширина символа отладочного шрифта - 6 пикселей)
+func (f *floatingText) Bounds() entities.AABB {
+	width := float64(len(f.text) * 6)
+	return entities.AABB{X: f.x - width/2, Y: f.y - 16, Width: width, Height: 16}
+}
+
+// Update поднимает текст и отсчитывает время его жизни
+func (f *floatingText) Update(ctx entities.Context) bool {
+	f.y -= config.DamageNumberRise
+	f.frames--
+	return f.frames > 0
+}
+
+// Draw рисует текст, прозрачность которого растет к концу жизни
+func (f *floatingText) Draw(screen entities.Canvas, view transform.View) {
+	screen.DrawFloatingText(view, f.text, f.x, f.y, float64(f.frames)/config.DamageNumberFrames)
+}
+
+// hitMarker — крестик в точке попадания
+type hitMarker struct {
+	id     entities.ID
+	x, y   float64
+	frames int
+}
+
+// EntityID возвращает идентификатор эффекта
+func (m *hitMarker) EntityID() entities.ID { return m.id }
+
+// EntityKind возвращает тип сущности
+func (m *hitMarker) EntityKind() entities.Kind { return kindHitMarker }
+
+// BudgetKind относит эффект к ограниченным визуальным эффектам
+func (m *hitMarker) BudgetKind() budget.Kind { return budget.KindEffects }
+
+// DrawLayer возвращает слой отрисовки отметки попадания
+func (m *hitMarker) DrawLayer() canvas.Layer { return canvas.LayerParticles }
+
+// Bounds возвращает область крестика
+func (m *hitMarker) Bounds() entities.AABB {
+	return entities.AABB{X: m.x - 8, Y: m.y - 8, Width: 16, Height: 16}
+}
+
+// Update отсчитывает время жизни отметки
+func (m *hitMarker) Update(ctx entities.Context) bool {
+	m.frames--
+	return m.frames > 0
+}
+
+// Draw рисует гаснущий крестик
+func (m *hitMarker) Draw(screen entities.Canvas, view transform.View) {
+	screen.DrawHitMarker(view, m.x, m.y, float64(m.frames)/config.HitMarkerFrames)
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"log"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/i18n"
+	"platformer/internal/network"
+)
+
+// eventEmote — событие эмоции игрока
+const eventEmote network.EventType = "emote"
+
+// emotes — ключи переводов доступных эмоций; эмоция с индексом i вызывается клавишей i+1.
+// По сети передается индекс, поэтому каждый игрок видит эмоции на своем языке.
+var emotes = []string{
+	"emote.hello",
+	"emote.laugh",
+	"emote.oops",
+	"emote.good_game",
+}
+
+// emotePayload — данные события эмоции
+type emotePayload struct {
+	Emote int `json:"emote"` // Индекс эмоции в emotes
+}
+
+// activeEmote — эмоция, которая сейчас показывается над персонажем
+type activeEmote struct {
+	index  int // Индекс эмоции в emotes
+	frames int // Сколько кадров эмоция еще показывается
+}
+
+// active сообщает, показывается ли эмоция
+func (e activeEmote) active() bool {
+	return e.frames > 0
+}
+
+// updateEmotes обрабатывает клавиши эмоций и отсчитывает время их показа
+func (g *Game) updateEmotes() {
+	if g.localEmote.frames > 0 {
+		g.localEmote.frames--
+	}
+	if g.remoteEmote.frames > 0 {
+		g.remoteEmote.frames--
+	}
+
+	g.emoteCooldown.Tick()
+	emote := g.input.Emote
+	if emote >= 0 && emote != g.prevEmoteKey && g.emoteCooldown.Trigger() {
+		g.playEmote(emote)
+	}
+	g.prevEmoteKey = emote
+}
+
+// playEmote показывает эмоцию над локальным игроком и отправляет ее удаленному
+func (g *Game) playEmote(index int) {
+	g.localEmote = activeEmote{index: index, frames: config.EmoteFrames}
+
+	if g.net == nil {
+		return
+	}
+	event, err := network.NewEvent(eventEmote, emotePayload{Emote: index})
+	if err == nil {
+		err = g.net.SendEvent(event)
+	}
+	if err != nil {
+		log.Printf("send %s: %v", eventEmote, err)
+	}
+}
+
+// handleEmoteEvent показывает эмоцию удаленного игрока
+func (g *Game) handleEmoteEvent(event network.Event) {
+	var payload emotePayload
+	if err := event.Decode(&payload); err != nil {
+		return
+	}
+	if payload.Emote < 0 || payload.Emote >= len(emotes) {
+		return
+	}
+	g.remoteEmote = activeEmote{index: payload.Emote, frames: config.EmoteFrames}
+}
+
+// drawEmotes рисует облачка с эмоциями над игроками
+func (g *Game) drawEmotes(screen canvas.Canvas) {
+	g.drawEmote(screen, g.localEmote, g.player)
+	if g.remote != nil {
+		g.drawEmote(screen, g.remoteEmote, g.remote)
+	}
+}
+
+func (g *Game) drawEmote(screen canvas.Canvas, emote activeEmote, player *entities.Player) {
+	if !emote.active() {
+		return
+	}
+
+	// Прогресс анимации от 0 (появление) до 1 (исчезновение)
+	progress := 1 - float64(emote.frames)/config.EmoteFrames
+	screen.DrawEmoteBubble(i18n.T(emotes[emote.index]), player.X+player.Width/2, player.Y, g.camera.View(), progress)
+}
@@ -0,0 +1,63 @@
+package game
+
+import (
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/physics"
+)
+
+// entityContext — уровень игры, в котором сущности делают шаг (entities.Context)
+type entityContext struct {
+	g *Game
+}
+
+var _ entities.Context = entityContext{}
+
+// PlatformsNear возвращает платформы из ячеек сетки рядом с прямоугольником
+func (c entityContext) PlatformsNear(x, y, width, height float64) []*entities.Platform {
+	return c.g.platformsNear(x, y, width, height)
+}
+
+// SweepPlatforms проверяет весь путь хитбокса body за шаг и возвращает первое касание платформы
+func (c entityContext) SweepPlatforms(body entities.Collider, dx, dy float64) (time, normalX, normalY float64, hit bool) {
+	g := c.g
+	box := body.Bounds()
+	nearest := physics.Contact{Time: 1}
+	g.nearby = g.grid.QuerySwept(box.X, box.Y, box.Width, box.Height, dx, dy, g.nearby[:0])
+	for _, platform := range g.nearby {
+		if contact, ok := physics.Sweep(body, dx, dy, platform); ok && contact.Time < nearest.Time {
+			nearest = contact
+			hit = true
+		}
+	}
+	return nearest.Time, nearest.NormalX, nearest.NormalY, hit
+}
+
+// LevelSize возвращает размеры текущего уровня
+func (c entityContext) LevelSize() (width, height float64) {
+	return c.g.level.Width, c.g.level.Height
+}
+
+// Friction возвращает трение поверхности с учетом погоды комнаты
+func (c entityContext) Friction(ground string) float64 {
+	return c.g.surfaceFriction(true, ground, config.Friction)
+}
+
+// MovePlayer выполняет физику шага персонажа от лица его пилота, потому что код
+// физики работает с персонажем текущего пилота (g.player). Персонажи без пилота
+// (соперник у клиента) двигаются по снимкам хоста, а не здесь.
+func (c entityContext) MovePlayer(player *entities.Player) {
+	g := c.g
+	if player != g.player {
+		for _, p := range g.pilots() {
+			if p.player == player {
+				g.withPilot(p, func() { c.MovePlayer(player) })
+				return
+			}
+		}
+		return
+	}
+	g.applyGravity()
+	g.updatePlayerPosition()
+	g.checkCollisions()
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"math"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/i18n"
+	"platformer/internal/level"
+	"platformer/internal/transform"
+	"platformer/internal/trigger"
+)
+
+// Сцена побега собрана из общих систем уровня: ее запускает триггер вида
+// level.TriggerEscape, через пропасти перевозят движущиеся платформы уровня
+// (Platform.Motion), платформы целиком позади камеры рушатся сами по своему
+// счетчику (Platform.CrumbleFrames, platforms.go), а за левым краем камеры едет зона
+// гибели (killZone, triggers.go). Сама сцена только ведет камеру и двигает
+// зону вслед за ней. Рухнувший уровень не вернуть, поэтому погибший во время
+// побега игрок начинает уровень заново.
+
+// escapeSequence — состояние сцены побега с рушащимся уровнем
+type escapeSequence struct {
+	active  bool      // Побег начался
+	front   float64   // Левая граница камеры: все, что позади, рушится
+	zone    *killZone // Зона гибели за левой границей камеры
+	caught  bool      // Обрушение догнало игрока
+	restart bool      // Игрок погиб во время побега: уровень начнется заново после шага
+}
+
+// resetEscape возвращает сцену побега в начальное состояние
+func (g *Game) resetEscape() {
+	g.escape = escapeSequence{}
+}
+
+// onEscapeTrigger начинает побег, когда игрок входит в его триггер
+func (g *Game) onEscapeTrigger(volume *trigger.Volume, phase trigger.Phase) {
+	e := &g.escape
+	if phase != trigger.Enter || g.level.Escape == nil || e.active || g.levelComplete {
+		return
+	}
+	e.active = true
+	e.front = g.camera.X
+
+	// Зона гибели занимает всю высоту уровня слева от границы обрушения
+	e.zone = &killZone{volume: &trigger.Volume{Kind: level.TriggerKill}}
+	e.zone.volume.Bounds.Height = g.level.Height
+	g.killZones = append(g.killZones, e.zone)
+	g.moveCollapse()
+}
+
+// updateEscape двигает камеру, начинает обрушение платформ позади нее
+// и перезапускает уровень, если обрушение догнало игрока
+func (g *Game) updateEscape() {
+	e := &g.escape
+	if e.restart {
+		g.resetLevel()
+		g.escape.caught = true
+		return
+	}
+	cfg := g.level.Escape
+	if cfg == nil || !e.active || g.levelComplete {
+		return
+	}
+	player := g.player
+
+	// Камера едет сама и не дает игроку обогнать ее
+	viewWidth, _ := g.camera.View().WorldSize()
+	maxFront := g.camera.Bounds.X + g.camera.Bounds.Width - viewWidth
+	e.front = math.Min(e.front+cfg.ScrollSpeed, maxFront)
+	g.camera.X = e.front
+	if player.X+player.Width > e.front+viewWidth {
+		player.X = e.front + viewWidth - player.Width
+		player.VelocityX = math.Min(player.VelocityX, 0)
+	}
+
+	// Платформы, которые целиком остались позади камеры, начинают рушиться
+	for _, platform := range g.platforms {
+		if platform.X+platform.Width < e.front && !platform.Crumbling() {
+			platform.CrumbleFrames = max(cfg.CrumbleFrames, 1)
+			g.worldChanged()
+		}
+	}
+	g.moveCollapse()
+}
+
+// moveCollapse ставит зону гибели за границу обрушения: игрок касается ее,
+// только когда целиком скрылся за левым краем камеры
+func (g *Game) moveCollapse() {
+	bounds := &g.escape.zone.volume.Bounds
+	bounds.Width = g.level.Width
+	bounds.X = g.escape.front - config.PlayerWidth - bounds.Width
+}
+
+// caughtByCollapse отмечает гибель во время побега: вместо появления
+// на контрольной точке уровень начнется заново после шага (respawnPlayer)
+func (g *Game) caughtByCollapse() bool {
+	if !g.escape.active {
+		return false
+	}
+	g.escape.restart = true
+	return true
+}
+
+// platformView возвращает вид для отрисовки платформы: рушащиеся платформы дрожат
+func (g *Game) platformView(view transform.View, platform *entities.Platform) transform.View {
+	if !platform.Crumbling() {
+		return view
+	}
+	phase := float64(g.levelFrames) + platform.X
+	view.ShakeX += math.Sin(phase*1.7) * 2
+	view.ShakeY += math.Cos(phase*2.3) * 2
+	return view
+}
+
+// drawEscape рисует границу обрушения и итог побега
+func (g *Game) drawEscape(screen canvas.Canvas) {
+	cfg := g.level.Escape
+	if cfg == nil {
+		return
+	}
+
+	switch {
+	case g.levelComplete && !g.isCoop():
+		screen.DrawBanner(i18n.T("escape.won"))
+	case g.escape.active:
+		screen.DrawCollapseFront()
+	case g.escape.caught:
+		screen.DrawBanner(i18n.T("escape.lost"))
+	}
+}
@@ -0,0 +1,224 @@
+package game
+
+import (
+	"math"
+
+	"platformer/internal/budget"
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/i18n"
+	"platformer/internal/level"
+	"platformer/internal/mission"
+	"platformer/internal/physics"
+)
+
+// createEscortMission создает задание сопровождения для NPC, отмеченного в уровне.
+// Если на уровне нет такого NPC или выхода, задания нет.
+func createEscortMission(lvl *level.Level) *mission.Escort {
+	if lvl.Exit == nil {
+		return nil
+	}
+
+	for _, spawn := range lvl.NPCs {
+		if !spawn.Escort {
+			continue
+		}
+
+		npc := entities.NewNPC(spawn.X, spawn.Y, spawn.Width, spawn.Height)
+		exit := mission.Area{
+			X:      lvl.Exit.X,
+			Y:      lvl.Exit.Y,
+			Width:  lvl.Exit.Width,
+			Height: lvl.Exit.Height,
+		}
+		return mission.NewEscort(npc, exit, config.NPCFollowSpeed, config.NPCFollowDistance, lvl.Height)
+	}
+
+	return nil
+}
+
+// handleInteraction обрабатывает клавишу разговора с NPC (E)
+func (g *Game) handleInteraction() {
+	interactKeyPressed := g.input.Interact
+
+	// Реагируем только на новое нажатие, как и для стрельбы
+	if interactKeyPressed && !g.prevInteractKeyPressed && g.canTalkToEscort() {
+		g.escort.Interact()
+	}
+
+	g.prevInteractKeyPressed = interactKeyPressed
+}
+
+// canTalkToEscort проверяет, стоит ли игрок достаточно близко к сопровождаемому NPC
+// и не отделяет ли их платформа (например, пол между этажами)
+func (g *Game) canTalkToEscort() bool {
+	if g.escort == nil || g.escort.Finished() {
+		return false
+	}
+
+	npc := g.escort.NPC
+	dx := (npc.X + npc.Width/2) - (g.player.X + g.player.Width/2)
+	dy := (npc.Y + npc.Height/2) - (g.player.Y + g.player.Height/2)
+	distance := math.Hypot(dx, dy)
+	if distance > config.NPCInteractDistance {
+		return false
+	}
+
+	// Проверяем прямую видимость лучом, который задевает только платформы
+	centerX, centerY := g.player.Center()
+	_, blocked := g.raycast(physics.Vec{X: centerX, Y: centerY}, physics.Vec{X: dx, Y: dy}, distance, physics.LayerPlatform)
+	return !blocked
+}
+
+// updateEscort обновляет задание сопровождения и состояние прохождения уровня
+func (g *Game) updateEscort() {
+	if g.escort == nil {
+		return
+	}
+
+	g.escort.Update(g.player.X, g.player.Width)
+
+	// Итог задания определяет прохождение уровня; в кооперативе к выходу
+	// должны подойти еще и оба игрока
+	if g.escort.Completed() && !g.isCoop() {
+		g.levelComplete = true
+	}
+}
+
+// updateNPCs выполняет шаг живых NPC: оглушение, гравитацию, движение и столкновения.
+// Погибшие NPC остаются в списке как трупы (corpses), поэтому результат Update не нужен.
+func (g *Game) updateNPCs() {
+	ctx := entityContext{g}
+	for _, npc := range g.npcs {
+		npc.Update(ctx)
+	}
+}
+
+// damageNPCs наносит урон NPC от пуль всех персонажей: игроков за этим компьютером
+// и соперника. Сопровождаемого NPC ранят только пули соперника в поединке,
+// свои пули и пули союзника пролетают сквозь него. Пробивающая пуля пролетает
+// сквозь несколько NPC, теряя урон с каждым из них; пробитые NPC запоминаются по ID пули.
+func (g *Game) damageNPCs() {
+	live := 0
+	for _, p := range g.pilots() {
+		p.bullets = g.pierceNPCs(p, p.bullets)
+		live += len(p.bullets)
+	}
+
+	// Забываем пули, которых больше нет (врезались в платформу или улетели)
+	if len(g.enemyHits) > live {
+		hits := make(map[entities.ID]map[entities.ID]bool, live)
+		for _, p := range g.pilots() {
+			for _, bullet := range p.bullets {
+				if pierced, ok := g.enemyHits[bullet.ID]; ok {
+					hits[bullet.ID] = pierced
+				}
+			}
+		}
+		g.enemyHits = hits
+	}
+
+	g.collectCorpses()
+}
+
+// pierceNPCs наносит NPC урон от пуль bullets пилота shooter и возвращает пули,
+// которые не застряли в NPC
+func (g *Game) pierceNPCs(shooter *pilot, bullets []*entities.Bullet) []*entities.Bullet {
+	remaining := bullets[:0]
+	for _, bullet := range bullets {
+		stuck := false
+		for _, npc := range g.npcs {
+			pierced := g.enemyHits[bullet.ID]
+			if npc.IsDead() || pierced[npc.ID] || !physics.Overlaps(bullet, npc) {
+				continue
+			}
+			if g.escort != nil && npc == g.escort.NPC && !g.hurtsEscort(shooter) {
+				continue
+			}
+
+			damage := pierceDamage(len(pierced))
+			npc.TakeHit(entities.NewHit(damage, bullet.X+bullet.Width/2, npc.X+npc.Width/2))
+			g.showDamage(npc, damage)
+			g.camera.Shake(config.CameraShakeStrength, config.CameraShakeFrames)
+
+			// Пуля застревает в NPC, когда исчерпала пробивание
+			if len(pierced) >= bullet.Pierce {
+				delete(g.enemyHits, bullet.ID)
+				stuck = true
+				break
+			}
+			if pierced == nil {
+				pierced = make(map[entities.ID]bool)
+				g.enemyHits[bullet.ID] = pierced
+			}
+			pierced[npc.ID] = true
+		}
+		if !stuck {
+			remaining = append(remaining, bullet)
+		}
+	}
+	return remaining
+}
+
+// hurtsEscort сообщает, ранят ли пули пилота shooter сопровождаемого NPC:
+// его ранит только соперник в поединке, а не тот, кто его сопровождает, и не союзник
+func (g *Game) hurtsEscort(shooter *pilot) bool {
+	return shooter == g.opponent && !g.isCoop()
+}
+
+// pierceDamage возвращает урон пули, которая уже пробила pierced NPC
+func pierceDamage(pierced int) int {
+	damage := float64(config.BulletDamage) * math.Pow(config.BulletPierceFalloff, float64(pierced))
+	return max(1, int(math.Round(damage)))
+}
+
+// collectCorpses переносит погибших NPC из списка активных в список трупов.
+// Сопровождаемый NPC остается в списке: по нему проверяется итог задания.
+func (g *Game) collectCorpses() {
+	alive := g.npcs[:0]
+	for _, npc := range g.npcs {
+		if npc.IsDead() && (g.escort == nil || npc != g.escort.NPC) {
+			g.corpses = append(g.corpses, npc)
+			continue
+		}
+		alive = append(alive, npc)
+	}
+	g.npcs = alive
+
+	// При превышении лимита исчезают самые давние трупы
+	g.corpses = budget.Apply(g.content, budget.KindCorpses, g.corpses)
+}
+
+// drawExit рисует выход с уровня: выход задания сопровождения или выход побега
+func (g *Game) drawExit(screen canvas.Canvas) {
+	view := g.camera.View()
+	switch {
+	case g.escort != nil:
+		exit := g.escort.Exit
+		screen.DrawExit(exit.X, exit.Y, exit.Width, exit.Height, view)
+	case g.level.Escape != nil && g.level.Exit != nil:
+		exit := g.level.Exit
+		screen.DrawExit(exit.X, exit.Y, exit.Width, exit.Height, view)
+	}
+}
+
+// drawEscort рисует реплику NPC и итог задания
+func (g *Game) drawEscort(screen canvas.Canvas) {
+	if g.escort == nil {
+		return
+	}
+
+	// Реплику NPC показываем, когда игрок стоит рядом
+	if g.canTalkToEscort() {
+		npc := g.escort.NPC
+		screen.DrawSpeech(g.escort.Dialogue(), npc.X, npc.Y, g.camera.View())
+	}
+
+	switch {
+	case g.escort.Completed() && !g.isCoop():
+		screen.DrawBanner(i18n.T("level.complete"))
+	case g.escort.Failed():
+		screen.DrawBanner(i18n.T("escort.failed"))
+	}
+}
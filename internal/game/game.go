@@ -1,24 +1,44 @@
 package game
 
 import (
+	"errors"
 	"fmt"
-	"image/color"
+	"io/fs"
+	"log"
 	"math"
-
-	"github.com/hajimehoshi/ebiten/v2"
-
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"platformer/internal/assets"
+	"platformer/internal/budget"
+	"platformer/internal/canvas"
 	"platformer/internal/config"
+	"platformer/internal/console"
 	"platformer/internal/entities"
+	"platformer/internal/highscore"
+	"platformer/internal/i18n"
+	"platformer/internal/leaderboard"
+	"platformer/internal/level"
+	"platformer/internal/matchmaking"
+	"platformer/internal/metrics"
+	"platformer/internal/mission"
 	"platformer/internal/network"
 	"platformer/internal/physics"
-	"platformer/internal/renderer"
+	"platformer/internal/preload"
+	"platformer/internal/profile"
+	"platformer/internal/rcon"
+	"platformer/internal/save"
+	"platformer/internal/sound"
+	"platformer/internal/speedrun"
+	"platformer/internal/timer"
+	"platformer/internal/transform"
+	"platformer/internal/trigger"
+	"platformer/internal/weather"
+	"platformer/internal/world"
 )
 
-// Camera представляет камеру, которая следует за игроком
-type Camera struct {
-	X, Y float64 // Позиция камеры в игровом мире
-}
-
 // Mode определяет режим игры.
 type Mode string
 
@@ -30,49 +50,216 @@ const (
 
 // Options описывает параметры запуска игры.
 type Options struct {
-	Mode    Mode
-	Address string
-}
+	Mode      Mode
+	Address   string       // Адрес хоста; клиент может указать ws://host:port/ для подключения по WebSocket
+	LevelPath string       // Путь к JSON-файлу уровня (пустой - встроенный уровень)
+	Level     *level.Level // Уровень, собранный в коде (проверки физики); имеет приоритет над LevelPath
 
-// Update обновляет позицию камеры, чтобы она следовала за игроком
-func (c *Camera) Update(playerX, playerY float64) {
-	// Центрируем камеру на игроке
-	// Камера должна показывать игрока в центре экрана (или немного смещена вперед)
-	targetX := playerX - config.ScreenWidth/2 + config.PlayerWidth/2
+	WebSocketAddress string // Адрес, на котором хост принимает клиентов по WebSocket (пустой - только TCP)
 
-	// Ограничиваем камеру границами мира
-	// Камера не должна выходить за левую границу мира
-	if targetX < 0 {
-		targetX = 0
-	}
-	// Камера не должна выходить за правую границу мира
-	if targetX > config.WorldWidth-config.ScreenWidth {
-		targetX = config.WorldWidth - config.ScreenWidth
-	}
+	PlayerName        string // Имя игрока для таблицы рекордов и сетевой игры; имеет приоритет над именем профиля
+	LeaderboardURL    string // Адрес сервера таблицы рекордов (пустой - отключено)
+	LeaderboardSecret string // Ключ для подписи результатов
+
+	HighScorePath string // Файл локальной таблицы рекордов (пустой - в каталоге настроек пользователя)
+
+	Speedrun     bool   // Режим спидрана: таймер с временами участков
+	SpeedrunPath string // Файл, в который дописываются результаты забегов
+
+	SavePath string // Файл быстрого сохранения (F5/F9)
+	LoadPath string // Сохранение, которое загружается при запуске (пустой - новая игра)
+	Daily    bool   // Ежедневное испытание: уровень и зерно выбираются по дню (daily.go)
+
+	ProfileDir string // Каталог профилей игроков (пустой - в каталоге настроек пользователя)
+	Profile    int    // Номер слота профиля, выбранного при запуске (с 1)
+
+	Skin     string // PNG-файл скина персонажа; имеет приоритет над скином профиля
+	Language string // Язык интерфейса (например, "en"); имеет приоритет над языком профиля
+
+	ClipSeconds int // Длина клипа по F10 в секундах (0 - запись клипов выключена)
+
+	SplitScreen  bool     // Делить экран пополам, когда есть второй персонаж (splitscreen.go)
+	SecondPlayer Controls // Управление второго игрока за этим компьютером (пустое - играет один)
+	Bot          bool     // Персонажем этого игрока управляет бот (bot.go)
+
+	ReconnectSeconds int // Сколько секунд восстанавливать оборвавшееся соединение (0 - не восстанавливать)
+
+	NetConditions network.Conditions // Искусственные задержки и потери сети для отладки предсказания (нулевые - выключены)
+
+	RecordPath   string // Файл, в который хост записывает матч (пустой - не записывать)
+	PlaybackPath string // Запись матча, которую игра показывает глазами клиента вместо подключения к хосту
+
+	Match        MatchMode // Вид сетевой игры хоста: матч, кооператив, дезматч или захват флага (клиент узнает его от хоста)
+	ScoreLimit   int       // Сколько убийств нужно для победы в раунде дезматча или захватов в захвате флага (0 - по умолчанию)
+	FriendlyFire bool      // Пули ранят игроков своей команды (хост может переключить правило в лобби)
+
+	Password      string   // Пароль сервера: хост требует его от клиентов, а клиент отправляет хосту
+	MaxPlayers    int      // Сколько игроков вмещает хост вместе с собой (0 - сколько позволяет сборка)
+	LevelRotation []string // Уровни, которые хост меняет после каждого матча (первый - начальный, если не задан LevelPath)
+	SnapshotRate  int      // Сколько снимков в секунду хост отправляет клиенту (0 - на каждом шаге)
+
+	RconAddress  string // Адрес, на котором хост принимает команды удаленного управления (пустой - выключено)
+	RconPassword string // Пароль удаленного управления
 
-	// Плавно перемещаем камеру к целевой позиции
-	// Это создает более плавное движение камеры
-	c.X += (targetX - c.X) * 0.1
+	MatchmakingURL string // Адрес сервера подбора игр, на котором хост регистрирует комнату (пустой - отключено)
+	RoomCode       string // Код комнаты, к которой подключается клиент вместо Address
 
-	// Камера по Y всегда центрирована на игроке (или можно сделать фиксированной)
-	c.Y = playerY - config.ScreenHeight/2 + config.PlayerHeight/2
+	Dev      bool   // Режим разработки: ресурсы читаются с диска и обновляются при изменении
+	AssetDir string // Каталог ресурсов для режима разработки
+
+	PprofAddr string // Адрес HTTP-сервера pprof и замеров производительности (пустой - выключен)
+
+	Seed int64 // Зерно случайных чисел симуляции: с одинаковым зерном и вводом игра проходит одинаково
+
+	// Headless - работа без окна: профили не загружаются, клипы не записываются,
+	// игра запускается через RunHeadless, а Draw не вызывается
+	Headless    bool
+	Window      Window      // Клавиатура, звук и ресурсы отрисовки окна (nil - без окна, window.New)
+	Input       InputSource // Источник управления (nil - пустой ввод)
+	SecondInput InputSource // Источник управления второго игрока за этим компьютером (nil - пустой ввод)
 }
 
+// ticksPerSecond — количество шагов симуляции в секунду (не зависит от частоты обновлений окна)
+const ticksPerSecond = 60
+
 // Game представляет основное состояние игры
 type Game struct {
-	player    *entities.Player     // Игровой персонаж
-	platforms []*entities.Platform // Список всех платформ на уровне (пустой, но оставляем для совместимости)
-	bullets   []*entities.Bullet   // Список всех активных пуль на экране
-	npcs      []*entities.NPC      // Список всех NPC на карте
-	camera    Camera               // Камера, следующая за игроком
-	remote    *entities.Player     // Удаленный игрок
-	enemyFire []*entities.Bullet   // Пули удаленного игрока
-	net       *network.Manager     // Менеджер сетевого подключения
-	options   Options              // Опции запуска
+	*pilot            // Персонаж, которым управляет текущий шаг (обычно local)
+	local    *pilot   // Персонаж этого игрока
+	locals   []*pilot // Персонажи игроков за этим компьютером: local и второй игрок (Options.SecondPlayer)
+	opponent *pilot   // Персонаж клиента, которого моделирует хост (nil - не хост)
+
+	opponentInputs   []network.InputMessage // Полученные хостом вводы клиента, которые еще не применены
+	opponentInputSeq uint32                 // Номер последнего примененного ввода клиента
+	opponentViewTick uint32                 // Шаг хоста, на котором клиент видит персонажа хоста
+	simTick          uint32                 // Номер шага симуляции хоста (растет весь сеанс)
+	rng              *rand.Rand             // Случайные числа симуляции с зерном Options.Seed (determinism.go)
+	snapshotTick     uint32                 // Шаг хоста в последнем примененном снимке (клиент)
+	hitboxes         hitboxHistory          // Хитбоксы персонажа хоста в отправленных снимках
+	pilotHits        []float64              // Буфер попаданий пуль в персонажа (X пуль)
+
+	prediction prediction // Предсказание движения персонажа клиента (клиент)
+	replaying  bool       // Клиент повторяет неподтвержденные вводы после снимка
+
+	level           *level.Level         // Данные текущего уровня
+	platforms       []*entities.Platform // Список всех платформ на уровне
+	grid            *physics.Grid        // Сетка платформ для быстрого поиска столкновений
+	stream          worldStream          // Подгрузка частей большого уровня (chunks.go)
+	split           splitScreen          // Вторая половина разделенного экрана (splitscreen.go)
+	movers          []*platformMover     // Движения движущихся платформ
+	minimapRevision int                  // Версия миникарты: растет при смене комнаты или набора платформ
+	drawQueue       canvas.DrawQueue     // Очередь отрисовки кадра по слоям
+	weather         *weather.System      // Осадки текущей комнаты (nil - ясно)
+	nearby          []*entities.Platform // Буфер результатов запроса к сетке
+	rayBodies       []physics.Body       // Буфер кандидатов для лучей
+	npcs            []*entities.NPC      // Список NPC текущей комнаты
+	corpses         []*entities.NPC      // Погибшие NPC текущей комнаты в порядке гибели
+	world           *world.Registry      // Прочие объекты мира с общими Update/Draw
+	camera          Camera               // Камера, следующая за игроком
+	remote          *entities.Player     // Удаленный игрок
+	remoteTrack     remoteTrack          // Буфер состояний персонажа хоста для интерполяции (клиент)
+	enemyFire       []*entities.Bullet   // Пули удаленного игрока
+	net             *network.Manager     // Менеджер сетевого подключения
+	rcon            *rcon.Server         // Удаленное управление хостом (nil - выключено)
+	rotation        int                  // Номер текущего уровня в Options.LevelRotation (хост)
+	options         Options              // Опции запуска
+	timers          *timer.Manager       // Центральный менеджер кадровых таймеров
+	clock           stepClock            // Накопитель времени для фиксированного шага симуляции
+	content         *budget.Tracker      // Ограничения количества пуль, частиц, трупов и эффектов
+	window          Window               // Клавиатура и ресурсы окна (без окна - noWindow)
+	sounds          Sounds               // Звуковые эффекты и музыка окна (без окна и при повторе ввода - тишина)
+	volume          sound.Volume         // Настройки громкости
+	metrics         *metrics.Recorder    // Замеры времени шагов и отрисовки
+
+	loader *preload.Loader // Фоновая загрузка ресурсов перед главным меню
+
+	scene     scene // Активный экран (меню, игра, таблица рекордов)
+	menuIndex int   // Выбранный пункт главного меню
+
+	browser     *network.Browser // Поиск игр в локальной сети (nil - список игр закрыт)
+	serverIndex int              // Выбранная игра в списке
+	serverErr   error            // Ошибка поиска игр или подключения к выбранной
+
+	matchmaking *matchmaking.Client       // Клиент сервера подбора игр (nil - отключен)
+	matchRoom   *matchmaking.Registration // Комната хоста на сервере подбора (nil - нет)
+	codeEntry   bool                      // В списке игр вводится код комнаты
+	codeBuffer  []rune                    // Вводимый код комнаты
+	codeLookup  chan lookupResult         // Идущий поиск комнаты по коду (nil - нет)
+
+	scores          *leaderboard.Client // Клиент онлайн-таблицы рекордов (nil - отключена)
+	highScores      *highscore.Table    // Локальная таблица рекордов (nil - файл недоступен)
+	leaderboard     leaderboardState    // Таблицы рекордов, загруженные для меню
+	levelFrames     int                 // Сколько кадров идет прохождение уровня
+	resultSubmitted bool                // Результат уровня уже отправлен
+	daily           *dailyChallenge     // Испытание дня (nil - обычная игра)
+	speedrun        *speedrun.Run       // Таймер спидрана (nil - режим выключен)
+	paused          bool                // Игра стоит на паузе
+	pendingLoad     *save.State         // Сохранение, которое загрузится после загрузки ресурсов
+	savePathFixed   bool                // Файл быстрого сохранения задан флагом, а не профилем
+	nameFixed       bool                // Имя игрока задано флагом, а не профилем
+
+	profiles     []*profile.Profile // Слоты профилей (nil - профили недоступны)
+	profile      *profile.Profile   // Текущий профиль
+	profileIndex int                // Выбранный слот на экране профилей
+	renaming     bool               // Идет ввод нового имени профиля
+	renameBuffer []rune             // Введенное имя профиля
+	optionIndex  int                // Выбранный пункт меню настроек
+	screenshot   screenshotRequest  // Запрошенный снимок экрана
+	clips        *clipRecorder      // Запись последних секунд игры (nil - выключена)
+	clipDone     chan struct{}      // Сигнал окончания фонового сохранения клипа
+	skin         string             // Файл текущего скина персонажа (пустой - встроенный спрайт)
+	console      *console.Console   // Отладочная консоль (клавиша `)
+	gravityScale float64            // Множитель гравитации персонажа (команда консоли gravity)
+
+	emoteCooldown timer.Cooldown // Минимальный интервал между эмоциями
+
+	localEmote  activeEmote // Эмоция локального игрока
+	remoteEmote activeEmote // Эмоция удаленного игрока
+
+	room           int                     // Индекс текущей комнаты (-1, если уровень без комнат)
+	roomTransition roomTransition          // Переход камеры между комнатами
+	doorLocked     bool                    // Игрок еще стоит в двери, через которую пришел
+	roomNPCs       map[int][]*entities.NPC // Живые NPC комнат, из которых ушел игрок (rooms.go)
+
+	match    versusMatch   // Состояние сетевого матча
+	coop     coopCampaign  // Кооперативная кампания (coop.active - игра идет в кооперативе)
+	lobby    lobby         // Лобби перед началом сетевого матча
+	settings matchSettings // Правила сетевой игры, которые задает хост
+
+	matchOutbox []network.Event // События матча, которые ждут места в очереди отправки (versus.go)
+
+	deathmatch     deathmatchState // Счет и раунды дезматча (deathmatch.active - игра идет в дезматче)
+	ctf            ctfMatch        // Флаги и базы захвата флага (ctf.active - игра идет в захвате флага)
+	respawn        respawnState    // Отсчеты до появления погибших в сетевой игре персонажей
+	worldSync      worldSync       // Изменения уровня, которые хост передает клиенту
+	scoreboardOpen bool            // Зажата клавиша Tab: показывается таблица счета
+
+	escort        *mission.Escort // Задание сопровождения NPC
+	levelComplete bool            // Пройден ли уровень
+	escape        escapeSequence  // Сцена побега с рушащимся уровнем
+
+	enemyHits map[entities.ID]map[entities.ID]bool // NPC, которых уже пробила каждая пуля клиента
+
+	// Триггеры уровня и их состояние
+	triggers      *trigger.Set
+	damageTimers  map[*trigger.Volume]timer.ID // Таймеры урона опасных зон, в которых стоит игрок
+	killZones     []*killZone                  // Зоны гибели уровня (triggers.go)
+	checkpoint    *level.Point                 // Последняя контрольная точка
+	message       string                       // Сообщение сработавшего триггера
+	messageFrames int                          // Сколько кадров еще показывать сообщение
 
 	// Отслеживание состояния клавиш для одноразовых нажатий
-	// Храним предыдущее состояние клавиш стрельбы
-	prevShootKeyPressed bool // Предыдущее состояние клавиши стрельбы
+	// Храним предыдущее состояние клавиш стрельбы и разговора
+	prevRendererKeyPressed bool // Предыдущее состояние клавиши переключения отрисовки
+	prevRetryKeyPressed    bool // Предыдущее состояние клавиши повторного запуска сервера
+	prevDebugKeyPressed    bool // Предыдущее состояние клавиши отладочной информации
+	prevPauseKeyPressed    bool // Предыдущее состояние клавиши паузы
+	prevSaveKeyPressed     bool // Предыдущее состояние клавиши быстрого сохранения
+	prevLoadKeyPressed     bool // Предыдущее состояние клавиши быстрой загрузки
+	prevEmoteKey           int  // Клавиша эмоции, зажатая на прошлом шаге (-1 - ни одной)
+
+	debugPage debugPage             // Страница отладочной информации (F3)
+	contacts  []canvas.DebugContact // Контакты с платформами за последний шаг
 }
 
 // NewGame создает новую игру с начальными параметрами
@@ -86,85 +273,587 @@ func NewGame() *Game {
 
 // NewGameWithOptions создает новую игру с заданными опциями.
 func NewGameWithOptions(opts Options) (*Game, error) {
-	// Создаем персонажа в начальной позиции
-	player := entities.NewPlayer(100, 100)
+	var daily *dailyChallenge
+	if opts.Daily {
+		var err error
+		if daily, err = applyDaily(&opts, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	local := newPilot()
+	local.source = idleInput{}
+	gameInstance := &Game{
+		pilot:        local,
+		local:        local,
+		locals:       []*pilot{local},
+		level:        level.Default(),    // Встроенный уровень (заменяется загруженным из файла)
+		camera:       Camera{X: 0, Y: 0}, // Инициализируем камеру
+		rng:          newRand(opts.Seed),
+		enemyFire:    make([]*entities.Bullet, 0),
+		options:      opts,
+		daily:        daily,
+		timers:       timer.NewManager(),
+		grid:         physics.NewGrid(physics.DefaultCellSize),
+		triggers:     trigger.NewSet(),
+		console:      console.New(),
+		prevEmoteKey: -1,
+		metrics:      metrics.NewRecorder(),
+		gravityScale: 1,
+		content: budget.NewTracker(budget.Caps{
+			budget.KindBullets:   config.MaxBullets,
+			budget.KindParticles: config.MaxParticles,
+			budget.KindCorpses:   config.MaxCorpses,
+			budget.KindEffects:   config.MaxEffects,
+		}),
+		emoteCooldown: timer.NewCooldown(config.EmoteCooldownFrames),
+		enemyHits:     make(map[entities.ID]map[entities.ID]bool),
+		volume:        sound.DefaultVolume(),
+		scores:        leaderboard.NewClient(opts.LeaderboardURL, opts.LeaderboardSecret),
+		matchmaking:   matchmaking.NewClient(opts.MatchmakingURL),
+	}
+	gameInstance.camera.rng = gameInstance.rng
+	gameInstance.window = opts.Window
+	if gameInstance.window == nil {
+		gameInstance.window = noWindow{}
+	}
+	gameInstance.sounds = gameInstance.window
+	if opts.Level != nil {
+		gameInstance.level = opts.Level
+	}
+	gameInstance.nameFixed = opts.PlayerName != ""
+	if !gameInstance.nameFixed {
+		gameInstance.options.PlayerName = "player"
+	}
+	gameInstance.savePathFixed = opts.SavePath != ""
+	if !gameInstance.savePathFixed {
+		gameInstance.options.SavePath = save.DefaultQuickSave
+	}
+	if opts.LoadPath != "" {
+		state, err := save.Read(opts.LoadPath)
+		if err != nil {
+			return nil, err
+		}
+		// Без явного -level загружается уровень, на котором было сделано сохранение
+		if gameInstance.options.LevelPath == "" {
+			gameInstance.options.LevelPath = state.LevelPath
+		}
+		gameInstance.pendingLoad = &state
+	}
+	if opts.Mode == ModeHost {
+		if gameInstance.options.LevelPath == "" && len(opts.LevelRotation) > 0 {
+			gameInstance.options.LevelPath = opts.LevelRotation[0]
+		}
+		gameInstance.settings = matchSettings{FriendlyFire: opts.FriendlyFire}
+		gameInstance.setupMatch(opts.Match)
+	}
+	gameInstance.highScores = loadHighScores(opts.HighScorePath)
+	// Без Input персонаж стоит: так играет сервер без окна, а при просмотре
+	// записи окно не дает клавиатуру, потому что персонажем клиента управляет запись
+	if opts.Input != nil {
+		local.source = opts.Input
+	}
+	if opts.Bot {
+		// Боту не нужны ни окно, ни клавиатура, поэтому он играет и без окна
+		local.source = newBotInput(gameInstance, local)
+	}
+	if opts.SecondPlayer != "" {
+		if err := gameInstance.addSecondPlayer(opts.SecondPlayer); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Headless {
+		// Без окна нет ни профилей, ни клипов: нечего показывать и записывать
+		gameInstance.options.ClipSeconds = 0
+	} else {
+		gameInstance.loadProfiles()
+	}
+	if gameInstance.profile == nil && !opts.Headless {
+		// Без профилей скин и язык задаются только флагами
+		gameInstance.applySkin(opts.Skin)
+		gameInstance.applyLanguage(opts.Language)
+	}
+	gameInstance.world = world.NewRegistry(gameInstance.content)
 
-	// Создаем пустую карту (все платформы убраны)
-	platforms := createLevel()
+	gameInstance.registerTriggerHandlers()
+	gameInstance.registerConsoleCommands()
+	gameInstance.clips = newClipRecorder(gameInstance.options.ClipSeconds)
+	gameInstance.clipDone = make(chan struct{}, 1)
 
-	// Создаем NPC на карте
-	npcs := []*entities.NPC{
-		entities.NewNPC(500, config.WorldHeight-100, 40, 40), // NPC в центре карты
-		entities.NewNPC(600, config.WorldHeight-100, 40, 40), // NPC дальше
-		entities.NewNPC(650, config.WorldHeight-100, 40, 40), // NPC еще дальше
+	if opts.PprofAddr != "" {
+		if err := metrics.Serve(opts.PprofAddr, gameInstance.metrics); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Dev {
+		if err := assets.UseDirectory(opts.AssetDir); err != nil {
+			return nil, err
+		}
 	}
 
-	gameInstance := &Game{
-		player:              player,
-		platforms:           platforms,
-		bullets:             make([]*entities.Bullet, 0), // Инициализируем пустой список пуль
-		npcs:                npcs,                        // Добавляем NPC
-		camera:              Camera{X: 0, Y: 0},          // Инициализируем камеру
-		prevShootKeyPressed: false,                       // Инициализируем состояние клавиши стрельбы
-		enemyFire:           make([]*entities.Bullet, 0),
-		options:             opts,
-	}
-
-	if opts.Mode != ModeLocal {
-		manager, err := startNetwork(opts)
+	var manager *network.Manager
+	if opts.PlaybackPath != "" {
+		// Запись смотрится как сетевая игра клиента на уровне, который записал хост
+		var err error
+		manager, err = network.Playback(opts.PlaybackPath, network.Hello{Name: gameInstance.options.PlayerName, Color: defaultColor(ModeClient), Team: defaultTeam(ModeClient)})
 		if err != nil {
 			return nil, err
 		}
-		if manager != nil {
-			gameInstance.net = manager
-			gameInstance.remote = entities.NewPlayer(player.X, player.Y)
+		gameInstance.options.Mode = ModeClient
+		if host, ok := manager.Remote(); ok && gameInstance.options.LevelPath == "" {
+			gameInstance.options.LevelPath = host.Level
+		}
+	}
+
+	if opts.Mode == ModeClient && opts.RoomCode != "" {
+		// Адрес и уровень хоста нужны до загрузки уровня и подключения
+		if err := resolveRoom(gameInstance.matchmaking, &gameInstance.options); err != nil {
+			return nil, err
+		}
+	}
+
+	// Ресурсы загружаются в фоне, пока показывается экран загрузки
+	gameInstance.loader = gameInstance.createLoader()
+	gameInstance.loader.Start()
+	gameInstance.scene = sceneLoading
+
+	if gameInstance.options.Mode != ModeLocal {
+		if manager == nil {
+			var err error
+			manager, err = startNetwork(gameInstance.options)
+			if err != nil {
+				return nil, err
+			}
+		}
+		manager.SetConditions(opts.NetConditions)
+		if opts.Mode == ModeHost && opts.RecordPath != "" {
+			if err := manager.Record(opts.RecordPath); err != nil {
+				_ = manager.Close()
+				return nil, err
+			}
+		}
+		gameInstance.useNetwork(manager)
+		gameInstance.registerMatch()
+	}
+	if gameInstance.isHost() && opts.RconAddress != "" {
+		server, err := rcon.Listen(opts.RconAddress, opts.RconPassword)
+		if err != nil {
+			return nil, err
 		}
+		log.Printf("rcon: listening on %s", server.Addr())
+		gameInstance.rcon = server
 	}
 
 	return gameInstance, nil
 }
 
+// createLoader составляет список ресурсов, которые загружаются до главного меню
+func (g *Game) createLoader() *preload.Loader {
+	loader := preload.New()
+	g.window.Preload(loader)
+	g.addLevelTask(loader)
+
+	return loader
+}
+
+// addLevelTask добавляет в загрузку уровень из -level, если он задан
+func (g *Game) addLevelTask(loader *preload.Loader) {
+	path := g.options.LevelPath
+	if path == "" || g.options.Level != nil {
+		return
+	}
+	loader.Add(i18n.T("loading.level", path), func() error {
+		loaded, err := loadLevel(path)
+		if err != nil {
+			return err
+		}
+		// Уровень подменяется до завершения загрузки, а игровой цикл
+		// не читает его, пока загрузка не закончена
+		g.level = loaded
+		return nil
+	})
+}
+
+// loadLevel загружает уровень из файла path, а если такого файла нет - встроенный
+// уровень с тем же именем (например, "-level rooms" или "-level levels/rooms.json")
+func loadLevel(path string) (*level.Level, error) {
+	loaded, err := level.Load(path)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return loaded, err
+	}
+
+	data, embeddedErr := assets.GetLevel(strings.TrimSuffix(filepath.Base(path), ".json"))
+	if embeddedErr != nil {
+		// Сообщаем об исходной ошибке: пользователь указывал путь к файлу
+		return nil, err
+	}
+	return level.Parse(data)
+}
+
+// updateLoading ждет окончания фоновой загрузки и создает игровой мир
+func (g *Game) updateLoading() error {
+	if !g.loader.Done() {
+		return nil
+	}
+	if err := g.loader.Err(); err != nil {
+		return err
+	}
+
+	// Создаем персонажа, платформы, NPC и задание по загруженному уровню.
+	// Хост моделирует и персонажа клиента, а клиент только показывает его.
+	if g.isHost() {
+		g.opponent = newPilot()
+	}
+	g.resetLevel()
+	if g.options.Mode == ModeClient {
+		g.remote = entities.NewPlayer(g.player.X, g.player.Y)
+	}
+
+	// В локальном режиме игра начинается с главного меню,
+	// а в сетевых режимах - с лобби
+	g.scene = sceneMenu
+	if g.net != nil {
+		g.openLobby()
+	}
+
+	// Загруженное при запуске сохранение сразу продолжает игру
+	if state := g.pendingLoad; state != nil {
+		g.pendingLoad = nil
+		if err := g.restoreSave(*state); err != nil {
+			return err
+		}
+		g.startPlaying()
+	}
+	return nil
+}
+
+// newPlayer создает персонажа в точке появления spawn
+func newPlayer(spawn level.Point) *entities.Player {
+	player := entities.NewPlayer(spawn.X, spawn.Y)
+	player.GrantAirJumps(config.MaxAirJumps)
+	return player
+}
+
+// enterSpawnRoom входит в комнату, где находится игрок, и сразу наводит на него камеру
+func (g *Game) enterSpawnRoom() {
+	player := g.player
+	g.enterRoom(g.level.RoomAt(player.X+player.Width/2, player.Y+player.Height/2))
+	g.camera.Snap(player.X, player.Y)
+}
+
+// resetLevel возвращает уровень в начальное состояние: персонаж в точке появления,
+// без пуль, с новыми NPC и заданием. Сетевое соединение при этом не затрагивается.
+func (g *Game) resetLevel() {
+	g.local.reset(newPlayer(g.spawnPoint(g.local)))
+	g.prediction.steps = g.prediction.steps[:0]
+	if g.opponent != nil {
+		g.opponent.reset(newPlayer(g.spawnPoint(g.opponent)))
+		g.remote = g.opponent.player
+	}
+	if second := g.second(); second != nil {
+		// Второй игрок за этим компьютером показывается и следует камере так же, как второй персонаж по сети
+		spawn := g.spawnPoint(second)
+		spawn.X += config.SecondPlayerOffset
+		second.reset(newPlayer(spawn))
+		g.remote = second.player
+	}
+	g.buildPlatforms()
+
+	g.corpses = g.corpses[:0]
+	g.world.Clear()
+	g.enemyFire = g.enemyFire[:0]
+	g.enemyHits = make(map[entities.ID]map[entities.ID]bool)
+
+	g.timers.Clear()
+	g.createTriggers()
+	g.roomTransition = roomTransition{}
+	g.doorLocked = false
+	g.roomNPCs = make(map[int][]*entities.NPC) // NPC комнат создаются заново при первом входе
+
+	g.escort = createEscortMission(g.level) // Отмеченный в уровне NPC просит проводить его к выходу
+	g.levelComplete = false
+	g.resetEscape()
+	g.levelFrames = 0
+	g.resultSubmitted = false
+	g.paused = false
+	g.startSpeedrun()
+	g.respawn = respawnState{}
+	g.resetFlags() // В захвате флага персонажи начинают на базах своих команд
+
+	g.enterSpawnRoom()
+	g.resetStreaming()
+}
+
+// buildPlatforms создает платформы и их движения по данным уровня
+func (g *Game) buildPlatforms() {
+	g.platforms = createLevel(g.level)
+	g.grid.Rebuild(g.platforms)
+	g.createMovers()
+	g.resetWorldSync()
+}
+
+// useNetwork начинает сетевую игру через manager
+func (g *Game) useNetwork(manager *network.Manager) {
+	manager.SetReconnectWindow(time.Duration(g.options.ReconnectSeconds) * time.Second)
+	g.net = manager
+}
+
+// startNetwork запускает хост или подключается к нему; в рукопожатии
+// передается имя игрока (уже с учетом профиля), а хост добавляет свой уровень
 func startNetwork(opts Options) (*network.Manager, error) {
+	hello := network.Hello{Name: opts.PlayerName, Color: defaultColor(opts.Mode), Team: defaultTeam(opts.Mode)}
 	switch opts.Mode {
 	case ModeLocal, Mode(""):
 		return nil, nil
 	case ModeHost:
-		return network.Host(opts.Address)
+		hello.Level = opts.LevelPath
+		if opts.MaxPlayers > network.MaxPlayers {
+			return nil, fmt.Errorf("max players %d: this build supports up to %d", opts.MaxPlayers, network.MaxPlayers)
+		}
+		manager, err := network.Host(opts.Address, opts.WebSocketAddress, hello)
+		if err != nil {
+			return nil, err
+		}
+		manager.SetPassword(opts.Password)
+		manager.SetMaxPlayers(opts.MaxPlayers)
+		return manager, nil
 	case ModeClient:
-		return network.Join(opts.Address)
+		hello.Password = opts.Password
+		return network.Join(opts.Address, hello)
 	default:
 		return nil, fmt.Errorf("unknown game mode: %s", opts.Mode)
 	}
 }
 
-// createLevel создает пустую карту без платформ
-func createLevel() []*entities.Platform {
-	// Возвращаем пустой список платформ
-	// Оставляем только пол на всю ширину мира для того, чтобы персонаж не падал в бесконечность
-	platforms := make([]*entities.Platform, 0)
-	platforms = append(platforms, entities.NewPlatform(0, config.WorldHeight-60, config.WorldWidth, 1000))
+// createLevel создает платформы по данным уровня
+func createLevel(lvl *level.Level) []*entities.Platform {
+	platforms := make([]*entities.Platform, 0, len(lvl.Platforms))
+	for _, data := range lvl.Platforms {
+		platform := entities.NewPlatform(data.X, data.Y, data.Width, data.Height)
+		platform.Material = data.Material
+		platform.Conveyor = data.Conveyor
+		platforms = append(platforms, platform)
+	}
 	return platforms
 }
 
-// Update обновляет логику игры каждый кадр
-func (g *Game) Update() error {
-	// Обрабатываем ввод с клавиатуры
+// materialFriction — трение платформ из особых материалов
+var materialFriction = map[string]float64{
+	level.MaterialIce: config.IceFriction,
+	level.MaterialMud: config.MudFriction,
+}
+
+// surfaceFriction возвращает трение для объекта, стоящего на материале ground.
+// В воздухе и на обычных платформах используется fallback, а под скользким
+// дождем обычные платформы становятся мокрыми.
+func (g *Game) surfaceFriction(onGround bool, ground string, fallback float64) float64 {
+	if !onGround {
+		return fallback
+	}
+	if friction, ok := materialFriction[ground]; ok {
+		return friction
+	}
+	if w := g.level.RoomWeather(g.room); ground == level.MaterialDefault && w != nil && w.Kind == level.WeatherRain && w.Slippery {
+		return math.Max(fallback, config.WetFriction)
+	}
+	return fallback
+}
+
+// Update обновляет логику игры каждый кадр окна; elapsed - время с прошлого кадра.
+// Меню обрабатываются на каждом вызове, а игровой процесс продвигается
+// фиксированными шагами симуляции независимо от частоты кадров окна.
+func (g *Game) Update(elapsed time.Duration) error {
+	// Музыка играет и плавно переключается на всех экранах, M выключает звук
+	g.handleMute()
+	g.handleScreenshot()
+	g.pollClipDone()
+	if g.scene == scenePlaying {
+		g.handleClip()
+		g.updateConsole()
+	}
+	g.updateMusic()
+	g.updateRcon()
+
+	switch g.scene {
+	case sceneLoading:
+		return g.updateLoading()
+	case sceneMenu:
+		g.updateMenu()
+		return nil
+	case sceneLeaderboard:
+		g.updateLeaderboardScene()
+		return nil
+	case sceneHighScores:
+		g.updateHighScoreScene()
+		return nil
+	case sceneProfiles:
+		g.updateProfileScene()
+		return nil
+	case sceneOptions:
+		g.updateOptionsScene()
+		return nil
+	case sceneLobby:
+		g.updateLobby()
+		return nil
+	case sceneServers:
+		g.updateServerScene()
+		return nil
+	}
+
+	g.handleHotkeys()
+	err := g.Advance(elapsed)
+	g.metrics.Frame(metrics.Entities{
+		Platforms: len(g.platforms),
+		NPCs:      len(g.npcs),
+		Bullets:   len(g.bullets) + len(g.enemyFire),
+		Corpses:   len(g.corpses),
+		World:     g.world.Len(),
+	}, g.content)
+	return err
+}
+
+// Advance продвигает игровой процесс на прошедшее время elapsed,
+// выполняя нужное число фиксированных шагов симуляции.
+// Используется и в Update окна, и сервером без окна с собственной частотой тиков.
+func (g *Game) Advance(elapsed time.Duration) error {
+	for steps := g.clock.Advance(elapsed); steps > 0; steps-- {
+		if err := g.tick(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tick выполняет шаг симуляции и замеряет его время
+func (g *Game) tick() error {
+	start := time.Now()
+	err := g.step()
+	g.metrics.ObserveTick(time.Since(start))
+	return err
+}
+
+// step выполняет один фиксированный шаг симуляции
+func (g *Game) step() error {
+	// Пока открыта консоль, клавиатура занята вводом команды, поэтому локальная игра
+	// стоит. В сетевой игре соперник не ждет, и симуляция продолжается.
+	if g.console.Open() && g.net == nil {
+		return nil
+	}
+
+	// На паузе игровой процесс и таймер спидрана стоят
+	if g.paused {
+		return nil
+	}
+	g.input = g.local.source.Input()
+	if g.console.Open() || g.isDead(g.local) {
+		// В сетевой игре симуляция идет и с открытой консолью, но персонаж стоит.
+		// Погибший персонаж ждет появления и тоже не управляется.
+		g.input = NoInput
+	}
+	if g.profile != nil {
+		g.profile.Stats.PlayFrames++
+	}
+
+	// Во время перехода между комнатами игровой процесс заморожен
+	if g.updateRoomTransition() {
+		return nil
+	}
+
+	// Когда сетевой матч окончен, игровой процесс стоит, но сеть продолжает работать,
+	// чтобы игроки могли проголосовать за реванш
+	if g.updateMatch() {
+		return g.updateNetwork()
+	}
+
+	// Клиент не моделирует мир сам, а показывает снимки хоста
+	if g.options.Mode == ModeClient {
+		return g.stepClient()
+	}
+
+	// Продвигаем таймеры и перезарядки на один шаг
+	g.timers.Update()
+	g.tickCooldowns()
+
+	// Обрабатываем ввод управления персонажем
 	g.handleInput()
+	g.handleWeaponSwitch()
+	g.updateEmotes()
+
+	// Контакты прошлого шага больше не нужны отладочному слою
+	g.contacts = g.contacts[:0]
+
+	// Подгружаем части большого уровня вокруг камеры и персонажей
+	g.updateStreaming()
 
-	// Применяем гравитацию к персонажу
-	g.applyGravity()
+	// Двигаем платформы и зоны гибели, рушим рушащиеся платформы
+	// и переносим стоящего на платформе персонажа
+	g.updatePlatforms()
+	g.updateCrumbling()
+	g.updateKillZones()
+	g.carryPlayer()
 
-	// Обновляем позицию персонажа на основе скорости
-	g.updatePlayerPosition()
+	// Запоминаем, стоял ли персонаж на земле, чтобы услышать приземление
+	wasOnGround := g.player.OnGround
 
-	// Проверяем коллизии с платформами
-	g.checkCollisions()
+	// Применяем к персонажу гравитацию, скорость и столкновения с платформами
+	g.player.Update(entityContext{g})
+	if g.player.OnGround && !wasOnGround {
+		g.sounds.Play(sound.EffectLand)
+	}
 
 	// Обновляем все пули
 	g.updateBullets()
 
+	// Хост двигает персонажа клиента по его вводу, а второго игрока
+	// за этим компьютером - по его клавишам или геймпаду
+	g.stepOpponent()
+	g.stepLocals()
+
+	// Проверяем, не вошел ли игрок в дверь другой комнаты
+	g.checkDoors()
+
+	// Рассылаем события входа и выхода из триггеров уровня
+	g.updateTriggers()
+
+	// В кооперативе хост ждет у выхода обоих игроков
+	g.updateCoop()
+
+	// Обновляем NPC и задание сопровождения
+	g.handleInteraction()
+	g.updateEscort()
+	g.updateNPCs()
+	g.damagePilots()
+	g.damageNPCs()
+
+	// В захвате флага хост подбирает, возвращает и засчитывает флаги
+	g.updateCTF()
+
+	// Погибшие в сетевой игре персонажи ждут появления
+	g.updateRespawns()
+
+	// Хост сообщает клиенту об изменениях уровня
+	g.updateWorldSync()
+
+	// Обновляем прочие объекты мира
+	g.world.Update(entityContext{g})
+
+	// Считаем время прохождения уровня и отправляем результат по завершении
+	if g.levelComplete {
+		g.submitResults()
+		g.finishSpeedrun()
+	} else {
+		g.levelFrames++
+		if g.speedrun != nil {
+			g.speedrun.Tick()
+		}
+	}
+
 	// Обновляем камеру, чтобы она следовала за игроком
 	g.camera.Update(g.player.X, g.player.Y)
+	g.updateSplitScreen()
+	g.weather.Update()
+
+	// Во время побега камера едет сама, а уровень позади рушится
+	g.updateEscape()
 
 	// Синхронизируем состояние с удаленным игроком
 	if err := g.updateNetwork(); err != nil {
@@ -178,62 +867,252 @@ func (g *Game) Update() error {
 func (g *Game) handleInput() {
 	player := g.player
 
+	// Оглушенный персонаж не управляется: он летит по инерции от удара,
+	// а на земле постепенно останавливается из-за трения
+	if player.IsStunned() {
+		player.StunFrames--
+		if player.OnGround {
+			player.VelocityX *= g.surfaceFriction(player.OnGround, player.Ground, config.Friction)
+		}
+		player.Sprinting = false
+		return
+	}
+
+	// Проверяем нажатие клавиши рывка (Shift)
+	g.handleDash()
+
+	// Проверяем нажатие клавиши приседания (стрелка вниз или S)
+	g.handleCrouch()
+
+	// Скорость движения (в приседе персонаж двигается медленнее)
+	moveSpeed := config.MoveSpeed
+	if player.Crouching {
+		moveSpeed *= config.CrouchSpeedMultiplier
+	}
+
 	// Проверяем нажатие клавиш движения влево/вправо
-	// ebiten.IsKeyPressed проверяет, нажата ли клавиша в данный момент
-	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) || ebiten.IsKeyPressed(ebiten.KeyA) {
-		// Движение влево - уменьшаем скорость по X
-		player.VelocityX = -config.MoveSpeed
-		player.FacingRight = false // Персонаж смотрит влево
-	} else if ebiten.IsKeyPressed(ebiten.KeyArrowRight) || ebiten.IsKeyPressed(ebiten.KeyD) {
-		// Движение вправо - увеличиваем скорость по X
-		player.VelocityX = config.MoveSpeed
-		player.FacingRight = true // Персонаж смотрит вправо
-	} else {
-		// Если клавиши не нажаты, применяем трение для замедления
-		player.VelocityX *= config.Friction
-		// Если скорость стала очень маленькой, останавливаем персонажа
-		if math.Abs(player.VelocityX) < 0.1 {
-			player.VelocityX = 0
+	// Во время рывка горизонтальная скорость задается самим рывком.
+	// Нажатие Shift дает рывок, а если продолжать держать Shift, персонаж переходит на бег.
+	if !player.IsDashing() {
+		sprintKeyPressed := g.input.Sprint && !player.Crouching
+
+		if g.input.Left {
+			// Движение влево - уменьшаем скорость по X
+			applyHorizontalInput(player, -1, moveSpeed, sprintKeyPressed)
+			player.FacingRight = false // Персонаж смотрит влево
+		} else if g.input.Right {
+			// Движение вправо - увеличиваем скорость по X
+			applyHorizontalInput(player, 1, moveSpeed, sprintKeyPressed)
+			player.FacingRight = true // Персонаж смотрит вправо
+		} else {
+			// Если клавиши не нажаты, применяем трение для замедления
+			// После бега персонаж тормозит дольше, чем после обычной ходьбы
+			friction := config.Friction
+			if math.Abs(player.VelocityX) > config.MoveSpeed {
+				friction = config.SprintFriction
+			}
+			// Лед и грязь заменяют обычное трение, пока персонаж стоит на них
+			player.VelocityX *= g.surfaceFriction(player.OnGround, player.Ground, friction)
+			player.Sprinting = false
+			// Если скорость стала очень маленькой, останавливаем персонажа
+			if math.Abs(player.VelocityX) < 0.1 {
+				player.VelocityX = 0
+			}
 		}
 	}
 
 	// Проверяем нажатие клавиши прыжка (пробел или стрелка вверх)
-	// Прыгать можно только если персонаж стоит на платформе
-	if (ebiten.IsKeyPressed(ebiten.KeySpace) || ebiten.IsKeyPressed(ebiten.KeyArrowUp) || ebiten.IsKeyPressed(ebiten.KeyW)) && player.OnGround {
+	// Прыгать можно, если персонаж стоит на платформе или только что сошел с нее
+	jumpKeyPressed := g.input.Jump
+	// Во время рывка и в приседе прыгать нельзя
+	canJump := !player.IsDashing() && !player.Crouching
+	// Прыжок всегда направлен против гравитации
+	gravity := player.GravityDirection()
+	if canJump && jumpKeyPressed && player.CanJump() {
 		// Применяем силу прыжка (отрицательное значение, так как Y растет вниз)
-		player.VelocityY = config.JumpStrength
+		player.VelocityY = config.JumpStrength * gravity
 		// Помечаем, что персонаж больше не на земле
 		player.OnGround = false
+		// Прыжок расходует "время койота", чтобы нельзя было прыгнуть повторно в воздухе
+		player.CoyoteFrames = 0
+		player.Jumping = true
+		g.sounds.Play(sound.EffectJump)
+	} else if canJump && jumpKeyPressed && !g.prevJumpKeyPressed && player.CanAirJump() {
+		// Прыжок в воздухе срабатывает только на новое нажатие,
+		// иначе удержание клавиши сразу израсходовало бы его после обычного прыжка
+		player.VelocityY = config.AirJumpStrength * gravity
+		player.AirJumps--
+		player.Jumping = true
+		g.sounds.Play(sound.EffectJump)
+	}
+	g.prevJumpKeyPressed = jumpKeyPressed
+
+	// Переменная высота прыжка: если клавишу отпустили, пока персонаж еще быстро
+	// летит вверх, обрезаем скорость до минимальной силы прыжка
+	if player.Jumping {
+		if !jumpKeyPressed && player.VelocityY*gravity < config.MinJumpStrength {
+			player.VelocityY = config.MinJumpStrength * gravity
+			player.Jumping = false
+		} else if player.VelocityY*gravity >= 0 {
+			// Персонаж начал падать - прыжок больше не укорачиваем
+			player.Jumping = false
+		}
 	}
 
 	// Проверяем нажатие клавиши стрельбы (J или Enter)
 	// Отслеживаем одноразовое нажатие, чтобы предотвратить непрерывную стрельбу
 	// Проверяем, нажата ли клавиша сейчас
-	shootKeyPressed := ebiten.IsKeyPressed(ebiten.KeyJ) || ebiten.IsKeyPressed(ebiten.KeyEnter)
+	shootKeyPressed := g.input.Shoot
 
 	// Если клавиша нажата сейчас, но не была нажата в предыдущем кадре,
-	// значит это новое нажатие - стреляем
-	if shootKeyPressed && !g.prevShootKeyPressed {
-		g.shoot() // Вызываем функцию стрельбы
+	// значит это новое нажатие - стреляем (если оружие перезарядилось)
+	if shootKeyPressed && !g.prevShootKeyPressed && g.weaponCooldown().Trigger() {
+		g.fire() // Стреляем из текущего оружия
 	}
 
 	// Сохраняем текущее состояние клавиши для следующего кадра
 	g.prevShootKeyPressed = shootKeyPressed
 }
 
+// applyHorizontalInput задает горизонтальную скорость при движении в направлении direction (-1 или 1).
+// Обычная ходьба сразу дает скорость moveSpeed, бег плавно разгоняет персонажа до SprintSpeed,
+// а после отпускания клавиши бега скорость плавно падает обратно до ходьбы.
+func applyHorizontalInput(player *entities.Player, direction, moveSpeed float64, sprinting bool) {
+	player.Sprinting = sprinting
+	speed := player.VelocityX * direction // Скорость в сторону движения
+
+	switch {
+	case sprinting:
+		// Разгон начинается со скорости ходьбы
+		if speed < moveSpeed {
+			speed = moveSpeed
+		}
+		speed = math.Min(speed+config.SprintAcceleration, config.SprintSpeed)
+	case speed > moveSpeed:
+		// Торможение после бега с отдельным трением
+		speed = math.Max(speed*config.SprintFriction, moveSpeed)
+	default:
+		speed = moveSpeed
+	}
+
+	player.VelocityX = speed * direction
+}
+
+// handleCrouch приседает, пока зажата клавиша вниз и персонаж стоит на земле,
+// и встает, когда клавишу отпустили и над головой есть место
+func (g *Game) handleCrouch() {
+	player := g.player
+
+	crouchKeyPressed := g.input.Crouch
+	if crouchKeyPressed && player.CanJump() && !player.IsDashing() {
+		player.Crouch()
+		return
+	}
+
+	if player.Crouching && g.canStandUp() {
+		player.Stand()
+	}
+}
+
+// canStandUp проверяет, не упрется ли присевший персонаж головой в платформу, если встанет
+func (g *Game) canStandUp() bool {
+	player := g.player
+	standing := *player
+	standing.Stand()
+
+	for _, platform := range g.platformsNear(standing.X, standing.Y, standing.Width, standing.Height) {
+		if physics.Overlaps(&standing, platform) {
+			return false
+		}
+	}
+	return true
+}
+
+// handleDash запускает рывок по нажатию Shift и отсчитывает его длительность
+func (g *Game) handleDash() {
+	player := g.player
+
+	// Отсчитываем активный рывок и кадры неуязвимости
+	if player.DashFrames > 0 {
+		player.DashFrames--
+		if player.DashFrames == 0 && player.VelocityX != 0 {
+			// Рывок закончился - возвращаемся к обычной скорости в направлении рывка.
+			// Во время рывка персонаж не разворачивается, поэтому направление берется
+			// из FacingRight: после удара о стену скорость равна +0 и знака не дает.
+			player.VelocityX = config.MoveSpeed
+			if !player.FacingRight {
+				player.VelocityX = -config.MoveSpeed
+			}
+		}
+	}
+	if player.InvulnerableFrames > 0 {
+		player.InvulnerableFrames--
+	}
+
+	dashKeyPressed := g.input.Dash
+
+	// Рывок срабатывает на новое нажатие, если он перезарядился
+	if dashKeyPressed && !g.prevDashKeyPressed && g.dashCooldown.Trigger() {
+		player.DashFrames = config.DashFrames
+		player.VelocityX = config.DashSpeed
+		if !player.FacingRight {
+			player.VelocityX = -config.DashSpeed
+		}
+		// Гравитация не действует во время рывка, поэтому обнуляем вертикальную скорость
+		player.VelocityY = 0
+		player.Jumping = false
+
+		if config.DashInvulnerable {
+			player.InvulnerableFrames = config.DashFrames
+		}
+	}
+
+	g.prevDashKeyPressed = dashKeyPressed
+}
+
+// handleHotkeys обрабатывает клавиши окна, которые не относятся к управлению
+// персонажем: сохранения, паузу, отладку и перезапуск сервера. Без окна
+// эти клавиши не читаются, а симуляция получает только Input.
+func (g *Game) handleHotkeys() {
+	// Пока открыта консоль, буквы набирают команду
+	if g.console.Open() {
+		return
+	}
+	// Таблица счета видна, пока зажата клавиша Tab
+	g.scoreboardOpen = g.window.Pressed(KeyTab)
+	// Быстрое сохранение и загрузка работают и на паузе
+	g.handleQuickSave()
+	g.handlePause()
+	g.handleRendererSwitch()
+	g.handleDebugToggle()
+	g.handleListenRetry()
+}
+
+// handleRendererSwitch переключает бэкенд отрисовки по клавише F2
+func (g *Game) handleRendererSwitch() {
+	rendererKeyPressed := g.window.Pressed(KeyF2)
+
+	if rendererKeyPressed && !g.prevRendererKeyPressed {
+		g.window.NextRenderer()
+	}
+
+	g.prevRendererKeyPressed = rendererKeyPressed
+}
+
 // applyGravity применяет гравитацию к персонажу
 func (g *Game) applyGravity() {
 	player := g.player
 
-	// Если персонаж не на земле, применяем гравитацию
-	if !player.OnGround {
-		// Увеличиваем скорость падения
-		player.VelocityY += config.Gravity
+	// Если персонаж не на земле и не выполняет рывок, применяем гравитацию
+	if !player.OnGround && !player.IsDashing() {
+		// Увеличиваем скорость падения (при перевернутой гравитации - вверх)
+		direction := player.GravityDirection()
+		player.VelocityY += config.Gravity * g.gravityScale * direction
 
 		// Ограничиваем максимальную скорость падения
 		// Это предотвращает слишком быстрое падение
-		if player.VelocityY > config.MaxFallSpeed {
-			player.VelocityY = config.MaxFallSpeed
+		if player.VelocityY*direction > config.MaxFallSpeed {
+			player.VelocityY = config.MaxFallSpeed * direction
 		}
 	}
 }
@@ -242,87 +1121,210 @@ func (g *Game) applyGravity() {
 func (g *Game) updatePlayerPosition() {
 	player := g.player
 
-	// Обновляем позицию по X (горизонтальное движение)
-	player.X += player.VelocityX
+	if physics.NeedsSweep(player, player.VelocityX, player.VelocityY) {
+		// На большой скорости персонаж мог бы проскочить тонкую платформу,
+		// поэтому двигаем его с непрерывной проверкой столкновений
+		g.sweepPlayer()
+	} else {
+		// Обновляем позицию по X (горизонтальное движение)
+		player.X += player.VelocityX
 
-	// Обновляем позицию по Y (вертикальное движение)
-	player.Y += player.VelocityY
+		// Обновляем позицию по Y (вертикальное движение)
+		player.Y += player.VelocityY
+	}
 
 	// Предотвращаем выход персонажа за границы мира по горизонтали
 	if player.X < 0 {
 		player.X = 0
 		player.VelocityX = 0
-	} else if player.X+config.PlayerWidth > config.WorldWidth {
-		player.X = config.WorldWidth - config.PlayerWidth
+	} else if player.X+player.Width > g.level.Width {
+		player.X = g.level.Width - player.Width
 		player.VelocityX = 0
 	}
 
-	// Если персонаж упал за нижнюю границу экрана, возвращаем его наверх
-	if player.Y > config.ScreenHeight {
-		player.Y = 100
-		player.X = 100
-		player.VelocityY = 0
-		player.VelocityX = 0
+	// Персонаж, упавший ниже плоскости гибели (или улетевший за верхнюю границу
+	// при перевернутой гравитации) или задевший зону гибели, погибает
+	if player.Y > g.level.DeathY() || (player.GravityFlipped && player.Y+player.Height < 0) || g.inKillZone(player) {
+		g.respawnPlayer()
 	}
 }
 
+// sweepPlayer сдвигает персонажа на его скорость за кадр, останавливая его у первой
+// платформы на пути. Скорость по оси удара обнуляется, а остаток пути персонаж
+// проходит вдоль поверхности (например, скользит по полу после приземления).
+func (g *Game) sweepPlayer() {
+	player := g.player
+	dx, dy := player.VelocityX, player.VelocityY
+
+	// Двух итераций достаточно: после удара движение остается только по одной оси
+	for i := 0; i < 2 && (dx != 0 || dy != 0); i++ {
+		nearest := physics.Contact{Time: 1}
+		hit := false
+		g.nearby = g.grid.QuerySwept(player.X, player.Y, player.Width, player.Height, dx, dy, g.nearby[:0])
+		for _, platform := range g.nearby {
+			if contact, ok := physics.Sweep(player, dx, dy, platform); ok && contact.Time < nearest.Time {
+				nearest = contact
+				hit = true
+			}
+		}
+
+		player.X += dx * nearest.Time
+		player.Y += dy * nearest.Time
+		if !hit {
+			return
+		}
+
+		g.recordContact(player.X+player.Width/2, player.Y+player.Height/2, nearest.NormalX, nearest.NormalY)
+
+		// Гасим движение в сторону платформы и продолжаем вдоль нее
+		remaining := 1 - nearest.Time
+		if nearest.NormalX != 0 {
+			player.VelocityX = 0
+			dx = 0
+			dy *= remaining
+		} else {
+			player.VelocityY = 0
+			dy = 0
+			dx *= remaining
+		}
+	}
+}
+
+// platformsNear возвращает платформы из ячеек сетки рядом с прямоугольником.
+// Результат действителен до следующего запроса к сетке.
+func (g *Game) platformsNear(x, y, width, height float64) []*entities.Platform {
+	// Небольшой запас нужен, чтобы учесть смещение объекта при разрешении столкновений
+	const margin = 1
+	g.nearby = g.grid.Query(x-margin, y-margin, width+2*margin, height+2*margin, g.nearby[:0])
+	return g.nearby
+}
+
+// raycast выпускает луч по миру: платформам рядом с лучом, игрокам и живым NPC.
+// Маска mask задает, какие слои объектов луч может задеть.
+func (g *Game) raycast(origin, direction physics.Vec, maxDist float64, mask physics.Layer) (physics.RayHit, bool) {
+	return g.raycastExcept(origin, direction, maxDist, mask, nil)
+}
+
+// raycastExcept работает как raycast, но пропускает объект except
+// (например, стрелка, из края хитбокса которого выходит луч)
+func (g *Game) raycastExcept(origin, direction physics.Vec, maxDist float64, mask physics.Layer, except entities.Collider) (physics.RayHit, bool) {
+	bodies := g.rayBodies[:0]
+	if mask&physics.LayerPlatform != 0 {
+		g.nearby = g.grid.QueryRay(origin, direction, maxDist, g.nearby[:0])
+		bodies = physics.PlatformBodies(g.nearby, bodies)
+	}
+	if mask&physics.LayerPlayer != 0 {
+		// Во время шага персонажа клиента g.player - это он же, поэтому свой персонаж берется из g.local
+		bodies = append(bodies, physics.Body{Collider: g.local.player, Layer: physics.LayerPlayer})
+		if g.remote != nil {
+			bodies = append(bodies, physics.Body{Collider: g.remote, Layer: physics.LayerPlayer})
+		}
+	}
+	if mask&physics.LayerNPC != 0 {
+		for _, npc := range g.npcs {
+			if !npc.IsDead() {
+				bodies = append(bodies, physics.Body{Collider: npc, Layer: physics.LayerNPC})
+			}
+		}
+	}
+	if except != nil {
+		kept := bodies[:0]
+		for _, body := range bodies {
+			if body.Collider != except {
+				kept = append(kept, body)
+			}
+		}
+		bodies = kept
+	}
+	g.rayBodies = bodies
+
+	return physics.Raycast(origin, direction, maxDist, mask, bodies)
+}
+
+// collisionSlop — допуск в пикселях, с которым персонаж считается стоявшим вплотную к грани платформы
+const collisionSlop = 0.01
+
 // checkCollisions проверяет столкновения персонажа с платформами
 func (g *Game) checkCollisions() {
 	player := g.player
 	player.OnGround = false // Предполагаем, что персонаж не на земле
+	player.Support = nil
+
+	// Где был верх персонажа до сдвига на скорость этого шага
+	prevTop := player.Y - player.VelocityY
 
-	// Проверяем каждую платформу
-	for _, platform := range g.platforms {
+	// Проверяем платформы рядом с персонажем
+	for _, platform := range g.platformsNear(player.X, player.Y, player.Width, player.Height) {
 		// Проверяем, пересекается ли персонаж с платформой
-		if physics.IsColliding(player, platform, config.PlayerWidth, config.PlayerHeight) {
-			// Вычисляем, с какой стороны произошло столкновение
-			// Это нужно для правильной обработки коллизий
-
-			// Вычисляем центр персонажа и платформы
-			playerCenterX := player.X + config.PlayerWidth/2
-			playerCenterY := player.Y + config.PlayerHeight/2
-			platformCenterX := platform.X + platform.Width/2
-			platformCenterY := platform.Y + platform.Height/2
-
-			// Вычисляем расстояния между центрами
-			dx := playerCenterX - platformCenterX
-			dy := playerCenterY - platformCenterY
-
-			// Вычисляем минимальное расстояние для разделения
-			minDistX := (config.PlayerWidth + platform.Width) / 2
-			minDistY := (config.PlayerHeight + platform.Height) / 2
-
-			// Определяем, с какой стороны произошло столкновение
-			overlapX := minDistX - math.Abs(dx)
-			overlapY := minDistY - math.Abs(dy)
-
-			// Если перекрытие по Y меньше, чем по X, значит столкновение вертикальное
-			if overlapY < overlapX {
+		if physics.Overlaps(player, platform) {
+			// Вычисляем, с какой стороны произошло столкновение:
+			// глубину перекрытия по осям и направление от центра платформы к персонажу
+			overlapX, overlapY, dx, dy := physics.Penetration(player, platform)
+
+			// Если перекрытие по Y меньше, чем по X, значит столкновение вертикальное.
+			// Вертикальное оно и тогда, когда до этого шага персонаж был целиком
+			// над платформой или под ней: он задел угол или стык соседних плиток
+			// и должен встать на платформу, а не упереться в ее бок.
+			crossedTop := prevTop+player.Height <= platform.Y+collisionSlop
+			crossedBottom := prevTop >= platform.Y+platform.Height-collisionSlop
+			if overlapY < overlapX || crossedTop || crossedBottom {
 				// Вертикальное столкновение
 				if dy < 0 {
 					// Персонаж сверху платформы - ставим его на платформу
-					player.Y = platform.Y - config.PlayerHeight
+					player.Y = platform.Y - player.Height
 					player.VelocityY = 0
-					player.OnGround = true
+					g.recordContact(player.X+player.Width/2, platform.Y, 0, -1)
 				} else {
 					// Персонаж снизу платформы - останавливаем движение вверх
 					player.Y = platform.Y + platform.Height
 					player.VelocityY = 0
+					g.recordContact(player.X+player.Width/2, platform.Y+platform.Height, 0, 1)
+				}
+
+				// Опорой служит сторона платформы, к которой тянет гравитация:
+				// обычно верхняя, а при перевернутой гравитации - нижняя
+				if (dy < 0) != player.GravityFlipped {
+					player.OnGround = true
+					player.Ground = platform.Material
+					player.Support = platform
 				}
 			} else {
 				// Горизонтальное столкновение
 				if dx < 0 {
 					// Персонаж слева от платформы
-					player.X = platform.X - config.PlayerWidth
+					player.X = platform.X - player.Width
 					player.VelocityX = 0
+					g.recordContact(platform.X, player.Y+player.Height/2, -1, 0)
 				} else {
 					// Персонаж справа от платформы
 					player.X = platform.X + platform.Width
 					player.VelocityX = 0
+					g.recordContact(platform.X+platform.Width, player.Y+player.Height/2, 1, 0)
 				}
 			}
 		}
 	}
+
+	// Персонаж, который стоит вплотную к платформе, тоже на земле: иначе
+	// движущаяся платформа или конвейер переносили бы его только через кадр
+	if !player.OnGround && player.VelocityY*player.GravityDirection() >= 0 {
+		if platform := g.groundUnder(player); platform != nil {
+			player.OnGround = true
+			player.Ground = platform.Material
+			player.Support = platform
+		}
+	}
+
+	// Обновляем "время койота"
+	if player.OnGround {
+		// На земле запас кадров всегда полный, а прыжки в воздухе восстанавливаются
+		player.CoyoteFrames = config.CoyoteTimeFrames
+		player.Land()
+	} else if player.CoyoteFrames > 0 {
+		// Персонаж сошел с края - отсчитываем оставшиеся кадры
+		// (после прыжка запас уже обнулен в handleInput)
+		player.CoyoteFrames--
+	}
 }
 
 // shoot создает новую пулю и добавляет ее в список пуль
@@ -333,11 +1335,11 @@ func (g *Game) shoot() {
 	// Пуля появляется в центре персонажа по вертикали
 	// И с края персонажа по горизонтали (в зависимости от направления взгляда)
 	var bulletX float64
-	bulletY := player.Y + config.PlayerHeight/2 - config.BulletHeight/2
+	bulletY := player.Y + player.Height/2 - config.BulletHeight/2
 
 	// Если персонаж смотрит вправо, пуля появляется справа от персонажа
 	if player.FacingRight {
-		bulletX = player.X + config.PlayerWidth
+		bulletX = player.X + player.Width
 	} else {
 		// Если персонаж смотрит влево, пуля появляется слева от персонажа
 		bulletX = player.X - config.BulletWidth
@@ -349,175 +1351,264 @@ func (g *Game) shoot() {
 		velocityX = -config.BulletSpeed
 	}
 
-	// Создаем новую пулю
+	// Создаем новую пулю; она может несколько раз отскочить от платформ
 	bullet := entities.NewBullet(bulletX, bulletY, velocityX, config.BulletWidth, config.BulletHeight)
+	bullet.Bounces = config.BulletRicochets
+	bullet.Pierce = config.BulletPierce
 
-	// Добавляем пулю в список активных пуль
-	g.bullets = append(g.bullets, bullet)
+	// Добавляем пулю в список активных пуль; при превышении лимита исчезают самые старые
+	g.bullets = budget.Apply(g.content, budget.KindBullets, append(g.bullets, bullet))
 }
 
-// updateBullets обновляет позиции всех пуль и удаляет те, что вышли за границы экрана
+// updateBullets продвигает все пули и удаляет те, что вышли за границы уровня
+// или застряли в платформе; при превышении лимита исчезают самые старые
 func (g *Game) updateBullets() {
-	// Создаем новый список для хранения активных пуль
-	activeBullets := make([]*entities.Bullet, 0)
-
-	// Проходим по всем пулям
-	for _, bullet := range g.bullets {
-		// Обновляем позицию пули на основе ее скорости
-		bullet.Update()
-
-		// Проверяем, не вышла ли пуля за границы мира
-		// Если пуля еще в мире, добавляем ее в список активных
-		if bullet.X > -config.BulletWidth && bullet.X < config.WorldWidth+config.BulletWidth {
-			// Проверяем коллизии пули с платформами
-			hitPlatform := false
-			for _, platform := range g.platforms {
-				if physics.IsBulletColliding(bullet, platform) {
-					// Если пуля попала в платформу, помечаем ее для удаления
-					hitPlatform = true
-					break
-				}
-			}
-
-			// Если пуля не попала в платформу, оставляем ее активной
-			if !hitPlatform {
-				activeBullets = append(activeBullets, bullet)
-			}
-		}
-		// Если пуля вышла за границы экрана или попала в платформу, она не добавляется в activeBullets
-		// и таким образом удаляется из игры
-	}
-
-	// Заменяем старый список пуль на новый (без удаленных пуль)
-	g.bullets = activeBullets
+	g.bullets = budget.Apply(g.content, budget.KindBullets, world.Step(entityContext{g}, g.bullets))
 }
 
-// updateNetwork синхронизирует состояние игры между игроками.
+// updateNetwork обменивается состоянием с соперником: хост рассылает снимок мира,
+// а клиент отправляет свой ввод и показывает последний снимок хоста.
 func (g *Game) updateNetwork() error {
 	if g.net == nil {
 		return nil
 	}
 
-	if state, ok := g.net.LatestState(); ok {
-		g.applyRemoteState(state)
+	g.flushMatchEvents()
+	for _, event := range g.net.PollEvents() {
+		g.handleNetworkEvent(event)
 	}
 
-	if err := g.net.Send(g.buildLocalState()); err != nil {
-		return err
+	if g.isHost() {
+		// Пули клиента рисуются так же, как пули соперника у клиента
+		g.enemyFire = g.opponent.bullets
+		g.simTick++
+		if g.snapshotDue() {
+			if err := g.net.SendSnapshot(g.buildSnapshot()); err != nil {
+				return err
+			}
+		}
+		g.hitboxes.record(g.simTick, g.local.player)
+	} else {
+		// Снимки могут прийти не по порядку (или повторно): устаревшие пропускаются
+		snapshots := g.net.PollSnapshots()
+		latest := -1
+		for i, snapshot := range snapshots {
+			if snapshot.Tick <= g.snapshotTick {
+				continue
+			}
+			g.snapshotTick = snapshot.Tick
+			g.remoteTrack.push(snapshot.Tick, snapshot.Host)
+			latest = i
+		}
+		if latest >= 0 {
+			g.applySnapshot(snapshots[latest])
+		}
+		g.updateRemote()
+		if err := g.net.SendInput(g.prediction.last); err != nil {
+			return err
+		}
 	}
 
+	// Соединение не восстановилось: игра продолжается без сети
 	if err := g.net.Err(); err != nil {
-		return err
+		g.goOffline(err)
 	}
 
 	return nil
 }
 
-func (g *Game) buildLocalState() network.StateMessage {
-	player := g.player
-
-	msg := network.StateMessage{
-		Player: network.PlayerState{
-			X:           player.X,
-			Y:           player.Y,
-			VelocityX:   player.VelocityX,
-			VelocityY:   player.VelocityY,
-			OnGround:    player.OnGround,
-			FacingRight: player.FacingRight,
-		},
-		Bullets: make([]network.BulletState, 0, len(g.bullets)),
+// handleNetworkEvent передает событие от удаленного игрока соответствующей подсистеме
+func (g *Game) handleNetworkEvent(event network.Event) {
+	switch event.Type {
+	case eventMatchOver, eventRematchVote, eventRematchStart:
+		g.handleMatchEvent(event)
+	case eventLobbyState, eventLobbyStart, eventMatchSettings:
+		g.handleLobbyEvent(event)
+	case eventEmote:
+		g.handleEmoteEvent(event)
+	case eventPlayerHit:
+		g.handlePlayerHitEvent(event)
+	case eventRifleShot:
+		g.handleRifleShotEvent(event)
+	case eventCoopExit, eventCoopStage:
+		g.handleCoopEvent(event)
+	case eventDeathmatch:
+		g.handleDeathmatchEvent(event)
+	case eventCTF:
+		g.handleCTFEvent(event)
+	case eventPlayerDied, eventPlayerRespawn:
+		g.handleRespawnEvent(event)
+	case eventWorld:
+		g.handleWorldEvent(event)
+	case eventServerChange:
+		g.handleServerChange(event)
 	}
+}
 
-	for _, bullet := range g.bullets {
-		msg.Bullets = append(msg.Bullets, network.BulletState{
-			X:         bullet.X,
-			Y:         bullet.Y,
-			VelocityX: bullet.VelocityX,
-		})
-	}
+// Draw отрисовывает все объекты игры на экране, записывает кадр клипа
+// и делает запрошенный снимок экрана
+func (g *Game) Draw(screen canvas.Canvas) {
+	start := time.Now()
+	defer func() { g.metrics.ObserveDraw(time.Since(start)) }()
 
-	return msg
+	g.drawScene(screen)
+	if g.scene == scenePlaying {
+		g.captureClipFrame(screen)
+	}
+	if g.screenshot.pending {
+		g.takeScreenshot(screen)
+	}
 }
 
-func (g *Game) applyRemoteState(state network.StateMessage) {
-	if g.remote == nil {
-		g.remote = entities.NewPlayer(state.Player.X, state.Player.Y)
+// drawScene отрисовывает активный экран: меню или игровой мир
+func (g *Game) drawScene(screen canvas.Canvas) {
+	switch g.scene {
+	case sceneLoading:
+		progress, current := g.loader.Progress()
+		screen.DrawLoading(progress, current)
+		return
+	case sceneMenu:
+		g.drawMenu(screen)
+		return
+	case sceneLeaderboard:
+		g.drawLeaderboardScene(screen)
+		return
+	case sceneHighScores:
+		g.drawHighScoreScene(screen)
+		return
+	case sceneProfiles:
+		g.drawProfileScene(screen)
+		return
+	case sceneOptions:
+		g.drawOptionsScene(screen)
+		return
+	case sceneLobby:
+		g.drawLobby(screen)
+		return
+	case sceneServers:
+		g.drawServerScene(screen)
+		return
 	}
 
-	g.remote.X = state.Player.X
-	g.remote.Y = state.Player.Y
-	g.remote.VelocityX = state.Player.VelocityX
-	g.remote.VelocityY = state.Player.VelocityY
-	g.remote.OnGround = state.Player.OnGround
-	g.remote.FacingRight = state.Player.FacingRight
-
-	if g.enemyFire == nil {
-		g.enemyFire = make([]*entities.Bullet, 0, len(state.Bullets))
+	// Мир рисуется через камеру, а при разделенном экране - в каждую половину через свою
+	queue := &g.drawQueue
+	if g.splitActive() {
+		g.drawSplitWorld(screen)
 	} else {
-		g.enemyFire = g.enemyFire[:0]
+		g.queueWorld(queue, g.camera.View())
 	}
 
-	for _, bullet := range state.Bullets {
-		g.enemyFire = append(g.enemyFire, entities.NewBullet(
-			bullet.X,
-			bullet.Y,
-			bullet.VelocityX,
-			config.BulletWidth,
-			config.BulletHeight,
-		))
+	// Интерфейс рисуется поверх мира в порядке добавления
+	for _, draw := range []canvas.DrawFunc{
+		g.drawEscort,                       // Реплика сопровождаемого NPC и итог задания
+		g.drawEscape,                       // Граница обрушения и итог побега
+		g.drawTriggerMessage,               // Сообщение сработавшего триггера
+		g.onFullScreen(g.drawNames),        // Имена игроков в сетевой игре
+		g.onFullScreen(g.drawEmotes),       // Эмоции игроков
+		g.drawMatch,                        // Счет и состояние сетевого матча
+		g.drawCoop,                         // Ожидание второго игрока у выхода в кооперативе
+		g.drawScoreboard,                   // Таблица счета дезматча (Tab)
+		g.drawRespawn,                      // Отсчет до появления погибшего персонажа
+		g.drawListenStatus,                 // Состояние ожидания второго игрока у хоста
+		g.drawReconnect,                    // Восстановление оборвавшегося соединения
+		g.drawConnection,                   // Пинг и качество соединения
+		g.drawMinimap,                      // Миникарта
+		g.onFullScreen(g.drawPhysicsDebug), // Отладочный слой физики
+		g.drawSpeedrun,                     // Таймер спидрана
+		g.drawPause,                        // Надпись паузы
+		g.drawDebugInfo,                    // Отладочная информация
+		g.drawConsole,                      // Отладочная консоль
+	} {
+		queue.Add(canvas.LayerUI, 0, draw)
 	}
+
+	// Снимок без интерфейса делается до того, как интерфейс будет нарисован
+	if g.screenshot.pending && g.screenshot.clean {
+		queue.FlushBelow(screen, canvas.LayerUI)
+		g.takeScreenshot(screen)
+	}
+	queue.Flush(screen)
 }
 
-// Draw отрисовывает все объекты игры на экране
-func (g *Game) Draw(screen *ebiten.Image) {
-	// Очищаем экран, заливая его цветом неба
-	screen.Fill(color.RGBA{R: 135, G: 206, B: 235, A: 255}) // Светло-голубой цвет
+// queueWorld добавляет в очередь отрисовку мира через вид view: фон, платформы,
+// персонажей, пули, объекты мира, осадки и темноту
+func (g *Game) queueWorld(queue *canvas.DrawQueue, view transform.View) {
+	// Очищаем экран, заливая его фоном текущего бэкенда отрисовки
+	queue.Add(canvas.LayerBackground, 0, func(screen canvas.Canvas) { screen.DrawBackground() })
 
-	// Рисуем все платформы с учетом позиции камеры
-	for _, platform := range g.platforms {
+	// Рисуем платформы, попадающие в видимую область
+	viewWidth, viewHeight := view.WorldSize()
+	for _, platform := range g.platformsNear(view.X, view.Y, viewWidth, viewHeight) {
 		// Проверяем, видна ли платформа на экране (оптимизация отрисовки)
-		if platform.X+platform.Width > g.camera.X && platform.X < g.camera.X+config.ScreenWidth {
-			renderer.DrawPlatformWithCamera(screen, platform, g.camera.X, g.camera.Y)
+		if view.Visible(platform.X, platform.Y, platform.Width, platform.Height) {
+			platform := platform
+			queue.Add(canvas.LayerTerrain, 0, func(screen canvas.Canvas) {
+				platform.Draw(screen, g.platformView(view, platform))
+			})
 		}
 	}
 
-	// Рисуем удаленного игрока и его пули, если он подключен
-	if g.remote != nil {
-		if g.remote.X+config.PlayerWidth > g.camera.X && g.remote.X < g.camera.X+config.ScreenWidth {
-			renderer.DrawPlayerWithCamera(screen, g.remote, g.camera.X, g.camera.Y)
-		}
-		for _, bullet := range g.enemyFire {
-			if bullet.X+bullet.Width > g.camera.X && bullet.X < g.camera.X+config.ScreenWidth {
-				renderer.DrawBulletWithCamera(screen, bullet, g.camera.X, g.camera.Y)
-			}
+	// Выход с уровня рисуется за персонажами
+	queue.Add(canvas.LayerTerrain, 1, g.drawExit)
+	queue.Add(canvas.LayerTerrain, 1, g.drawCTF)
+
+	// Рисуем всех видимых NPC, кроме погибших
+	for _, npc := range g.npcs {
+		if !npc.IsDead() {
+			world.QueueDraw(queue, canvas.LayerEntities, depthNPC, npc, view)
 		}
 	}
 
-	// Рисуем персонажа с учетом позиции камеры
-	renderer.DrawPlayerWithCamera(screen, g.player, g.camera.X, g.camera.Y)
+	// В сетевой игре персонажи окрашены в цвета игроков; цвета и команды
+	// меняются в лобби, поэтому оттенки задаются перед каждым кадром
+	localTint, remoteTint := g.playerTints()
+	g.player.Tint = localTint
 
-	// Рисуем все пули с учетом позиции камеры
-	for _, bullet := range g.bullets {
-		// Проверяем, видна ли пуля на экране (оптимизация отрисовки)
-		if bullet.X+bullet.Width > g.camera.X && bullet.X < g.camera.X+config.ScreenWidth {
-			renderer.DrawBulletWithCamera(screen, bullet, g.camera.X, g.camera.Y)
+	// Рисуем удаленного игрока и его пули, если он подключен
+	if g.remote != nil {
+		if !g.remoteDead() && view.Visible(g.remote.X, g.remote.Y, g.remote.Width, g.remote.Height) {
+			remote := g.remote
+			remote.Tint = remoteTint
+			queue.Add(canvas.LayerEntities, depthRemotePlayer, func(screen canvas.Canvas) {
+				remote.Draw(screen, view)
+				screen.DrawHealthBar(remote, view)
+			})
 		}
+		g.queueBullets(queue, view, g.enemyFire)
+	}
+	for _, p := range g.locals[1:] {
+		g.queueBullets(queue, view, p.bullets)
 	}
 
-	// Рисуем всех NPC с учетом позиции камеры
-	for _, npc := range g.npcs {
-		// Проверяем, виден ли NPC на экране (оптимизация отрисовки)
-		if npc.X+npc.Width > g.camera.X && npc.X < g.camera.X+config.ScreenWidth {
-			renderer.DrawNPCWithCamera(screen, npc, g.camera.X, g.camera.Y)
-		}
+	// Рисуем персонажа с учетом позиции камеры; погибший в сетевой игре не виден до появления
+	if !g.isDead(g.local) {
+		world.QueueDraw(queue, canvas.LayerEntities, depthPlayer, g.player, view)
 	}
 
-	// Выводим отладочную информацию
-	renderer.DrawDebugInfo(screen, g.player, len(g.bullets))
+	// Рисуем все пули с учетом позиции камеры
+	g.queueBullets(queue, view, g.bullets)
+
+	// Рисуем прочие объекты мира, каждый в своем слое
+	g.world.Draw(queue, view)
+
+	// Осадки идут поверх мира, но под темнотой
+	queue.Add(canvas.LayerParticles, 1, func(screen canvas.Canvas) { screen.DrawWeather(g.weather, view) })
+
+	// Темнота и источники света накладываются на весь мир, но не на интерфейс
+	queue.Add(canvas.LayerLighting, 0, g.drawLighting)
 }
 
-// Layout возвращает размеры игрового экрана
-// Эта функция требуется интерфейсом ebiten.Game
-func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return config.ScreenWidth, config.ScreenHeight
+// Глубина персонажей внутри слоя сущностей: персонаж игрока рисуется перед
+// вторым игроком, а оба - перед NPC
+const (
+	depthNPC          = 0
+	depthRemotePlayer = 1
+	depthPlayer       = 2
+)
+
+// queueBullets добавляет в очередь отрисовки видимые пули
+func (g *Game) queueBullets(queue *canvas.DrawQueue, view transform.View, bullets []*entities.Bullet) {
+	for _, bullet := range bullets {
+		world.QueueDraw(queue, canvas.LayerProjectiles, 0, bullet, view)
+	}
 }
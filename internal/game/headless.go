@@ -0,0 +1,60 @@
+package game
+
+import (
+	"log"
+	"time"
+)
+
+// StartHeadless готовит игру к работе без окна: дожидается загрузки уровня
+// и сразу начинает игровой процесс, минуя меню и лобби: хост без окна сам
+// отправляет команду начать каждому клиенту, зашедшему в лобби. Спрайты, шрифты и звук
+// в режиме Options.Headless не загружаются, а Draw не вызывается.
+func (g *Game) StartHeadless() error {
+	g.loader.Wait()
+	if err := g.updateLoading(); err != nil {
+		return err
+	}
+	if g.scene != scenePlaying {
+		g.startPlaying()
+	}
+	return nil
+}
+
+// Tick выполняет один шаг симуляции независимо от прошедшего времени.
+// Позволяет прогнать игру на заданное число шагов без окна (проверки в CI).
+func (g *Game) Tick() error {
+	return g.tick()
+}
+
+// RunHeadless запускает игру без окна (выделенный сервер, проверки в CI).
+// При ticks > 0 выполняет ровно столько шагов подряд, не дожидаясь реального времени;
+// при ticks == 0 работает в реальном времени с частотой ticksPerSecond, пока шаг
+// не вернет ошибку (например, при разрыве соединения).
+func (g *Game) RunHeadless(ticks int) error {
+	if err := g.StartHeadless(); err != nil {
+		return err
+	}
+
+	if ticks > 0 {
+		for i := 0; i < ticks; i++ {
+			if err := g.Tick(); err != nil {
+				return err
+			}
+		}
+		log.Printf("headless: %d ticks, player at (%.1f, %.1f), level frames %d, state hash %016x",
+			ticks, g.player.X, g.player.Y, g.levelFrames, g.StateHash())
+		return nil
+	}
+
+	ticker := time.NewTicker(time.Second / ticksPerSecond)
+	defer ticker.Stop()
+	last := time.Now()
+	for now := range ticker.C {
+		g.updateRcon()
+		if err := g.Advance(now.Sub(last)); err != nil {
+			return err
+		}
+		last = now
+	}
+	return nil
+}
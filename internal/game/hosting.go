@@ -0,0 +1,60 @@
+package game
+
+import (
+	"platformer/internal/canvas"
+	"platformer/internal/i18n"
+	"platformer/internal/network"
+)
+
+// handleListenRetry по клавише R вручную перезапускает listener хоста,
+// если он упал до подключения клиента
+func (g *Game) handleListenRetry() {
+	retryKeyPressed := g.window.Pressed(KeyR)
+
+	if retryKeyPressed && !g.prevRetryKeyPressed && g.isHost() {
+		g.net.Retry()
+	}
+
+	g.prevRetryKeyPressed = retryKeyPressed
+}
+
+// drawListenStatus выводит хосту состояние ожидания клиента
+func (g *Game) drawListenStatus(screen canvas.Canvas) {
+	// Пока хост ждет вернувшегося клиента, выводится drawReconnect
+	if !g.isHost() || g.net.Reconnect().Active {
+		return
+	}
+	if text, ok := g.listenStatusText(); ok {
+		screen.DrawNetworkStatus(text)
+	}
+}
+
+// listenStatusText возвращает текст состояния ожидания клиента;
+// false - клиент подключен и выводить нечего
+func (g *Game) listenStatusText() (string, bool) {
+	status := g.net.ListenStatus()
+	address := status.Address
+	if status.WebSocketAddress != "" {
+		address = i18n.T("host.address_ws", status.Address, status.WebSocketAddress)
+	}
+	if code := g.matchCode(); code != "" {
+		address = i18n.T("host.address_room", address, code)
+	}
+	switch status.State {
+	case network.ListenStarting:
+		return i18n.T("host.starting", address), true
+	case network.ListenListening:
+		if status.Err != nil {
+			// Подключался клиент другой версии
+			return i18n.T("host.rejected", address, status.Err), true
+		}
+		return i18n.T("host.waiting", address), true
+	case network.ListenRetrying:
+		return i18n.T("host.retrying",
+			status.Err, status.Attempt, status.MaxAttempts), true
+	case network.ListenFailed:
+		return i18n.T("host.failed", address, status.Err), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,46 @@
+package game
+
+// Input — состояние управления персонажем на одном шаге симуляции: какие клавиши
+// зажаты. Новые нажатия симуляция определяет сама, сравнивая с прошлым шагом.
+// Шаг читает ввод только отсюда, поэтому игра может работать без окна и клавиатуры.
+type Input struct {
+	Left         bool
+	Right        bool
+	Jump         bool
+	Crouch       bool
+	Sprint       bool
+	Dash         bool
+	Shoot        bool
+	Interact     bool
+	SwitchWeapon bool
+	Emote        int  // Индекс зажатой клавиши эмоции (-1 - ни одной)
+	VoteYes      bool // Согласие на реванш
+	VoteNo       bool // Отказ от реванша
+}
+
+// NoInput — ввод, в котором ничего не нажато
+var NoInput = Input{Emote: -1}
+
+// InputSource — источник управления персонажем: клавиатура или геймпад окна
+// (Options.Input), а без окна - сценарий или пустой ввод
+type InputSource interface {
+	// Input возвращает ввод для очередного шага симуляции
+	Input() Input
+}
+
+// Controls — чем управляет персонажем второй игрок за этим компьютером
+type Controls string
+
+const (
+	ControlsKeyboard Controls = "keys"    // Своя половина клавиатуры: стрелки, правый Shift, Enter
+	ControlsGamepad  Controls = "gamepad" // Первый подключенный геймпад
+	ControlsBot      Controls = "bot"     // Бот-соперник для тренировки (bot.go)
+)
+
+// idleInput — источник без нажатий (сервер без окна)
+type idleInput struct{}
+
+// Input возвращает пустой ввод
+func (idleInput) Input() Input {
+	return NoInput
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"math"
+	"time"
+
+	"platformer/internal/config"
+	"platformer/internal/network"
+)
+
+// Снимки приходят неравномерно: один задерживается, потом два приходят разом.
+// Если сразу показывать последний снимок, персонаж хоста у клиента дергается.
+// Поэтому клиент копит последние состояния персонажа хоста с номерами шагов хоста
+// и показывает его с небольшой задержкой, плавно переходя от одного состояния
+// к другому. Тогда у клиента почти всегда есть состояния до и после нужного момента.
+// Часы показа идут по синхронизированным часам хоста (network.ClockSync).
+
+// timedState — состояние персонажа хоста на шаге хоста tick
+type timedState struct {
+	tick  uint32
+	state network.PlayerState
+}
+
+// remoteTrack — буфер последних состояний персонажа хоста и часы показа
+type remoteTrack struct {
+	states []timedState // Состояния по возрастанию tick
+	clock  float64      // Показываемый шаг хоста (дробный)
+	ready  bool         // Часы показа уже запущены
+}
+
+// push добавляет состояние из снимка. Снимки приходят по порядку, но на всякий
+// случай устаревшие и повторные отбрасываются.
+func (t *remoteTrack) push(tick uint32, state network.PlayerState) {
+	if n := len(t.states); n > 0 && tick <= t.states[n-1].tick {
+		return
+	}
+	t.states = append(t.states, timedState{tick: tick, state: state})
+	if extra := len(t.states) - config.InterpolationBuffer; extra > 0 {
+		t.states = append(t.states[:0], t.states[extra:]...)
+	}
+}
+
+// advance сдвигает часы показа на один шаг. Часы идут на InterpolationDelay шагов
+// позади шага arrived, снимок которого сейчас должен приходить; небольшое
+// отставание или опережение выравнивается постепенно, а большое (например,
+// после долгого перерыва) - сразу.
+func (t *remoteTrack) advance(arrived float64) {
+	if len(t.states) == 0 {
+		return
+	}
+	target := arrived - config.InterpolationDelay
+	if !t.ready || math.Abs(target-t.clock) > config.InterpolationSnapSteps {
+		t.clock = target
+		t.ready = true
+		return
+	}
+	t.clock++
+	t.clock += (target - t.clock) * config.InterpolationCatchUp
+}
+
+// viewTick возвращает шаг хоста, на котором сейчас показывается его персонаж
+// (0 - показ еще не начался)
+func (t *remoteTrack) viewTick() uint32 {
+	if !t.ready || t.clock < 1 {
+		return 0
+	}
+	return uint32(math.Round(t.clock))
+}
+
+// sample возвращает состояние персонажа хоста на шаге часов показа. Положение
+// и скорость плавно переходят между соседними состояниями, а остальное берется
+// из более раннего из них. Если нужного момента в буфере нет, берется крайнее состояние.
+func (t *remoteTrack) sample() (network.PlayerState, bool) {
+	if len(t.states) == 0 {
+		return network.PlayerState{}, false
+	}
+
+	i := 0
+	for i < len(t.states) && float64(t.states[i].tick) <= t.clock {
+		i++
+	}
+	switch i {
+	case 0:
+		return t.states[0].state, true
+	case len(t.states):
+		return t.states[i-1].state, true
+	}
+
+	a, b := t.states[i-1], t.states[i]
+	// Телепорт (появление после гибели) не растягивается на путь через уровень
+	steps := float64(b.tick - a.tick)
+	if math.Hypot(b.state.X-a.state.X, b.state.Y-a.state.Y) > config.InterpolationTeleport*steps {
+		return a.state, true
+	}
+
+	k := (t.clock - float64(a.tick)) / steps
+	state := a.state
+	state.X = lerp(a.state.X, b.state.X, k)
+	state.Y = lerp(a.state.Y, b.state.Y, k)
+	state.VelocityX = lerp(a.state.VelocityX, b.state.VelocityX, k)
+	state.VelocityY = lerp(a.state.VelocityY, b.state.VelocityY, k)
+	return state, true
+}
+
+// lerp возвращает значение между a и b в доле k
+func lerp(a, b, k float64) float64 {
+	return a + (b-a)*k
+}
+
+// arrivingTick возвращает шаг хоста, снимок которого должен приходить клиенту
+// сейчас. По синхронизированным часам это шаг хоста половину пути назад: такая
+// оценка не дергается вместе с неравномерным приходом снимков. Пока часы
+// не синхронизированы, берется последний полученный снимок.
+func (g *Game) arrivingTick() float64 {
+	clock, ok := g.net.Clock()
+	if !ok {
+		return float64(g.snapshotTick)
+	}
+	elapsed := time.Since(clock.TickTime) - clock.RTT/2
+	return float64(clock.Tick) + elapsed.Seconds()*ticksPerSecond
+}
+
+// updateRemote показывает персонажа хоста у клиента с задержкой интерполяции
+func (g *Game) updateRemote() {
+	g.remoteTrack.advance(g.arrivingTick())
+	if state, ok := g.remoteTrack.sample(); ok {
+		applyPlayerState(g.remote, state)
+	}
+}
@@ -0,0 +1,103 @@
+package game
+
+import (
+	"errors"
+	"strings"
+
+	"platformer/internal/console"
+	"platformer/internal/i18n"
+)
+
+// registerKickCommands регистрирует команды хоста для отключения игроков
+func (g *Game) registerKickCommands() {
+	g.console.Register(console.Command{Name: "kick", Help: "console.kick.help", Run: g.cmdKick})
+	g.console.Register(console.Command{Name: "ban", Usage: "[address]", Help: "console.ban.help", Run: g.cmdBan})
+	g.console.Register(console.Command{Name: "unban", Usage: "<address>", Help: "console.unban.help", Run: g.cmdUnban})
+	g.console.Register(console.Command{Name: "bans", Help: "console.bans.help", Run: g.cmdBans})
+}
+
+// errHostOnly возвращается командами, доступными только хосту
+func errHostOnly() error {
+	return errors.New(i18n.T("console.host_only"))
+}
+
+// kickPlayer отключает второго игрока (хост)
+func (g *Game) kickPlayer() error {
+	if !g.isHost() {
+		return errHostOnly()
+	}
+	return g.net.Kick()
+}
+
+// banPlayer запрещает подключения с адреса второго игрока и отключает его (хост).
+// Возвращает запрещенный адрес.
+func (g *Game) banPlayer() (string, error) {
+	if !g.isHost() {
+		return "", errHostOnly()
+	}
+	address, ok := g.net.RemoteAddress()
+	if !ok {
+		if !g.net.Connected() {
+			return "", errors.New(i18n.T("console.no_player"))
+		}
+		// Через посредника адрес игрока неизвестен: можно только отключить
+		return "", errors.New(i18n.T("console.ban.no_address"))
+	}
+	return address, g.net.Ban(address)
+}
+
+// cmdKick отключает второго игрока
+func (g *Game) cmdKick(args []string) (string, error) {
+	if err := g.kickPlayer(); err != nil {
+		return "", err
+	}
+	return i18n.T("console.kicked"), nil
+}
+
+// cmdBan запрещает адрес второго игрока или указанный адрес
+func (g *Game) cmdBan(args []string) (string, error) {
+	switch len(args) {
+	case 0:
+		address, err := g.banPlayer()
+		if err != nil {
+			return "", err
+		}
+		return i18n.T("console.banned", address), nil
+	case 1:
+		if !g.isHost() {
+			return "", errHostOnly()
+		}
+		if err := g.net.Ban(args[0]); err != nil {
+			return "", err
+		}
+		return i18n.T("console.banned", args[0]), nil
+	default:
+		return "", errUsage("ban", "[address]")
+	}
+}
+
+// cmdUnban снимает запрет с адреса
+func (g *Game) cmdUnban(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errUsage("unban", "<address>")
+	}
+	if !g.isHost() {
+		return "", errHostOnly()
+	}
+	if !g.net.Unban(args[0]) {
+		return "", errors.New(i18n.T("console.not_banned", args[0]))
+	}
+	return i18n.T("console.unbanned", args[0]), nil
+}
+
+// cmdBans выводит запрещенные адреса
+func (g *Game) cmdBans(args []string) (string, error) {
+	if !g.isHost() {
+		return "", errHostOnly()
+	}
+	bans := g.net.Bans()
+	if len(bans) == 0 {
+		return i18n.T("console.bans.empty"), nil
+	}
+	return i18n.T("console.bans", strings.Join(bans, ", ")), nil
+}
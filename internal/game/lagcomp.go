@@ -0,0 +1,79 @@
+package game
+
+import (
+	"platformer/internal/config"
+	"platformer/internal/entities"
+)
+
+// Клиент видит персонажа хоста в прошлом: снимок идет до него по сети, а затем
+// еще ждет в буфере интерполяции. Если проверять выстрелы клиента по нынешнему
+// положению персонажа хоста, клиенту пришлось бы стрелять на упреждение.
+// Поэтому хост помнит, где его персонаж был на каждом отправленном шаге,
+// а клиент в каждом вводе сообщает, какой шаг хоста он сейчас видит. Попадания
+// выстрелов клиента хост проверяет по персонажу хоста, возвращенному в тот момент.
+
+// hitboxState — хитбокс персонажа хоста на отправленном шаге
+type hitboxState struct {
+	tick   uint32
+	bounds entities.AABB
+}
+
+// hitboxHistory — хитбоксы персонажа хоста на последних отправленных шагах
+type hitboxHistory struct {
+	states []hitboxState // По возрастанию tick
+}
+
+// record запоминает хитбокс персонажа на шаге tick, снимок которого отправлен
+func (h *hitboxHistory) record(tick uint32, player *entities.Player) {
+	if n := len(h.states); n > 0 && tick <= h.states[n-1].tick {
+		return
+	}
+	h.states = append(h.states, hitboxState{tick: tick, bounds: player.Bounds()})
+	if extra := len(h.states) - config.LagCompensationHistory; extra > 0 {
+		h.states = h.states[:copy(h.states, h.states[extra:])]
+	}
+}
+
+// at возвращает хитбокс персонажа на шаге tick. Слишком старый момент ограничивается
+// LagCompensationMaxSteps шагами: иначе клиент с огромной задержкой попадал бы
+// в соперника, который давно ушел с линии огня.
+func (h *hitboxHistory) at(tick uint32) (entities.AABB, bool) {
+	n := len(h.states)
+	if n == 0 || tick == 0 {
+		return entities.AABB{}, false
+	}
+	latest := h.states[n-1].tick
+	if latest-min(tick, latest) > config.LagCompensationMaxSteps {
+		tick = latest - config.LagCompensationMaxSteps
+	}
+	past := h.states[0]
+	for i := n - 1; i >= 0; i-- {
+		if h.states[i].tick <= tick {
+			past = h.states[i]
+			break
+		}
+	}
+	return past.bounds, true
+}
+
+// rewindHost на хосте возвращает персонажа хоста туда, где его видел клиент,
+// и возвращает функцию, которая ставит персонажа обратно. Для выстрелов самого
+// хоста (shooter - не клиент) ничего не делает. Урон наносится только после
+// возврата: при гибели персонаж появляется заново, и возврат не должен затереть
+// точку появления.
+func (g *Game) rewindHost(shooter *pilot) func() {
+	if g.opponent == nil || shooter != g.opponent {
+		return func() {}
+	}
+	past, ok := g.hitboxes.at(g.opponentViewTick)
+	if !ok {
+		return func() {}
+	}
+
+	player := g.local.player
+	x, y, width, height := player.X, player.Y, player.Width, player.Height
+	player.X, player.Y, player.Width, player.Height = past.X, past.Y, past.Width, past.Height
+	return func() {
+		player.X, player.Y, player.Width, player.Height = x, y, width, height
+	}
+}
@@ -0,0 +1,42 @@
+package game
+
+import (
+	"log"
+
+	"platformer/internal/i18n"
+)
+
+// applyLanguage переключает язык интерфейса; пустой или неизвестный код - язык по умолчанию
+func (g *Game) applyLanguage(lang string) {
+	if lang == "" {
+		lang = i18n.DefaultLanguage
+	}
+	if err := i18n.SetLanguage(lang); err != nil {
+		log.Printf("%v", err)
+		if err := i18n.SetLanguage(i18n.DefaultLanguage); err != nil {
+			log.Printf("%v", err)
+		}
+	}
+	g.window.SetTitle(i18n.T("window.title"))
+}
+
+// languageOption создает пункт меню настроек для выбора языка интерфейса
+func languageOption() optionItem {
+	return optionItem{
+		title: func(g *Game) string {
+			return i18n.T("options.language", i18n.Name(i18n.Language()))
+		},
+		change: func(g *Game, delta int) {
+			langs := i18n.Languages()
+			current := 0
+			for i, lang := range langs {
+				if lang == i18n.Language() {
+					current = i
+				}
+			}
+			// Выбор в настройках заменяет язык из флага запуска
+			g.options.Language = ""
+			g.applyLanguage(langs[(current+delta+len(langs))%len(langs)])
+		},
+	}
+}
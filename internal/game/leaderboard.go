@@ -0,0 +1,181 @@
+package game
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"platformer/internal/config"
+	"platformer/internal/highscore"
+	"platformer/internal/i18n"
+	"platformer/internal/leaderboard"
+)
+
+// leaderboardState хранит таблицы рекордов, загруженные в фоне для меню
+type leaderboardState struct {
+	mu      sync.Mutex
+	loading bool
+	err     error
+	boards  map[leaderboard.Board][]leaderboard.Entry
+}
+
+// snapshot возвращает копию загруженных данных для отрисовки
+func (s *leaderboardState) snapshot() (loading bool, boards map[leaderboard.Board][]leaderboard.Entry, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	boards = make(map[leaderboard.Board][]leaderboard.Entry, len(s.boards))
+	for board, entries := range s.boards {
+		boards[board] = entries
+	}
+	return s.loading, boards, s.err
+}
+
+// leaderboardBoards возвращает таблицы, которые показываются в меню
+func leaderboardBoards() []leaderboard.Board {
+	return []leaderboard.Board{
+		leaderboard.BoardTimeAttack,
+		leaderboard.DailyBoard(time.Now()),
+	}
+}
+
+// resultBoards возвращает таблицы, куда отправляется результат уровня:
+// в таблицу дня попадают только результаты испытания этого дня (daily.go)
+func (g *Game) resultBoards() []leaderboard.Board {
+	boards := []leaderboard.Board{leaderboard.BoardTimeAttack}
+	if g.daily != nil {
+		boards = append(boards, leaderboard.DailyBoard(g.daily.Day))
+	}
+	return boards
+}
+
+// refreshLeaderboard загружает таблицы рекордов в фоне, не блокируя игровой цикл
+func (g *Game) refreshLeaderboard() {
+	if g.scores == nil {
+		return
+	}
+
+	state := &g.leaderboard
+	state.mu.Lock()
+	if state.loading {
+		state.mu.Unlock()
+		return
+	}
+	state.loading = true
+	state.mu.Unlock()
+
+	client := g.scores
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		boards := make(map[leaderboard.Board][]leaderboard.Entry)
+		var fetchErr error
+		for _, board := range leaderboardBoards() {
+			entries, err := client.Top(ctx, board, 0)
+			if err != nil {
+				fetchErr = err
+				continue
+			}
+			boards[board] = entries
+		}
+
+		state.mu.Lock()
+		state.loading = false
+		state.err = fetchErr
+		state.boards = boards
+		state.mu.Unlock()
+	}()
+}
+
+// loadHighScores загружает локальную таблицу рекордов. Если файл недоступен,
+// игра продолжает работать без нее.
+func loadHighScores(path string) *highscore.Table {
+	if path == "" {
+		defaultPath, err := highscore.DefaultPath()
+		if err != nil {
+			log.Printf("high scores disabled: %v", err)
+			return nil
+		}
+		path = defaultPath
+	}
+
+	table, err := highscore.Load(path, config.HighScoreCount)
+	if err != nil {
+		// Испорченный файл не должен мешать игре: начинаем с пустой таблицы
+		log.Printf("high scores: %v", err)
+	}
+	return table
+}
+
+// submitResults записывает результат прохождения уровня в локальную таблицу
+// и отправляет его в онлайн-таблицы рекордов
+func (g *Game) submitResults() {
+	if g.resultSubmitted {
+		return
+	}
+	g.resultSubmitted = true
+
+	entry := leaderboard.Entry{
+		Name:   g.options.PlayerName,
+		Level:  g.level.Name,
+		Score:  g.levelScore(),
+		TimeMs: g.levelTime().Milliseconds(),
+	}
+	g.recordHighScore(entry)
+	g.recordLevelStats(entry.Score)
+
+	if g.scores == nil {
+		return
+	}
+
+	client := g.scores
+	boards := g.resultBoards()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		for _, board := range boards {
+			if err := client.Submit(ctx, board, entry); err != nil {
+				log.Printf("leaderboard: %v", err)
+			}
+		}
+	}()
+}
+
+// recordHighScore добавляет результат в локальную таблицу рекордов и сохраняет ее
+func (g *Game) recordHighScore(entry leaderboard.Entry) {
+	if g.highScores == nil {
+		return
+	}
+
+	rank := g.highScores.Add(entry)
+	if rank == 0 {
+		return
+	}
+	if err := g.highScores.Save(); err != nil {
+		log.Printf("high scores: %v", err)
+	}
+	g.showMessage(i18n.T("highscore.new", rank))
+}
+
+// levelTime возвращает время, потраченное на прохождение уровня
+func (g *Game) levelTime() time.Duration {
+	return time.Duration(g.levelFrames) * time.Second / ticksPerSecond
+}
+
+// levelScore вычисляет очки за уровень: здоровье сопровождаемого NPC плюс бонус за скорость
+func (g *Game) levelScore() int {
+	score := 0
+	if g.escort != nil {
+		score += g.escort.NPC.Health * 100
+	}
+
+	// Бонус уменьшается на 10 очков за каждую секунду прохождения
+	bonus := 10000 - int(g.levelTime().Seconds())*10
+	if bonus > 0 {
+		score += bonus
+	}
+	return score
+}
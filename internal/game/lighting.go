@@ -0,0 +1,30 @@
+package game
+
+import (
+	"platformer/internal/canvas"
+	"platformer/internal/entities"
+	"platformer/internal/level"
+)
+
+// drawLighting затемняет темную комнату и высвечивает источники света уровня
+// и свет, который несут персонажи игроков
+func (g *Game) drawLighting(screen canvas.Canvas) {
+	lighting := g.level.RoomLighting(g.room)
+	if lighting == nil {
+		return
+	}
+	lights := append(make([]level.Light, 0, len(lighting.Lights)+2), lighting.Lights...)
+	if lighting.PlayerRadius > 0 {
+		for _, player := range []*entities.Player{g.player, g.remote} {
+			if player == nil {
+				continue
+			}
+			lights = append(lights, level.Light{
+				X:      player.X + player.Width/2,
+				Y:      player.Y + player.Height/2,
+				Radius: lighting.PlayerRadius,
+			})
+		}
+	}
+	screen.DrawLighting(g.camera.View(), lighting.Ambient, lights)
+}
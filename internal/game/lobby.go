@@ -0,0 +1,304 @@
+package game
+
+import (
+	"image/color"
+	"log"
+
+	"platformer/internal/canvas"
+	"platformer/internal/i18n"
+	"platformer/internal/network"
+)
+
+// В сетевой игре после загрузки уровня игроки попадают в лобби: там видно,
+// кто подключен, игроки выбирают цвет и команду и отмечают готовность, хост задает
+// правила матча (matchSettings), а затем начинает// матч, когда готовы оба. Клиент, подключившийся к уже идущему матчу
+// (например, к хосту без окна), сразу получает от хоста команду начать.
+
+// События лобби
+const (
+	eventLobbyState    network.EventType = "lobby_state"    // Цвет, команда и готовность игрока
+	eventLobbyStart    network.EventType = "lobby_start"    // Хост начинает матч
+	eventMatchSettings network.EventType = "match_settings" // Хост сообщает правила матча
+)
+
+// matchSettings — правила сетевой игры. Их задает хост, а клиент получает в лобби.
+type matchSettings struct {
+	FriendlyFire bool `json:"friendly_fire"` // Пули ранят игроков своей команды
+}
+
+// lobbyPayload — состояние игрока в лобби. Имя, начальные цвет и команда приходят в рукопожатии.
+type lobbyPayload struct {
+	Color int  `json:"color"` // Индекс в canvas.PlayerColors
+	Team  int  `json:"team"`  // Команда игрока (teams.go)
+	Ready bool `json:"ready"`
+}
+
+// lobby — состояние лобби сетевой игры
+type lobby struct {
+	local      lobbyPayload // Этот игрок
+	remote     lobbyPayload // Удаленный игрок
+	remoteSeen bool         // Состояние удаленного игрока получено после подключения
+	connected  bool         // Соединение было открыто на прошлом кадре
+}
+
+// defaultColor возвращает цвет игрока по умолчанию: хост берет первый цвет, клиент - второй
+func defaultColor(mode Mode) int {
+	if mode == ModeHost {
+		return 0
+	}
+	return 1
+}
+
+// openLobby открывает лобби
+func (g *Game) openLobby() {
+	g.lobby = lobby{local: lobbyPayload{Color: defaultColor(g.options.Mode), Team: defaultTeam(g.options.Mode)}}
+	g.scene = sceneLobby
+}
+
+// updateLobby обрабатывает события сети и клавиши лобби:
+// ←/→ - выбор цвета, T - смена команды, пробел - готовность; у хоста еще
+// F - огонь по своим и Enter - начало матча
+func (g *Game) updateLobby() {
+	for _, event := range g.net.PollEvents() {
+		g.handleNetworkEvent(event)
+	}
+	if err := g.net.Err(); err != nil {
+		g.goOffline(err)
+		g.scene = sceneMenu
+		return
+	}
+	if g.scene != sceneLobby {
+		// Хост уже начал матч
+		return
+	}
+
+	l := &g.lobby
+	connected := g.net.Connected()
+	if !connected {
+		l.remote, l.remoteSeen = lobbyPayload{}, false
+	}
+	changed := connected && !l.connected
+	l.connected = connected
+
+	if g.window.JustPressed(KeyArrowLeft) || g.window.JustPressed(KeyA) {
+		g.cycleLobbyColor(-1)
+		changed = true
+	}
+	if g.window.JustPressed(KeyArrowRight) || g.window.JustPressed(KeyD) {
+		g.cycleLobbyColor(1)
+		changed = true
+	}
+	if g.window.JustPressed(KeyT) {
+		g.cycleLobbyTeam()
+		changed = true
+	}
+	if g.window.JustPressed(KeySpace) {
+		l.local.Ready = !l.local.Ready
+		changed = true
+	}
+	if changed {
+		g.sendLobbyState()
+		g.net.SetHello(network.Hello{Name: g.options.PlayerName, Color: l.local.Color, Team: l.local.Team})
+	}
+
+	// Хост переключает огонь по своим и сообщает новые правила клиенту
+	if g.isHost() && g.window.JustPressed(KeyF) {
+		g.settings.FriendlyFire = !g.settings.FriendlyFire
+		g.sendMatchSettings()
+	}
+
+	// Хост может отключить второго игрока или запретить его адрес
+	if g.isHost() && l.remoteSeen {
+		if g.window.JustPressed(KeyK) {
+			if err := g.kickPlayer(); err != nil {
+				log.Printf("lobby: %v", err)
+			}
+		}
+		if g.window.JustPressed(KeyB) {
+			if _, err := g.banPlayer(); err != nil {
+				log.Printf("lobby: %v", err)
+				// Адрес неизвестен (игра через посредника): игрок хотя бы отключается
+				_ = g.kickPlayer()
+			}
+		}
+	}
+
+	if g.window.JustPressed(KeyEnter) && g.isHost() && g.lobbyReady() {
+		g.sendMatchEvent(eventLobbyStart, struct{}{})
+		g.startPlaying()
+	}
+}
+
+// cycleLobbyColor переключает цвет игрока на следующий (step = 1) или
+// предыдущий (step = -1), пропуская цвет удаленного игрока
+func (g *Game) cycleLobbyColor(step int) {
+	l := &g.lobby
+	n := len(canvas.PlayerColors)
+	connected := g.net.Connected()
+	for i := 0; i < n; i++ {
+		l.local.Color = ((l.local.Color+step)%n + n) % n
+		if !connected || l.local.Color != g.remoteColor() {
+			return
+		}
+	}
+}
+
+// remoteColor возвращает цвет удаленного игрока: выбранный в лобби,
+// а до первого состояния лобби - из рукопожатия
+func (g *Game) remoteColor() int {
+	if g.lobby.remoteSeen {
+		return g.lobby.remote.Color
+	}
+	if hello, ok := g.net.Remote(); ok {
+		return hello.Color
+	}
+	return defaultColor(ModeHost)
+}
+
+// playerTints возвращает оттенки персонажей этого и удаленного игрока.
+// Вне сетевой игры персонажи рисуются без оттенка, а в захвате флага - цветом команды.
+func (g *Game) playerTints() (local, remote color.Color) {
+	if g.second() != nil {
+		// Второй игрок за этим компьютером окрашен, чтобы игроки не путали персонажей
+		return nil, canvas.PlayerColor(1)
+	}
+	if !g.isVersus() {
+		return nil, nil
+	}
+	if g.isCTF() {
+		return canvas.TeamColor(g.lobby.local.Team), canvas.TeamColor(g.remoteTeam())
+	}
+	return canvas.PlayerColor(g.lobby.local.Color), canvas.PlayerColor(g.remoteColor())
+}
+
+// lobbyReady сообщает, подключен ли второй игрок и готовы ли оба.
+// Захват флага начинается, только когда игроки в разных командах.
+func (g *Game) lobbyReady() bool {
+	l := g.lobby
+	return l.connected && l.remoteSeen && l.local.Ready && l.remote.Ready && !g.sameTeamBlocked()
+}
+
+// sameTeamBlocked сообщает, что матч нельзя начать, потому что игроки в одной команде
+func (g *Game) sameTeamBlocked() bool {
+	return g.isCTF() && g.teammates()
+}
+
+// sendLobbyState отправляет удаленному игроку состояние этого игрока в лобби
+func (g *Game) sendLobbyState() {
+	if g.net.Connected() {
+		g.sendMatchEvent(eventLobbyState, g.lobby.local)
+	}
+}
+
+// sendMatchSettings отправляет клиенту правила матча
+func (g *Game) sendMatchSettings() {
+	if g.net.Connected() {
+		g.sendMatchEvent(eventMatchSettings, g.settings)
+	}
+}
+
+// sendModeState отправляет клиенту правила и состояние текущего вида сетевой игры
+func (g *Game) sendModeState() {
+	g.sendMatchSettings()
+	if g.isCoop() {
+		// Клиент загружает уровень кампании, на котором игра идет сейчас
+		g.sendCoopStage()
+	}
+	if g.isDeathmatch() {
+		g.sendDeathmatch()
+	}
+	if g.isCTF() {
+		g.sendCTF()
+	}
+	// Клиент видит уровень таким, каким он стал у хоста
+	g.sendWorld()
+}
+
+// handleLobbyEvent обрабатывает событие лобби от удаленного игрока
+func (g *Game) handleLobbyEvent(event network.Event) {
+	switch event.Type {
+	case eventLobbyState:
+		var payload lobbyPayload
+		if err := event.Decode(&payload); err != nil {
+			return
+		}
+		g.lobby.remote, g.lobby.remoteSeen = payload, true
+		if g.isHost() {
+			g.sendModeState()
+		}
+		if g.isHost() && g.scene == scenePlaying {
+			// Клиент подключился к уже идущему матчу
+			g.sendMatchEvent(eventLobbyStart, struct{}{})
+		}
+
+	case eventLobbyStart:
+		if !g.isHost() && g.scene == sceneLobby {
+			g.startPlaying()
+		}
+
+	case eventMatchSettings:
+		if g.isHost() {
+			return
+		}
+		var payload matchSettings
+		if err := event.Decode(&payload); err != nil {
+			return
+		}
+		g.settings = payload
+	}
+}
+
+// drawLobby рисует лобби: игроков, состояние подключения и подсказку
+func (g *Game) drawLobby(screen canvas.Canvas) {
+	l := g.lobby
+	local := canvas.LobbyPlayer{
+		Name: g.options.PlayerName, Color: l.local.Color, Ready: l.local.Ready,
+		Team: l.local.Team, TeamName: teamName(l.local.Team),
+		Host: g.isHost(), Local: true, Present: true,
+	}
+	hello, _ := g.net.Remote()
+	remote := canvas.LobbyPlayer{
+		Name: hello.Name, Color: g.remoteColor(), Ready: l.remote.Ready,
+		Team: g.remoteTeam(), TeamName: teamName(g.remoteTeam()),
+		Host: !g.isHost(), Present: l.remoteSeen,
+	}
+	players := []canvas.LobbyPlayer{local, remote}
+	if !g.isHost() {
+		players = []canvas.LobbyPlayer{remote, local}
+	}
+
+	hint := i18n.T("lobby.hint")
+	if g.isHost() {
+		hint = i18n.T("lobby.hint_host")
+	}
+	rules := i18n.T("lobby.friendly_fire_off")
+	if g.settings.FriendlyFire {
+		rules = i18n.T("lobby.friendly_fire_on")
+	}
+	screen.DrawLobby(players, rules, g.lobbyStatus(), hint)
+}
+
+// lobbyStatus возвращает строку состояния лобби
+func (g *Game) lobbyStatus() string {
+	if text, ok := g.reconnectText(); ok {
+		return text
+	}
+	if g.isHost() && !g.net.Connected() {
+		if text, ok := g.listenStatusText(); ok {
+			return text
+		}
+	}
+
+	switch {
+	case !g.lobby.remoteSeen:
+		return i18n.T("lobby.connecting")
+	case g.sameTeamBlocked():
+		return i18n.T("lobby.same_team")
+	case g.lobbyReady() && g.isHost():
+		return i18n.T("lobby.can_start")
+	case g.lobbyReady():
+		return i18n.T("lobby.waiting_host")
+	default:
+		return i18n.T("lobby.waiting_ready")
+	}
+}
@@ -0,0 +1,134 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"platformer/internal/i18n"
+	"platformer/internal/matchmaking"
+	"platformer/internal/network"
+)
+
+const (
+	maxCodeLength = 8                // Наибольшая длина вводимого кода комнаты
+	lookupTimeout = 10 * time.Second // Сколько ждать ответа сервера подбора
+)
+
+// lookupResult — результат поиска комнаты по коду
+type lookupResult struct {
+	room matchmaking.Room
+	err  error
+}
+
+// resolveRoom находит комнату opts.RoomCode на сервере подбора и подставляет
+// в опции клиента адрес хоста и, если уровень не задан явно, уровень хоста
+func resolveRoom(client *matchmaking.Client, opts *Options) error {
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+
+	room, err := client.Lookup(ctx, opts.RoomCode)
+	if err != nil {
+		return err
+	}
+	opts.Address = room.Address
+	if opts.LevelPath == "" {
+		opts.LevelPath = room.Level
+	}
+	return nil
+}
+
+// registerMatch регистрирует игру хоста на сервере подбора, если он задан
+func (g *Game) registerMatch() {
+	if !g.isHost() || g.matchmaking == nil {
+		return
+	}
+	g.matchRoom = g.matchmaking.Register(matchmaking.Room{
+		Name:    g.options.PlayerName,
+		Level:   g.options.LevelPath,
+		Address: g.net.ListenStatus().Address,
+	})
+}
+
+// closeMatch удаляет игру хоста с сервера подбора
+func (g *Game) closeMatch() {
+	_ = g.matchRoom.Close()
+	g.matchRoom = nil
+}
+
+// roomCode возвращает код комнаты хоста (пустой - комнаты нет или она еще не зарегистрирована)
+func (g *Game) matchCode() string {
+	if g.matchRoom == nil {
+		return ""
+	}
+	code, _ := g.matchRoom.Code()
+	return code
+}
+
+// updateCodeEntry принимает ввод кода комнаты в списке игр и по Enter ищет ее в фоне
+func (g *Game) updateCodeEntry() {
+	if g.codeLookup != nil {
+		select {
+		case result := <-g.codeLookup:
+			g.codeLookup = nil
+			if result.err != nil {
+				g.serverErr = result.err
+				return
+			}
+			g.codeEntry = false
+			g.joinServer(network.Server{
+				Name:    result.room.Name,
+				Level:   result.room.Level,
+				Address: result.room.Address,
+				Open:    true,
+			})
+		default:
+		}
+		if g.window.JustPressed(KeyEscape) {
+			// Ответ придет в буферизованный канал, который уже никто не читает
+			g.codeLookup = nil
+			g.codeEntry = false
+		}
+		return
+	}
+
+	g.codeBuffer = g.window.AppendChars(g.codeBuffer)
+	if len(g.codeBuffer) > maxCodeLength {
+		g.codeBuffer = g.codeBuffer[:maxCodeLength]
+	}
+	if g.window.JustPressed(KeyBackspace) && len(g.codeBuffer) > 0 {
+		g.codeBuffer = g.codeBuffer[:len(g.codeBuffer)-1]
+	}
+
+	if g.window.JustPressed(KeyEnter) && len(g.codeBuffer) > 0 {
+		g.serverErr = nil
+		client, code := g.matchmaking, string(g.codeBuffer)
+		results := make(chan lookupResult, 1)
+		g.codeLookup = results
+		// Запрос к серверу не должен останавливать игровой цикл
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+			defer cancel()
+			room, err := client.Lookup(ctx, code)
+			results <- lookupResult{room: room, err: err}
+		}()
+	}
+	if g.window.JustPressed(KeyEscape) {
+		g.codeEntry = false
+	}
+}
+
+// codeEntryText возвращает строку ввода кода комнаты с подсказкой
+func (g *Game) codeEntryText() string {
+	code := string(g.codeBuffer)
+	switch {
+	case g.codeLookup != nil:
+		return i18n.T("servers.code_searching", code)
+	case errors.Is(g.serverErr, matchmaking.ErrNotFound):
+		return i18n.T("servers.code_not_found", code)
+	case g.serverErr != nil:
+		return i18n.T("servers.code_error", code, g.serverErr)
+	default:
+		return i18n.T("servers.code_prompt", code)
+	}
+}
@@ -0,0 +1,165 @@
+package game
+
+import (
+	"platformer/internal/canvas"
+	"platformer/internal/i18n"
+	"platformer/internal/leaderboard"
+)
+
+// scene определяет, какой экран сейчас активен
+type scene int
+
+const (
+	sceneLoading     scene = iota // Экран загрузки ресурсов
+	sceneMenu                     // Главное меню
+	scenePlaying                  // Игровой процесс
+	sceneLeaderboard              // Таблица рекордов
+	sceneHighScores               // Локальная таблица рекордов
+	sceneProfiles                 // Выбор профиля
+	sceneOptions                  // Настройки
+	sceneLobby                    // Лобби сетевой игры
+	sceneServers                  // Список игр в локальной сети
+)
+
+// menuItem — пункт главного меню
+type menuItem struct {
+	title  string
+	action func(g *Game)
+}
+
+// mainMenuItems возвращает пункты главного меню. «Продолжить» есть, только если
+// у текущего профиля есть сохраненное прохождение.
+func (g *Game) mainMenuItems() []menuItem {
+	items := make([]menuItem, 0, 7)
+	if g.profile != nil && g.profile.HasSave() {
+		items = append(items, menuItem{title: i18n.T("menu.continue"), action: (*Game).continueGame})
+	}
+	items = append(items,
+		menuItem{title: i18n.T("menu.play"), action: (*Game).startPlaying},
+		menuItem{title: i18n.T("menu.join"), action: (*Game).openServers},
+	)
+	if g.profile != nil {
+		items = append(items, menuItem{title: i18n.T("menu.profile", g.profile.Name), action: (*Game).openProfiles})
+	}
+	return append(items,
+		menuItem{title: i18n.T("menu.options"), action: (*Game).openOptions},
+		menuItem{title: i18n.T("menu.high_scores"), action: (*Game).openHighScores},
+		menuItem{title: i18n.T("menu.leaderboard"), action: (*Game).openLeaderboard},
+	)
+}
+
+// updateMenu обрабатывает навигацию по главному меню
+func (g *Game) updateMenu() {
+	items := g.mainMenuItems()
+	// Набор пунктов меняется (например, появляется «Продолжить»)
+	if g.menuIndex >= len(items) {
+		g.menuIndex = 0
+	}
+
+	if g.window.JustPressed(KeyArrowDown) || g.window.JustPressed(KeyS) {
+		g.menuIndex = (g.menuIndex + 1) % len(items)
+	}
+	if g.window.JustPressed(KeyArrowUp) || g.window.JustPressed(KeyW) {
+		g.menuIndex = (g.menuIndex + len(items) - 1) % len(items)
+	}
+	if g.window.JustPressed(KeyEnter) || g.window.JustPressed(KeySpace) {
+		items[g.menuIndex].action(g)
+	}
+}
+
+// updateLeaderboardScene обрабатывает экран таблицы рекордов
+func (g *Game) updateLeaderboardScene() {
+	if g.window.JustPressed(KeyR) {
+		g.refreshLeaderboard()
+	}
+	if g.window.JustPressed(KeyEscape) || g.window.JustPressed(KeyEnter) {
+		g.scene = sceneMenu
+	}
+}
+
+// updateHighScoreScene обрабатывает экран локальной таблицы рекордов
+func (g *Game) updateHighScoreScene() {
+	if g.window.JustPressed(KeyEscape) || g.window.JustPressed(KeyEnter) {
+		g.scene = sceneMenu
+	}
+}
+
+// startPlaying переключает игру из меню в игровой процесс
+func (g *Game) startPlaying() {
+	g.scene = scenePlaying
+	g.clock.Reset()
+
+	// Клавиши подтверждения в меню совпадают с клавишами стрельбы и прыжка,
+	// поэтому считаем их уже нажатыми, чтобы не выстрелить в первом кадре
+	g.prevShootKeyPressed = true
+	g.prevJumpKeyPressed = true
+}
+
+// openLeaderboard открывает таблицу рекордов и запускает ее загрузку
+func (g *Game) openLeaderboard() {
+	g.scene = sceneLeaderboard
+	g.refreshLeaderboard()
+}
+
+// openHighScores открывает локальную таблицу рекордов
+func (g *Game) openHighScores() {
+	g.scene = sceneHighScores
+}
+
+// drawMenu рисует главное меню
+func (g *Game) drawMenu(screen canvas.Canvas) {
+	items := g.mainMenuItems()
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		titles = append(titles, item.title)
+	}
+	screen.DrawMenu(i18n.T("menu.title"), titles, g.menuIndex)
+}
+
+// drawLeaderboardScene рисует таблицу рекордов
+func (g *Game) drawLeaderboardScene(screen canvas.Canvas) {
+	if g.scores == nil {
+		screen.DrawLeaderboard(nil, i18n.T("leaderboard.disabled"))
+		return
+	}
+
+	loading, boards, err := g.leaderboard.snapshot()
+
+	sections := make([]canvas.LeaderboardSection, 0, len(boards))
+	for _, board := range leaderboardBoards() {
+		sections = append(sections, canvas.LeaderboardSection{
+			Title:   boardTitle(board),
+			Entries: boards[board],
+		})
+	}
+
+	status := i18n.T("leaderboard.hint")
+	switch {
+	case loading:
+		status = i18n.T("loading.title")
+	case err != nil:
+		status = i18n.T("leaderboard.error", err)
+	}
+	screen.DrawLeaderboard(sections, status)
+}
+
+// drawHighScoreScene рисует локальную таблицу рекордов
+func (g *Game) drawHighScoreScene(screen canvas.Canvas) {
+	if g.highScores == nil {
+		screen.DrawLeaderboard(nil, i18n.T("highscore.unavailable"))
+		return
+	}
+
+	screen.DrawLeaderboard([]canvas.LeaderboardSection{{
+		Title:   i18n.T("highscore.title"),
+		Entries: g.highScores.Entries(),
+	}}, i18n.T("hint.back"))
+}
+
+// boardTitle возвращает заголовок таблицы рекордов для меню
+func boardTitle(board leaderboard.Board) string {
+	if board == leaderboard.BoardTimeAttack {
+		return i18n.T("leaderboard.time_attack")
+	}
+	return i18n.T("leaderboard.daily", board)
+}
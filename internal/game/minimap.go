@@ -0,0 +1,35 @@
+package game
+
+import (
+	"platformer/internal/canvas"
+	"platformer/internal/entities"
+)
+
+// invalidateMinimap сбрасывает миникарту, чтобы она построилась заново
+// (при смене комнаты или изменении набора платформ)
+func (g *Game) invalidateMinimap() {
+	g.minimapRevision++
+}
+
+// drawMinimap рисует миникарту текущей комнаты с отметками игроков и NPC
+func (g *Game) drawMinimap(screen canvas.Canvas) {
+	// Движущиеся платформы рисуются поверх закешированных неподвижных каждый кадр
+	moving := make([]*entities.Platform, 0, len(g.movers))
+	for _, mover := range g.movers {
+		moving = append(moving, mover.platform)
+	}
+
+	markers := make([]canvas.MinimapMarker, 0, len(g.npcs)+2)
+	for _, npc := range g.npcs {
+		if !npc.IsDead() {
+			markers = append(markers, canvas.MinimapMarker{X: npc.X + npc.Width/2, Y: npc.Y + npc.Height/2, Kind: canvas.MarkerNPC})
+		}
+	}
+	if g.remote != nil {
+		markers = append(markers, canvas.MinimapMarker{X: g.remote.X + g.remote.Width/2, Y: g.remote.Y + g.remote.Height/2, Kind: canvas.MarkerRemote})
+	}
+	// Персонаж игрока рисуется последним, чтобы его отметку не закрывали другие
+	markers = append(markers, canvas.MinimapMarker{X: g.player.X + g.player.Width/2, Y: g.player.Y + g.player.Height/2, Kind: canvas.MarkerPlayer})
+
+	screen.DrawMinimap(g.minimapRevision, g.level.RoomBounds(g.room), g.platforms, moving, g.camera.View(), markers)
+}
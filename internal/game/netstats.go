@@ -0,0 +1,32 @@
+package game
+
+import (
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+)
+
+// connectionQuality оценивает соединение по худшему из показателей:
+// пингу, доле потерь и времени с последнего обновления
+func connectionQuality(pingMs, ageMs int, loss float64) canvas.ConnectionQuality {
+	switch {
+	case pingMs >= config.NetPingPoorMs || loss >= config.NetLossPoor || ageMs >= config.NetStalePoorMs:
+		return canvas.ConnectionPoor
+	case pingMs >= config.NetPingFairMs || loss >= config.NetLossFair || ageMs >= config.NetStaleFairMs:
+		return canvas.ConnectionFair
+	default:
+		return canvas.ConnectionGood
+	}
+}
+
+// drawConnection выводит качество соединения с удаленным игроком,
+// чтобы было видно, когда задержка вызвана сетью, а не игрой
+func (g *Game) drawConnection(screen canvas.Canvas) {
+	stats, ok := g.net.Stats()
+	if !ok {
+		return
+	}
+	pingMs := int(stats.Ping.Milliseconds())
+	ageMs := int(stats.LastUpdate.Milliseconds())
+	screen.DrawConnectionInfo(pingMs, int(stats.Loss*100+0.5), ageMs,
+		connectionQuality(pingMs, ageMs, stats.Loss))
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"fmt"
+	"math"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+	"platformer/internal/sound"
+)
+
+// optionItem — пункт меню настроек: подпись с текущим значением и его изменение
+// стрелками влево (delta = -1) и вправо (delta = 1)
+type optionItem struct {
+	title  func(g *Game) string
+	change func(g *Game, delta int)
+}
+
+// volumeOption создает пункт настройки громкости, хранящейся в поле value; name - ключ перевода подписи
+func volumeOption(name string, value func(v *sound.Volume) *float64) optionItem {
+	return optionItem{
+		title: func(g *Game) string {
+			return fmt.Sprintf("%s: %d%%", i18n.T(name), int(math.Round(*value(&g.volume)*100)))
+		},
+		change: func(g *Game, delta int) {
+			level := value(&g.volume)
+			*level = math.Round((*level+float64(delta)*config.VolumeStep)*100) / 100
+			*level = math.Max(0, math.Min(1, *level))
+		},
+	}
+}
+
+// optionItems возвращает пункты меню настроек
+func optionItems() []optionItem {
+	return []optionItem{
+		volumeOption("options.master", func(v *sound.Volume) *float64 { return &v.Master }),
+		volumeOption("options.music", func(v *sound.Volume) *float64 { return &v.Music }),
+		volumeOption("options.effects", func(v *sound.Volume) *float64 { return &v.Effects }),
+		{
+			title: func(g *Game) string {
+				if g.volume.Muted {
+					return i18n.T("options.sound_off")
+				}
+				return i18n.T("options.sound_on")
+			},
+			change: func(g *Game, delta int) { g.volume.Muted = !g.volume.Muted },
+		},
+		nameOption(),
+		skinOption(),
+		languageOption(),
+	}
+}
+
+// nameOption создает пункт меню настроек с именем игрока: изменение начинает
+// переименование текущего профиля, а новое имя видят и другие игроки по сети
+func nameOption() optionItem {
+	return optionItem{
+		title: func(g *Game) string {
+			if g.renaming {
+				return i18n.T("profile.rename_prompt", string(g.renameBuffer))
+			}
+			return i18n.T("options.name", g.options.PlayerName)
+		},
+		change: func(g *Game, delta int) {
+			for i, p := range g.profiles {
+				if p == g.profile {
+					g.profileIndex = i
+					g.renaming = true
+					g.renameBuffer = g.renameBuffer[:0]
+				}
+			}
+		},
+	}
+}
+
+// openOptions открывает меню настроек
+func (g *Game) openOptions() {
+	g.scene = sceneOptions
+	g.optionIndex = 0
+}
+
+// updateOptionsScene обрабатывает меню настроек. Изменения сразу слышны,
+// а при выходе из меню сохраняются в профиль.
+func (g *Game) updateOptionsScene() {
+	if g.renaming {
+		g.updateRename()
+		return
+	}
+	items := optionItems()
+
+	if g.window.JustPressed(KeyArrowDown) || g.window.JustPressed(KeyS) {
+		g.optionIndex = (g.optionIndex + 1) % len(items)
+	}
+	if g.window.JustPressed(KeyArrowUp) || g.window.JustPressed(KeyW) {
+		g.optionIndex = (g.optionIndex + len(items) - 1) % len(items)
+	}
+
+	delta := 0
+	if g.window.JustPressed(KeyArrowLeft) || g.window.JustPressed(KeyA) {
+		delta = -1
+	}
+	if g.window.JustPressed(KeyArrowRight) || g.window.JustPressed(KeyD) ||
+		g.window.JustPressed(KeyEnter) {
+		delta = 1
+	}
+	if delta != 0 {
+		items[g.optionIndex].change(g, delta)
+		g.sounds.SetVolume(g.volume)
+	}
+
+	if g.window.JustPressed(KeyEscape) {
+		g.SaveProfile()
+		g.scene = sceneMenu
+	}
+}
+
+// handleMute включает и выключает звук по нажатию M на любом экране
+func (g *Game) handleMute() {
+	// Во время ввода имени профиля и команд консоли M - обычная буква
+	if g.renaming || g.console.Open() || !g.window.JustPressed(KeyM) {
+		return
+	}
+	g.volume.Muted = !g.volume.Muted
+	g.sounds.SetVolume(g.volume)
+}
+
+// drawOptionsScene рисует меню настроек
+func (g *Game) drawOptionsScene(screen canvas.Canvas) {
+	items := optionItems()
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		titles = append(titles, item.title(g))
+	}
+	hint := i18n.T("options.hint")
+	if g.renaming {
+		hint = i18n.T("profile.rename_hint")
+	}
+	screen.DrawMenuWithHint(i18n.T("options.title"), titles, g.optionIndex, hint)
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/timer"
+)
+
+// pilot — персонаж вместе со всем, что нужно для управления им: вводом, прошлым
+// состоянием клавиш, оружием, перезарядками и пулями. Game встраивает текущего
+// пилота, поэтому код управления (handleInput, fire, applyGravity, checkCollisions)
+// обращается к g.player и g.bullets и одинаково работает для персонажа этого игрока
+// и для персонажа клиента, которого моделирует авторитетный хост.
+type pilot struct {
+	player  *entities.Player   // Персонаж
+	bullets []*entities.Bullet // Активные пули персонажа
+	input   Input              // Ввод текущего шага симуляции
+	source  InputSource        // Откуда берется ввод игрока за этим компьютером (nil - ввод приходит по сети)
+
+	weapon        weapon         // Текущее оружие
+	shootCooldown timer.Cooldown // Перезарядка бластера
+	rifleCooldown timer.Cooldown // Перезарядка винтовки
+	dashCooldown  timer.Cooldown // Перезарядка рывка
+
+	// Предыдущее состояние клавиш для одноразовых нажатий
+	prevShootKeyPressed    bool
+	prevJumpKeyPressed     bool
+	prevDashKeyPressed     bool
+	prevInteractKeyPressed bool
+	prevWeaponKeyPressed   bool
+}
+
+// newPilot создает пилота без персонажа; персонаж появляется при запуске уровня
+func newPilot() *pilot {
+	return &pilot{
+		bullets:       make([]*entities.Bullet, 0),
+		input:         NoInput,
+		shootCooldown: timer.NewCooldown(config.ShootCooldownFrames),
+		rifleCooldown: timer.NewCooldown(config.RifleCooldownFrames),
+		dashCooldown:  timer.NewCooldown(config.DashCooldownFrames),
+	}
+}
+
+// reset ставит персонажа player и сбрасывает пули и перезарядки
+func (p *pilot) reset(player *entities.Player) {
+	p.player = player
+	p.bullets = p.bullets[:0]
+	p.shootCooldown.Reset()
+	p.rifleCooldown.Reset()
+	p.dashCooldown.Reset()
+}
+
+// tickCooldowns продвигает перезарядки на один шаг
+func (p *pilot) tickCooldowns() {
+	p.shootCooldown.Tick()
+	p.rifleCooldown.Tick()
+	p.dashCooldown.Tick()
+}
+
+// pilots возвращает всех пилотов, которых моделирует эта игра: игроков
+// за этим компьютером и персонажа клиента у хоста
+func (g *Game) pilots() []*pilot {
+	if g.opponent == nil {
+		return g.locals
+	}
+	return append(g.locals[:len(g.locals):len(g.locals)], g.opponent)
+}
+
+// addSecondPlayer добавляет второго игрока за этим компьютером с управлением
+// controls. Вдвоем за одним компьютером можно играть только в локальной игре;
+// бот во втором игроке - соперник для тренировки, а человек - союзник.
+func (g *Game) addSecondPlayer(controls Controls) error {
+	if g.options.Mode != ModeLocal && g.options.Mode != Mode("") {
+		return errors.New("second player: only available in local mode")
+	}
+	second := newPilot()
+	if controls == ControlsBot {
+		// Бот смотрит на мир сам и играет и без окна
+		second.source = newBotInput(g, second)
+		g.locals = append(g.locals, second)
+		return nil
+	}
+	if controls != ControlsKeyboard && controls != ControlsGamepad {
+		return fmt.Errorf("second player controls %q: use %q, %q or %q", controls, ControlsKeyboard, ControlsGamepad, ControlsBot)
+	}
+	// Клавиши или геймпад второго игрока дает окно; без окна второй игрок стоит
+	source := g.options.SecondInput
+	if source == nil {
+		source = idleInput{}
+	}
+	second.source = source
+	g.locals = append(g.locals, second)
+	return nil
+}
+
+// second возвращает второго игрока за этим компьютером (nil - играет один)
+func (g *Game) second() *pilot {
+	if len(g.locals) < 2 {
+		return nil
+	}
+	return g.locals[1]
+}
+
+// stepLocals выполняет шаг персонажей остальных игроков за этим компьютером
+// так же, как шаг персонажа первого игрока
+func (g *Game) stepLocals() {
+	for _, p := range g.locals[1:] {
+		p.input = p.source.Input()
+		if g.console.Open() {
+			p.input = NoInput
+		}
+		g.withPilot(p, func() {
+			p.tickCooldowns()
+			g.handleInput()
+			g.handleWeaponSwitch()
+			g.carryPlayer()
+			g.player.Update(entityContext{g})
+			g.updateBullets()
+		})
+	}
+}
+
+// withPilot выполняет fn, подставив p текущим пилотом, и возвращает прежнего
+func (g *Game) withPilot(p *pilot, fn func()) {
+	prev := g.pilot
+	g.pilot = p
+	defer func() { g.pilot = prev }()
+	fn()
+}
@@ -0,0 +1,131 @@
+package game
+
+import (
+	"platformer/internal/entities"
+	"platformer/internal/level"
+)
+
+// platformMover двигает платформу туда и обратно по данным уровня
+type platformMover struct {
+	platform         *entities.Platform
+	originX, originY float64 // Начальная позиция платформы
+	motion           level.Motion
+	frame            int // Сколько кадров платформа уже движется
+}
+
+// createMovers создает движения для платформ уровня, у которых задано Motion.
+// Платформы создаются из данных уровня в том же порядке, поэтому индексы совпадают.
+func (g *Game) createMovers() {
+	g.movers = g.movers[:0]
+	for i, data := range g.level.Platforms {
+		if data.Motion == nil || i >= len(g.platforms) {
+			continue
+		}
+		platform := g.platforms[i]
+		g.movers = append(g.movers, &platformMover{
+			platform: platform,
+			originX:  platform.X,
+			originY:  platform.Y,
+			motion:   *data.Motion,
+		})
+	}
+}
+
+// updatePlatforms двигает движущиеся платформы и запоминает их смещение за кадр
+func (g *Game) updatePlatforms() {
+	for _, mover := range g.movers {
+		platform := mover.platform
+		mover.frame++
+		progress := motionProgress(mover.motion, mover.frame)
+
+		x := mover.originX + mover.motion.DX*progress
+		y := mover.originY + mover.motion.DY*progress
+		platform.VelocityX = x - platform.X
+		platform.VelocityY = y - platform.Y
+		g.grid.Move(platform, x, y)
+	}
+}
+
+// motionProgress возвращает долю пути движения на кадре frame:
+// от 0 до 1 и обратно до 0 за 2*Frames кадров
+func motionProgress(motion level.Motion, frame int) float64 {
+	frames := motion.Frames
+	phase := frame % (2 * frames)
+	progress := float64(phase) / float64(frames)
+	if phase > frames {
+		progress = 2 - progress
+	}
+	return progress
+}
+
+// carryPlayer сдвигает стоящего на платформе персонажа вместе с ней,
+// а на конвейере - еще и на скорость ленты, чтобы он не соскальзывал
+func (g *Game) carryPlayer() {
+	player := g.player
+	support := player.Support
+	if !player.OnGround || support == nil {
+		return
+	}
+
+	player.X += support.VelocityX + support.Conveyor
+	player.Y += support.VelocityY
+}
+
+// groundUnder возвращает платформу, которой персонаж касается ногами, не пересекаясь с ней.
+// Так персонаж остается на опоре, даже если за кадр не «проваливается» в нее гравитацией.
+func (g *Game) groundUnder(player *entities.Player) *entities.Platform {
+	// Полоска высотой в пиксель под ногами (над головой при перевернутой гравитации)
+	probe := entities.AABB{X: player.X, Y: player.Bottom(), Width: player.Width, Height: 1}
+	if player.GravityFlipped {
+		probe.Y = player.Top() - 1
+	}
+
+	for _, platform := range g.platformsNear(probe.X, probe.Y, probe.Width, probe.Height) {
+		if probe.Intersects(platform.Bounds()) {
+			return platform
+		}
+	}
+	return nil
+}
+
+// updateCrumbling отсчитывает обрушение рушащихся платформ и убирает рухнувшие.
+// Отсчет ведет только хост: клиенту рухнувшие платформы присылает хост (worldsync.go).
+func (g *Game) updateCrumbling() {
+	ctx := entityContext{g}
+	for i := 0; i < len(g.platforms); {
+		platform := g.platforms[i]
+		if !platform.Update(ctx) {
+			g.removePlatform(platform)
+			continue
+		}
+		i++
+	}
+}
+
+// removePlatform убирает рухнувшую платформу из уровня
+func (g *Game) removePlatform(target *entities.Platform) {
+	for i, platform := range g.platforms {
+		if platform == target {
+			g.platforms = append(g.platforms[:i], g.platforms[i+1:]...)
+			g.grid.Remove(target)
+			g.stream.forgetPlatform(target)
+			g.removeMover(target)
+			g.invalidateMinimap()
+			if index := g.platformIndex(target); index >= 0 {
+				g.worldSync.removed = append(g.worldSync.removed, index)
+				g.worldChanged()
+			}
+			return
+		}
+	}
+}
+
+// removeMover прекращает движение платформы (например, когда она рухнула)
+func (g *Game) removeMover(platform *entities.Platform) {
+	for i, mover := range g.movers {
+		if mover.platform == platform {
+			g.movers = append(g.movers[:i], g.movers[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package game
+
+import (
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/network"
+)
+
+// Снимок хоста приходит с опозданием на время пути по сети, поэтому клиент
+// не ждет его, а сразу двигает своего персонажа по своему вводу так же, как это
+// сделает хост. Каждый ввод получает номер, а хост в снимке сообщает номер
+// последнего примененного ввода. Получив снимок, клиент ставит персонажа в
+// состояние из снимка и заново применяет вводы, до которых хост еще не дошел.
+// Если предсказание совпало с хостом, персонаж остается на месте, а если нет
+// (попадание, толчок) - поправляется без ожидания следующих снимков.
+
+// pilotState — сохраненное состояние пилота и его персонажа
+type pilotState struct {
+	pilot  pilot
+	player entities.Player
+}
+
+// save запоминает состояние пилота и персонажа
+func (p *pilot) save() pilotState {
+	return pilotState{pilot: *p, player: *p.player}
+}
+
+// restore возвращает пилота и персонажа в сохраненное состояние.
+// Персонаж остается тем же объектом, а пули не затрагиваются.
+func (p *pilot) restore(s pilotState) {
+	player, bullets := p.player, p.bullets
+	*p = s.pilot
+	*player = s.player
+	p.player, p.bullets = player, bullets
+}
+
+// predictedStep — ввод клиента, который хост еще не подтвердил,
+// и состояние пилота перед его применением
+type predictedStep struct {
+	seq    uint32
+	input  Input
+	before pilotState
+}
+
+// prediction — вводы клиента, отправленные хосту, но еще не примененные им
+type prediction struct {
+	seq   uint32               // Номер последнего ввода
+	steps []predictedStep      // Неподтвержденные вводы по возрастанию номера
+	last  network.InputMessage // Ввод текущего шага для отправки хосту
+}
+
+// predictInput запоминает ввод шага, сразу применяет его к персонажу клиента
+// и готовит сообщение для хоста
+func (g *Game) predictInput() {
+	pr := &g.prediction
+	pr.seq++
+	pr.steps = append(pr.steps, predictedStep{seq: pr.seq, input: g.input, before: g.local.save()})
+	if extra := len(pr.steps) - config.PredictionHistory; extra > 0 {
+		pr.steps = pr.steps[:copy(pr.steps, pr.steps[extra:])]
+	}
+
+	pr.last = inputMessage(g.input)
+	pr.last.Seq = pr.seq
+	pr.last.ViewTick = g.remoteTrack.viewTick()
+
+	input := g.input
+	g.predictStep(input)
+	g.input = input
+}
+
+// predictStep продвигает персонажа клиента на один шаг по вводу input так же,
+// как это делает хост в stepOpponent. Стреляет и переключает оружие только хост,
+// а платформы клиент двигает раз за шаг в stepClient, поэтому здесь предсказывается лишь движение.
+func (g *Game) predictStep(input Input) {
+	input.Shoot = false
+	g.input = input
+	g.tickCooldowns()
+	g.handleInput()
+	g.player.Update(entityContext{g})
+}
+
+// reconcile ставит персонажа клиента в состояние state из снимка, в котором хост
+// применил вводы до номера ack включительно, и повторяет более поздние вводы.
+// Скрытое состояние (рывок, время койота, перезарядки) берется из сохраненного
+// перед первым неподтвержденным вводом: хост моделирует его так же.
+func (g *Game) reconcile(state network.PlayerState, ack uint32) {
+	pr := &g.prediction
+	pending := 0
+	for pending < len(pr.steps) && pr.steps[pending].seq <= ack {
+		pending++
+	}
+	pr.steps = pr.steps[:copy(pr.steps, pr.steps[pending:])]
+
+	input := g.input
+	if len(pr.steps) > 0 {
+		g.local.restore(pr.steps[0].before)
+	}
+	applyPlayerState(g.player, state)
+
+	// Повтор уже прозвучал и посчитан при первом предсказании
+	sounds := g.sounds
+	g.sounds = silence{}
+	g.replaying = true
+	for i := range pr.steps {
+		pr.steps[i].before = g.local.save()
+		g.predictStep(pr.steps[i].input)
+	}
+	g.replaying = false
+	g.sounds = sounds
+	g.input = input
+}
@@ -0,0 +1,215 @@
+package game
+
+import (
+	"fmt"
+	"log"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+	"platformer/internal/profile"
+	"platformer/internal/save"
+	"platformer/internal/sound"
+)
+
+// loadProfiles загружает слоты профилей и выбирает слот из опций запуска.
+// Если каталог профилей недоступен, игра работает без профилей.
+func (g *Game) loadProfiles() {
+	root := g.options.ProfileDir
+	if root == "" {
+		defaultRoot, err := profile.DefaultRoot()
+		if err != nil {
+			log.Printf("profiles disabled: %v", err)
+			return
+		}
+		root = defaultRoot
+	}
+
+	profiles, err := profile.LoadAll(root, config.ProfileSlots)
+	if err != nil {
+		// Испорченный профиль заменяется новым, остальные слоты доступны
+		log.Printf("profiles: %v", err)
+	}
+	g.profiles = profiles
+
+	slot := g.options.Profile
+	if slot < 1 || slot > len(profiles) {
+		slot = 1
+	}
+	g.profileIndex = slot - 1
+	g.selectProfile(profiles[slot-1])
+}
+
+// selectProfile делает профиль текущим: его имя идет в таблицы рекордов,
+// сохранение - в быстрые сохранения, а настройки применяются к игре
+func (g *Game) selectProfile(p *profile.Profile) {
+	g.profile = p
+	if !g.nameFixed {
+		g.options.PlayerName = p.Name
+	}
+	if !g.savePathFixed {
+		g.options.SavePath = p.SavePath()
+	}
+
+	g.debugPage = debugPageByName(p.Settings.DebugPage)
+	g.volume = sound.Volume{
+		Master:  p.Settings.MasterVolume,
+		Music:   p.Settings.MusicVolume,
+		Effects: p.Settings.EffectsVolume,
+		Muted:   p.Settings.Muted,
+	}
+	g.sounds.SetVolume(g.volume)
+
+	skin := p.Settings.Skin
+	if g.options.Skin != "" {
+		skin = g.options.Skin
+	}
+	g.applySkin(skin)
+
+	lang := p.Settings.Language
+	if g.options.Language != "" {
+		lang = g.options.Language
+	}
+	g.applyLanguage(lang)
+
+	g.window.SetRenderer(p.Settings.Renderer)
+}
+
+// SaveProfile записывает текущие настройки и статистику профиля;
+// окно вызывает его и при закрытии
+func (g *Game) SaveProfile() {
+	p := g.profile
+	if p == nil {
+		return
+	}
+	p.Settings.Renderer = g.window.Renderer()
+	p.Settings.DebugPage = debugPageNames[g.debugPage]
+	p.Settings.MasterVolume = g.volume.Master
+	p.Settings.MusicVolume = g.volume.Music
+	p.Settings.EffectsVolume = g.volume.Effects
+	p.Settings.Muted = g.volume.Muted
+	p.Settings.Skin = g.skin
+	p.Settings.Language = i18n.Language()
+	if err := p.Save(); err != nil {
+		log.Printf("profile: %v", err)
+	}
+}
+
+// recordLevelStats добавляет в статистику профиля пройденный уровень
+func (g *Game) recordLevelStats(score int) {
+	if g.profile == nil {
+		return
+	}
+	stats := &g.profile.Stats
+	stats.LevelsCompleted++
+	stats.BestScore = max(stats.BestScore, score)
+	g.SaveProfile()
+}
+
+// continueGame загружает сохраненное прохождение текущего профиля
+func (g *Game) continueGame() {
+	state, err := save.Read(g.options.SavePath)
+	if err == nil {
+		err = g.restoreSave(state)
+	}
+	if err != nil {
+		log.Printf("continue: %v", err)
+		return
+	}
+	g.startPlaying()
+}
+
+// openProfiles открывает экран выбора профиля
+func (g *Game) openProfiles() {
+	g.scene = sceneProfiles
+	g.renaming = false
+}
+
+// updateProfileScene обрабатывает экран выбора профиля
+func (g *Game) updateProfileScene() {
+	if g.renaming {
+		g.updateRename()
+		return
+	}
+
+	count := len(g.profiles)
+	if g.window.JustPressed(KeyArrowDown) || g.window.JustPressed(KeyS) {
+		g.profileIndex = (g.profileIndex + 1) % count
+	}
+	if g.window.JustPressed(KeyArrowUp) || g.window.JustPressed(KeyW) {
+		g.profileIndex = (g.profileIndex + count - 1) % count
+	}
+	if g.window.JustPressed(KeyN) {
+		g.renaming = true
+		g.renameBuffer = g.renameBuffer[:0]
+		return
+	}
+	if g.window.JustPressed(KeyEnter) || g.window.JustPressed(KeySpace) {
+		selected := g.profiles[g.profileIndex]
+		if selected != g.profile {
+			// Прохождение в памяти принадлежит прежнему профилю: новый начинает уровень заново
+			g.SaveProfile()
+			g.selectProfile(selected)
+			g.resetLevel()
+		}
+		g.scene = sceneMenu
+		g.menuIndex = 0
+	}
+	if g.window.JustPressed(KeyEscape) {
+		g.scene = sceneMenu
+	}
+}
+
+// updateRename принимает ввод нового имени выбранного профиля
+func (g *Game) updateRename() {
+	g.renameBuffer = g.window.AppendChars(g.renameBuffer)
+	if len(g.renameBuffer) > profile.MaxNameLength {
+		g.renameBuffer = g.renameBuffer[:profile.MaxNameLength]
+	}
+	if g.window.JustPressed(KeyBackspace) && len(g.renameBuffer) > 0 {
+		g.renameBuffer = g.renameBuffer[:len(g.renameBuffer)-1]
+	}
+
+	if g.window.JustPressed(KeyEnter) {
+		p := g.profiles[g.profileIndex]
+		if p.Rename(string(g.renameBuffer)) {
+			if p == g.profile {
+				// Новое имя текущего профиля заменяет имя из флага запуска
+				g.options.PlayerName = p.Name
+				g.nameFixed = false
+			}
+			if err := p.Save(); err != nil {
+				log.Printf("profile: %v", err)
+			}
+		}
+		g.renaming = false
+	}
+	if g.window.JustPressed(KeyEscape) {
+		g.renaming = false
+	}
+}
+
+// drawProfileScene рисует список профилей с их статистикой
+func (g *Game) drawProfileScene(screen canvas.Canvas) {
+	items := make([]string, 0, len(g.profiles))
+	for i, p := range g.profiles {
+		name := p.Name
+		if g.renaming && i == g.profileIndex {
+			name = i18n.T("profile.rename_prompt", string(g.renameBuffer))
+		}
+		current := " "
+		if p == g.profile {
+			current = "*"
+		}
+		stats := p.Stats
+		items = append(items, fmt.Sprintf("%s %d. %-16s %s", current, p.Slot(), name,
+			i18n.T("profile.stats", stats.LevelsCompleted, stats.BestScore, stats.Deaths, stats.ShotsFired,
+				int(stats.PlayTime(simulationStep).Minutes()))))
+	}
+
+	hint := i18n.T("profile.hint")
+	if g.renaming {
+		hint = i18n.T("profile.rename_hint")
+	}
+	screen.DrawMenuWithHint(i18n.T("profile.title"), items, g.profileIndex, hint)
+}
@@ -0,0 +1,154 @@
+package game
+
+import (
+	"log"
+
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/network"
+	"platformer/internal/physics"
+)
+
+// eventPlayerHit — выстрел попал в одного из игроков.
+// Попадания определяет хост; здоровье и счет клиент получает в снимке мира,
+// а событие нужно только для эффектов: числа урона, отметки попадания и тряски камеры.
+const eventPlayerHit network.EventType = "player_hit"
+
+// playerHitPayload — данные события попадания
+type playerHitPayload struct {
+	Client bool `json:"client"` // Попали в персонажа клиента (иначе - в персонажа хоста)
+	Damage int  `json:"damage"` // Нанесенный урон (0 - игрок был неуязвим)
+	Killed bool `json:"killed"` // Игрок погиб и появился заново
+}
+
+// damagePilots на хосте наносит каждому персонажу урон от пуль соперника.
+// В кооперативе и у игроков одной команды (без огня по своим) пули союзника
+// пролетают сквозь персонажа. Без клиента соперником может быть бот второго
+// игрока за этим компьютером.
+func (g *Game) damagePilots() {
+	rival := g.opponent
+	if rival == nil {
+		rival = g.second()
+	}
+	if rival == nil || g.playersAllied() {
+		return
+	}
+	g.local.bullets = g.shootPilot(g.local, rival)
+	rival.bullets = g.shootPilot(rival, g.local)
+}
+
+// playersAllied сообщает, что персонажи игроков не ранят друг друга: в кооперативе,
+// у игроков одной команды без огня по своим и у двух людей за одним компьютером.
+// С ботом за этим компьютером тренируются, поэтому его пули ранят.
+func (g *Game) playersAllied() bool {
+	if second := g.second(); second != nil {
+		return !isBot(second)
+	}
+	return g.isCoop() || g.friendlyFireBlocked()
+}
+
+// shootPilot наносит персонажу victim урон от пуль стрелка shooter
+// и возвращает непопавшие пули
+func (g *Game) shootPilot(shooter, victim *pilot) []*entities.Bullet {
+	if g.isDead(victim) {
+		// Погибший персонаж ждет появления, и пули пролетают сквозь него
+		return shooter.bullets
+	}
+	remaining := shooter.bullets[:0]
+	hits := g.pilotHits[:0]
+
+	restore := g.rewindHost(shooter)
+	for _, bullet := range shooter.bullets {
+		if !physics.Overlaps(bullet, victim.player) {
+			remaining = append(remaining, bullet)
+			continue
+		}
+		// Пуля исчезает, даже если игрок неуязвим (например, во время рывка)
+		hitX, _ := bullet.Center()
+		hits = append(hits, hitX)
+	}
+	restore()
+
+	for _, hitX := range hits {
+		g.hitPilot(victim, config.BulletDamage, hitX)
+	}
+	g.pilotHits = hits
+	return remaining
+}
+
+// hitPilot на хосте наносит персонажу victim урон damage от выстрела со стороны sourceX,
+// засчитывает очко сопернику при гибели и сообщает клиенту о попадании
+func (g *Game) hitPilot(victim *pilot, damage int, sourceX float64) {
+	if g.playersAllied() || g.isDead(victim) {
+		// Союзники не ранят друг друга, а погибший ждет появления
+		return
+	}
+	player := victim.player
+	playerX, _ := player.Center()
+	if !player.TakeHit(entities.NewHit(damage, sourceX, playerX)) {
+		return
+	}
+
+	killed := player.Health <= 0
+	if killed {
+		// Очко получает соперник (в захвате флага очки дают только захваты),
+		// а игрок появляется заново с полным здоровьем после отсчета
+		switch {
+		case g.isCTF():
+		case victim == g.local:
+			g.match.remoteScore++
+		default:
+			g.match.localScore++
+		}
+		g.recordKill(victim)
+		g.withPilot(victim, g.respawnPlayer)
+		if !g.isVersus() {
+			// Вне сетевого матча отсчета нет: персонаж сразу появляется с полным здоровьем
+			player.Health = player.MaxHealth
+		}
+	}
+
+	payload := playerHitPayload{Client: victim != g.local, Damage: damage, Killed: killed}
+	g.showPlayerHit(payload)
+	g.sendPlayerHit(payload)
+}
+
+// showPlayerHit показывает эффекты попадания: пострадавший видит тряску камеры,
+// а стрелок - отметку на сопернике. Число урона видят оба. На хосте вызывается
+// и во время шага персонажа клиента, поэтому свой персонаж берется из g.local.
+func (g *Game) showPlayerHit(payload playerHitPayload) {
+	ownHit := payload.Client == (g.options.Mode == ModeClient)
+	if ownHit {
+		g.camera.Shake(config.CameraShakeStrength, config.CameraShakeFrames)
+		g.showDamage(g.local.player, payload.Damage)
+		return
+	}
+	if g.remote != nil {
+		centerX, centerY := g.remote.Center()
+		g.showHitMarker(centerX, centerY)
+		g.showDamage(g.remote, payload.Damage)
+	}
+}
+
+// sendPlayerHit сообщает клиенту о попадании
+func (g *Game) sendPlayerHit(payload playerHitPayload) {
+	if g.net == nil {
+		return
+	}
+	event, err := network.NewEvent(eventPlayerHit, payload)
+	if err == nil {
+		err = g.net.SendEvent(event)
+	}
+	if err != nil {
+		log.Printf("send %s: %v", eventPlayerHit, err)
+	}
+}
+
+// handlePlayerHitEvent показывает клиенту эффекты попадания, о котором сообщил хост
+func (g *Game) handlePlayerHitEvent(event network.Event) {
+	var payload playerHitPayload
+	if err := event.Decode(&payload); err != nil {
+		return
+	}
+	g.showPlayerHit(payload)
+}
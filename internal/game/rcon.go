@@ -0,0 +1,155 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"platformer/internal/console"
+	"platformer/internal/i18n"
+	"platformer/internal/level"
+	"platformer/internal/network"
+)
+
+// Хост с Options.RconAddress принимает администраторов по TCP (internal/rcon):
+// их команды выполняет та же консоль, что открывается клавишей `, поэтому
+// выделенным сервером без окна можно управлять удаленно. Для этого у хоста есть
+// команды players, level и mode: смена уровня или вида игры начинает матч заново
+// у обоих игроков, не разрывая соединения.
+
+// eventServerChange — хост сменил уровень или вид сетевой игры
+const eventServerChange network.EventType = "server_change"
+
+// serverChangePayload — уровень нового матча
+type serverChangePayload struct {
+	Level string `json:"level,omitempty"` // Уровень хоста (пустой - встроенный)
+	Match int    `json:"match"`           // Номер нового матча
+}
+
+// registerServerCommands регистрирует команды управления сервером (хост)
+func (g *Game) registerServerCommands() {
+	g.console.Register(console.Command{Name: "players", Help: "console.players.help", Run: g.cmdPlayers})
+	g.console.Register(console.Command{Name: "level", Usage: "<level>", Help: "console.level.help", Run: g.cmdLevel})
+	g.console.Register(console.Command{Name: "mode", Usage: "<versus|coop|deathmatch|ctf>", Help: "console.mode.help", Run: g.cmdMode})
+}
+
+// updateRcon выполняет команды, пришедшие по удаленному управлению
+func (g *Game) updateRcon() {
+	for _, request := range g.rcon.Poll() {
+		log.Printf("rcon: %s: %s", request.Remote, request.Line)
+		request.Reply(g.console.Run(request.Line))
+	}
+}
+
+// cmdPlayers выводит игроков сетевой игры
+func (g *Game) cmdPlayers(args []string) (string, error) {
+	if !g.isHost() {
+		return "", errHostOnly()
+	}
+	lines := []string{i18n.T("console.players.host", g.options.PlayerName, teamName(g.lobby.local.Team))}
+	hello, ok := g.net.Remote()
+	if !g.net.Connected() || !ok {
+		return strings.Join(append(lines, i18n.T("console.players.none")), "\n"), nil
+	}
+	address, ok := g.net.RemoteAddress()
+	if !ok {
+		address = i18n.T("console.players.relay")
+	}
+	stats, _ := g.net.Stats()
+	lines = append(lines, i18n.T("console.players.client", hello.Name, teamName(g.remoteTeam()), address, stats.Ping.Milliseconds()))
+	return strings.Join(lines, "\n"), nil
+}
+
+// cmdLevel начинает матч заново на другом уровне
+func (g *Game) cmdLevel(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errUsage("level", "<level>")
+	}
+	if !g.isHost() {
+		return "", errHostOnly()
+	}
+	if err := g.changeServer(args[0], g.options.Match); err != nil {
+		return "", err
+	}
+	return i18n.T("console.loaded", g.level.Name), nil
+}
+
+// cmdMode начинает матч заново в другом виде сетевой игры
+func (g *Game) cmdMode(args []string) (string, error) {
+	const usage = "<versus|coop|deathmatch|ctf>"
+	if len(args) != 1 {
+		return "", errUsage("mode", usage)
+	}
+	if !g.isHost() {
+		return "", errHostOnly()
+	}
+	match := MatchMode(strings.ToLower(args[0]))
+	switch match {
+	case MatchVersus, MatchCoop, MatchDeathmatch, MatchCTF:
+	default:
+		return "", errUsage("mode", usage)
+	}
+	if err := g.changeServer("", match); err != nil {
+		return "", err
+	}
+	return i18n.T("console.mode", match), nil
+}
+
+// changeServer на хосте переходит на уровень path (пустой - текущий) и вид
+// сетевой игры match и начинает новый матч у обоих игроков
+func (g *Game) changeServer(path string, match MatchMode) error {
+	if g.scene == sceneLoading {
+		return errors.New(i18n.T("console.loading"))
+	}
+	if path != "" {
+		loaded, err := loadLevel(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		g.level = loaded
+		g.options.LevelPath = path
+	}
+
+	g.setupMatch(match)
+	g.match = versusMatch{number: g.match.number + 1}
+	g.sendMatchEvent(eventServerChange, serverChangePayload{Level: g.options.LevelPath, Match: g.match.number})
+	if g.isCoop() {
+		// Кампания начинается с уровня, на котором сейчас хост
+		g.enterCoopStage(g.coop.stage)
+	} else {
+		g.resetLevel()
+	}
+	g.sendModeState()
+	log.Printf("server: level %q, match %s", g.options.LevelPath, match)
+	return nil
+}
+
+// handleServerChange начинает у клиента новый матч на уровне хоста.
+// Состояние нового вида игры хост присылает следом.
+func (g *Game) handleServerChange(event network.Event) {
+	if g.isHost() {
+		return
+	}
+	var payload serverChangePayload
+	if err := event.Decode(&payload); err != nil {
+		return
+	}
+	lvl := level.Default()
+	if payload.Level != "" {
+		loaded, err := loadLevel(payload.Level)
+		if err != nil {
+			// Без уровня хоста клиент остается на текущем
+			log.Printf("server: load %s: %v", payload.Level, err)
+			loaded = g.level
+		}
+		lvl = loaded
+	}
+
+	g.coop, g.deathmatch, g.ctf = coopCampaign{}, deathmatchState{}, ctfMatch{}
+	g.match = versusMatch{number: payload.Match}
+	g.level = lvl
+	g.options.LevelPath = payload.Level
+	g.worldSync.known = false
+	g.resetLevel()
+}
@@ -0,0 +1,71 @@
+package game
+
+import (
+	"errors"
+	"log"
+	"math"
+
+	"platformer/internal/canvas"
+	"platformer/internal/i18n"
+	"platformer/internal/network"
+)
+
+// drawReconnect выводит состояние восстановления оборвавшегося соединения
+func (g *Game) drawReconnect(screen canvas.Canvas) {
+	if text, ok := g.reconnectText(); ok {
+		screen.DrawNetworkStatus(text)
+	}
+}
+
+// reconnectText возвращает текст восстановления соединения: клиенту - попытки
+// переподключения, хосту - ожидание вернувшегося клиента; false - соединение
+// не восстанавливается
+func (g *Game) reconnectText() (string, bool) {
+	state := g.net.Reconnect()
+	if !state.Active {
+		return "", false
+	}
+
+	seconds := int(math.Ceil(state.Remaining.Seconds()))
+	if g.isHost() {
+		return i18n.T("net.peer_lost", seconds), true
+	}
+	return i18n.T("net.reconnecting", state.Attempt, seconds), true
+}
+
+// goOffline отключает сеть, когда соединение не удалось восстановить,
+// и продолжает игру локально вместо завершения
+func (g *Game) goOffline(err error) {
+	log.Printf("network: %v, continuing offline", err)
+
+	_ = g.net.Close()
+	g.net = nil
+	g.matchOutbox = nil
+	g.closeMatch()
+	g.options.Mode = ModeLocal
+	g.opponent = nil
+	g.remote = nil
+	g.coop = coopCampaign{}
+	g.deathmatch = deathmatchState{}
+	g.ctf = ctfMatch{}
+	g.respawn = respawnState{}
+	g.settings = matchSettings{}
+	g.worldSync.known = false
+	g.enemyFire = g.enemyFire[:0]
+	g.prediction = prediction{}
+
+	switch {
+	case errors.Is(err, network.ErrBanned):
+		g.showMessage(i18n.T("net.banned"))
+	case errors.Is(err, network.ErrKicked):
+		g.showMessage(i18n.T("net.kicked"))
+	case errors.Is(err, network.ErrWrongPassword):
+		g.showMessage(i18n.T("net.wrong_password"))
+	case errors.Is(err, network.ErrServerFull):
+		g.showMessage(i18n.T("net.server_full"))
+	case errors.Is(err, network.ErrPlaybackFinished):
+		g.showMessage(i18n.T("net.playback_finished"))
+	default:
+		g.showMessage(i18n.T("net.offline"))
+	}
+}
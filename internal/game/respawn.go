@@ -0,0 +1,213 @@
+package game
+
+import (
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/i18n"
+	"platformer/internal/level"
+	"platformer/internal/network"
+)
+
+// В сетевой игре погибший персонаж появляется не сразу: хост сообщает обоим
+// игрокам о гибели, и config.RespawnFrames кадров персонаж не виден, не управляется
+// и не получает урона. Затем хост ставит его в точку появления уровня, самую
+// дальнюю от соперника (точку появления его команды, а без них - начало уровня
+// или контрольную точку; в захвате флага - базу команды), и сообщает о появлении.
+// Отсчет до появления игроки видят у себя, а появляется персонаж только по команде хоста.
+
+// События гибели и появления персонажей
+const (
+	eventPlayerDied    network.EventType = "player_died"    // Хост сообщает о гибели персонажа
+	eventPlayerRespawn network.EventType = "player_respawn" // Хост сообщает о появлении персонажа
+)
+
+// respawnPayload — о каком персонаже событие
+type respawnPayload struct {
+	Client bool `json:"client"`           // Персонаж клиента (иначе - персонаж хоста)
+	Frames int  `json:"frames,omitempty"` // Сколько кадров осталось до появления (при гибели)
+}
+
+// deathTimer — отсчет до появления погибшего персонажа
+type deathTimer struct {
+	frames int         // Сколько кадров осталось до появления (0 - персонаж жив)
+	at     level.Point // Где персонаж погиб: хост держит его там до появления
+}
+
+// respawnState — отсчеты до появления персонажей хоста и клиента
+type respawnState struct {
+	host   deathTimer
+	client deathTimer
+}
+
+// deathTimer возвращает отсчет персонажа клиента (client) или хоста
+func (g *Game) deathTimer(client bool) *deathTimer {
+	if client {
+		return &g.respawn.client
+	}
+	return &g.respawn.host
+}
+
+// isClientPilot сообщает, управляет ли персонажем p игрок клиента
+func (g *Game) isClientPilot(p *pilot) bool {
+	return g.isHost() == (p != g.local)
+}
+
+// isDead сообщает, ждет ли персонаж p появления после гибели
+func (g *Game) isDead(p *pilot) bool {
+	return g.deathTimer(g.isClientPilot(p)).frames > 0
+}
+
+// remoteDead сообщает, ждет ли появления персонаж удаленного игрока
+func (g *Game) remoteDead() bool {
+	return g.deathTimer(g.isHost()).frames > 0
+}
+
+// killPilot на хосте начинает отсчет до появления погибшего персонажа текущего шага
+func (g *Game) killPilot() {
+	client := g.isClientPilot(g.pilot)
+	t := g.deathTimer(client)
+	t.frames, t.at = config.RespawnFrames, level.Point{X: g.player.X, Y: g.player.Y}
+	g.player.VelocityX, g.player.VelocityY = 0, 0
+
+	payload := respawnPayload{Client: client, Frames: t.frames}
+	g.showDeath(payload)
+	if g.net.Connected() {
+		g.sendMatchEvent(eventPlayerDied, payload)
+	}
+}
+
+// updateRespawns ведет отсчеты до появления. Хост держит погибших персонажей
+// на месте гибели и по окончании отсчета ставит их в точку появления;
+// клиент только показывает отсчет и ждет команды хоста.
+func (g *Game) updateRespawns() {
+	for _, client := range []bool{false, true} {
+		t := g.deathTimer(client)
+		if t.frames == 0 {
+			continue
+		}
+		if !g.isHost() {
+			if t.frames > 1 {
+				t.frames--
+			}
+			continue
+		}
+
+		p := g.local
+		if client {
+			p = g.opponent
+		}
+		t.frames--
+		if p == nil {
+			t.frames = 0
+			continue
+		}
+		if t.frames > 0 {
+			p.player.X, p.player.Y = t.at.X, t.at.Y
+			p.player.VelocityX, p.player.VelocityY = 0, 0
+			continue
+		}
+		g.withPilot(p, g.revivePilot)
+	}
+}
+
+// revivePilot на хосте ставит персонажа текущего шага в точку появления
+// с полным здоровьем и сообщает об этом клиенту
+func (g *Game) revivePilot() {
+	client := g.isClientPilot(g.pilot)
+	*g.deathTimer(client) = deathTimer{}
+	g.placePlayer(g.respawnPoint())
+	g.player.Health = g.player.MaxHealth
+	if g.net.Connected() {
+		g.sendMatchEvent(eventPlayerRespawn, respawnPayload{Client: client})
+	}
+}
+
+// spawnPoint возвращает точку, где персонаж p начинает уровень: в сетевой игре -
+// точку появления его команды и роли (хост или клиент), иначе - начало уровня
+func (g *Game) spawnPoint(p *pilot) level.Point {
+	if !g.isVersus() {
+		return g.level.Spawn
+	}
+	slot := 0
+	if g.isClientPilot(p) {
+		slot = 1
+	}
+	return g.level.SpawnFor(g.pilotTeam(p), slot)
+}
+
+// farthestSpawn возвращает точку появления персонажа текущего шага, самую дальнюю
+// от соперника: одну из точек появления его команды, а если в уровне их нет -
+// начало уровня или контрольную точку
+func (g *Game) farthestSpawn() level.Point {
+	candidates := g.level.SpawnPointsFor(g.pilotTeam(g.pilot))
+	if len(candidates) == 0 {
+		candidates = append(candidates, g.level.Spawn)
+		for _, t := range g.level.Triggers {
+			if t.Kind == level.TriggerCheckpoint {
+				candidates = append(candidates, checkpointPoint(entities.AABB{X: t.Bounds.X, Y: t.Bounds.Y, Width: t.Bounds.Width, Height: t.Bounds.Height}))
+			}
+		}
+	}
+
+	rival := g.remote
+	if g.pilot != g.local {
+		rival = g.local.player
+	}
+	best := candidates[0]
+	if rival == nil {
+		return best
+	}
+
+	rivalX, rivalY := rival.Center()
+	distance := func(point level.Point) float64 {
+		dx := point.X + config.PlayerWidth/2 - rivalX
+		dy := point.Y + config.PlayerHeight/2 - rivalY
+		return dx*dx + dy*dy
+	}
+	bestDistance := distance(best)
+	for _, point := range candidates[1:] {
+		if d := distance(point); d > bestDistance {
+			best, bestDistance = point, d
+		}
+	}
+	return best
+}
+
+// showDeath сообщает о гибели удаленного игрока; свою гибель игрок видит по отсчету
+func (g *Game) showDeath(payload respawnPayload) {
+	if payload.Client != g.isHost() {
+		return
+	}
+	hello, _ := g.net.Remote()
+	g.showMessage(i18n.T("respawn.died", hello.Name))
+}
+
+// handleRespawnEvent принимает у клиента гибель и появление персонажей от хоста
+func (g *Game) handleRespawnEvent(event network.Event) {
+	if g.isHost() {
+		return
+	}
+	var payload respawnPayload
+	if err := event.Decode(&payload); err != nil {
+		return
+	}
+	t := g.deathTimer(payload.Client)
+	switch event.Type {
+	case eventPlayerDied:
+		t.frames = max(payload.Frames, 1)
+		g.showDeath(payload)
+	case eventPlayerRespawn:
+		*t = deathTimer{}
+	}
+}
+
+// drawRespawn выводит отсчет до появления погибшего персонажа этого игрока
+func (g *Game) drawRespawn(screen canvas.Canvas) {
+	t := g.deathTimer(!g.isHost())
+	if !g.isVersus() || t.frames == 0 {
+		return
+	}
+	seconds := (t.frames + ticksPerSecond - 1) / ticksPerSecond
+	screen.DrawBanner(i18n.T("respawn.countdown", seconds))
+}
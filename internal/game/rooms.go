@@ -0,0 +1,192 @@
+package game
+
+import (
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/weather"
+)
+
+// roomTransition описывает переход камеры между комнатами.
+// Пока переход активен, игровой процесс заморожен.
+type roomTransition struct {
+	active bool
+	frame  int     // Текущий кадр перехода
+	fromX  float64 // Позиция камеры в начале перехода
+	fromY  float64
+	toX    float64 // Позиция камеры в конце перехода
+	toY    float64
+}
+
+// enterRoom делает комнату текущей: ограничивает камеру ее границами
+// и оставляет активными только сущности этой комнаты
+func (g *Game) enterRoom(index int) {
+	g.room = index
+	g.camera.Bounds = g.level.CameraBounds(index)
+	g.invalidateMinimap()
+	g.weather = weather.New(g.level.RoomWeather(index))
+	g.weather.Limit(g.content)
+	g.despawnOutsideRoom()
+	g.sounds.PlayMusic(g.roomMusic())
+}
+
+// roomMusic возвращает трек текущей комнаты: свой трек комнаты или общий трек уровня
+func (g *Game) roomMusic() string {
+	if g.room >= 0 && g.room < len(g.level.Rooms) && g.level.Rooms[g.room].Music != "" {
+		return g.level.Rooms[g.room].Music
+	}
+	return g.level.Music
+}
+
+// updateMusic продвигает переходы музыки и приглушает ее, пока на экране диалог
+func (g *Game) updateMusic() {
+	dialogue := g.scene == scenePlaying && (g.messageFrames > 0 || g.canTalkToEscort())
+	g.sounds.DuckMusic(dialogue)
+	g.sounds.UpdateMusic()
+}
+
+// despawnOutsideRoom удаляет сущности предыдущей комнаты и возвращает NPC текущей.
+// При первом входе NPC комнаты создаются по данным уровня, а в комнате, где игрок
+// уже был, остаются те, кого он там оставил: убитые NPC не появляются снова.
+// Сопровождаемый NPC не пересоздается: если он следует за игроком, он переходит вместе с ним.
+func (g *Game) despawnOutsideRoom() {
+	bounds := g.level.RoomBounds(g.room)
+
+	// Пули предыдущей комнаты исчезают
+	g.bullets = g.bullets[:0]
+	g.corpses = g.corpses[:0]
+	g.world.Clear()
+
+	npcs, visited := g.roomNPCs[g.room]
+	delete(g.roomNPCs, g.room)
+	if !visited {
+		npcs = make([]*entities.NPC, 0, len(g.level.NPCs))
+		for _, spawn := range g.level.NPCs {
+			if spawn.Escort {
+				continue
+			}
+			if bounds.ContainsPoint(spawn.X+spawn.Width/2, spawn.Y+spawn.Height/2) {
+				npcs = append(npcs, entities.NewNPC(spawn.X, spawn.Y, spawn.Width, spawn.Height))
+			}
+		}
+	}
+
+	if g.escort != nil {
+		npc := g.escort.NPC
+		if g.escort.Following() {
+			// NPC проходит через дверь вслед за игроком
+			npc.X = g.player.X
+			npc.Y = g.player.Y + g.player.Height - npc.Height
+			npc.VelocityX = 0
+			npc.VelocityY = 0
+		}
+		if bounds.ContainsPoint(npc.X+npc.Width/2, npc.Y+npc.Height/2) {
+			// Сопровождаемый NPC всегда идет первым в списке
+			npcs = append([]*entities.NPC{npc}, npcs...)
+		}
+	}
+
+	g.npcs = npcs
+}
+
+// parkRoomNPCs запоминает живых NPC комнаты, из которой уходит игрок,
+// чтобы при возвращении они ждали его на тех же местах и с тем же здоровьем
+func (g *Game) parkRoomNPCs() {
+	parked := make([]*entities.NPC, 0, len(g.npcs))
+	for _, npc := range g.npcs {
+		if npc.IsDead() || (g.escort != nil && npc == g.escort.NPC) {
+			continue
+		}
+		parked = append(parked, npc)
+	}
+	g.roomNPCs[g.room] = parked
+}
+
+// checkDoors начинает переход в другую комнату, когда игрок входит в дверь
+func (g *Game) checkDoors() {
+	player := g.player
+
+	touching := -1
+	for i, door := range g.level.Doors {
+		if door.Bounds.Intersects(player.X, player.Y, player.Width, player.Height) {
+			touching = i
+			break
+		}
+	}
+
+	// После перехода игрок стоит в двери, ведущей обратно;
+	// двери снова срабатывают только после того, как он из нее выйдет
+	if g.doorLocked {
+		if touching < 0 {
+			g.doorLocked = false
+		}
+		return
+	}
+	if touching < 0 {
+		return
+	}
+
+	target := g.level.RoomIndex(g.level.Doors[touching].To)
+	if target < 0 || target == g.room {
+		return
+	}
+	g.startRoomTransition(target)
+}
+
+// startRoomTransition переносит игрока в комнату target и запускает сдвиг камеры
+func (g *Game) startRoomTransition(target int) {
+	player := g.player
+
+	// Проводим игрока сквозь дверь внутрь новой комнаты
+	bounds := g.level.RoomBounds(target)
+	player.X = clamp(player.X, bounds.X, bounds.X+bounds.Width-player.Width)
+	player.Y = clamp(player.Y, bounds.Y, bounds.Y+bounds.Height-player.Height)
+
+	// Выход из комнаты завершает участок спидрана
+	g.splitSpeedrun()
+
+	g.roomTransition = roomTransition{
+		active: true,
+		fromX:  g.camera.X,
+		fromY:  g.camera.Y,
+	}
+
+	g.parkRoomNPCs()
+	g.enterRoom(target)
+	g.doorLocked = true
+
+	g.roomTransition.toX, g.roomTransition.toY = g.camera.Target(player.X, player.Y)
+}
+
+// updateRoomTransition плавно сдвигает камеру к новой комнате.
+// Возвращает true, пока переход не закончен и игровой процесс заморожен.
+func (g *Game) updateRoomTransition() bool {
+	t := &g.roomTransition
+	if !t.active {
+		return false
+	}
+
+	t.frame++
+	progress := float64(t.frame) / config.RoomTransitionFrames
+	if progress >= 1 {
+		progress = 1
+		t.active = false
+	}
+
+	// Плавное замедление в начале и в конце перехода
+	eased := progress * progress * (3 - 2*progress)
+	g.camera.X = t.fromX + (t.toX-t.fromX)*eased
+	g.camera.Y = t.fromY + (t.toY-t.fromY)*eased
+
+	return t.active
+}
+
+// clamp ограничивает значение отрезком [min, max]
+func clamp(value, min, max float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
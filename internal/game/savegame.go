@@ -0,0 +1,203 @@
+package game
+
+import (
+	"fmt"
+	"log"
+
+	"platformer/internal/entities"
+	"platformer/internal/i18n"
+	"platformer/internal/mission"
+	"platformer/internal/save"
+)
+
+// handleQuickSave сохраняет игру по F5 и загружает быстрое сохранение по F9.
+// В сетевой игре сохранения недоступны: состояние соперника в файл не попадает.
+func (g *Game) handleQuickSave() {
+	saveKeyPressed := g.window.Pressed(KeyF5)
+	loadKeyPressed := g.window.Pressed(KeyF9)
+	saveJustPressed := saveKeyPressed && !g.prevSaveKeyPressed
+	loadJustPressed := loadKeyPressed && !g.prevLoadKeyPressed
+	g.prevSaveKeyPressed = saveKeyPressed
+	g.prevLoadKeyPressed = loadKeyPressed
+
+	if g.net != nil {
+		return
+	}
+
+	switch {
+	case saveJustPressed:
+		if err := g.saveGame(g.options.SavePath); err != nil {
+			log.Printf("quicksave: %v", err)
+			g.showMessage(i18n.T("save.failed"))
+			return
+		}
+		g.SaveProfile()
+		g.showMessage(i18n.T("save.done"))
+	case loadJustPressed:
+		state, err := save.Read(g.options.SavePath)
+		if err == nil {
+			err = g.restoreSave(state)
+		}
+		if err != nil {
+			log.Printf("quickload: %v", err)
+			g.showMessage(i18n.T("save.load_failed"))
+			return
+		}
+		g.showMessage(i18n.T("save.loaded"))
+	}
+}
+
+// saveGame записывает текущее прохождение уровня в файл path
+func (g *Game) saveGame(path string) error {
+	// Во время побега и переходов между комнатами часть состояния живет
+	// только в памяти (рушащиеся платформы, сдвиг камеры), поэтому сохраняться нельзя
+	if g.levelComplete || g.escape.active || g.roomTransition.active {
+		return fmt.Errorf("saving is not possible right now")
+	}
+
+	state := save.State{
+		LevelPath:  g.options.LevelPath,
+		LevelName:  g.level.Name,
+		Frames:     g.levelFrames,
+		Score:      g.levelScore(),
+		Weapon:     int(g.weapon),
+		Checkpoint: g.checkpoint,
+	}
+
+	var err error
+	if state.Player, err = entities.Encode(g.player); err != nil {
+		return err
+	}
+	for _, npc := range g.allNPCs() {
+		if npc.IsDead() || (g.escort != nil && npc == g.escort.NPC) {
+			continue
+		}
+		record, err := entities.Encode(npc)
+		if err != nil {
+			return err
+		}
+		state.NPCs = append(state.NPCs, record)
+	}
+	for index := range g.level.Rooms {
+		parked, visited := g.roomNPCs[index]
+		if !visited {
+			continue
+		}
+		room := save.Room{Index: index, NPCs: make([]entities.Record, 0, len(parked))}
+		for _, npc := range parked {
+			record, err := entities.Encode(npc)
+			if err != nil {
+				return err
+			}
+			room.NPCs = append(room.NPCs, record)
+		}
+		state.Rooms = append(state.Rooms, room)
+	}
+	if g.escort != nil {
+		record, err := entities.Encode(g.escort.NPC)
+		if err != nil {
+			return err
+		}
+		state.Escort = &save.Escort{NPC: record, State: int(g.escort.State)}
+	}
+	for _, volume := range g.triggers.Volumes() {
+		if volume.Spent() && volume.ID != "" {
+			state.SpentTriggers = append(state.SpentTriggers, volume.ID)
+		}
+	}
+
+	return save.Write(path, state)
+}
+
+// restoreSave перезапускает уровень и восстанавливает в нем сохраненное состояние.
+// Если сохранение не подходит к текущему уровню, игра не меняется.
+func (g *Game) restoreSave(state save.State) error {
+	if state.LevelName != g.level.Name {
+		return fmt.Errorf("save is for level %q, current level is %q", state.LevelName, g.level.Name)
+	}
+
+	// Сначала разбираем все сущности, чтобы испорченный файл не оставил уровень наполовину загруженным
+	player, err := decodeAs[*entities.Player](state.Player)
+	if err != nil {
+		return err
+	}
+	npcs := make([]*entities.NPC, 0, len(state.NPCs))
+	for _, record := range state.NPCs {
+		npc, err := decodeAs[*entities.NPC](record)
+		if err != nil {
+			return err
+		}
+		npcs = append(npcs, npc)
+	}
+	rooms := make(map[int][]*entities.NPC, len(state.Rooms))
+	for _, room := range state.Rooms {
+		parked := make([]*entities.NPC, 0, len(room.NPCs))
+		for _, record := range room.NPCs {
+			npc, err := decodeAs[*entities.NPC](record)
+			if err != nil {
+				return err
+			}
+			parked = append(parked, npc)
+		}
+		rooms[room.Index] = parked
+	}
+	var escortNPC *entities.NPC
+	if state.Escort != nil {
+		if escortNPC, err = decodeAs[*entities.NPC](state.Escort.NPC); err != nil {
+			return err
+		}
+	}
+
+	g.resetLevel()
+	// Загруженный забег не считается спидраном: часть времени прошла в другой игре
+	g.speedrun = nil
+
+	g.player = player
+	g.levelFrames = state.Frames
+	if w := weapon(state.Weapon); w >= 0 && w < weaponCount {
+		g.weapon = w
+	}
+	g.checkpoint = state.Checkpoint
+
+	spent := make(map[string]bool, len(state.SpentTriggers))
+	for _, id := range state.SpentTriggers {
+		spent[id] = true
+	}
+	for _, volume := range g.triggers.Volumes() {
+		if spent[volume.ID] {
+			volume.Spend()
+		}
+	}
+
+	if g.escort != nil && escortNPC != nil {
+		g.escort.NPC = escortNPC
+		g.escort.State = mission.State(state.Escort.State)
+	}
+
+	// Комната определяется по позиции персонажа; ее NPC и NPC комнат, где игрок
+	// уже был, берутся из сохранения, чтобы убитые до сохранения NPC не появились снова
+	g.roomNPCs = rooms
+	g.enterSpawnRoom()
+	restored := make([]*entities.NPC, 0, len(npcs)+1)
+	if g.escort != nil && len(g.npcs) > 0 && g.npcs[0] == g.escort.NPC {
+		restored = append(restored, g.escort.NPC)
+	}
+	g.npcs = append(restored, npcs...)
+	g.resetStreaming() // Дальние NPC из сохранения ждут загрузки своих частей
+
+	return nil
+}
+
+// decodeAs восстанавливает сущность из записи и проверяет, что она нужного типа
+func decodeAs[T entities.Serializable](record entities.Record) (T, error) {
+	var zero T
+	decoded, err := entities.Decode(record)
+	if err != nil {
+		return zero, err
+	}
+	entity, ok := decoded.(T)
+	if !ok {
+		return zero, fmt.Errorf("save: entity %d has unexpected kind %q", record.ID, record.Kind)
+	}
+	return entity, nil
+}
@@ -0,0 +1,63 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+)
+
+// screenshotRequest — снимок экрана, который будет сделан в ближайшем кадре
+type screenshotRequest struct {
+	pending bool
+	clean   bool // Снять мир без интерфейса и отладочной информации
+}
+
+// handleScreenshot по F12 запрашивает снимок экрана, по Shift+F12 - снимок без интерфейса.
+// Снимок делает Draw, потому что кадр доступен только там.
+func (g *Game) handleScreenshot() {
+	if !g.window.JustPressed(KeyF12) {
+		return
+	}
+	g.screenshot = screenshotRequest{pending: true, clean: g.window.Pressed(KeyShift)}
+}
+
+// takeScreenshot сохраняет кадр в PNG-файл с отметкой времени в каталоге снимков.
+// Пиксели читаются сразу, а файл пишется в фоне, чтобы игра не подтормаживала.
+func (g *Game) takeScreenshot(screen canvas.Canvas) {
+	g.screenshot = screenshotRequest{}
+
+	frame := image.NewRGBA(image.Rect(0, 0, config.ScreenWidth, config.ScreenHeight))
+	screen.ReadPixels(frame)
+
+	path := filepath.Join(config.ScreenshotDir, time.Now().Format("2006-01-02_15-04-05.000")+".png")
+	g.showMessage(i18n.T("screenshot.saved", path))
+	go func() {
+		if err := writePNG(path, frame); err != nil {
+			log.Printf("screenshot: %v", err)
+		}
+	}()
+}
+
+// writePNG записывает изображение в PNG-файл path, создавая каталог при необходимости
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return fmt.Errorf("encode %s: %w", path, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,139 @@
+package game
+
+import (
+	"log"
+
+	"platformer/internal/canvas"
+	"platformer/internal/entities"
+	"platformer/internal/i18n"
+	"platformer/internal/level"
+	"platformer/internal/network"
+)
+
+// openServers открывает список игр в локальной сети и начинает их поиск
+func (g *Game) openServers() {
+	g.scene = sceneServers
+	g.serverIndex = 0
+	g.codeEntry = false
+	g.browser, g.serverErr = network.Browse()
+}
+
+// closeServers останавливает поиск игр и возвращает в главное меню
+func (g *Game) closeServers() {
+	if g.browser != nil {
+		_ = g.browser.Close()
+		g.browser = nil
+	}
+	g.scene = sceneMenu
+}
+
+// foundServers возвращает найденные игры (пусто, если поиск не удалось начать)
+func (g *Game) foundServers() []network.Server {
+	if g.browser == nil {
+		return nil
+	}
+	return g.browser.Servers()
+}
+
+// updateServerScene обрабатывает список игр в локальной сети
+func (g *Game) updateServerScene() {
+	if g.codeEntry {
+		g.updateCodeEntry()
+		return
+	}
+	// С сервером подбора к игре можно подключиться и по коду комнаты
+	if g.matchmaking != nil && g.window.JustPressed(KeyTab) {
+		g.codeEntry = true
+		g.codeBuffer = g.codeBuffer[:0]
+		g.serverErr = nil
+		return
+	}
+
+	servers := g.foundServers()
+	// Список меняется, пока он открыт
+	if g.serverIndex >= len(servers) {
+		g.serverIndex = max(len(servers)-1, 0)
+	}
+
+	if len(servers) > 0 {
+		if g.window.JustPressed(KeyArrowDown) || g.window.JustPressed(KeyS) {
+			g.serverIndex = (g.serverIndex + 1) % len(servers)
+		}
+		if g.window.JustPressed(KeyArrowUp) || g.window.JustPressed(KeyW) {
+			g.serverIndex = (g.serverIndex + len(servers) - 1) % len(servers)
+		}
+		if g.window.JustPressed(KeyEnter) || g.window.JustPressed(KeySpace) {
+			g.joinServer(servers[g.serverIndex])
+			return
+		}
+	}
+	if g.window.JustPressed(KeyEscape) {
+		g.closeServers()
+	}
+}
+
+// joinServer подключается к найденной игре и открывает лобби. Уровень берется
+// у хоста: у игроков он должен совпадать.
+func (g *Game) joinServer(server network.Server) {
+	lvl := g.level
+	if server.Level != g.options.LevelPath {
+		lvl = level.Default()
+		if server.Level != "" {
+			loaded, err := loadLevel(server.Level)
+			if err != nil {
+				g.serverErr = err
+				return
+			}
+			lvl = loaded
+		}
+	}
+
+	manager, err := network.Join(server.Address, network.Hello{Name: g.options.PlayerName, Color: defaultColor(ModeClient), Team: defaultTeam(ModeClient), Password: g.options.Password})
+	if err != nil {
+		g.serverErr = err
+		return
+	}
+	log.Printf("network: joined %s at %s", server.Name, server.Address)
+
+	_ = g.browser.Close()
+	g.browser = nil
+	g.useNetwork(manager)
+	g.options.Mode = ModeClient
+	g.options.Address = server.Address
+	g.options.LevelPath = server.Level
+	g.level = lvl
+	g.resetLevel()
+	g.remote = entities.NewPlayer(g.player.X, g.player.Y)
+	g.openLobby()
+}
+
+// drawServerScene рисует список игр в локальной сети
+func (g *Game) drawServerScene(screen canvas.Canvas) {
+	servers := g.foundServers()
+	items := make([]string, 0, len(servers))
+	for _, server := range servers {
+		levelName := server.Level
+		if levelName == "" {
+			levelName = i18n.T("servers.default_level")
+		}
+		item := i18n.T("servers.item", server.Name, levelName, server.Address)
+		if !server.Open {
+			item = i18n.T("servers.busy", item)
+		}
+		items = append(items, item)
+	}
+
+	hint := i18n.T("servers.hint")
+	switch {
+	case g.codeEntry:
+		hint = g.codeEntryText()
+	case g.serverErr != nil:
+		hint = i18n.T("servers.error", g.serverErr)
+	case len(servers) == 0:
+		hint = i18n.T("servers.searching")
+	}
+	if g.matchmaking != nil && !g.codeEntry {
+		hint = i18n.T("servers.with_code", hint)
+	}
+	screen.DrawMenuWithHint(i18n.T("servers.title"), items, g.serverIndex, hint)
+}
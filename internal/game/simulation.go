@@ -0,0 +1,98 @@
+package game
+
+import (
+	"platformer/internal/entities"
+	"platformer/internal/level"
+)
+
+// Simulation — игра без окна для проверок физики: уровень собирается в коде,
+// ввод на каждом шаге задает сценарий, а состояние доступно между шагами.
+// Окно, звук и ресурсы не нужны, а пакет game не зависит от ebiten,
+// поэтому симуляцию можно гонять в go test и CI без графики и звука.
+// Шаг детерминирован (determinism.go): одинаковые уровень, зерно и сценарий
+// всегда дают одинаковый результат.
+type Simulation struct {
+	game  *Game
+	input scriptInput
+}
+
+// ScriptStep — ввод, который держится Ticks шагов подряд.
+// Ввод строится от NoInput, чтобы не зажать эмоцию с индексом 0.
+type ScriptStep struct {
+	Ticks int
+	Input Input
+}
+
+// scriptInput — источник ввода симуляции: текущий шаг сценария
+type scriptInput struct {
+	current Input
+}
+
+// Input возвращает ввод текущего шага сценария
+func (s *scriptInput) Input() Input {
+	return s.current
+}
+
+// NewSimulation запускает локальную игру без окна на уровне lvl
+// со случайными числами из зерна seed. Персонаж стоит в точке появления уровня.
+func NewSimulation(lvl *level.Level, seed int64) (*Simulation, error) {
+	s := &Simulation{input: scriptInput{current: NoInput}}
+	g, err := NewGameWithOptions(Options{
+		Mode:     ModeLocal,
+		Level:    lvl,
+		Headless: true,
+		Seed:     seed,
+		Input:    &s.input,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := g.StartHeadless(); err != nil {
+		return nil, err
+	}
+	s.game = g
+	return s, nil
+}
+
+// Step выполняет ticks шагов, держа ввод input
+func (s *Simulation) Step(ticks int, input Input) error {
+	s.input.current = input
+	for i := 0; i < ticks; i++ {
+		if err := s.game.Tick(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run выполняет сценарий по шагам и оставляет ввод пустым
+func (s *Simulation) Run(script []ScriptStep) error {
+	for _, step := range script {
+		if err := s.Step(step.Ticks, step.Input); err != nil {
+			return err
+		}
+	}
+	s.input.current = NoInput
+	return nil
+}
+
+// Player возвращает персонажа. Его можно менять между шагами,
+// например задать скорость перед проверкой столкновения.
+func (s *Simulation) Player() *entities.Player {
+	return s.game.local.player
+}
+
+// Platforms возвращает платформы уровня в порядке данных уровня
+func (s *Simulation) Platforms() []*entities.Platform {
+	return s.game.platforms
+}
+
+// Frames возвращает, сколько шагов прошло на уровне
+func (s *Simulation) Frames() int {
+	return s.game.levelFrames
+}
+
+// StateHash возвращает хэш состояния симуляции (Game.StateHash)
+func (s *Simulation) StateHash() uint64 {
+	return s.game.StateHash()
+}
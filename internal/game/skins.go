@@ -0,0 +1,73 @@
+package game
+
+import (
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+)
+
+// applySkin загружает скин персонажа из файла path; пустой путь возвращает встроенный спрайт.
+// Если скин не загрузился (нет файла, неверный размер), остается встроенный спрайт
+// и возвращается false.
+func (g *Game) applySkin(path string) bool {
+	if err := g.window.SetSkin(path); err != nil {
+		log.Printf("%v", err)
+		_ = g.window.SetSkin("")
+		g.skin = ""
+		return false
+	}
+	g.skin = path
+	return true
+}
+
+// availableSkins возвращает скины для выбора в настройках: встроенный спрайт (пустой путь),
+// PNG-файлы каталога скинов и текущий скин, если он лежит в другом месте
+func (g *Game) availableSkins() []string {
+	skins := []string{""}
+	matches, err := filepath.Glob(filepath.Join(config.SkinDir, "*.png"))
+	if err != nil {
+		log.Printf("skins: %v", err)
+	}
+	sort.Strings(matches)
+	skins = append(skins, matches...)
+
+	for _, skin := range skins {
+		if skin == g.skin {
+			return skins
+		}
+	}
+	return append(skins, g.skin)
+}
+
+// skinOption создает пункт меню настроек для выбора скина персонажа
+func skinOption() optionItem {
+	return optionItem{
+		title: func(g *Game) string {
+			if g.skin == "" {
+				return i18n.T("options.skin_default")
+			}
+			return i18n.T("options.skin", strings.TrimSuffix(filepath.Base(g.skin), filepath.Ext(g.skin)))
+		},
+		change: func(g *Game, delta int) {
+			skins := g.availableSkins()
+			current := 0
+			for i, skin := range skins {
+				if skin == g.skin {
+					current = i
+				}
+			}
+			// Выбор в настройках заменяет скин из флага запуска
+			g.options.Skin = ""
+			// Неподходящие файлы пропускаем; встроенный спрайт загружается всегда
+			for i := 1; i <= len(skins); i++ {
+				if g.applySkin(skins[(current+i*delta+i*len(skins))%len(skins)]) {
+					return
+				}
+			}
+		},
+	}
+}
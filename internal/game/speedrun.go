@@ -0,0 +1,62 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"platformer/internal/canvas"
+	"platformer/internal/i18n"
+	"platformer/internal/speedrun"
+)
+
+// startSpeedrun запускает таймер спидрана заново (в режиме -speedrun)
+func (g *Game) startSpeedrun() {
+	if !g.options.Speedrun {
+		return
+	}
+	g.speedrun = speedrun.NewRun(g.level.Name, simulationStep)
+}
+
+// splitSpeedrun отмечает конец участка забега, когда игрок покидает комнату
+func (g *Game) splitSpeedrun() {
+	if g.speedrun == nil || g.room < 0 || g.room >= len(g.level.Rooms) {
+		return
+	}
+	g.speedrun.Split(g.level.Rooms[g.room].ID)
+}
+
+// finishSpeedrun останавливает таймер по завершении уровня и дописывает забег в файл результатов
+func (g *Game) finishSpeedrun() {
+	if g.speedrun == nil || !g.speedrun.Finish(g.level.Name) {
+		return
+	}
+	if err := g.speedrun.Append(g.options.SpeedrunPath, time.Now()); err != nil {
+		log.Printf("speedrun results: %v", err)
+	}
+}
+
+// handlePause ставит игру на паузу и снимает с нее по нажатию P.
+// В сетевой игре пауза недоступна: соперник продолжает играть.
+func (g *Game) handlePause() {
+	keyPressed := g.window.Pressed(KeyP)
+	if keyPressed && !g.prevPauseKeyPressed && g.net == nil {
+		g.paused = !g.paused
+	}
+	g.prevPauseKeyPressed = keyPressed
+}
+
+// drawSpeedrun выводит таймер спидрана и времена участков
+func (g *Game) drawSpeedrun(screen canvas.Canvas) {
+	if g.speedrun == nil {
+		return
+	}
+	screen.DrawSpeedrun(g.speedrun.Elapsed(), g.speedrun.Splits(), g.speedrun.Finished())
+}
+
+// drawPause выводит надпись паузы
+func (g *Game) drawPause(screen canvas.Canvas) {
+	if !g.paused {
+		return
+	}
+	screen.DrawBanner(i18n.T("pause.banner"))
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+)
+
+// В режиме разделенного экрана (Options.SplitScreen) экран делится на две
+// половины: слева мир виден камерой g.camera, которая следует за персонажем
+// этого игрока, а справа - своей камерой, которая следует за вторым
+// персонажем (g.remote). Мир рисуется дважды, каждый раз в свою половину
+// кадра (canvas.Canvas.DrawHalf). Интерфейс рисуется поверх
+// один раз на весь экран, кроме подписей над персонажами: они рисуются в каждой
+// половине вместе с миром. Пока второго персонажа нет, экран не делится.
+
+// splitScreen — вторая половина разделенного экрана
+type splitScreen struct {
+	camera    Camera // Камера правой половины
+	following bool   // Камера уже наведена на второго персонажа
+}
+
+// splitActive сообщает, делится ли сейчас экран
+func (g *Game) splitActive() bool {
+	return g.options.SplitScreen && g.remote != nil
+}
+
+// updateSplitScreen сужает камеры до половины экрана и ведет камеру правой
+// половины за вторым персонажем. Без разделения камера занимает весь экран.
+func (g *Game) updateSplitScreen() {
+	if !g.splitActive() {
+		g.camera.ScreenWidth = 0
+		g.split.following = false
+		return
+	}
+
+	g.camera.ScreenWidth = config.ScreenWidth / 2
+	camera := &g.split.camera
+	camera.Bounds = g.camera.Bounds
+	camera.Zoom = g.camera.Zoom
+	camera.ScreenWidth = g.camera.ScreenWidth
+	if !g.split.following {
+		camera.Snap(g.remote.X, g.remote.Y)
+		g.split.following = true
+		return
+	}
+	camera.Update(g.remote.X, g.remote.Y)
+}
+
+// drawSplitWorld рисует мир в обе половины экрана screen
+func (g *Game) drawSplitWorld(screen canvas.Canvas) {
+	cameras := [2]Camera{g.camera, g.split.camera}
+	for i := range cameras {
+		screen.DrawHalf(i, func(view canvas.Canvas) {
+			g.withCamera(cameras[i], func() {
+				g.queueWorld(&g.drawQueue, g.camera.View())
+				for _, draw := range g.viewportOverlays() {
+					g.drawQueue.Add(canvas.LayerUI, 0, draw)
+				}
+				g.drawQueue.Flush(view)
+			})
+		})
+	}
+	screen.DrawSplitDivider()
+}
+
+// withCamera выполняет fn, подставив camera вместо камеры игры, чтобы
+// отрисовка, которая берет вид из g.camera, рисовала через нее
+func (g *Game) withCamera(camera Camera, fn func()) {
+	prev := g.camera
+	g.camera = camera
+	defer func() { g.camera = prev }()
+	fn()
+}
+
+// viewportOverlays возвращает подписи, привязанные к персонажам в мире:
+// при разделении экрана они рисуются в каждой половине
+func (g *Game) viewportOverlays() []canvas.DrawFunc {
+	return []canvas.DrawFunc{
+		g.drawNames,        // Имена игроков в сетевой игре
+		g.drawEmotes,       // Эмоции игроков
+		g.drawPhysicsDebug, // Отладочный слой физики
+	}
+}
+
+// onFullScreen возвращает draw для интерфейса на весь экран; подписи
+// из viewportOverlays при разделении экрана уже нарисованы в половинах
+func (g *Game) onFullScreen(draw canvas.DrawFunc) canvas.DrawFunc {
+	if g.splitActive() {
+		return func(canvas.Canvas) {}
+	}
+	return draw
+}
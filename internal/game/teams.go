@@ -0,0 +1,84 @@
+package game
+
+import (
+	"platformer/internal/i18n"
+	"platformer/internal/level"
+)
+
+// В сетевой игре каждый игрок состоит в одной из level.TeamCount команд: начальная
+// команда, как и цвет, приходит в рукопожатии, а в лобби ее можно сменить.
+// Игроки одной команды не ранят друг друга, если хост не включил огонь по своим, имена над персонажами и строки
+// лобби рисуются цветом команды, а командные режимы (захват флага) берут
+// команды игроков отсюда.
+
+// teamNames — ключи перевода названий команд
+var teamNames = [level.TeamCount]string{"team.red", "team.blue"}
+
+// teamName возвращает название команды team
+func teamName(team int) string {
+	return i18n.T(teamNames[team])
+}
+
+// defaultTeam возвращает команду игрока по умолчанию: хост играет за первую команду, клиент - за вторую
+func defaultTeam(mode Mode) int {
+	if mode == ModeHost {
+		return 0
+	}
+	return 1
+}
+
+// validTeam приводит команду из сети к допустимой
+func validTeam(team int) int {
+	if team < 0 || team >= level.TeamCount {
+		return 0
+	}
+	return team
+}
+
+// cycleLobbyTeam переводит игрока в следующую команду
+func (g *Game) cycleLobbyTeam() {
+	g.lobby.local.Team = (g.lobby.local.Team + 1) % level.TeamCount
+}
+
+// remoteTeam возвращает команду удаленного игрока: выбранную в лобби,
+// а до первого состояния лобби - из рукопожатия
+func (g *Game) remoteTeam() int {
+	if g.lobby.remoteSeen {
+		return validTeam(g.lobby.remote.Team)
+	}
+	if hello, ok := g.net.Remote(); ok {
+		return validTeam(hello.Team)
+	}
+	return defaultTeam(g.remoteMode())
+}
+
+// remoteMode возвращает роль удаленного игрока
+func (g *Game) remoteMode() Mode {
+	if g.isHost() {
+		return ModeClient
+	}
+	return ModeHost
+}
+
+// teamOf возвращает команду игрока клиента (client) или хоста
+func (g *Game) teamOf(client bool) int {
+	if client == g.isHost() {
+		return g.remoteTeam()
+	}
+	return g.lobby.local.Team
+}
+
+// pilotTeam возвращает команду персонажа p
+func (g *Game) pilotTeam(p *pilot) int {
+	return g.teamOf(g.isClientPilot(p))
+}
+
+// teammates сообщает, играют ли оба игрока сетевой игры за одну команду
+func (g *Game) teammates() bool {
+	return g.isVersus() && g.lobby.local.Team == g.remoteTeam()
+}
+
+// friendlyFireBlocked сообщает, что игроки в одной команде и пули не ранят своих
+func (g *Game) friendlyFireBlocked() bool {
+	return g.teammates() && !g.settings.FriendlyFire
+}
@@ -0,0 +1,40 @@
+package game
+
+import "time"
+
+const (
+	// simulationStep — фиксированная длительность одного шага симуляции
+	simulationStep = time.Second / ticksPerSecond
+
+	// maxStepsPerUpdate ограничивает число шагов за один вызов Update,
+	// чтобы после долгой паузы (например, перетаскивания окна) игра не пыталась
+	// догнать все пропущенное время и не зависала
+	maxStepsPerUpdate = 5
+)
+
+// stepClock накапливает прошедшее время и превращает его в целое число
+// фиксированных шагов симуляции. Благодаря этому физика ведет себя одинаково
+// при любой частоте обновлений окна и на сервере без окна, где тики идут с другой частотой.
+type stepClock struct {
+	accumulator time.Duration
+}
+
+// Advance добавляет прошедшее время и возвращает, сколько шагов симуляции нужно выполнить
+func (c *stepClock) Advance(elapsed time.Duration) int {
+	c.accumulator += elapsed
+
+	steps := int(c.accumulator / simulationStep)
+	c.accumulator -= time.Duration(steps) * simulationStep
+
+	if steps > maxStepsPerUpdate {
+		// Отбрасываем время, которое не успеваем просчитать
+		steps = maxStepsPerUpdate
+		c.accumulator = 0
+	}
+	return steps
+}
+
+// Reset сбрасывает накопленное время (например, при возврате из меню)
+func (c *stepClock) Reset() {
+	c.accumulator = 0
+}
@@ -0,0 +1,235 @@
+package game
+
+import (
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/i18n"
+	"platformer/internal/level"
+	"platformer/internal/sound"
+	"platformer/internal/timer"
+	"platformer/internal/trigger"
+)
+
+// registerTriggerHandlers связывает виды триггеров уровня с игровой логикой
+func (g *Game) registerTriggerHandlers() {
+	g.triggers.On(level.TriggerExit, g.onExitTrigger)
+	g.triggers.On(level.TriggerCheckpoint, g.onCheckpointTrigger)
+	g.triggers.On(level.TriggerDamage, g.onDamageTrigger)
+	g.triggers.On(level.TriggerMessage, g.onMessageTrigger)
+	g.triggers.On(level.TriggerGravity, g.onGravityTrigger)
+	g.triggers.On(level.TriggerEscape, g.onEscapeTrigger)
+}
+
+// createTriggers заново создает триггеры по данным уровня
+func (g *Game) createTriggers() {
+	g.triggers.Clear()
+	g.damageTimers = make(map[*trigger.Volume]timer.ID)
+	g.killZones = g.killZones[:0]
+	g.checkpoint = nil
+	g.message = ""
+	g.messageFrames = 0
+
+	for _, t := range g.level.Triggers {
+		volume := &trigger.Volume{
+			ID:     t.ID,
+			Kind:   t.Kind,
+			Bounds: entities.AABB{X: t.Bounds.X, Y: t.Bounds.Y, Width: t.Bounds.Width, Height: t.Bounds.Height},
+			Once:   t.Once,
+			Data:   t,
+		}
+		g.triggers.Add(volume)
+		if t.Kind == level.TriggerKill {
+			zone := &killZone{volume: volume, originX: t.Bounds.X, originY: t.Bounds.Y}
+			if t.Motion != nil {
+				zone.motion = *t.Motion
+			}
+			g.killZones = append(g.killZones, zone)
+		}
+	}
+}
+
+// killZone — зона гибели: она проверяется при движении каждого персонажа,
+// а не через события триггеров, потому что те приходят только
+// от персонажа этого игрока
+type killZone struct {
+	volume           *trigger.Volume
+	originX, originY float64      // Начальная позиция зоны
+	motion           level.Motion // Движение зоны (нулевое - зона стоит на месте)
+	frame            int          // Сколько кадров зона уже движется
+}
+
+// updateKillZones двигает подвижные зоны гибели, как движущиеся платформы
+func (g *Game) updateKillZones() {
+	for _, zone := range g.killZones {
+		if zone.motion.Frames <= 0 {
+			continue
+		}
+		zone.frame++
+		progress := motionProgress(zone.motion, zone.frame)
+		zone.volume.Bounds.X = zone.originX + zone.motion.DX*progress
+		zone.volume.Bounds.Y = zone.originY + zone.motion.DY*progress
+	}
+}
+
+// inKillZone сообщает, задевает ли персонаж зону гибели
+func (g *Game) inKillZone(player *entities.Player) bool {
+	for _, zone := range g.killZones {
+		if zone.volume.Bounds.Intersects(player.AABB) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateTriggers проверяет, в какие триггеры вошел или из каких вышел игрок
+func (g *Game) updateTriggers() {
+	g.triggers.Update(g.player)
+
+	if g.messageFrames > 0 {
+		g.messageFrames--
+	}
+}
+
+// onExitTrigger завершает уровень, когда игрок доходит до выхода.
+// В кооперативе уровень пройден, только когда у выхода оба игрока (см. updateCoop).
+func (g *Game) onExitTrigger(volume *trigger.Volume, phase trigger.Phase) {
+	if phase == trigger.Enter && !g.isCoop() {
+		g.levelComplete = true
+	}
+}
+
+// onCheckpointTrigger запоминает точку, в которой игрок появится после падения
+func (g *Game) onCheckpointTrigger(volume *trigger.Volume, phase trigger.Phase) {
+	if phase != trigger.Enter {
+		return
+	}
+
+	point := checkpointPoint(volume.Bounds)
+	if g.checkpoint != nil && *g.checkpoint == point {
+		return
+	}
+	g.checkpoint = &point
+	g.showMessage(i18n.T("trigger.checkpoint"))
+	g.sounds.Play(sound.EffectPickup)
+}
+
+// checkpointPoint возвращает точку появления на контрольной точке bounds: персонаж стоит по центру на ее дне
+func checkpointPoint(bounds entities.AABB) level.Point {
+	return level.Point{
+		X: bounds.X + (bounds.Width-config.PlayerWidth)/2,
+		Y: bounds.Bottom() - config.PlayerHeight,
+	}
+}
+
+// onDamageTrigger наносит урон сразу при входе в опасную зону
+// и затем через равные промежутки времени, пока игрок остается внутри
+func (g *Game) onDamageTrigger(volume *trigger.Volume, phase trigger.Phase) {
+	switch phase {
+	case trigger.Enter:
+		g.damageFromZone(volume)
+		g.damageTimers[volume] = g.timers.Every(config.DamageZoneIntervalFrames, func() {
+			g.damageFromZone(volume)
+		})
+	case trigger.Exit:
+		if id, ok := g.damageTimers[volume]; ok {
+			g.timers.Cancel(id)
+			delete(g.damageTimers, volume)
+		}
+	}
+}
+
+// damageFromZone наносит игроку урон опасной зоны и отбрасывает его от ее центра
+func (g *Game) damageFromZone(volume *trigger.Volume) {
+	data, _ := volume.Data.(level.Trigger)
+	zoneX, _ := volume.Bounds.Center()
+	playerX, _ := g.player.Center()
+
+	if !g.player.TakeHit(entities.NewHit(data.Damage, zoneX, playerX)) {
+		return
+	}
+	g.showDamage(g.player, data.Damage)
+	if g.player.Health <= 0 {
+		// Погибший персонаж появляется на контрольной точке с полным здоровьем
+		g.respawnPlayer()
+		g.player.Health = g.player.MaxHealth
+	}
+}
+
+// onMessageTrigger показывает сообщение сценки
+func (g *Game) onMessageTrigger(volume *trigger.Volume, phase trigger.Phase) {
+	if data, ok := volume.Data.(level.Trigger); ok && phase == trigger.Enter {
+		g.showMessage(i18n.T(data.Message))
+	}
+}
+
+// onGravityTrigger переворачивает гравитацию, когда игрок входит в зону
+func (g *Game) onGravityTrigger(volume *trigger.Volume, phase trigger.Phase) {
+	if phase == trigger.Enter {
+		g.player.FlipGravity()
+	}
+}
+
+// showMessage показывает сообщение в центре экрана на несколько секунд
+func (g *Game) showMessage(text string) {
+	g.message = text
+	g.messageFrames = config.TriggerMessageFrames
+}
+
+// respawnPoint возвращает точку появления: базу команды в захвате флага,
+// дальнюю от соперника точку в матче, последнюю контрольную точку или начало уровня
+func (g *Game) respawnPoint() level.Point {
+	if g.isCTF() {
+		return g.basePoint(g.pilotTeam(g.pilot))
+	}
+	if g.isVersus() && g.isHost() && !g.isCoop() {
+		return g.farthestSpawn()
+	}
+	if g.checkpoint != nil {
+		return *g.checkpoint
+	}
+	return g.level.Spawn
+}
+
+// respawnPlayer возвращает персонажа в точку появления. В сетевой игре
+// персонаж появляется только после отсчета (respawn.go).
+func (g *Game) respawnPlayer() {
+	if g.isDead(g.pilot) {
+		// Персонаж уже погиб и ждет появления
+		return
+	}
+	if g.profile != nil && g.pilot == g.local && !g.replaying {
+		g.profile.Stats.Deaths++
+	}
+	g.recordDeath()
+	g.dropFlag()
+
+	if g.caughtByCollapse() {
+		return
+	}
+	if g.isVersus() && g.isHost() {
+		g.killPilot()
+		return
+	}
+	g.placePlayer(g.respawnPoint())
+}
+
+// placePlayer ставит персонажа текущего шага в точку point
+func (g *Game) placePlayer(point level.Point) {
+	player := g.player
+	player.X = point.X
+	player.Y = point.Y
+	player.VelocityX = 0
+	player.VelocityY = 0
+	// Точки появления рассчитаны на обычную гравитацию
+	if player.GravityFlipped {
+		player.FlipGravity()
+	}
+}
+
+// drawTriggerMessage выводит сообщение последнего сработавшего триггера
+func (g *Game) drawTriggerMessage(screen canvas.Canvas) {
+	if g.messageFrames > 0 && g.message != "" {
+		screen.DrawBanner(g.message)
+	}
+}
@@ -0,0 +1,324 @@
+package game
+
+import (
+	"errors"
+	"log"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+	"platformer/internal/network"
+)
+
+// MatchMode — вид сетевой игры, которую ведет хост
+type MatchMode string
+
+const (
+	MatchVersus     MatchMode = "versus"     // Игроки сражаются друг с другом на время
+	MatchCoop       MatchMode = "coop"       // Игроки вместе проходят уровни кампании (coop.go)
+	MatchDeathmatch MatchMode = "deathmatch" // Игроки сражаются раундами до заданного числа убийств (deathmatch.go)
+	MatchCTF        MatchMode = "ctf"        // Игроки захватывают флаг соперника (ctf.go)
+)
+
+// События сетевого матча
+const (
+	eventMatchOver    network.EventType = "match_over"    // Хост объявляет конец матча
+	eventRematchVote  network.EventType = "rematch_vote"  // Голос игрока за реванш
+	eventRematchStart network.EventType = "rematch_start" // Хост запускает новый матч
+)
+
+// matchPayload — данные событий конца и начала матча
+type matchPayload struct {
+	Match int `json:"match"` // Номер матча
+}
+
+// rematchVotePayload — голос за реванш
+type rematchVotePayload struct {
+	Match  int  `json:"match"`  // Номер завершенного матча, к которому относится голос
+	Accept bool `json:"accept"` // Согласен ли игрок на реванш
+}
+
+// vote — голос игрока за реванш
+type vote int
+
+const (
+	voteNone vote = iota
+	voteAccept
+	voteDecline
+)
+
+// versusMatch хранит состояние матча между двумя игроками по сети.
+// Хост ведет отсчет времени матча и решает, когда он закончен и когда начинается реванш.
+type versusMatch struct {
+	number      int  // Номер текущего матча (растет с каждым реваншем)
+	frames      int  // Сколько кадров идет матч
+	over        bool // Матч закончен, идет голосование за реванш
+	localVote   vote // Голос локального игрока
+	remoteVote  vote // Голос удаленного игрока
+	localScore  int  // Очки локального игрока
+	remoteScore int  // Очки удаленного игрока
+}
+
+// isVersus сообщает, идет ли сетевой матч
+func (g *Game) isVersus() bool {
+	return g.net != nil
+}
+
+// isHost сообщает, является ли эта копия игры хостом матча
+func (g *Game) isHost() bool {
+	return g.options.Mode == ModeHost
+}
+
+// setupMatch готовит на хосте состояние вида сетевой игры match
+func (g *Game) setupMatch(match MatchMode) {
+	g.options.Match = match
+	g.coop, g.deathmatch, g.ctf = coopCampaign{}, deathmatchState{}, ctfMatch{}
+	switch match {
+	case MatchCoop:
+		// Кампания начинается с уровня хоста (с учетом загруженного сохранения)
+		g.coop = newCoopCampaign(g.options.LevelPath)
+	case MatchDeathmatch:
+		g.deathmatch = newDeathmatch(g.options.ScoreLimit)
+	case MatchCTF:
+		g.ctf = newCTF(g.options.ScoreLimit)
+	}
+}
+
+// isPlayback сообщает, показывает ли игра запись матча вместо сетевой игры
+func (g *Game) isPlayback() bool {
+	return g.options.PlaybackPath != "" && g.net != nil
+}
+
+// updateMatch ведет отсчет времени матча на хосте и обрабатывает голосование за реванш.
+// Возвращает true, если матч окончен и игровой процесс остановлен.
+func (g *Game) updateMatch() bool {
+	if !g.isVersus() {
+		return false
+	}
+
+	// Дезматч идет раундами до числа убийств, а не на время
+	if g.isDeathmatch() {
+		return g.updateRound()
+	}
+
+	match := &g.match
+	if !match.over {
+		// Время матча идет только пока соперник подключен.
+		// Клиент считает время только для отображения, конец матча объявляет хост.
+		if g.net.Connected() {
+			match.frames++
+			// Кооперативная кампания не ограничена по времени
+			if g.isHost() && !g.isCoop() && match.frames >= config.VersusMatchFrames {
+				g.endMatch()
+				g.sendMatchEvent(eventMatchOver, matchPayload{Match: match.number})
+			}
+		}
+		return match.over
+	}
+
+	// Голосуем за реванш: Y - согласиться, N - отказаться.
+	// Сервер без окна голосовать не может и всегда согласен.
+	if match.localVote == voteNone {
+		if g.input.VoteYes || (g.options.Headless && g.isHost()) {
+			g.castRematchVote(true)
+		} else if g.input.VoteNo {
+			g.castRematchVote(false)
+		}
+	}
+
+	return true
+}
+
+// endMatch останавливает матч и открывает голосование за реванш
+func (g *Game) endMatch() {
+	g.match.over = true
+	g.match.localVote = voteNone
+	g.match.remoteVote = voteNone
+}
+
+// castRematchVote отправляет голос локального игрока
+func (g *Game) castRematchVote(accept bool) {
+	g.match.localVote = voteDecline
+	if accept {
+		g.match.localVote = voteAccept
+	}
+
+	g.sendMatchEvent(eventRematchVote, rematchVotePayload{Match: g.match.number, Accept: accept})
+	g.tryStartRematch()
+}
+
+// tryStartRematch запускает реванш на хосте, если оба игрока согласились
+func (g *Game) tryStartRematch() {
+	if !g.isHost() || g.match.localVote != voteAccept || g.match.remoteVote != voteAccept {
+		return
+	}
+
+	if g.rotateLevel() {
+		return
+	}
+	next := g.match.number + 1
+	g.sendMatchEvent(eventRematchStart, matchPayload{Match: next})
+	g.startRematch(next)
+}
+
+// rotateLevel на хосте со сменой уровней начинает следующий матч на следующем
+// уровне Options.LevelRotation. Кооперативная кампания идет по своим уровням.
+// Возвращает false, если уровень не сменился и нужен обычный реванш.
+func (g *Game) rotateLevel() bool {
+	levels := g.options.LevelRotation
+	if len(levels) < 2 || g.isCoop() {
+		return false
+	}
+	next := (g.rotation + 1) % len(levels)
+	if err := g.changeServer(levels[next], g.options.Match); err != nil {
+		log.Printf("rotation: %v", err)
+		return false
+	}
+	g.rotation = next
+	return true
+}
+
+// snapshotDue сообщает, пора ли хосту отправить снимок при частоте Options.SnapshotRate
+func (g *Game) snapshotDue() bool {
+	rate := g.options.SnapshotRate
+	if rate <= 0 || rate >= ticksPerSecond {
+		return true
+	}
+	return g.simTick%uint32(ticksPerSecond/rate) == 0
+}
+
+// startRematch сбрасывает уровень, очки и пули, не разрывая соединение.
+// Кооперативная кампания начинается заново с первого уровня.
+func (g *Game) startRematch(number int) {
+	g.match = versusMatch{number: number}
+	if g.isCoop() && g.isHost() {
+		g.enterCoopStage(0)
+		return
+	}
+	g.resetLevel()
+}
+
+// handleMatchEvent обрабатывает событие матча от удаленного игрока
+func (g *Game) handleMatchEvent(event network.Event) {
+	switch event.Type {
+	case eventMatchOver:
+		var payload matchPayload
+		if err := event.Decode(&payload); err != nil || payload.Match != g.match.number {
+			return
+		}
+		if !g.match.over {
+			g.endMatch()
+		}
+
+	case eventRematchVote:
+		var payload rematchVotePayload
+		if err := event.Decode(&payload); err != nil || payload.Match != g.match.number {
+			// Голос за другой матч устарел
+			return
+		}
+		g.match.remoteVote = voteDecline
+		if payload.Accept {
+			g.match.remoteVote = voteAccept
+		}
+		g.tryStartRematch()
+
+	case eventRematchStart:
+		var payload matchPayload
+		if err := event.Decode(&payload); err != nil || payload.Match <= g.match.number {
+			return
+		}
+		g.startRematch(payload.Match)
+	}
+}
+
+// sendMatchEvent отправляет событие матча удаленному игроку. Потерянный голос
+// или начало реванша развели бы стороны по разным матчам, поэтому событие,
+// которому не хватило места в очереди отправки, ждет следующего шага
+// (flushMatchEvents), а следующие события встают за ним, не обгоняя его.
+func (g *Game) sendMatchEvent(eventType network.EventType, payload any) {
+	event, err := network.NewEvent(eventType, payload)
+	if err != nil {
+		log.Printf("send %s: %v", eventType, err)
+		return
+	}
+	g.matchOutbox = append(g.matchOutbox, event)
+	g.flushMatchEvents()
+}
+
+// flushMatchEvents отправляет события матча, которые ждут места в очереди отправки
+func (g *Game) flushMatchEvents() {
+	sent := 0
+	for _, event := range g.matchOutbox {
+		err := g.net.SendEvent(event)
+		if errors.Is(err, network.ErrEventQueueFull) {
+			break
+		}
+		if err != nil {
+			log.Printf("send %s: %v", event.Type, err)
+		}
+		sent++
+	}
+	g.matchOutbox = g.matchOutbox[:copy(g.matchOutbox, g.matchOutbox[sent:])]
+}
+
+// drawNames выводит в сетевой игре имена игроков над их персонажами
+func (g *Game) drawNames(screen canvas.Canvas) {
+	if !g.isVersus() {
+		return
+	}
+
+	view := g.camera.View()
+	// Имена рисуются цветом команды игрока
+	if !g.isDead(g.local) {
+		screen.DrawNameTag(g.options.PlayerName, g.player, view, canvas.TeamColor(g.lobby.local.Team))
+	}
+	if hello, ok := g.net.Remote(); ok && g.remote != nil && !g.remoteDead() {
+		screen.DrawNameTag(hello.Name, g.remote, view, canvas.TeamColor(g.remoteTeam()))
+	}
+}
+
+// drawMatch выводит таймер матча, счет и экран голосования за реванш
+func (g *Game) drawMatch(screen canvas.Canvas) {
+	if !g.isVersus() {
+		return
+	}
+
+	if g.isDeathmatch() {
+		g.drawDeathmatch(screen)
+		return
+	}
+
+	match := g.match
+	switch {
+	case g.isCoop():
+		screen.DrawCoopInfo(g.coop.stage+1, g.coop.stages)
+	case g.isCTF():
+		red, blue := match.localScore, match.remoteScore
+		if g.lobby.local.Team != 0 {
+			red, blue = blue, red
+		}
+		screen.DrawCTFInfo(red, blue, g.ctf.limit, (config.VersusMatchFrames-match.frames)/ticksPerSecond)
+	default:
+		screen.DrawMatchInfo(match.localScore, match.remoteScore, (config.VersusMatchFrames-match.frames)/ticksPerSecond, g.isHost())
+	}
+
+	if !match.over {
+		return
+	}
+
+	status := i18n.T("match.over")
+	if g.isCoop() {
+		status = i18n.T("coop.campaign_complete")
+	}
+	switch {
+	case match.localVote == voteDecline || match.remoteVote == voteDecline:
+		status = i18n.T("match.no_rematch")
+	case match.localVote == voteAccept && match.remoteVote == voteAccept:
+		status = i18n.T("match.rematch_starting")
+	case match.localVote == voteAccept:
+		status = i18n.T("match.waiting_vote")
+	case match.remoteVote == voteAccept:
+		status = i18n.T("match.rematch_offered")
+	}
+	screen.DrawBanner(status)
+}
@@ -0,0 +1,203 @@
+package game
+
+import (
+	"log"
+	"math"
+
+	"platformer/internal/budget"
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/network"
+	"platformer/internal/physics"
+	"platformer/internal/sound"
+	"platformer/internal/timer"
+	"platformer/internal/transform"
+)
+
+// weapon — оружие персонажа
+type weapon int
+
+const (
+	weaponBlaster weapon = iota // Стреляет летящими пулями
+	weaponRifle                 // Точная винтовка: мгновенный луч
+	weaponCount
+)
+
+// weaponNames — ключи переводов названий оружия для интерфейса
+var weaponNames = map[weapon]string{
+	weaponBlaster: "weapon.blaster",
+	weaponRifle:   "weapon.rifle",
+}
+
+// eventRifleShot — выстрел винтовки. Попадание определяет хост, а клиенту
+// событие нужно, чтобы нарисовать след луча.
+const eventRifleShot network.EventType = "rifle_shot"
+
+// rifleShotPayload — данные выстрела винтовки: отрезок следа луча
+type rifleShotPayload struct {
+	FromX float64 `json:"x"` // Точка вылета луча
+	FromY float64 `json:"y"`
+	ToX   float64 `json:"to_x"` // Точка, где луч остановился
+	ToY   float64 `json:"to_y"`
+}
+
+// rifleMask — слои, которые задевает луч персонажа клиента: как и его пули,
+// он попадает и в NPC хоста. Луч персонажа хоста проходит сквозь NPC, если это не кооператив.
+const rifleMask = physics.LayerPlatform | physics.LayerPlayer | physics.LayerNPC
+
+// handleWeaponSwitch переключает оружие по нажатию Q
+func (g *Game) handleWeaponSwitch() {
+	keyPressed := g.input.SwitchWeapon
+	if keyPressed && !g.prevWeaponKeyPressed {
+		g.weapon = (g.weapon + 1) % weaponCount
+	}
+	g.prevWeaponKeyPressed = keyPressed
+}
+
+// weaponCooldown возвращает перезарядку текущего оружия
+func (g *Game) weaponCooldown() *timer.Cooldown {
+	if g.weapon == weaponRifle {
+		return &g.rifleCooldown
+	}
+	return &g.shootCooldown
+}
+
+// fire стреляет из текущего оружия
+func (g *Game) fire() {
+	if g.profile != nil && g.pilot == g.local {
+		g.profile.Stats.ShotsFired++
+	}
+	g.sounds.Play(sound.EffectShoot)
+	if g.weapon == weaponRifle {
+		g.fireRifle()
+		return
+	}
+	g.shoot()
+}
+
+// muzzle возвращает точку вылета выстрела и направление по X
+func (g *Game) muzzle() (physics.Vec, float64) {
+	player := g.player
+	_, centerY := player.Center()
+	if player.FacingRight {
+		return physics.Vec{X: player.Right(), Y: centerY}, 1
+	}
+	return physics.Vec{X: player.Left(), Y: centerY}, -1
+}
+
+// fireRifle выпускает мгновенный луч до первой платформы или игрока на пути.
+// Выстрелы обоих персонажей моделирует хост: он сразу наносит урон и отправляет
+// клиенту след луча.
+func (g *Game) fireRifle() {
+	origin, directionX := g.muzzle()
+
+	mask := physics.LayerPlatform | physics.LayerPlayer
+	switch {
+	case g.isCoop() || (g.second() != nil && !isBot(g.second())):
+		// В кооперативе и вдвоем за одним компьютером луч проходит сквозь союзника и попадает в NPC
+		mask = physics.LayerPlatform | physics.LayerNPC
+	case g.pilot == g.opponent:
+		mask = rifleMask
+	}
+	restore := g.rewindHost(g.pilot)
+	hit, ok := g.raycastExcept(origin, physics.Vec{X: directionX}, config.RifleRange, mask, g.player)
+	restore()
+	end := physics.Vec{X: origin.X + directionX*config.RifleRange, Y: origin.Y}
+	if ok {
+		end = hit.Point
+		switch target := hit.Body.Collider.(type) {
+		case *entities.NPC:
+			target.TakeHit(entities.NewHit(config.RifleDamage, origin.X, hit.Point.X))
+			g.showDamage(target, config.RifleDamage)
+			g.camera.Shake(config.CameraShakeStrength, config.CameraShakeFrames)
+		case *entities.Player:
+			if victim := g.pilotOf(target); victim != nil {
+				g.hitPilot(victim, config.RifleDamage, origin.X)
+			}
+		}
+	}
+	g.world.Add(newTracer(origin, end))
+
+	if g.net == nil {
+		return
+	}
+	event, err := network.NewEvent(eventRifleShot, rifleShotPayload{FromX: origin.X, FromY: origin.Y, ToX: end.X, ToY: end.Y})
+	if err == nil {
+		err = g.net.SendEvent(event)
+	}
+	if err != nil {
+		log.Printf("send %s: %v", eventRifleShot, err)
+	}
+}
+
+// pilotOf возвращает пилота персонажа player (nil - персонаж никем не моделируется)
+func (g *Game) pilotOf(player *entities.Player) *pilot {
+	switch {
+	case g.opponent != nil && player == g.opponent.player:
+		return g.opponent
+	}
+	for _, p := range g.locals {
+		if player == p.player {
+			return p
+		}
+	}
+	return nil
+}
+
+// handleRifleShotEvent рисует у клиента след выстрела, который смоделировал хост
+func (g *Game) handleRifleShotEvent(event network.Event) {
+	var payload rifleShotPayload
+	if err := event.Decode(&payload); err != nil {
+		return
+	}
+	g.world.Add(newTracer(physics.Vec{X: payload.FromX, Y: payload.FromY}, physics.Vec{X: payload.ToX, Y: payload.ToY}))
+}
+
+// kindTracer — тип сущности следа выстрела
+const kindTracer entities.Kind = "tracer"
+
+// tracer — короткоживущий след выстрела винтовки от дула до точки попадания
+type tracer struct {
+	id       entities.ID
+	from, to physics.Vec
+	frames   int // Сколько кадров след еще виден
+}
+
+// newTracer создает след выстрела
+func newTracer(from, to physics.Vec) *tracer {
+	return &tracer{id: entities.NextID(), from: from, to: to, frames: config.TracerFrames}
+}
+
+// EntityID возвращает идентификатор следа
+func (t *tracer) EntityID() entities.ID { return t.id }
+
+// EntityKind возвращает тип сущности
+func (t *tracer) EntityKind() entities.Kind { return kindTracer }
+
+// BudgetKind относит след к ограниченным визуальным эффектам
+func (t *tracer) BudgetKind() budget.Kind { return budget.KindEffects }
+
+// DrawLayer возвращает слой отрисовки следа выстрела
+func (t *tracer) DrawLayer() canvas.Layer { return canvas.LayerProjectiles }
+
+// Bounds возвращает прямоугольник, в котором лежит след
+func (t *tracer) Bounds() entities.AABB {
+	return entities.AABB{
+		X:      math.Min(t.from.X, t.to.X),
+		Y:      math.Min(t.from.Y, t.to.Y) - 1,
+		Width:  math.Max(math.Abs(t.to.X-t.from.X), 1),
+		Height: math.Max(math.Abs(t.to.Y-t.from.Y), 2),
+	}
+}
+
+// Update отсчитывает время жизни следа
+func (t *tracer) Update(ctx entities.Context) bool {
+	t.frames--
+	return t.frames > 0
+}
+
+// Draw рисует след, который постепенно гаснет
+func (t *tracer) Draw(screen entities.Canvas, view transform.View) {
+	screen.DrawTracer(view, t.from.X, t.from.Y, t.to.X, t.to.Y, float64(t.frames)/config.TracerFrames)
+}
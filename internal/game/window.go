@@ -0,0 +1,141 @@
+package game
+
+import (
+	"platformer/internal/preload"
+	"platformer/internal/sound"
+)
+
+// Игра не работает с окном напрямую: клавиатуру, звук и ресурсы отрисовки
+// ей дает Options.Window, а кадр она рисует на canvas.Canvas в Draw. Поэтому
+// симуляция, меню и сборка кадра собираются без графической библиотеки,
+// а без окна (Options.Window == nil) клавиши не нажаты и звука нет.
+
+// Key — клавиша, которую игра читает вне управления персонажем: меню,
+// консоль и горячие клавиши. Управление персонажем приходит через InputSource.
+type Key int
+
+const (
+	KeyArrowLeft Key = iota
+	KeyArrowRight
+	KeyArrowUp
+	KeyArrowDown
+	KeyA
+	KeyB
+	KeyD
+	KeyF
+	KeyK
+	KeyM
+	KeyN
+	KeyP
+	KeyR
+	KeyS
+	KeyT
+	KeyW
+	KeyEnter
+	KeySpace
+	KeyEscape
+	KeyBackspace
+	KeyBackquote
+	KeyTab
+	KeyShift
+	KeyF2
+	KeyF3
+	KeyF5
+	KeyF9
+	KeyF10
+	KeyF12
+)
+
+// Keyboard — клавиатура окна
+type Keyboard interface {
+	// Pressed сообщает, зажата ли клавиша
+	Pressed(key Key) bool
+	// JustPressed сообщает, нажата ли клавиша на этом кадре
+	JustPressed(key Key) bool
+	// AppendChars дописывает к chars символы, введенные на этом кадре
+	AppendChars(chars []rune) []rune
+}
+
+// Sounds — звуковые эффекты и музыка
+type Sounds interface {
+	Play(effect sound.Effect)
+	PlayMusic(name string) // Плавно переключает музыку на трек name (пустой - тишина)
+	DuckMusic(ducked bool) // Приглушает музыку на время диалога
+	UpdateMusic()          // Продвигает плавные переходы громкости музыки
+	SetVolume(volume sound.Volume)
+}
+
+// Window — окно, в котором идет игра
+type Window interface {
+	Keyboard
+	Sounds
+
+	// Preload добавляет в загрузку ресурсы окна: спрайты, шрифты и звук
+	Preload(loader *preload.Loader)
+	// SetSkin заменяет спрайт персонажа скином из PNG-файла path (пустой - встроенный спрайт)
+	SetSkin(path string) error
+	// SetTitle меняет заголовок окна
+	SetTitle(title string)
+
+	// Renderer возвращает название текущего бэкенда отрисовки мира
+	Renderer() string
+	// SetRenderer переключает отрисовку на бэкенд name (неизвестное название не меняет бэкенд)
+	SetRenderer(name string)
+	// NextRenderer переключает отрисовку на следующий бэкенд по кругу
+	NextRenderer()
+	// FrameRate возвращает измеренные частоту кадров и частоту обновлений окна
+	FrameRate() (fps, tps float64)
+}
+
+// noWindow — окно игры без окна: клавиши не нажаты, звука нет
+type noWindow struct {
+	silence
+}
+
+// Pressed сообщает, что клавиша не зажата
+func (noWindow) Pressed(Key) bool { return false }
+
+// JustPressed сообщает, что клавиша не нажата
+func (noWindow) JustPressed(Key) bool { return false }
+
+// AppendChars возвращает chars без новых символов
+func (noWindow) AppendChars(chars []rune) []rune { return chars }
+
+// Preload ничего не добавляет в загрузку
+func (noWindow) Preload(*preload.Loader) {}
+
+// SetSkin ничего не загружает
+func (noWindow) SetSkin(string) error { return nil }
+
+// SetTitle ничего не меняет
+func (noWindow) SetTitle(string) {}
+
+// Renderer возвращает пустое название: без окна мир не рисуется
+func (noWindow) Renderer() string { return "" }
+
+// SetRenderer ничего не меняет
+func (noWindow) SetRenderer(string) {}
+
+// NextRenderer ничего не меняет
+func (noWindow) NextRenderer() {}
+
+// FrameRate возвращает нулевые частоты
+func (noWindow) FrameRate() (fps, tps float64) { return 0, 0 }
+
+// silence — звук, который ничего не играет (нет окна или идет повтор ввода)
+type silence struct{}
+
+// Play ничего не играет
+func (silence) Play(sound.Effect) {}
+
+// PlayMusic ничего не играет
+func (silence) PlayMusic(string) {}
+
+// DuckMusic ничего не приглушает
+func (silence) DuckMusic(bool) {}
+
+// UpdateMusic ничего не продвигает
+func (silence) UpdateMusic() {}
+
+// SetVolume ничего не меняет
+func (silence) SetVolume(sound.Volume) {}
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/mission"
+	"platformer/internal/network"
+)
+
+// Изменения уровня в сетевой игре моделирует хост: рушит платформы при побеге,
+// ведет задание сопровождения и двигает платформы. Клиент получает от хоста
+// не отдельные изменения, а все отличия уровня от его данных: так событие можно
+// повторить без вреда, а подключившийся позже клиент сразу видит тот же уровень.
+// Хост отправляет состояние при каждом изменении и раз в config.WorldSyncFrames
+// кадров, чтобы движущиеся платформы клиента не уходили от хоста.
+
+// eventWorld — хост рассылает изменения уровня
+const eventWorld network.EventType = "world"
+
+// worldPayload — отличия уровня хоста от данных уровня. Платформы указаны
+// индексами в данных уровня: ID сущностей у хоста и клиента разные.
+type worldPayload struct {
+	Generation int           `json:"generation"`          // Номер запуска уровня у хоста: растет при каждом перезапуске
+	Removed    []int         `json:"removed,omitempty"`   // Рухнувшие платформы
+	Crumbling  []int         `json:"crumbling,omitempty"` // Рушащиеся платформы
+	MoverFrame int           `json:"mover_frame"`         // Сколько кадров движутся движущиеся платформы
+	Escort     mission.State `json:"escort"`              // Состояние задания сопровождения
+}
+
+// worldSync — синхронизация изменений уровня
+type worldSync struct {
+	levelPlatforms []*entities.Platform // Платформы в порядке данных уровня, включая рухнувшие
+	removed        []int                // Индексы рухнувших платформ
+	escort         mission.State        // Последнее отправленное состояние задания сопровождения (хост)
+	generation     int                  // Номер запуска уровня (у клиента - последний полученный от хоста)
+	known          bool                 // Клиент уже получил состояние уровня от хоста
+	changed        bool                 // Уровень изменился и еще не отправлен клиенту (хост)
+	frames         int                  // Кадров с последней отправки (хост)
+}
+
+// resetWorldSync запоминает платформы только что запущенного уровня
+func (g *Game) resetWorldSync() {
+	w := &g.worldSync
+	w.levelPlatforms = append(w.levelPlatforms[:0], g.platforms...)
+	w.removed = w.removed[:0]
+	w.escort = mission.StateWaiting
+	if g.isHost() {
+		w.generation++
+		w.changed = true
+	}
+}
+
+// platformIndex возвращает индекс платформы в данных уровня (-1 - не платформа уровня)
+func (g *Game) platformIndex(platform *entities.Platform) int {
+	for i, p := range g.worldSync.levelPlatforms {
+		if p == platform {
+			return i
+		}
+	}
+	return -1
+}
+
+// worldChanged отмечает, что уровень хоста изменился и его нужно отправить клиенту
+func (g *Game) worldChanged() {
+	g.worldSync.changed = true
+}
+
+// updateWorldSync на хосте отправляет клиенту изменившийся уровень
+// и раз в config.WorldSyncFrames кадров - уровень без изменений
+func (g *Game) updateWorldSync() {
+	if !g.isVersus() || !g.isHost() || !g.net.Connected() {
+		return
+	}
+	w := &g.worldSync
+	if g.escort != nil && g.escort.State != w.escort {
+		w.escort = g.escort.State
+		w.changed = true
+	}
+	w.frames++
+	if !w.changed && w.frames < config.WorldSyncFrames {
+		return
+	}
+	g.sendWorld()
+}
+
+// sendWorld отправляет клиенту отличия уровня хоста от данных уровня
+func (g *Game) sendWorld() {
+	if !g.net.Connected() {
+		return
+	}
+	w := &g.worldSync
+	w.changed, w.frames = false, 0
+	g.sendMatchEvent(eventWorld, g.worldState())
+}
+
+// worldState собирает отличия уровня от данных уровня
+func (g *Game) worldState() worldPayload {
+	w := g.worldSync
+	payload := worldPayload{Generation: w.generation, Removed: w.removed}
+	for i, platform := range w.levelPlatforms {
+		if platform.Crumbling() {
+			payload.Crumbling = append(payload.Crumbling, i)
+		}
+	}
+	if len(g.movers) > 0 {
+		payload.MoverFrame = g.movers[0].frame
+	}
+	if g.escort != nil {
+		payload.Escort = g.escort.State
+	}
+	return payload
+}
+
+// handleWorldEvent применяет у клиента уровень хоста
+func (g *Game) handleWorldEvent(event network.Event) {
+	if g.isHost() {
+		return
+	}
+	var payload worldPayload
+	if err := event.Decode(&payload); err != nil {
+		return
+	}
+
+	w := &g.worldSync
+	if w.known && payload.Generation != w.generation {
+		// Хост перезапустил уровень: платформы начинаются заново по данным уровня
+		g.buildPlatforms()
+		g.resetEscape()
+		g.invalidateMinimap()
+	}
+	w.generation, w.known = payload.Generation, true
+
+	for _, i := range payload.Crumbling {
+		if i >= 0 && i < len(w.levelPlatforms) && !w.levelPlatforms[i].Crumbling() {
+			// Клиент не отсчитывает обрушение: платформа только дрожит, пока хост ее не уберет
+			w.levelPlatforms[i].CrumbleFrames = 1
+		}
+	}
+	for _, i := range payload.Removed {
+		if i >= 0 && i < len(w.levelPlatforms) {
+			g.removePlatform(w.levelPlatforms[i])
+		}
+	}
+	for _, mover := range g.movers {
+		mover.frame = payload.MoverFrame
+	}
+	if g.escort != nil {
+		g.escort.State = payload.Escort
+	}
+}
@@ -0,0 +1,113 @@
+package highscore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"platformer/internal/leaderboard"
+)
+
+// DefaultFileName — имя файла рекордов в каталоге настроек пользователя
+const DefaultFileName = "highscores.json"
+
+// DefaultPath возвращает путь к файлу рекордов по умолчанию
+// (например, ~/.config/platformer/highscores.json)
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("highscore: %w", err)
+	}
+	return filepath.Join(dir, "platformer", DefaultFileName), nil
+}
+
+// Table — локальная таблица лучших результатов, которая хранится в JSON-файле.
+// Записи идут от лучшей к худшей: больше очков, при равенстве - меньше время.
+type Table struct {
+	path    string
+	limit   int
+	entries []leaderboard.Entry
+}
+
+// fileData — формат файла рекордов
+type fileData struct {
+	Entries []leaderboard.Entry `json:"entries"`
+}
+
+// Load загружает таблицу из файла path и оставляет в ней не больше limit записей.
+// Если файла еще нет, возвращается пустая таблица.
+func Load(path string, limit int) (*Table, error) {
+	table := &Table{path: path, limit: limit}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return table, nil
+	}
+	if err != nil {
+		return table, fmt.Errorf("highscore: read %s: %w", path, err)
+	}
+
+	var file fileData
+	if err := json.Unmarshal(data, &file); err != nil {
+		return table, fmt.Errorf("highscore: parse %s: %w", path, err)
+	}
+	table.entries = file.Entries
+	table.sortAndTrim()
+	return table, nil
+}
+
+// Entries возвращает записи таблицы от лучшей к худшей
+func (t *Table) Entries() []leaderboard.Entry {
+	return append([]leaderboard.Entry(nil), t.entries...)
+}
+
+// Add добавляет результат и возвращает его место в таблице (с 1) или 0, если он в таблицу не попал
+func (t *Table) Add(entry leaderboard.Entry) int {
+	t.entries = append(t.entries, entry)
+	t.sortAndTrim()
+
+	for i := range t.entries {
+		if t.entries[i] == entry {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Save записывает таблицу в файл. Данные сначала пишутся во временный файл,
+// чтобы прерванная запись не испортила уже сохраненные рекорды.
+func (t *Table) Save() error {
+	data, err := json.MarshalIndent(fileData{Entries: t.entries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("highscore: encode: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return fmt.Errorf("highscore: %w", err)
+	}
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("highscore: write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		return fmt.Errorf("highscore: save %s: %w", t.path, err)
+	}
+	return nil
+}
+
+// sortAndTrim упорядочивает записи и отбрасывает лишние
+func (t *Table) sortAndTrim() {
+	sort.SliceStable(t.entries, func(i, j int) bool {
+		if t.entries[i].Score != t.entries[j].Score {
+			return t.entries[i].Score > t.entries[j].Score
+		}
+		return t.entries[i].TimeMs < t.entries[j].TimeMs
+	})
+	if t.limit > 0 && len(t.entries) > t.limit {
+		t.entries = t.entries[:t.limit]
+	}
+}
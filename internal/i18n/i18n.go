@@ -0,0 +1,99 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage — язык, на котором написаны исходные строки игры. Строки, которых нет
+// в переводе текущего языка, берутся из него.
+const DefaultLanguage = "ru"
+
+// locales — файлы переводов locales/<язык>.json: объект "ключ": "строка".
+// Строки могут содержать подстановки fmt (%d, %s), аргументы передаются в T.
+//
+//go:embed locales/*.json
+var locales embed.FS
+
+// catalog — строки одного языка по ключам
+type catalog map[string]string
+
+var (
+	catalogs = make(map[string]catalog) // Загруженные языки
+	language = DefaultLanguage          // Текущий язык
+)
+
+func init() {
+	entries, err := locales.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: %v", err))
+	}
+	for _, entry := range entries {
+		data, err := locales.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: %v", err))
+		}
+		var strs catalog
+		if err := json.Unmarshal(data, &strs); err != nil {
+			// Встроенные переводы проверяются при сборке, ошибка здесь - ошибка в файле перевода
+			panic(fmt.Sprintf("i18n: parse %s: %v", entry.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))] = strs
+	}
+	if _, ok := catalogs[DefaultLanguage]; !ok {
+		panic("i18n: missing default language " + DefaultLanguage)
+	}
+}
+
+// Languages возвращает коды доступных языков по алфавиту
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// SetLanguage переключает язык интерфейса (например, "en")
+func SetLanguage(lang string) error {
+	if _, ok := catalogs[lang]; !ok {
+		return fmt.Errorf("i18n: unknown language %q (available: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	language = lang
+	return nil
+}
+
+// Language возвращает код текущего языка
+func Language() string {
+	return language
+}
+
+// Name возвращает название языка lang на нем самом (для меню выбора языка)
+func Name(lang string) string {
+	if name, ok := catalogs[lang]["language.name"]; ok {
+		return name
+	}
+	return lang
+}
+
+// T возвращает строку key на текущем языке, подставляя в нее args.
+// Если строки нет ни в текущем языке, ни в языке по умолчанию, возвращается сам ключ -
+// так тексты из данных уровня, не заведенные в переводы, показываются как есть.
+func T(key string, args ...any) string {
+	text, ok := catalogs[language][key]
+	if !ok {
+		text, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		text = key
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
@@ -0,0 +1,175 @@
+package leaderboard
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRequestTimeout = 5 * time.Second
+	defaultTopLimit       = 10
+)
+
+// ErrDisabled возвращается, если клиент таблицы рекордов не настроен
+var ErrDisabled = errors.New("leaderboard: client is not configured")
+
+// Board — название таблицы рекордов на сервере
+type Board string
+
+// BoardTimeAttack — таблица рекордов на время прохождения уровня
+const BoardTimeAttack Board = "time-attack"
+
+// DailyBoard возвращает таблицу ежедневного испытания для указанного дня (по UTC)
+func DailyBoard(day time.Time) Board {
+	return Board("daily-" + day.UTC().Format("2006-01-02"))
+}
+
+// Entry — одна запись таблицы рекордов
+type Entry struct {
+	Name   string `json:"name"`
+	Level  string `json:"level"`
+	Score  int    `json:"score"`
+	TimeMs int64  `json:"time_ms"`
+}
+
+// Result — подписанный результат, отправляемый на сервер
+type Result struct {
+	Board     Board  `json:"board"`
+	Entry     Entry  `json:"entry"`
+	Timestamp int64  `json:"timestamp"` // Время отправки (Unix, секунды)
+	Signature string `json:"signature"` // HMAC-SHA256 от канонической строки результата
+}
+
+// Sign вычисляет подпись результата общим секретом клиента и сервера
+func Sign(secret []byte, result Result) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(canonical(result)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись результата (используется сервером)
+func Verify(secret []byte, result Result) bool {
+	expected := Sign(secret, result)
+	return hmac.Equal([]byte(expected), []byte(result.Signature))
+}
+
+// canonical строит строку для подписи; порядок полей фиксирован,
+// чтобы подпись не зависела от способа кодирования JSON
+func canonical(result Result) string {
+	return strings.Join([]string{
+		string(result.Board),
+		result.Entry.Name,
+		result.Entry.Level,
+		strconv.Itoa(result.Entry.Score),
+		strconv.FormatInt(result.Entry.TimeMs, 10),
+		strconv.FormatInt(result.Timestamp, 10),
+	}, "\n")
+}
+
+// Client отправляет результаты и загружает таблицы рекордов по HTTP.
+// Нулевой указатель на Client допустим: все методы возвращают ErrDisabled.
+type Client struct {
+	endpoint string
+	secret   []byte
+	http     *http.Client
+}
+
+// NewClient создает клиента для сервера endpoint (например, https://example.com/api).
+// Если endpoint пустой, возвращает nil — таблица рекордов отключена.
+func NewClient(endpoint, secret string) *Client {
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	if endpoint == "" {
+		return nil
+	}
+
+	return &Client{
+		endpoint: endpoint,
+		secret:   []byte(secret),
+		http:     &http.Client{Timeout: defaultRequestTimeout},
+	}
+}
+
+// Submit подписывает и отправляет результат в таблицу board
+func (c *Client) Submit(ctx context.Context, board Board, entry Entry) error {
+	if c == nil {
+		return ErrDisabled
+	}
+
+	result := Result{
+		Board:     board,
+		Entry:     entry,
+		Timestamp: time.Now().Unix(),
+	}
+	result.Signature = Sign(c.secret, result)
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.boardURL(board, "results"), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("leaderboard: submit to %s failed: %s", board, resp.Status)
+	}
+	return nil
+}
+
+// Top загружает лучшие limit записей таблицы board
+func (c *Client) Top(ctx context.Context, board Board, limit int) ([]Entry, error) {
+	if c == nil {
+		return nil, ErrDisabled
+	}
+	if limit <= 0 {
+		limit = defaultTopLimit
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.boardURL(board, "top")+"?limit="+strconv.Itoa(limit), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("leaderboard: fetch %s failed: %s", board, resp.Status)
+	}
+
+	var entries []Entry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("leaderboard: decode %s: %w", board, err)
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (c *Client) boardURL(board Board, action string) string {
+	return c.endpoint + "/boards/" + url.PathEscape(string(board)) + "/" + action
+}
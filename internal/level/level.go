@@ -0,0 +1,491 @@
+package level
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"platformer/internal/config"
+)
+
+// Rect — прямоугольная область уровня в мировых координатах
+type Rect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Intersects проверяет, пересекается ли прямоугольник с другим прямоугольником
+func (r Rect) Intersects(x, y, width, height float64) bool {
+	return x < r.X+r.Width && x+width > r.X &&
+		y < r.Y+r.Height && y+height > r.Y
+}
+
+// ContainsPoint проверяет, находится ли точка внутри прямоугольника
+func (r Rect) ContainsPoint(x, y float64) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// Материалы платформ
+const (
+	MaterialDefault = ""    // Обычная поверхность с трением config.Friction
+	MaterialIce     = "ice" // Скользкий лед
+	MaterialMud     = "mud" // Вязкая грязь
+)
+
+// Platform — сплошная платформа уровня
+type Platform struct {
+	Rect
+	Material string  `json:"material,omitempty"` // Материал поверхности (влияет на трение)
+	Motion   *Motion `json:"motion,omitempty"`   // Движение платформы (nil - платформа неподвижна)
+	Conveyor float64 `json:"conveyor,omitempty"` // Скорость ленты конвейера в пикселях за кадр (положительная = вправо)
+}
+
+// Motion описывает движение платформы или зоны гибели туда и обратно: от начальной позиции
+// до точки, сдвинутой на (DX, DY), за Frames кадров и обратно
+type Motion struct {
+	DX     float64 `json:"dx"`
+	DY     float64 `json:"dy"`
+	Frames int     `json:"frames"`
+}
+
+// Point — точка уровня (например, место появления)
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// NPCSpawn описывает NPC, который появляется на уровне
+type NPCSpawn struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	Escort bool    `json:"escort,omitempty"` // Этого NPC нужно довести до выхода
+}
+
+// Room — комната уровня. Камера не выходит за границы текущей комнаты,
+// а переход между комнатами происходит только через двери.
+type Room struct {
+	ID       string    `json:"id"`
+	Bounds   Rect      `json:"bounds"`
+	Music    string    `json:"music,omitempty"`    // Трек комнаты (пустой - трек уровня)
+	Lighting *Lighting `json:"lighting,omitempty"` // Освещение комнаты (nil - освещение уровня)
+	Weather  *Weather  `json:"weather,omitempty"`  // Погода в комнате (nil - погода уровня)
+}
+
+// Виды погоды
+const (
+	WeatherNone = "none" // Ясно: отменяет погоду уровня в комнате
+	WeatherRain = "rain" // Дождь
+	WeatherSnow = "snow" // Снег
+)
+
+// Weather описывает погоду уровня или комнаты
+type Weather struct {
+	Kind      string  `json:"kind"`
+	Intensity float64 `json:"intensity"`          // Густота осадков от 0 до 1
+	Wind      float64 `json:"wind,omitempty"`     // Снос частиц ветром в пикселях за кадр
+	Slippery  bool    `json:"slippery,omitempty"` // Дождь делает обычные платформы скользкими
+}
+
+// Lighting описывает темный уровень или комнату: общую яркость и источники света
+type Lighting struct {
+	Ambient      float64 `json:"ambient"`          // Яркость без источников света: 0 - полная темнота, 1 - темноты нет
+	PlayerRadius float64 `json:"player_radius"`    // Радиус света, который несет персонаж (0 - персонаж без света)
+	Lights       []Light `json:"lights,omitempty"` // Неподвижные источники света
+}
+
+// Light — неподвижный источник света
+type Light struct {
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	Radius    float64 `json:"radius"`
+	Intensity float64 `json:"intensity,omitempty"` // Сила света от 0 до 1 (0 - полная сила)
+}
+
+// Door — дверь, ведущая в другую комнату
+type Door struct {
+	Bounds Rect   `json:"bounds"`
+	To     string `json:"to"` // ID комнаты, в которую ведет дверь
+}
+
+// Виды триггеров уровня
+const (
+	TriggerExit       = "exit"       // Выход с уровня: уровень пройден
+	TriggerCheckpoint = "checkpoint" // Контрольная точка: здесь игрок появится после падения
+	TriggerDamage     = "damage"     // Опасная зона: наносит урон, пока игрок внутри
+	TriggerMessage    = "message"    // Сценка: показывает сообщение
+	TriggerGravity    = "gravity"    // Переворачивает гравитацию игрока при входе
+	TriggerKill       = "kill"       // Зона гибели (пропасть, пресс): персонаж погибает, как только коснется ее
+	TriggerEscape     = "escape"     // Начало побега (Level.Escape): камера едет сама, уровень позади рушится
+)
+
+// Trigger — несплошная область, которая срабатывает, когда игрок входит в нее или выходит
+type Trigger struct {
+	ID      string  `json:"id,omitempty"`
+	Kind    string  `json:"kind"`
+	Bounds  Rect    `json:"bounds"`
+	Once    bool    `json:"once,omitempty"`    // Сработать только один раз
+	Damage  int     `json:"damage,omitempty"`  // Урон опасной зоны
+	Message string  `json:"message,omitempty"` // Текст сценки
+	Motion  *Motion `json:"motion,omitempty"`  // Движение зоны гибели (например, пресса) по тем же правилам, что и у платформ
+}
+
+// TeamCount — сколько команд в сетевой игре
+const TeamCount = 2
+
+// SpawnPoint — точка появления игрока в сетевой игре. Игроки занимают подходящие
+// их команде точки по возрастанию ID: хост - первую, клиент - вторую.
+type SpawnPoint struct {
+	Point
+	ID   int  `json:"id"`
+	Team *int `json:"team,omitempty"` // Команда, игроки которой здесь появляются (nil - любая)
+}
+
+// For сообщает, могут ли здесь появляться игроки команды team
+func (s SpawnPoint) For(team int) bool {
+	return s.Team == nil || *s.Team == team
+}
+
+// Base — база команды в режиме захвата флага: на ней стоит флаг команды
+// и появляются ее игроки, а принесенный сюда чужой флаг засчитывается
+type Base struct {
+	Team   int  `json:"team"` // Номер команды (с 0)
+	Bounds Rect `json:"bounds"`
+}
+
+// Escape описывает сцену побега: когда игрок входит в триггер вида TriggerEscape,
+// камера начинает сама ехать вправо, а платформы позади нее рушатся по таймеру.
+// За левым краем камеры едет зона гибели: отставший игрок погибает,
+// и уровень начинается заново.
+type Escape struct {
+	ScrollSpeed   float64 `json:"scroll_speed"`   // Скорость камеры в пикселях за кадр
+	CrumbleFrames int     `json:"crumble_frames"` // Через сколько кадров рушится платформа, которую миновала камера
+}
+
+// Level описывает данные уровня
+type Level struct {
+	Name        string       `json:"name"`
+	Width       float64      `json:"width"`
+	Height      float64      `json:"height"`
+	Spawn       Point        `json:"spawn"`
+	Platforms   []Platform   `json:"platforms"`
+	NPCs        []NPCSpawn   `json:"npcs"`
+	Exit        *Rect        `json:"exit,omitempty"`
+	Rooms       []Room       `json:"rooms,omitempty"`
+	Doors       []Door       `json:"doors,omitempty"`
+	Triggers    []Trigger    `json:"triggers,omitempty"`
+	Bases       []Base       `json:"bases,omitempty"`        // Базы команд для захвата флага
+	SpawnPoints []SpawnPoint `json:"spawn_points,omitempty"` // Точки появления игроков в сетевой игре
+	Escape      *Escape      `json:"escape,omitempty"`
+	Camera      *Rect        `json:"camera,omitempty"`      // Границы камеры уровня без комнат (nil - весь уровень)
+	DeathPlane  *float64     `json:"death_plane,omitempty"` // Высота, ниже которой персонаж погибает (nil - нижняя граница уровня)
+	Music       string       `json:"music,omitempty"`       // Фоновая музыка уровня (пустая - без музыки)
+	Lighting    *Lighting    `json:"lighting,omitempty"`    // Освещение уровня (nil - уровень освещен полностью)
+	Weather     *Weather     `json:"weather,omitempty"`     // Погода уровня (nil - ясно)
+}
+
+// Default возвращает встроенный уровень: пол на всю ширину мира, три NPC и выход в конце
+func Default() *Level {
+	floorY := float64(config.WorldHeight - 60)
+
+	return &Level{
+		Name:   "escort",
+		Music:  "calm",
+		Width:  config.WorldWidth,
+		Height: config.WorldHeight,
+		Spawn:  Point{X: 100, Y: 100},
+		SpawnPoints: []SpawnPoint{
+			// Второй игрок появляется рядом с первым, а не в нем
+			{ID: 0, Point: Point{X: 100, Y: 100}},
+			{ID: 1, Point: Point{X: 180, Y: 100}},
+		},
+		Platforms: []Platform{
+			// Пол на всю ширину мира, чтобы персонаж не падал в бесконечность
+			{Rect: Rect{X: 0, Y: floorY, Width: config.WorldWidth, Height: 1000}},
+		},
+		NPCs: []NPCSpawn{
+			{X: 500, Y: config.WorldHeight - 100, Width: 40, Height: 40, Escort: true}, // NPC в центре карты
+			{X: 600, Y: config.WorldHeight - 100, Width: 40, Height: 40},               // NPC дальше
+			{X: 650, Y: config.WorldHeight - 100, Width: 40, Height: 40},               // NPC еще дальше
+		},
+		Exit: &Rect{
+			X:      config.EscortExitX,
+			Y:      floorY - config.EscortExitHeight,
+			Width:  config.EscortExitWidth,
+			Height: config.EscortExitHeight,
+		},
+	}
+}
+
+// Load загружает уровень из JSON-файла
+func Load(path string) (*Level, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse разбирает уровень из JSON и проверяет его корректность
+func Parse(data []byte) (*Level, error) {
+	var lvl Level
+	if err := json.Unmarshal(data, &lvl); err != nil {
+		return nil, fmt.Errorf("parse level: %w", err)
+	}
+	if err := lvl.Validate(); err != nil {
+		return nil, err
+	}
+	return &lvl, nil
+}
+
+// Validate проверяет, что данные уровня согласованы
+func (l *Level) Validate() error {
+	if l.Width <= 0 || l.Height <= 0 {
+		return errors.New("level: width and height must be positive")
+	}
+	if l.Camera != nil && (l.Camera.Width <= 0 || l.Camera.Height <= 0) {
+		return errors.New("level: camera bounds must have a positive size")
+	}
+
+	ids := make(map[string]bool, len(l.Rooms))
+	for _, room := range l.Rooms {
+		if room.ID == "" {
+			return errors.New("level: room without id")
+		}
+		if ids[room.ID] {
+			return fmt.Errorf("level: duplicate room id %q", room.ID)
+		}
+		ids[room.ID] = true
+	}
+
+	if err := l.Lighting.validate("level"); err != nil {
+		return err
+	}
+	if err := l.Weather.validate("level"); err != nil {
+		return err
+	}
+	for _, room := range l.Rooms {
+		if err := room.Lighting.validate("room " + room.ID); err != nil {
+			return err
+		}
+		if err := room.Weather.validate("room " + room.ID); err != nil {
+			return err
+		}
+	}
+
+	for i, door := range l.Doors {
+		if !ids[door.To] {
+			return fmt.Errorf("level: door %d leads to unknown room %q", i, door.To)
+		}
+	}
+
+	for i, platform := range l.Platforms {
+		switch platform.Material {
+		case MaterialDefault, MaterialIce, MaterialMud:
+		default:
+			return fmt.Errorf("level: platform %d has unknown material %q", i, platform.Material)
+		}
+		if platform.Motion != nil && platform.Motion.Frames <= 0 {
+			return fmt.Errorf("level: platform %d motion frames must be positive", i)
+		}
+	}
+
+	escapeTriggers := 0
+	for i, trigger := range l.Triggers {
+		if trigger.Kind == TriggerEscape {
+			escapeTriggers++
+		}
+		switch trigger.Kind {
+		case TriggerExit, TriggerCheckpoint, TriggerDamage, TriggerMessage, TriggerGravity, TriggerKill, TriggerEscape:
+		default:
+			return fmt.Errorf("level: trigger %d has unknown kind %q", i, trigger.Kind)
+		}
+		if trigger.Motion != nil && trigger.Kind != TriggerKill {
+			return fmt.Errorf("level: trigger %d of kind %q cannot move", i, trigger.Kind)
+		}
+		if trigger.Motion != nil && trigger.Motion.Frames <= 0 {
+			return fmt.Errorf("level: trigger %d motion frames must be positive", i)
+		}
+	}
+
+	teams := make(map[int]bool, len(l.Bases))
+	for i, base := range l.Bases {
+		if base.Team < 0 || base.Team >= TeamCount {
+			return fmt.Errorf("level: base %d has unknown team %d", i, base.Team)
+		}
+		if teams[base.Team] {
+			return fmt.Errorf("level: team %d has more than one base", base.Team)
+		}
+		if base.Bounds.Width <= 0 || base.Bounds.Height <= 0 {
+			return fmt.Errorf("level: base %d must have a positive size", i)
+		}
+		teams[base.Team] = true
+	}
+
+	spawnIDs := make(map[int]bool, len(l.SpawnPoints))
+	for i, spawn := range l.SpawnPoints {
+		if spawnIDs[spawn.ID] {
+			return fmt.Errorf("level: spawn point %d repeats id %d", i, spawn.ID)
+		}
+		if spawn.Team != nil && (*spawn.Team < 0 || *spawn.Team >= TeamCount) {
+			return fmt.Errorf("level: spawn point %d has unknown team %d", i, *spawn.Team)
+		}
+		if !l.Bounds().ContainsPoint(spawn.X, spawn.Y) {
+			return fmt.Errorf("level: spawn point %d is outside the level", i)
+		}
+		spawnIDs[spawn.ID] = true
+	}
+
+	if l.Escape != nil && l.Escape.ScrollSpeed <= 0 {
+		return errors.New("level: escape scroll speed must be positive")
+	}
+	switch {
+	case l.Escape != nil && escapeTriggers == 0:
+		return fmt.Errorf("level: escape needs a trigger of kind %q", TriggerEscape)
+	case l.Escape == nil && escapeTriggers > 0:
+		return fmt.Errorf("level: trigger of kind %q without escape settings", TriggerEscape)
+	}
+
+	return nil
+}
+
+// validate проверяет параметры освещения; where называет уровень или комнату в ошибке
+func (l *Lighting) validate(where string) error {
+	if l == nil {
+		return nil
+	}
+	if l.Ambient < 0 || l.Ambient > 1 {
+		return fmt.Errorf("level: %s lighting ambient must be between 0 and 1", where)
+	}
+	if l.PlayerRadius < 0 {
+		return fmt.Errorf("level: %s lighting player radius must not be negative", where)
+	}
+	for i, light := range l.Lights {
+		if light.Radius <= 0 {
+			return fmt.Errorf("level: %s light %d radius must be positive", where, i)
+		}
+		if light.Intensity < 0 || light.Intensity > 1 {
+			return fmt.Errorf("level: %s light %d intensity must be between 0 and 1", where, i)
+		}
+	}
+	return nil
+}
+
+// validate проверяет параметры погоды; where называет уровень или комнату в ошибке
+func (w *Weather) validate(where string) error {
+	if w == nil {
+		return nil
+	}
+	switch w.Kind {
+	case WeatherNone, WeatherRain, WeatherSnow:
+	default:
+		return fmt.Errorf("level: %s has unknown weather %q", where, w.Kind)
+	}
+	if w.Intensity < 0 || w.Intensity > 1 {
+		return fmt.Errorf("level: %s weather intensity must be between 0 and 1", where)
+	}
+	return nil
+}
+
+// RoomWeather возвращает погоду комнаты index: свою погоду комнаты или погоду уровня
+// (nil - ясно)
+func (l *Level) RoomWeather(index int) *Weather {
+	if index >= 0 && index < len(l.Rooms) && l.Rooms[index].Weather != nil {
+		return l.Rooms[index].Weather
+	}
+	return l.Weather
+}
+
+// RoomLighting возвращает освещение комнаты index: свое освещение комнаты
+// или освещение уровня (nil - темноты нет)
+func (l *Level) RoomLighting(index int) *Lighting {
+	if index >= 0 && index < len(l.Rooms) && l.Rooms[index].Lighting != nil {
+		return l.Rooms[index].Lighting
+	}
+	return l.Lighting
+}
+
+// CameraBounds возвращает границы, за которые камера не выходит в комнате
+// index: границы комнаты, а вне комнат - границы камеры уровня или весь уровень
+func (l *Level) CameraBounds(index int) Rect {
+	if index >= 0 && index < len(l.Rooms) {
+		return l.Rooms[index].Bounds
+	}
+	if l.Camera != nil {
+		return *l.Camera
+	}
+	return l.Bounds()
+}
+
+// DeathY возвращает высоту, ниже которой персонаж погибает
+func (l *Level) DeathY() float64 {
+	if l.DeathPlane != nil {
+		return *l.DeathPlane
+	}
+	return l.Height
+}
+
+// Bounds возвращает границы всего уровня
+func (l *Level) Bounds() Rect {
+	return Rect{Width: l.Width, Height: l.Height}
+}
+
+// SpawnPointsFor возвращает точки появления игроков команды team по возрастанию ID
+func (l *Level) SpawnPointsFor(team int) []Point {
+	spawns := make([]SpawnPoint, 0, len(l.SpawnPoints))
+	for _, spawn := range l.SpawnPoints {
+		if spawn.For(team) {
+			spawns = append(spawns, spawn)
+		}
+	}
+	sort.Slice(spawns, func(i, j int) bool { return spawns[i].ID < spawns[j].ID })
+
+	points := make([]Point, len(spawns))
+	for i, spawn := range spawns {
+		points[i] = spawn.Point
+	}
+	return points
+}
+
+// SpawnFor возвращает точку появления игрока slot (0 - хост, 1 - клиент) из команды team.
+// Если подходящих точек нет, игроки появляются в начале уровня рядом друг с другом.
+func (l *Level) SpawnFor(team, slot int) Point {
+	if points := l.SpawnPointsFor(team); len(points) > 0 {
+		return points[slot%len(points)]
+	}
+	return Point{X: l.Spawn.X + float64(slot)*2*config.PlayerWidth, Y: l.Spawn.Y}
+}
+
+// RoomIndex возвращает индекс комнаты по ID (или -1, если комната не найдена)
+func (l *Level) RoomIndex(id string) int {
+	for i, room := range l.Rooms {
+		if room.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// RoomAt возвращает индекс комнаты, содержащей точку (или -1, если комнат нет
+// или точка не попадает ни в одну из них)
+func (l *Level) RoomAt(x, y float64) int {
+	for i, room := range l.Rooms {
+		if room.Bounds.ContainsPoint(x, y) {
+			return i
+		}
+	}
+	return -1
+}
+
+// RoomBounds возвращает границы комнаты по индексу; для -1 — границы всего уровня
+func (l *Level) RoomBounds(index int) Rect {
+	if index < 0 || index >= len(l.Rooms) {
+		return l.Bounds()
+	}
+	return l.Rooms[index].Bounds
+}
@@ -0,0 +1,280 @@
+package matchmaking
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Сервер подбора игр хранит комнаты: хост регистрирует свою игру и получает
+// короткий код комнаты, а клиент по коду узнает адрес хоста. Сами игроки
+// соединяются напрямую (или через посредника relay://), сервер подбора
+// видит только запросы регистрации и поиска.
+//
+// HTTP API:
+//
+//	POST   /rooms         - зарегистрировать комнату (тело - Room), ответ - Registered
+//	PUT    /rooms/<код>   - продлить комнату (тело - {"token": ...})
+//	DELETE /rooms/<код>   - удалить комнату (тело - {"token": ...})
+//	GET    /rooms/<код>   - найти комнату, ответ - Room
+//
+// Комната без продления исчезает через RoomTTL.
+
+const (
+	defaultRequestTimeout = 5 * time.Second
+	defaultRefreshPeriod  = 10 * time.Second // Как часто хост продлевает комнату
+	defaultRetryDelay     = 3 * time.Second  // Пауза перед повтором неудавшейся регистрации
+
+	// RoomTTL — сколько комната живет без продления
+	RoomTTL = 30 * time.Second
+
+	codeLength   = 5
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // Без похожих друг на друга 0/O и 1/I
+)
+
+var (
+	// ErrDisabled возвращается, если клиент сервера подбора не настроен
+	ErrDisabled = errors.New("matchmaking: client is not configured")
+	// ErrNotFound возвращается, если комнаты с таким кодом нет
+	ErrNotFound = errors.New("matchmaking: room not found")
+)
+
+// Room — зарегистрированная игра
+type Room struct {
+	Code    string `json:"code,omitempty"` // Код комнаты (выдается сервером)
+	Name    string `json:"name"`           // Имя игрока-хоста
+	Level   string `json:"level"`          // Уровень хоста (пустой - встроенный)
+	Address string `json:"address"`        // Адрес для подключения к хосту
+}
+
+// Registered — ответ сервера на регистрацию комнаты
+type Registered struct {
+	Code  string `json:"code"`
+	Token string `json:"token"` // Ключ для продления и удаления комнаты
+}
+
+// tokenRequest — тело запросов продления и удаления
+type tokenRequest struct {
+	Token string `json:"token"`
+}
+
+// NormalizeCode приводит введенный игроком код к виду, в котором его выдает сервер
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// newCode создает случайный код комнаты
+func newCode() string {
+	var buf [codeLength]byte
+	_, _ = rand.Read(buf[:])
+	for i, b := range buf {
+		buf[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return string(buf[:])
+}
+
+// Client регистрирует и ищет комнаты на сервере подбора по HTTP.
+// Нулевой указатель на Client допустим: все методы возвращают ErrDisabled.
+type Client struct {
+	endpoint string
+	http     *http.Client
+}
+
+// NewClient создает клиента для сервера endpoint (например, http://example.com:4200).
+// Если endpoint пустой, возвращает nil — подбор игр отключен.
+func NewClient(endpoint string) *Client {
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	if endpoint == "" {
+		return nil
+	}
+
+	return &Client{
+		endpoint: endpoint,
+		http:     &http.Client{Timeout: defaultRequestTimeout},
+	}
+}
+
+// Lookup находит комнату по коду
+func (c *Client) Lookup(ctx context.Context, code string) (Room, error) {
+	if c == nil {
+		return Room{}, ErrDisabled
+	}
+
+	var room Room
+	err := c.do(ctx, http.MethodGet, c.roomURL(NormalizeCode(code)), nil, &room)
+	return room, err
+}
+
+// register регистрирует комнату
+func (c *Client) register(ctx context.Context, room Room) (Registered, error) {
+	var registered Registered
+	err := c.do(ctx, http.MethodPost, c.endpoint+"/rooms", room, &registered)
+	return registered, err
+}
+
+// do отправляет запрос с телом body (nil - без тела) и разбирает ответ в result (nil - не читать)
+func (c *Client) do(ctx context.Context, method, target string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("matchmaking: %s %s failed: %s", method, target, resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (c *Client) roomURL(code string) string {
+	return c.endpoint + "/rooms/" + url.PathEscape(code)
+}
+
+// Registration — комната хоста, которая продлевается, пока не будет закрыта
+type Registration struct {
+	client *Client
+	room   Room
+
+	mu    sync.Mutex
+	code  string
+	token string
+	err   error // Ошибка последней попытки регистрации
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Register регистрирует комнату room в фоне и продлевает ее, пока регистрация
+// не закрыта. Если сервер недоступен или забыл комнату, регистрация повторяется
+// (код комнаты при этом может смениться). Для nil-клиента возвращает nil.
+func (c *Client) Register(room Room) *Registration {
+	if c == nil {
+		return nil
+	}
+
+	r := &Registration{
+		client: c,
+		room:   room,
+		closed: make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Code возвращает код комнаты; пустой код - комната еще не зарегистрирована
+// (тогда err - причина последней неудачи, если она была).
+func (r *Registration) Code() (string, error) {
+	if r == nil {
+		return "", ErrDisabled
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.code, r.err
+}
+
+// Close прекращает продление комнаты и удаляет ее с сервера в фоне,
+// не дожидаясь ответа (комната без продления исчезнет и сама).
+func (r *Registration) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.closeOnce.Do(func() { close(r.closed) })
+	return nil
+}
+
+// run регистрирует комнату и продлевает ее до закрытия регистрации,
+// а после закрытия удаляет
+func (r *Registration) run() {
+	delay := time.Duration(0)
+	for {
+		select {
+		case <-r.closed:
+			r.remove()
+			return
+		case <-time.After(delay):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+		err := r.refresh(ctx)
+		cancel()
+
+		r.mu.Lock()
+		r.err = err
+		r.mu.Unlock()
+
+		delay = defaultRefreshPeriod
+		if err != nil {
+			delay = defaultRetryDelay
+		}
+	}
+}
+
+// remove удаляет зарегистрированную комнату с сервера
+func (r *Registration) remove() {
+	r.mu.Lock()
+	code, token := r.code, r.token
+	r.code = ""
+	r.mu.Unlock()
+	if code == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout)
+	defer cancel()
+	_ = r.client.do(ctx, http.MethodDelete, r.client.roomURL(code), tokenRequest{Token: token}, nil)
+}
+
+// refresh продлевает комнату, а если ее еще нет (или сервер ее забыл) - регистрирует
+func (r *Registration) refresh(ctx context.Context) error {
+	r.mu.Lock()
+	code, token := r.code, r.token
+	r.mu.Unlock()
+
+	if code != "" {
+		err := r.client.do(ctx, http.MethodPut, r.client.roomURL(code), tokenRequest{Token: token}, nil)
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+
+	registered, err := r.client.register(ctx, r.room)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.code, r.token = registered.Code, registered.Token
+	r.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,209 @@
+package matchmaking
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	maxRooms       = 1000 // Наибольшее число комнат на сервере
+	maxRequestBody = 4096 // Наибольший размер тела запроса
+	maxFieldLength = 256  // Наибольшая длина имени, уровня и адреса
+)
+
+// Server — сервер подбора игр; используется как http.Handler
+type Server struct {
+	mu    sync.Mutex
+	rooms map[string]*serverRoom
+}
+
+// serverRoom — комната на сервере
+type serverRoom struct {
+	room    Room
+	token   string
+	expires time.Time
+}
+
+// NewServer создает сервер подбора игр без комнат
+func NewServer() *Server {
+	return &Server{rooms: make(map[string]*serverRoom)}
+}
+
+// ServeHTTP обрабатывает запросы к /rooms и /rooms/<код>
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBody)
+
+	path := strings.Trim(r.URL.Path, "/")
+	if path == "rooms" {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleRegister(w, r)
+		return
+	}
+
+	code, ok := strings.CutPrefix(path, "rooms/")
+	if !ok || code == "" || strings.Contains(code, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	code = NormalizeCode(code)
+
+	switch r.Method {
+	case http.MethodGet:
+		s.handleLookup(w, r, code)
+	case http.MethodPut:
+		s.handleRefresh(w, r, code)
+	case http.MethodDelete:
+		s.handleRemove(w, r, code)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleRegister регистрирует комнату и выдает ей код
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var room Room
+	if err := json.NewDecoder(r.Body).Decode(&room); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	room.Address = hostAddress(room.Address, r.RemoteAddr)
+	if room.Address == "" || len(room.Address) > maxFieldLength ||
+		len(room.Name) > maxFieldLength || len(room.Level) > maxFieldLength {
+		http.Error(w, "bad room", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.expire()
+	if len(s.rooms) >= maxRooms {
+		s.mu.Unlock()
+		http.Error(w, "too many rooms", http.StatusServiceUnavailable)
+		return
+	}
+	code := newCode()
+	for s.rooms[code] != nil {
+		code = newCode()
+	}
+	room.Code = code
+	registered := Registered{Code: code, Token: newToken()}
+	s.rooms[code] = &serverRoom{room: room, token: registered.Token, expires: time.Now().Add(RoomTTL)}
+	s.mu.Unlock()
+
+	log.Printf("matchmaking: room %s for %q at %s", code, room.Name, room.Address)
+	writeJSON(w, registered)
+}
+
+// handleLookup возвращает комнату по коду
+func (s *Server) handleLookup(w http.ResponseWriter, r *http.Request, code string) {
+	s.mu.Lock()
+	s.expire()
+	found := s.rooms[code]
+	var room Room
+	if found != nil {
+		room = found.room
+	}
+	s.mu.Unlock()
+
+	if found == nil {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, room)
+}
+
+// handleRefresh продлевает комнату хоста
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request, code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found, ok := s.ownedRoom(w, r, code)
+	if !ok {
+		return
+	}
+	found.expires = time.Now().Add(RoomTTL)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRemove удаляет комнату хоста
+func (s *Server) handleRemove(w http.ResponseWriter, r *http.Request, code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.ownedRoom(w, r, code); !ok {
+		return
+	}
+	delete(s.rooms, code)
+	log.Printf("matchmaking: room %s closed", code)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ownedRoom находит комнату и проверяет ключ хоста из тела запроса;
+// при ошибке сам отвечает клиенту. Вызывается под s.mu.
+func (s *Server) ownedRoom(w http.ResponseWriter, r *http.Request, code string) (*serverRoom, bool) {
+	var req tokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return nil, false
+	}
+
+	s.expire()
+	found := s.rooms[code]
+	if found == nil {
+		http.NotFound(w, r)
+		return nil, false
+	}
+	if found.token != req.Token {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return nil, false
+	}
+	return found, true
+}
+
+// expire удаляет непродленные комнаты. Вызывается под s.mu.
+func (s *Server) expire() {
+	now := time.Now()
+	for code, room := range s.rooms {
+		if now.After(room.expires) {
+			delete(s.rooms, code)
+		}
+	}
+}
+
+// hostAddress дополняет адрес хоста без IP (например, ":4000") адресом,
+// с которого пришел запрос: так хосту не нужно знать свой внешний адрес
+func hostAddress(address, remoteAddr string) string {
+	address = strings.TrimSpace(address)
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host != "" {
+		// URL (ws://, relay://) и полные адреса передаются как есть
+		return address
+	}
+	remoteHost, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return ""
+	}
+	return net.JoinHostPort(remoteHost, port)
+}
+
+// newToken создает ключ хоста для управления комнатой
+func newToken() string {
+	var buf [16]byte
+	_, _ = rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
+
+// writeJSON отправляет value в ответе
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
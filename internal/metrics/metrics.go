@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // Регистрирует обработчики /debug/pprof/ в http.DefaultServeMux
+	"runtime"
+	"sync"
+	"time"
+
+	"platformer/internal/budget"
+	"platformer/internal/config"
+)
+
+// Timing — длительность операции за последнее окно замера
+type Timing struct {
+	Avg   time.Duration `json:"avg_ns"`
+	Max   time.Duration `json:"max_ns"`
+	Count int           `json:"count"` // Сколько раз операция выполнялась за окно
+}
+
+// Entities — количество игровых объектов в момент замера
+type Entities struct {
+	Platforms int `json:"platforms"`
+	NPCs      int `json:"npcs"`
+	Bullets   int `json:"bullets"`
+	Corpses   int `json:"corpses"`
+	World     int `json:"world"` // Прочие объекты мира (частицы, подбираемые предметы и т.д.)
+}
+
+// Memory — статистика выделений памяти за последнее окно замера
+type Memory struct {
+	HeapAlloc      uint64 `json:"heap_alloc"`       // Занятая куча, в байтах
+	AllocsPerFrame uint64 `json:"allocs_per_frame"` // Среднее число выделений за кадр
+	BytesPerFrame  uint64 `json:"bytes_per_frame"`  // Средний объем выделений за кадр
+	NumGC          uint32 `json:"num_gc"`           // Сколько всего прошло сборок мусора
+}
+
+// Snapshot — последние замеры производительности
+type Snapshot struct {
+	Tick     Timing        `json:"tick"` // Шаг симуляции
+	Draw     Timing        `json:"draw"` // Отрисовка кадра (без работы GPU)
+	Entities Entities      `json:"entities"`
+	Content  []budget.Stat `json:"content"` // Ограничения динамического содержимого
+	Memory   Memory        `json:"memory"`
+}
+
+// window накапливает длительности операции до конца окна замера
+type window struct {
+	total time.Duration
+	max   time.Duration
+	count int
+}
+
+// observe добавляет длительность d в окно
+func (w *window) observe(d time.Duration) {
+	w.total += d
+	w.max = max(w.max, d)
+	w.count++
+}
+
+// close возвращает итог окна и начинает новое
+func (w *window) close() Timing {
+	t := Timing{Max: w.max, Count: w.count}
+	if w.count > 0 {
+		t.Avg = w.total / time.Duration(w.count)
+	}
+	*w = window{}
+	return t
+}
+
+// Recorder собирает время шагов и отрисовки, количество объектов и статистику памяти.
+// Итоги обновляются раз в config.MetricsWindowFrames кадров, поэтому чтение
+// статистики памяти (с остановкой мира) не тормозит каждый кадр.
+// Замеры пишет игровой цикл, а читает HTTP-сервер, поэтому доступ защищен мьютексом.
+type Recorder struct {
+	mu       sync.Mutex
+	tick     window
+	draw     window
+	frames   int
+	mallocs  uint64 // Счетчики выделений на начало окна
+	bytes    uint64
+	snapshot Snapshot
+}
+
+// NewRecorder создает сборщик замеров
+func NewRecorder() *Recorder {
+	r := &Recorder{}
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	r.mallocs, r.bytes = mem.Mallocs, mem.TotalAlloc
+	return r
+}
+
+// ObserveTick записывает длительность шага симуляции
+func (r *Recorder) ObserveTick(d time.Duration) {
+	r.mu.Lock()
+	r.tick.observe(d)
+	r.mu.Unlock()
+}
+
+// ObserveDraw записывает длительность отрисовки кадра
+func (r *Recorder) ObserveDraw(d time.Duration) {
+	r.mu.Lock()
+	r.draw.observe(d)
+	r.mu.Unlock()
+}
+
+// Frame отмечает конец кадра с количеством объектов entities и по окончании окна
+// замера обновляет итоги, в том числе статистику ограничений content
+func (r *Recorder) Frame(entities Entities, content *budget.Tracker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.snapshot.Entities = entities
+	r.frames++
+	if r.frames < config.MetricsWindowFrames {
+		return
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	frames := uint64(r.frames)
+	r.snapshot.Tick = r.tick.close()
+	r.snapshot.Draw = r.draw.close()
+	r.snapshot.Content = content.Stats()
+	r.snapshot.Memory = Memory{
+		HeapAlloc:      mem.HeapAlloc,
+		AllocsPerFrame: (mem.Mallocs - r.mallocs) / frames,
+		BytesPerFrame:  (mem.TotalAlloc - r.bytes) / frames,
+		NumGC:          mem.NumGC,
+	}
+	r.mallocs, r.bytes = mem.Mallocs, mem.TotalAlloc
+	r.frames = 0
+}
+
+// Snapshot возвращает последние итоги замеров
+func (r *Recorder) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshot
+}
+
+// Serve запускает HTTP-сервер профилирования на addr (например, ":6060"):
+// /debug/pprof/ - профили net/http/pprof, /debug/vars - переменные expvar,
+// среди которых замеры r под именем "game". Вызывается не больше одного раза.
+// Ошибка занятого адреса возвращается сразу, остальная работа сервера идет в фоне.
+func Serve(addr string, r *Recorder) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("metrics: %w", err)
+	}
+	expvar.Publish("game", expvar.Func(func() any { return r.Snapshot() }))
+	go func() {
+		// Сервер работает до завершения программы
+		if err := http.Serve(listener, nil); err != nil {
+			log.Printf("metrics: %v", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,166 @@
+package mission
+
+import (
+	"math"
+
+	"platformer/internal/entities"
+	"platformer/internal/i18n"
+)
+
+// State описывает состояние задания сопровождения
+type State int
+
+const (
+	StateWaiting   State = iota // NPC ждет на месте, пока с ним не заговорят
+	StateFollowing              // NPC следует за игроком
+	StateDead                   // NPC погиб — задание провалено
+	StateDelivered              // NPC доведен до выхода — задание выполнено
+)
+
+// String возвращает название состояния
+func (s State) String() string {
+	switch s {
+	case StateWaiting:
+		return "waiting"
+	case StateFollowing:
+		return "following"
+	case StateDead:
+		return "dead"
+	case StateDelivered:
+		return "delivered"
+	default:
+		return "unknown"
+	}
+}
+
+// Area — прямоугольная область уровня (например, выход)
+type Area struct {
+	X, Y          float64
+	Width, Height float64
+}
+
+// Contains проверяет, находится ли прямоугольник целиком внутри области
+func (a Area) Contains(x, y, width, height float64) bool {
+	return x >= a.X && x+width <= a.X+a.Width &&
+		y >= a.Y && y+height <= a.Y+a.Height
+}
+
+// Escort — задание, в котором дружественный NPC должен дойти за игроком до выхода
+type Escort struct {
+	NPC   *entities.NPC // Сопровождаемый NPC
+	Exit  Area          // Зона выхода, куда нужно довести NPC
+	State State         // Текущее состояние задания
+
+	FollowSpeed    float64 // Скорость движения NPC
+	FollowDistance float64 // Дистанция, на которой NPC останавливается возле игрока
+	FallLimit      float64 // Если NPC упал ниже этой координаты, он погибает
+}
+
+// NewEscort создает задание сопровождения в состоянии ожидания
+func NewEscort(npc *entities.NPC, exit Area, followSpeed, followDistance, fallLimit float64) *Escort {
+	return &Escort{
+		NPC:            npc,
+		Exit:           exit,
+		State:          StateWaiting,
+		FollowSpeed:    followSpeed,
+		FollowDistance: followDistance,
+		FallLimit:      fallLimit,
+	}
+}
+
+// Interact обрабатывает разговор игрока с NPC:
+// ожидающий NPC начинает следовать за игроком, следующий — останавливается и ждет.
+func (e *Escort) Interact() {
+	switch e.State {
+	case StateWaiting:
+		e.State = StateFollowing
+	case StateFollowing:
+		e.State = StateWaiting
+	}
+}
+
+// Dialogue возвращает реплику NPC для текущего состояния задания
+func (e *Escort) Dialogue() string {
+	switch e.State {
+	case StateWaiting:
+		return i18n.T("escort.waiting")
+	case StateFollowing:
+		return i18n.T("escort.following")
+	case StateDead:
+		return "..."
+	case StateDelivered:
+		return i18n.T("escort.delivered")
+	default:
+		return ""
+	}
+}
+
+// Finished сообщает, завершено ли задание (успешно или нет)
+func (e *Escort) Finished() bool {
+	return e.State == StateDead || e.State == StateDelivered
+}
+
+// Following сообщает, следует ли NPC за игроком
+func (e *Escort) Following() bool {
+	return e.State == StateFollowing
+}
+
+// Completed сообщает, выполнено ли задание
+func (e *Escort) Completed() bool {
+	return e.State == StateDelivered
+}
+
+// Failed сообщает, провалено ли задание
+func (e *Escort) Failed() bool {
+	return e.State == StateDead
+}
+
+// Update задает NPC направление движения к игроку и проверяет итог задания.
+// Гравитация и столкновения NPC обрабатываются игрой отдельно.
+func (e *Escort) Update(playerX, playerWidth float64) {
+	npc := e.NPC
+	if e.Finished() {
+		npc.VelocityX = 0
+		return
+	}
+
+	// Проверяем, не погиб ли NPC от урона или падения
+	if npc.IsDead() || npc.Y > e.FallLimit {
+		npc.Health = 0
+		npc.VelocityX = 0
+		e.State = StateDead
+		return
+	}
+
+	// Проверяем, дошел ли NPC до выхода
+	if e.Exit.Contains(npc.X, npc.Y, npc.Width, npc.Height) {
+		npc.VelocityX = 0
+		e.State = StateDelivered
+		return
+	}
+
+	// Оглушенный NPC не управляет движением - скорость задана отбрасыванием
+	if npc.IsStunned() {
+		return
+	}
+
+	if e.State != StateFollowing {
+		npc.VelocityX = 0
+		return
+	}
+
+	// Двигаемся к игроку, пока не окажемся на расстоянии FollowDistance
+	dx := (playerX + playerWidth/2) - (npc.X + npc.Width/2)
+	if math.Abs(dx) <= e.FollowDistance {
+		npc.VelocityX = 0
+		return
+	}
+
+	if dx > 0 {
+		npc.VelocityX = e.FollowSpeed
+		npc.FacingRight = true
+	} else {
+		npc.VelocityX = -e.FollowSpeed
+		npc.FacingRight = false
+	}
+}
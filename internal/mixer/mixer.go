@@ -0,0 +1,142 @@
+package mixer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+
+	"platformer/internal/assets"
+	"platformer/internal/config"
+	"platformer/internal/sound"
+)
+
+// Manager загружает звуковые эффекты и музыку и проигрывает их по запросу игры.
+// Методы nil-менеджера ничего не делают, поэтому игра работает и без звука.
+type Manager struct {
+	context *audio.Context
+	effects map[sound.Effect][]byte // Декодированные PCM-данные эффектов
+	music   music
+	volume  sound.Volume
+}
+
+// NewManager создает аудиоконтекст и декодирует все встроенные эффекты.
+// Аудиоконтекст в программе может быть только один, поэтому менеджер создается один раз.
+func NewManager() (*Manager, error) {
+	m := &Manager{
+		context: audio.NewContext(config.AudioSampleRate),
+		volume:  sound.DefaultVolume(),
+	}
+
+	var err error
+	if m.effects, err = loadEffects(); err != nil {
+		return nil, err
+	}
+	if m.music, err = loadMusic(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// loadEffects загружает и декодирует эффекты. Имя файла эффекта без расширения совпадает с sound.Effect.
+func loadEffects() (map[sound.Effect][]byte, error) {
+	names, err := assets.Sounds("effects")
+	if err != nil {
+		return nil, fmt.Errorf("mixer: %w", err)
+	}
+
+	effects := make(map[sound.Effect][]byte, len(names))
+	for _, name := range names {
+		file, err := assets.GetSound("effects/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("mixer: %w", err)
+		}
+		pcm, err := decode(file)
+		if err != nil {
+			return nil, fmt.Errorf("mixer: decode %s: %w", name, err)
+		}
+		effects[sound.Effect(name)] = pcm
+	}
+	return effects, nil
+}
+
+// Reload заново загружает эффекты и музыку после изменения файлов (режим разработки).
+// Играющий трек плавно перезапускается с новыми данными. Если файлы не читаются,
+// остаются прежние звуки.
+func (m *Manager) Reload() error {
+	if m == nil {
+		return nil
+	}
+	effects, err := loadEffects()
+	if err != nil {
+		return err
+	}
+	music, err := loadMusic()
+	if err != nil {
+		return err
+	}
+	m.effects = effects
+	m.music.files = music.files
+
+	if current := m.music.current; current != nil {
+		m.music.fading = append(m.music.fading, current)
+		m.music.current = nil
+		m.PlayMusic(current.name)
+	}
+	return nil
+}
+
+// decode переводит эффект целиком в PCM с частотой аудиоконтекста
+func decode(file assets.Sound) ([]byte, error) {
+	stream, _, err := openStream(file)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(stream)
+}
+
+// openStream открывает поток декодирования WAV или OGG и возвращает его длину в байтах PCM
+func openStream(file assets.Sound) (io.ReadSeeker, int64, error) {
+	switch file.Format {
+	case ".wav":
+		stream, err := wav.DecodeWithSampleRate(config.AudioSampleRate, bytes.NewReader(file.Data))
+		if err != nil {
+			return nil, 0, err
+		}
+		return stream, stream.Length(), nil
+	case ".ogg":
+		stream, err := vorbis.DecodeWithSampleRate(config.AudioSampleRate, bytes.NewReader(file.Data))
+		if err != nil {
+			return nil, 0, err
+		}
+		return stream, stream.Length(), nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported format %q", file.Format)
+	}
+}
+
+// SetVolume применяет настройки громкости. Музыка меняет громкость со следующего
+// вызова UpdateMusic, эффекты - со следующего проигрывания.
+func (m *Manager) SetVolume(volume sound.Volume) {
+	if m == nil {
+		return
+	}
+	m.volume = volume
+}
+
+// Play проигрывает эффект один раз. Одновременно может звучать несколько эффектов.
+func (m *Manager) Play(effect sound.Effect) {
+	if m == nil || m.volume.EffectsLevel() == 0 {
+		return
+	}
+	pcm, ok := m.effects[effect]
+	if !ok {
+		return
+	}
+	player := m.context.NewPlayerFromBytes(pcm)
+	player.SetVolume(m.volume.EffectsLevel())
+	player.Play()
+}
@@ -0,0 +1,149 @@
+package mixer
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+
+	"platformer/internal/assets"
+	"platformer/internal/config"
+)
+
+// track — играющий музыкальный трек
+type track struct {
+	name   string
+	player *audio.Player
+	fade   float64 // Громкость плавного перехода: от 0 до 1
+}
+
+// music — фоновая музыка: текущий трек и треки, которые затихают после смены
+type music struct {
+	files   map[string]assets.Sound // Файлы треков: декодируются потоком при запуске
+	current *track
+	fading  []*track
+	duck    float64 // Множитель громкости для приглушения (1 - без приглушения)
+	ducked  bool    // Музыка должна быть приглушена
+}
+
+// loadMusic находит встроенные треки. Имя файла без расширения - название трека в данных уровня.
+// Сами файлы декодируются только при запуске трека.
+func loadMusic() (music, error) {
+	m := music{files: make(map[string]assets.Sound), duck: 1}
+
+	names, err := assets.Sounds("music")
+	if err != nil {
+		return m, fmt.Errorf("mixer: %w", err)
+	}
+	for _, name := range names {
+		file, err := assets.GetSound("music/" + name)
+		if err != nil {
+			return m, fmt.Errorf("mixer: %w", err)
+		}
+		m.files[name] = file
+	}
+	return m, nil
+}
+
+// PlayMusic плавно переключает фоновую музыку на трек name; пустое имя плавно выключает музыку.
+// Если трек уже играет, ничего не происходит.
+func (m *Manager) PlayMusic(name string) {
+	if m == nil {
+		return
+	}
+	mu := &m.music
+	if mu.current != nil && mu.current.name == name {
+		return
+	}
+	if mu.current == nil && name == "" {
+		return
+	}
+
+	if mu.current != nil {
+		mu.fading = append(mu.fading, mu.current)
+		mu.current = nil
+	}
+	if name == "" {
+		return
+	}
+
+	player, err := m.openTrack(name)
+	if err != nil {
+		log.Printf("music %q: %v", name, err)
+		return
+	}
+	mu.current = &track{name: name, player: player}
+	m.applyVolume(mu.current)
+	player.Play()
+}
+
+// openTrack создает бесконечно повторяющийся поток трека
+func (m *Manager) openTrack(name string) (*audio.Player, error) {
+	file, ok := m.music.files[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown track")
+	}
+	stream, length, err := openStream(file)
+	if err != nil {
+		return nil, err
+	}
+	return m.context.NewPlayer(audio.NewInfiniteLoop(stream, length))
+}
+
+// DuckMusic приглушает музыку (например, пока идет диалог) или возвращает обычную громкость
+func (m *Manager) DuckMusic(ducked bool) {
+	if m == nil {
+		return
+	}
+	m.music.ducked = ducked
+}
+
+// UpdateMusic продвигает плавные переходы между треками и приглушение.
+// Вызывается каждый кадр, в том числе в меню и на паузе.
+func (m *Manager) UpdateMusic() {
+	if m == nil {
+		return
+	}
+	mu := &m.music
+
+	duckTarget := 1.0
+	if mu.ducked {
+		duckTarget = config.MusicDuckVolume
+	}
+	duckStep := (1 - config.MusicDuckVolume) / config.MusicDuckFrames
+	switch {
+	case mu.duck < duckTarget:
+		mu.duck = min(mu.duck+duckStep, duckTarget)
+	case mu.duck > duckTarget:
+		mu.duck = max(mu.duck-duckStep, duckTarget)
+	}
+
+	fadeStep := 1.0 / config.MusicCrossfadeFrames
+	if t := mu.current; t != nil {
+		t.fade = min(t.fade+fadeStep, 1)
+		m.applyVolume(t)
+	}
+
+	// Затихшие треки останавливаются и освобождают поток
+	kept := mu.fading[:0]
+	for _, t := range mu.fading {
+		t.fade -= fadeStep
+		if t.fade <= 0 {
+			if err := t.player.Close(); err != nil {
+				log.Printf("music %q: %v", t.name, err)
+			}
+			continue
+		}
+		m.applyVolume(t)
+		kept = append(kept, t)
+	}
+	for i := len(kept); i < len(mu.fading); i++ {
+		mu.fading[i] = nil
+	}
+	mu.fading = kept
+}
+
+// applyVolume задает громкость трека с учетом перехода и приглушения
+func (m *Manager) applyVolume(t *track) {
+	t.player.SetVolume(m.volume.MusicLevel() * t.fade * m.music.duck)
+}
@@ -0,0 +1,195 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Хост может отключить клиента (kick) или запретить подключения с его адреса (ban).
+// Перед отключением хост отправляет кадр с причиной, чтобы клиент не пытался
+// переподключиться и показал игроку, почему игра прервалась. Клиенту с
+// запрещенного адреса хост отвечает тем же кадром сразу после приветствия протокола.
+// Запреты действуют, пока работает хост, и проверяются по IP-адресу; у клиентов,
+// пришедших через посредника (relay://), адрес неизвестен.
+
+// defaultKickTimeout — сколько ждать отправки кадра отключения перед закрытием соединения
+const defaultKickTimeout = time.Second
+
+// disconnectReason — причина, по которой хост отключает клиента
+type disconnectReason byte
+
+const (
+	reasonKicked   disconnectReason = 1 // Хост отключил клиента
+	reasonBanned   disconnectReason = 2 // Адрес клиента запрещен
+	reasonPassword disconnectReason = 3 // Клиент прислал неверный пароль сервера (hello.go)
+	reasonFull     disconnectReason = 4 // На хосте нет места (limit.go)
+)
+
+var (
+	// ErrKicked — хост отключил этого клиента
+	ErrKicked = errors.New("network: kicked by the host")
+	// ErrBanned — хост запретил подключения с адреса этого клиента
+	ErrBanned = errors.New("network: banned by the host")
+	// ErrWrongPassword — хост не пустил клиента с неверным паролем сервера
+	ErrWrongPassword = errors.New("network: wrong server password")
+	// ErrNotConnected возвращается, если команде хоста нужен подключенный клиент, а его нет
+	ErrNotConnected = errors.New("network: no player is connected")
+
+	errNotHost = errors.New("network: only the host can do this")
+)
+
+// err возвращает ошибку, которую видит отключенный клиент
+func (r disconnectReason) err() error {
+	switch r {
+	case reasonBanned:
+		return ErrBanned
+	case reasonPassword:
+		return ErrWrongPassword
+	case reasonFull:
+		return ErrServerFull
+	default:
+		return ErrKicked
+	}
+}
+
+// isDisconnect сообщает, отключил ли хост клиента намеренно
+func isDisconnect(err error) bool {
+	return errors.Is(err, ErrKicked) || errors.Is(err, ErrBanned) || errors.Is(err, ErrWrongPassword) || errors.Is(err, ErrServerFull)
+}
+
+// Kick отключает подключенного клиента. Хост сразу снова ждет клиентов,
+// не ожидая возвращения отключенного, а клиент не переподключается.
+func (m *Manager) Kick() error {
+	if m == nil || !m.host {
+		return errNotHost
+	}
+	p := m.getPeer()
+	if p == nil {
+		return ErrNotConnected
+	}
+	p.disconnect(reasonKicked)
+	return nil
+}
+
+// Ban запрещает подключения с IP-адреса address и отключает клиента,
+// если он подключен с этого адреса.
+func (m *Manager) Ban(address string) error {
+	if m == nil || !m.host {
+		return errNotHost
+	}
+	ip := net.ParseIP(strings.TrimSpace(address))
+	if ip == nil {
+		return fmt.Errorf("network: %q is not an IP address", address)
+	}
+
+	m.mu.Lock()
+	if m.bans == nil {
+		m.bans = make(map[string]bool)
+	}
+	m.bans[ip.String()] = true
+	p := m.peer
+	m.mu.Unlock()
+
+	if p != nil && remoteIP(p.conn) == ip.String() {
+		p.disconnect(reasonBanned)
+	}
+	return nil
+}
+
+// Unban снимает запрет с IP-адреса address; false - адрес не был запрещен.
+func (m *Manager) Unban(address string) bool {
+	if m == nil {
+		return false
+	}
+	ip := net.ParseIP(strings.TrimSpace(address))
+	if ip == nil {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	banned := m.bans[ip.String()]
+	delete(m.bans, ip.String())
+	return banned
+}
+
+// Bans возвращает запрещенные адреса по порядку.
+func (m *Manager) Bans() []string {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	bans := make([]string, 0, len(m.bans))
+	for ip := range m.bans {
+		bans = append(bans, ip)
+	}
+	m.mu.RUnlock()
+
+	sort.Strings(bans)
+	return bans
+}
+
+// RemoteAddress возвращает IP-адрес подключенного игрока (false - соединения
+// нет или адрес неизвестен, например при игре через посредника).
+func (m *Manager) RemoteAddress() (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	p := m.getPeer()
+	if p == nil {
+		return "", false
+	}
+	ip := remoteIP(p.conn)
+	return ip, ip != ""
+}
+
+// isBanned сообщает, запрещен ли адрес, с которого пришло соединение conn
+func (m *Manager) isBanned(conn any) bool {
+	ip := remoteIP(conn)
+	if ip == "" {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.bans[ip]
+}
+
+// remoteIP возвращает IP-адрес другой стороны соединения (пустой - неизвестен)
+func remoteIP(conn any) string {
+	addressed, ok := conn.(interface{ RemoteAddr() net.Addr })
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addressed.RemoteAddr().String())
+	if err != nil {
+		return ""
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+// refuse отправляет клиенту, которого хост не пускает, причину отказа reason;
+// соединение закрывает вызывающий
+func refuse(conn net.Conn, reason disconnectReason) {
+	_ = conn.SetWriteDeadline(time.Now().Add(defaultKickTimeout))
+	writer := frameWriter{w: conn}
+	_ = writer.write(envelope{Type: MessageDisconnect, Reason: reason})
+}
+
+// disconnect отправляет другой стороне причину отключения и закрывает соединение
+func (p *peer) disconnect(reason disconnectReason) {
+	p.kicked.Store(true)
+	select {
+	case p.kickCh <- reason:
+	default:
+	}
+	// Если поток записи завис, соединение закрывается и без кадра
+	time.AfterFunc(defaultKickTimeout, func() { _ = p.close() })
+}
@@ -0,0 +1,167 @@
+package network
+
+import (
+	"sync"
+	"time"
+)
+
+// Обе стороны сразу после подключения и затем периодически отправляют запрос
+// (ping) со своим временем, а другая сторона отвечает (pong), возвращая это время.
+// Так каждая сторона знает время пути туда и обратно (пинг).
+//
+// Кроме того, клиенту нужно знать, на каком шаге симуляции хост находится прямо
+// сейчас: по этому общему времени он интерполирует персонажа хоста, а хост
+// проверяет выстрелы клиента. Поэтому хост добавляет в ответ номер последнего
+// отправленного шага и время, прошедшее с его отправки. Половина времени пути
+// дает момент по часам клиента, когда хост был на этом шаге. Из нескольких
+// последних замеров берется замер с самым коротким путем: на нем меньше всего
+// сказались задержки в очередях.
+
+const (
+	defaultClockInterval = time.Second            // Как часто повторяется замер
+	defaultClockRetry    = 100 * time.Millisecond // Пауза между запросами, пока замеров еще нет
+	defaultClockSamples  = 8                      // Сколько последних замеров учитывается
+)
+
+// clockMessage — запрос или ответ для замера пинга и синхронизации часов
+type clockMessage struct {
+	Reply     bool   // Ответ на запрос другой стороны
+	Time      int64  // Время отправки запроса по часам спрашивающего (мкс от подключения)
+	Tick      uint32 // Последний отправленный шаг (только в ответе хоста)
+	SinceTick int64  // Сколько мкс прошло с отправки этого шага (только в ответе хоста)
+}
+
+// ClockSync — оценка часов хоста на стороне клиента
+type ClockSync struct {
+	RTT      time.Duration // Время пути туда и обратно по последнему замеру
+	Tick     uint32        // Шаг симуляции хоста
+	TickTime time.Time     // Момент по часам клиента, когда хост был на шаге Tick
+}
+
+// clockSample — один замер часов
+type clockSample struct {
+	rtt      time.Duration
+	tick     uint32
+	tickTime time.Time
+}
+
+// peerClock — замеры одного соединения: пинг, на хосте - последний отправленный
+// шаг, на клиенте - последние замеры часов хоста
+type peerClock struct {
+	epoch time.Time // Начало отсчета времени запросов
+
+	mu       sync.Mutex
+	tick     uint32    // Последний отправленный шаг (хост)
+	tickSent time.Time // Когда он был отправлен (хост)
+	samples  []clockSample
+	rtt      time.Duration // Время пути по последнему замеру (0 - замеров еще нет)
+}
+
+// sent запоминает на хосте, что шаг tick отправлен сейчас
+func (c *peerClock) sent(tick uint32) {
+	c.mu.Lock()
+	c.tick, c.tickSent = tick, time.Now()
+	c.mu.Unlock()
+}
+
+// reply составляет ответ на запрос другой стороны. Хост, уже отправлявший
+// снимки, добавляет в ответ последний шаг.
+func (c *peerClock) reply(request clockMessage) clockMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	request.Reply = true
+	request.Tick = c.tick
+	if c.tick != 0 {
+		request.SinceTick = time.Since(c.tickSent).Microseconds()
+	}
+	return request
+}
+
+// record запоминает замер по ответу другой стороны
+func (c *peerClock) record(reply clockMessage) {
+	now := time.Now()
+	rtt := now.Sub(c.epoch) - time.Duration(reply.Time)*time.Microsecond
+	if rtt < 0 {
+		// Испорченный ответ
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rtt = rtt
+	if reply.Tick == 0 {
+		// Ответ клиента или хоста, который еще не отправлял снимков
+		return
+	}
+	c.samples = append(c.samples, clockSample{
+		rtt:      rtt,
+		tick:     reply.Tick,
+		tickTime: now.Add(-rtt/2 - time.Duration(reply.SinceTick)*time.Microsecond),
+	})
+	if extra := len(c.samples) - defaultClockSamples; extra > 0 {
+		c.samples = append(c.samples[:0], c.samples[extra:]...)
+	}
+}
+
+// estimate возвращает оценку часов хоста по лучшему из последних замеров
+func (c *peerClock) estimate() (ClockSync, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.samples) == 0 {
+		return ClockSync{}, false
+	}
+	best := c.samples[0]
+	for _, sample := range c.samples[1:] {
+		if sample.rtt < best.rtt {
+			best = sample
+		}
+	}
+	return ClockSync{RTT: c.rtt, Tick: best.tick, TickTime: best.tickTime}, true
+}
+
+// ping возвращает время пути по последнему замеру (0 - замеров еще нет)
+func (c *peerClock) ping() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rtt
+}
+
+// request составляет запрос с текущим временем
+func (c *peerClock) request() clockMessage {
+	return clockMessage{Time: time.Since(c.epoch).Microseconds()}
+}
+
+// clockLoop периодически замеряет пинг (а клиент - и часы хоста), пока
+// соединение открыто. Пока замеров нет, запросы идут чаще.
+func (p *peer) clockLoop() {
+	for {
+		request := p.clock.request()
+		if err := p.send(envelope{Type: MessageClock, Clock: &request}); err != nil {
+			return
+		}
+
+		wait := defaultClockInterval
+		if p.clock.ping() == 0 {
+			wait = defaultClockRetry
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-p.closed:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// handleClock отвечает на запрос другой стороны или запоминает ответ на свой
+func (p *peer) handleClock(msg clockMessage) {
+	if msg.Reply {
+		p.clock.record(msg)
+		return
+	}
+	reply := p.clock.reply(msg)
+	_ = p.send(envelope{Type: MessageClock, Clock: &reply})
+}
@@ -0,0 +1,162 @@
+package network
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Предсказание и интерполяцию трудно проверить в локальной сети, где кадры
+// приходят мгновенно и без потерь. Поэтому менеджер умеет изображать плохую
+// сеть (SetConditions): каждый кадр, который эта сторона отправляет и получает,
+// задерживается на Lag плюс случайную добавку до Jitter, а часть вводов
+// и снимков теряется. Порядок кадров при этом сохраняется, как в TCP,
+// а события не теряются: игра рассчитывает, что они доходят всегда.
+
+// delayLineSize — сколько задержанных кадров ждет в одну сторону (больше 15 секунд при 60 кадрах в секунду)
+const delayLineSize = 1024
+
+// Conditions — искусственные задержки и потери сети для отладки
+type Conditions struct {
+	Lag    time.Duration // Задержка каждого кадра в каждую сторону
+	Jitter time.Duration // Наибольшая случайная добавка к задержке
+	Loss   float64       // Доля теряемых вводов и снимков (от 0 до 1)
+}
+
+// active сообщает, меняют ли условия что-нибудь
+func (c Conditions) active() bool {
+	return c.Lag > 0 || c.Jitter > 0 || c.Loss > 0
+}
+
+// SetConditions включает изображение плохой сети для текущего и следующих
+// соединений (нулевые условия выключают его). Отрицательные значения
+// считаются нулем, а Loss больше 1 - единицей.
+func (m *Manager) SetConditions(conditions Conditions) {
+	if m == nil {
+		return
+	}
+	conditions.Lag = max(conditions.Lag, 0)
+	conditions.Jitter = max(conditions.Jitter, 0)
+	conditions.Loss = min(max(conditions.Loss, 0), 1)
+
+	m.mu.Lock()
+	m.conditions = conditions
+	peer := m.peer
+	m.mu.Unlock()
+	if peer != nil {
+		peer.simulate(conditions)
+	}
+}
+
+// netSim изображает плохую сеть для одного соединения
+type netSim struct {
+	conditions Conditions
+	in, out    *delayLine // Полученные и отправляемые кадры
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// simulate включает для соединения условия сети (нулевые - выключают)
+func (p *peer) simulate(conditions Conditions) {
+	if !conditions.active() {
+		p.sim.Store(nil)
+		return
+	}
+	p.sim.Store(&netSim{
+		conditions: conditions,
+		in:         newDelayLine(p.closed),
+		out:        newDelayLine(p.closed),
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	})
+}
+
+// delay возвращает задержку очередного кадра
+func (s *netSim) delay() time.Duration {
+	delay := s.conditions.Lag
+	if s.conditions.Jitter > 0 {
+		s.mu.Lock()
+		delay += time.Duration(s.rng.Int63n(int64(s.conditions.Jitter) + 1))
+		s.mu.Unlock()
+	}
+	return delay
+}
+
+// lose решает, потерять ли кадр. Теряются только вводы и снимки:
+// каждый следующий заменяет потерянный.
+func (s *netSim) lose(frame envelope) bool {
+	if s.conditions.Loss <= 0 || (frame.Type != MessageSnapshot && frame.Type != MessageInput) {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64() < s.conditions.Loss
+}
+
+// delayLine выполняет действия по очереди, каждое не раньше назначенного времени
+type delayLine struct {
+	items chan delayedItem
+	done  <-chan struct{}
+	last  atomic.Int64 // Время последнего назначенного действия (UnixNano): раньше него следующие не выполняются
+}
+
+// delayedItem — действие, отложенное до at
+type delayedItem struct {
+	at time.Time
+	fn func()
+}
+
+// newDelayLine запускает очередь, которая работает до закрытия done
+func newDelayLine(done <-chan struct{}) *delayLine {
+	d := &delayLine{items: make(chan delayedItem, delayLineSize), done: done}
+	go d.loop()
+	return d
+}
+
+// push откладывает fn на delay. Действие не обгоняет отложенные раньше,
+// даже если его задержка меньше. Переполненная очередь ждет.
+func (d *delayLine) push(delay time.Duration, fn func()) {
+	at := time.Now().Add(delay).UnixNano()
+	for {
+		last := d.last.Load()
+		if at < last {
+			at = last
+		}
+		if d.last.CompareAndSwap(last, at) {
+			break
+		}
+	}
+	select {
+	case d.items <- delayedItem{at: time.Unix(0, at), fn: fn}:
+	case <-d.done:
+	}
+}
+
+func (d *delayLine) loop() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		var item delayedItem
+		select {
+		case item = <-d.items:
+		case <-d.done:
+			return
+		}
+		if wait := time.Until(item.at); wait > 0 {
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-d.done:
+				return
+			}
+		}
+		item.fn()
+	}
+}
@@ -0,0 +1,550 @@
+package network
+
+import (
+	"encoding/binary"
+	"math"
+	"sync/atomic"
+)
+
+// Снимки мира передаются разностями: хост кодирует снимок относительно последнего
+// снимка, который клиент подтвердил (номер приходит вместе с вводом клиента),
+// и отправляет только изменившиеся поля игроков, новые, исчезнувшие и изменившиеся
+// пули и NPC. Пули летят по прямой, поэтому пуля, которая продолжает полет,
+// не передается вовсе: клиент сам продвигает ее от базового снимка.
+//
+// Чтобы обе стороны одинаково восстанавливали снимок, хост хранит в истории не свой
+// снимок, а тот, который получит клиент: с координатами, округленными до float32,
+// и пулями, продвинутыми той же функцией, что и у клиента. Если базовый снимок
+// устарел или клиент его не получил, хост отправляет ключевой кадр - полный снимок;
+// ключевые кадры также идут регулярно, раз в defaultKeyframeInterval снимков.
+
+const (
+	defaultKeyframeInterval = 60 // Снимков между ключевыми кадрами (раз в секунду)
+	defaultSnapshotHistory  = 32 // Сколько последних снимков хранится как базовые
+)
+
+// Биты изменившихся полей игрока
+const (
+	playerFieldX byte = 1 << iota
+	playerFieldY
+	playerFieldVelocityX
+	playerFieldVelocityY
+	playerFieldFlags
+	playerFieldHealth
+	playerFieldWeapon
+)
+
+// snapshotHistory — последние снимки по номерам для восстановления разностей
+type snapshotHistory struct {
+	seqs      [defaultSnapshotHistory]uint32
+	snapshots [defaultSnapshotHistory]SnapshotMessage
+}
+
+// put запоминает снимок с номером seq, вытесняя самый старый
+func (h *snapshotHistory) put(seq uint32, snapshot SnapshotMessage) {
+	i := seq % defaultSnapshotHistory
+	h.seqs[i] = seq
+	h.snapshots[i] = snapshot
+}
+
+// get возвращает снимок с номером seq, если он еще хранится
+func (h *snapshotHistory) get(seq uint32) (SnapshotMessage, bool) {
+	i := seq % defaultSnapshotHistory
+	if seq == 0 || h.seqs[i] != seq {
+		return SnapshotMessage{}, false
+	}
+	return h.snapshots[i], true
+}
+
+// deltaEncoder — сторона хоста: выбирает базовый снимок и помнит, что получит клиент
+type deltaEncoder struct {
+	history  snapshotHistory
+	keyframe uint32        // Номер последнего ключевого кадра
+	acked    atomic.Uint32 // Последний снимок, который подтвердил клиент (пишет поток чтения)
+}
+
+// ack отмечает, что клиент получил снимок seq
+func (e *deltaEncoder) ack(seq uint32) {
+	for {
+		prev := e.acked.Load()
+		if seq <= prev || e.acked.CompareAndSwap(prev, seq) {
+			return
+		}
+	}
+}
+
+// appendSnapshot кодирует снимок seq разностью от подтвержденного или ключевым кадром
+func (e *deltaEncoder) appendSnapshot(buf []byte, seq uint32, snapshot *SnapshotMessage) []byte {
+	current := quantizeSnapshot(snapshot)
+	buf = binary.AppendUvarint(buf, uint64(seq))
+
+	baseSeq := e.acked.Load()
+	base, ok := e.history.get(baseSeq)
+	if !ok || seq <= baseSeq || seq-e.keyframe >= defaultKeyframeInterval {
+		// Ключевой кадр: базового снимка нет
+		e.keyframe = seq
+		e.history.put(seq, current)
+		buf = binary.AppendUvarint(buf, 0)
+		return appendSnapshot(buf, &current)
+	}
+
+	ticks := seq - baseSeq
+	delta := diffSnapshot(&base, &current, ticks)
+	e.history.put(seq, delta.apply(&base, ticks))
+	buf = binary.AppendUvarint(buf, uint64(baseSeq))
+	return delta.appendTo(buf)
+}
+
+// deltaDecoder — сторона клиента: восстанавливает снимки из разностей
+type deltaDecoder struct {
+	history snapshotHistory
+	last    atomic.Uint32 // Номер последнего восстановленного снимка (читает отправка ввода)
+}
+
+// snapshot читает снимок из кадра вместе с его номером. Возвращает false, если
+// базового снимка уже нет: такой кадр пропускается до следующего ключевого кадра
+// (но номер все равно возвращается, чтобы кадр считался потерянным).
+func (e *deltaDecoder) snapshot(d *decoder) (SnapshotMessage, uint32, bool) {
+	seq := uint32(d.uvarint())
+	baseSeq := uint32(d.uvarint())
+
+	var snapshot SnapshotMessage
+	if baseSeq == 0 {
+		snapshot = d.snapshot()
+	} else {
+		base, ok := e.history.get(baseSeq)
+		if !ok {
+			return SnapshotMessage{}, seq, false
+		}
+		delta := d.snapshotDelta()
+		snapshot = delta.apply(&base, seq-baseSeq)
+	}
+	if d.err != nil {
+		return SnapshotMessage{}, 0, false
+	}
+
+	e.history.put(seq, snapshot)
+	if seq > e.last.Load() {
+		e.last.Store(seq)
+	}
+	return snapshot, seq, true
+}
+
+// snapshotDelta — разность двух снимков
+type snapshotDelta struct {
+	host, client               playerDelta
+	hostBullets, clientBullets bulletDelta
+	npcs                       npcDelta
+	scoresChanged              bool
+	hostScore, clientScore     int
+	inputSteps                 int64 // На сколько вырос номер примененного ввода клиента
+	tickSteps                  int64 // На сколько вырос номер шага хоста
+}
+
+// diffSnapshot вычисляет разность снимка current от base, отстоящего на ticks шагов
+func diffSnapshot(base, current *SnapshotMessage, ticks uint32) snapshotDelta {
+	return snapshotDelta{
+		host:          diffPlayer(base.Host, current.Host),
+		client:        diffPlayer(base.Client, current.Client),
+		hostBullets:   diffBullets(base.HostBullets, current.HostBullets, ticks),
+		clientBullets: diffBullets(base.ClientBullets, current.ClientBullets, ticks),
+		npcs:          diffNPCs(base.NPCs, current.NPCs),
+		scoresChanged: base.HostScore != current.HostScore || base.ClientScore != current.ClientScore,
+		hostScore:     current.HostScore,
+		clientScore:   current.ClientScore,
+		inputSteps:    int64(current.InputSeq) - int64(base.InputSeq),
+		tickSteps:     int64(current.Tick) - int64(base.Tick),
+	}
+}
+
+// apply восстанавливает снимок из base, отстоящего на ticks шагов
+func (d snapshotDelta) apply(base *SnapshotMessage, ticks uint32) SnapshotMessage {
+	s := SnapshotMessage{
+		Host:          d.host.apply(base.Host),
+		Client:        d.client.apply(base.Client),
+		HostBullets:   d.hostBullets.apply(base.HostBullets, ticks),
+		ClientBullets: d.clientBullets.apply(base.ClientBullets, ticks),
+		NPCs:          d.npcs.apply(base.NPCs),
+		HostScore:     base.HostScore,
+		ClientScore:   base.ClientScore,
+		InputSeq:      uint32(int64(base.InputSeq) + d.inputSteps),
+		Tick:          uint32(int64(base.Tick) + d.tickSteps),
+	}
+	if d.scoresChanged {
+		s.HostScore, s.ClientScore = d.hostScore, d.clientScore
+	}
+	return s
+}
+
+// appendTo кодирует разность
+func (d snapshotDelta) appendTo(buf []byte) []byte {
+	buf = d.host.appendTo(buf)
+	buf = d.client.appendTo(buf)
+	buf = d.hostBullets.appendTo(buf)
+	buf = d.clientBullets.appendTo(buf)
+	buf = d.npcs.appendTo(buf)
+	buf = appendBool(buf, d.scoresChanged)
+	if d.scoresChanged {
+		buf = binary.AppendVarint(buf, int64(d.hostScore))
+		buf = binary.AppendVarint(buf, int64(d.clientScore))
+	}
+	buf = binary.AppendVarint(buf, d.inputSteps)
+	return binary.AppendVarint(buf, d.tickSteps)
+}
+
+// snapshotDelta читает разность снимков
+func (d *decoder) snapshotDelta() snapshotDelta {
+	delta := snapshotDelta{
+		host:          d.playerDelta(),
+		client:        d.playerDelta(),
+		hostBullets:   d.bulletDelta(),
+		clientBullets: d.bulletDelta(),
+		npcs:          d.npcDelta(),
+		scoresChanged: d.byte1() != 0,
+	}
+	if delta.scoresChanged {
+		delta.hostScore = d.varint()
+		delta.clientScore = d.varint()
+	}
+	delta.inputSteps = d.varint64()
+	delta.tickSteps = d.varint64()
+	return delta
+}
+
+// playerDelta — изменившиеся поля игрока: маска полей и их новые значения
+type playerDelta struct {
+	fields byte
+	state  PlayerState
+}
+
+// diffPlayer сравнивает состояния игрока
+func diffPlayer(base, current PlayerState) playerDelta {
+	delta := playerDelta{state: current}
+	if current.X != base.X {
+		delta.fields |= playerFieldX
+	}
+	if current.Y != base.Y {
+		delta.fields |= playerFieldY
+	}
+	if current.VelocityX != base.VelocityX {
+		delta.fields |= playerFieldVelocityX
+	}
+	if current.VelocityY != base.VelocityY {
+		delta.fields |= playerFieldVelocityY
+	}
+	if playerFlags(current) != playerFlags(base) {
+		delta.fields |= playerFieldFlags
+	}
+	if current.Health != base.Health {
+		delta.fields |= playerFieldHealth
+	}
+	if current.Weapon != base.Weapon {
+		delta.fields |= playerFieldWeapon
+	}
+	return delta
+}
+
+// apply переносит изменившиеся поля на base
+func (d playerDelta) apply(base PlayerState) PlayerState {
+	p := base
+	if d.fields&playerFieldX != 0 {
+		p.X = d.state.X
+	}
+	if d.fields&playerFieldY != 0 {
+		p.Y = d.state.Y
+	}
+	if d.fields&playerFieldVelocityX != 0 {
+		p.VelocityX = d.state.VelocityX
+	}
+	if d.fields&playerFieldVelocityY != 0 {
+		p.VelocityY = d.state.VelocityY
+	}
+	if d.fields&playerFieldFlags != 0 {
+		setPlayerFlags(&p, playerFlags(d.state))
+	}
+	if d.fields&playerFieldHealth != 0 {
+		p.Health = d.state.Health
+	}
+	if d.fields&playerFieldWeapon != 0 {
+		p.Weapon = d.state.Weapon
+	}
+	return p
+}
+
+// appendTo кодирует маску и изменившиеся поля
+func (d playerDelta) appendTo(buf []byte) []byte {
+	buf = append(buf, d.fields)
+	if d.fields&playerFieldX != 0 {
+		buf = appendFloats(buf, d.state.X)
+	}
+	if d.fields&playerFieldY != 0 {
+		buf = appendFloats(buf, d.state.Y)
+	}
+	if d.fields&playerFieldVelocityX != 0 {
+		buf = appendFloats(buf, d.state.VelocityX)
+	}
+	if d.fields&playerFieldVelocityY != 0 {
+		buf = appendFloats(buf, d.state.VelocityY)
+	}
+	if d.fields&playerFieldFlags != 0 {
+		buf = append(buf, playerFlags(d.state))
+	}
+	if d.fields&playerFieldHealth != 0 {
+		buf = binary.AppendVarint(buf, int64(d.state.Health))
+	}
+	if d.fields&playerFieldWeapon != 0 {
+		buf = binary.AppendVarint(buf, int64(d.state.Weapon))
+	}
+	return buf
+}
+
+// playerDelta читает изменившиеся поля игрока
+func (d *decoder) playerDelta() playerDelta {
+	delta := playerDelta{fields: d.byte1()}
+	if delta.fields&playerFieldX != 0 {
+		delta.state.X = d.float()
+	}
+	if delta.fields&playerFieldY != 0 {
+		delta.state.Y = d.float()
+	}
+	if delta.fields&playerFieldVelocityX != 0 {
+		delta.state.VelocityX = d.float()
+	}
+	if delta.fields&playerFieldVelocityY != 0 {
+		delta.state.VelocityY = d.float()
+	}
+	if delta.fields&playerFieldFlags != 0 {
+		setPlayerFlags(&delta.state, d.byte1())
+	}
+	if delta.fields&playerFieldHealth != 0 {
+		delta.state.Health = d.varint()
+	}
+	if delta.fields&playerFieldWeapon != 0 {
+		delta.state.Weapon = d.varint()
+	}
+	return delta
+}
+
+// bulletDelta — исчезнувшие пули и пули, которые нельзя продвинуть от базового снимка
+// (новые или сменившие скорость после рикошета)
+type bulletDelta struct {
+	removed []uint64
+	changed []BulletState
+}
+
+// predictBullet продвигает пулю на ticks шагов полета по прямой. Результат
+// округляется до float32, как и все передаваемые координаты, а явные преобразования
+// не дают компилятору объединить умножение со сложением: у обеих сторон
+// результат должен совпадать до бита.
+func predictBullet(b BulletState, ticks uint32) BulletState {
+	n := float64(ticks)
+	b.X = quantize(b.X + float64(n*b.VelocityX))
+	b.Y = quantize(b.Y + float64(n*b.VelocityY))
+	return b
+}
+
+// bulletDriftTolerance — насколько продвинутая пуля может разойтись с настоящей,
+// прежде чем ее состояние будет отправлено заново
+const bulletDriftTolerance = 0.01
+
+// bulletOnCourse сообщает, совпадает ли продвинутая пуля predicted с настоящей actual.
+// Клиент показывает продвинутую пулю, поэтому погрешность не накапливается:
+// каждый снимок сравнивается с настоящим положением.
+func bulletOnCourse(predicted, actual BulletState) bool {
+	return predicted.VelocityX == actual.VelocityX && predicted.VelocityY == actual.VelocityY &&
+		predicted.Pierce == actual.Pierce &&
+		math.Abs(predicted.X-actual.X) <= bulletDriftTolerance &&
+		math.Abs(predicted.Y-actual.Y) <= bulletDriftTolerance
+}
+
+// diffBullets сравнивает пули с базовыми, продвинутыми на ticks шагов
+func diffBullets(base, current []BulletState, ticks uint32) bulletDelta {
+	var delta bulletDelta
+	known := make(map[uint64]BulletState, len(base))
+	for _, b := range base {
+		known[b.ID] = b
+	}
+	for _, b := range current {
+		prev, ok := known[b.ID]
+		if !ok || !bulletOnCourse(predictBullet(prev, ticks), b) {
+			delta.changed = append(delta.changed, b)
+		}
+		delete(known, b.ID)
+	}
+	for _, b := range base {
+		if _, gone := known[b.ID]; gone {
+			delta.removed = append(delta.removed, b.ID)
+		}
+	}
+	return delta
+}
+
+// apply восстанавливает пули из base: оставшиеся продвигаются или заменяются
+// изменившимися, новые добавляются в конец
+func (d bulletDelta) apply(base []BulletState, ticks uint32) []BulletState {
+	removed := make(map[uint64]bool, len(d.removed))
+	for _, id := range d.removed {
+		removed[id] = true
+	}
+	changed := make(map[uint64]BulletState, len(d.changed))
+	for _, b := range d.changed {
+		changed[b.ID] = b
+	}
+
+	bullets := make([]BulletState, 0, len(base)+len(d.changed))
+	for _, b := range base {
+		if removed[b.ID] {
+			continue
+		}
+		if next, ok := changed[b.ID]; ok {
+			bullets = append(bullets, next)
+			delete(changed, b.ID)
+			continue
+		}
+		bullets = append(bullets, predictBullet(b, ticks))
+	}
+	for _, b := range d.changed {
+		if _, added := changed[b.ID]; added {
+			bullets = append(bullets, b)
+		}
+	}
+	return bullets
+}
+
+// appendTo кодирует разность пуль
+func (d bulletDelta) appendTo(buf []byte) []byte {
+	buf = appendIDs(buf, d.removed)
+	return appendBullets(buf, d.changed)
+}
+
+// bulletDelta читает разность пуль
+func (d *decoder) bulletDelta() bulletDelta {
+	return bulletDelta{removed: d.ids(), changed: d.bullets()}
+}
+
+// npcDelta — исчезнувшие NPC и NPC, у которых изменилось хоть одно поле
+type npcDelta struct {
+	removed []uint64
+	changed []NPCState
+}
+
+// diffNPCs сравнивает NPC с базовыми
+func diffNPCs(base, current []NPCState) npcDelta {
+	var delta npcDelta
+	known := make(map[uint64]NPCState, len(base))
+	for _, npc := range base {
+		known[npc.ID] = npc
+	}
+	for _, npc := range current {
+		if prev, ok := known[npc.ID]; !ok || prev != npc {
+			delta.changed = append(delta.changed, npc)
+		}
+		delete(known, npc.ID)
+	}
+	for _, npc := range base {
+		if _, gone := known[npc.ID]; gone {
+			delta.removed = append(delta.removed, npc.ID)
+		}
+	}
+	return delta
+}
+
+// apply восстанавливает NPC из base
+func (d npcDelta) apply(base []NPCState) []NPCState {
+	removed := make(map[uint64]bool, len(d.removed))
+	for _, id := range d.removed {
+		removed[id] = true
+	}
+	changed := make(map[uint64]NPCState, len(d.changed))
+	for _, npc := range d.changed {
+		changed[npc.ID] = npc
+	}
+
+	npcs := make([]NPCState, 0, len(base)+len(d.changed))
+	for _, npc := range base {
+		if removed[npc.ID] {
+			continue
+		}
+		if next, ok := changed[npc.ID]; ok {
+			npc = next
+			delete(changed, npc.ID)
+		}
+		npcs = append(npcs, npc)
+	}
+	for _, npc := range d.changed {
+		if _, added := changed[npc.ID]; added {
+			npcs = append(npcs, npc)
+		}
+	}
+	return npcs
+}
+
+// appendTo кодирует разность NPC
+func (d npcDelta) appendTo(buf []byte) []byte {
+	buf = appendIDs(buf, d.removed)
+	return appendNPCs(buf, d.changed)
+}
+
+// npcDelta читает разность NPC
+func (d *decoder) npcDelta() npcDelta {
+	return npcDelta{removed: d.ids(), changed: d.npcs()}
+}
+
+// appendIDs кодирует список идентификаторов
+func appendIDs(buf []byte, ids []uint64) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(ids)))
+	for _, id := range ids {
+		buf = binary.AppendUvarint(buf, id)
+	}
+	return buf
+}
+
+// ids читает список идентификаторов
+func (d *decoder) ids() []uint64 {
+	ids := make([]uint64, d.count(1))
+	for i := range ids {
+		ids[i] = d.uvarint()
+	}
+	return ids
+}
+
+// quantize округляет число до точности float32, с которой оно передается
+func quantize(v float64) float64 {
+	return float64(float32(v))
+}
+
+// quantizeSnapshot возвращает копию снимка с числами, округленными так же,
+// как их получит клиент
+func quantizeSnapshot(s *SnapshotMessage) SnapshotMessage {
+	q := *s
+	q.Host = quantizePlayer(s.Host)
+	q.Client = quantizePlayer(s.Client)
+	q.HostBullets = quantizeBullets(s.HostBullets)
+	q.ClientBullets = quantizeBullets(s.ClientBullets)
+	q.NPCs = make([]NPCState, len(s.NPCs))
+	for i, npc := range s.NPCs {
+		npc.X, npc.Y = quantize(npc.X), quantize(npc.Y)
+		npc.Width, npc.Height = quantize(npc.Width), quantize(npc.Height)
+		npc.VelocityX, npc.VelocityY = quantize(npc.VelocityX), quantize(npc.VelocityY)
+		q.NPCs[i] = npc
+	}
+	return q
+}
+
+// quantizePlayer округляет числа состояния игрока
+func quantizePlayer(p PlayerState) PlayerState {
+	p.X, p.Y = quantize(p.X), quantize(p.Y)
+	p.VelocityX, p.VelocityY = quantize(p.VelocityX), quantize(p.VelocityY)
+	return p
+}
+
+// quantizeBullets округляет числа состояний пуль
+func quantizeBullets(bullets []BulletState) []BulletState {
+	q := make([]BulletState, len(bullets))
+	for i, b := range bullets {
+		b.X, b.Y = quantize(b.X), quantize(b.Y)
+		b.VelocityX, b.VelocityY = quantize(b.VelocityX), quantize(b.VelocityY)
+		q[i] = b
+	}
+	return q
+}
@@ -0,0 +1,272 @@
+package network
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Поиск хостов в локальной сети: клиент раз в секунду рассылает широковещательный
+// UDP-запрос на порт поиска, а каждый хост отвечает ему напрямую своим именем,
+// уровнем и TCP-портом для подключения. Адрес хоста клиент берет из ответа.
+// Запросы идут и на адрес 127.0.0.1, чтобы находился хост на этом же компьютере;
+// один хост, ответивший с нескольких адресов, узнается по ID и показывается один раз.
+
+const (
+	defaultDiscoveryPort     = 4002            // UDP-порт, на котором хосты ждут запросов поиска
+	defaultDiscoveryInterval = time.Second     // Как часто клиент повторяет запрос
+	defaultDiscoveryExpiry   = 3 * time.Second // Через сколько без ответа хост пропадает из списка
+	maxDiscoveryPacket       = 1024            // Наибольший размер запроса и ответа
+)
+
+// Начала пакетов поиска: по ним отбрасываются чужие пакеты на том же порту
+var (
+	discoveryQuery = []byte("platformer-discover\n")
+	discoveryReply = []byte("platformer-server\n")
+)
+
+// Server — хост, найденный в локальной сети
+type Server struct {
+	Name    string // Имя игрока-хоста
+	Level   string // Уровень хоста (пустой - встроенный)
+	Address string // Адрес для Join (ip:port)
+	Open    bool   // Хост еще ждет второго игрока
+}
+
+// serverInfo — ответ хоста на запрос поиска
+type serverInfo struct {
+	ID    uint64 `json:"id"`    // Случайный номер хоста: один хост может ответить с нескольких адресов
+	Name  string `json:"name"`  // Имя игрока-хоста
+	Level string `json:"level"` // Уровень хоста
+	Port  int    `json:"port"`  // TCP-порт для подключения
+	Open  bool   `json:"open"`  // Хост еще ждет второго игрока
+}
+
+// discoveryLoop на хосте отвечает на запросы поиска, пока менеджер не закрыт.
+// Если порт поиска занят (например, другим хостом на этом компьютере),
+// хоста можно найти только по адресу.
+func (m *Manager) discoveryLoop() {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: defaultDiscoveryPort})
+	if err != nil {
+		return
+	}
+	go func() {
+		<-m.closed
+		_ = conn.Close()
+	}()
+
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	info := serverInfo{ID: binary.LittleEndian.Uint64(id[:]), Port: listenPort(m.address)}
+
+	buf := make([]byte, maxDiscoveryPacket)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if m.isClosed() {
+				return
+			}
+			continue
+		}
+		if !bytes.Equal(buf[:n], discoveryQuery) {
+			continue
+		}
+
+		hello := m.getHello()
+		info.Name, info.Level, info.Open = hello.Name, hello.Level, !m.full()
+		payload, err := json.Marshal(info)
+		if err != nil {
+			continue
+		}
+		_, _ = conn.WriteToUDP(append(append([]byte(nil), discoveryReply...), payload...), from)
+	}
+}
+
+// listenPort возвращает порт из адреса listener хоста (например, 4000 для ":4000")
+func listenPort(address string) int {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0
+	}
+	value, err := strconv.Atoi(port)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// Browser ищет хосты в локальной сети, пока не будет закрыт
+type Browser struct {
+	conn *net.UDPConn
+
+	mu      sync.Mutex
+	servers map[uint64]foundServer
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// foundServer — хост и время его последнего ответа
+type foundServer struct {
+	server Server
+	seen   time.Time
+}
+
+// Browse начинает поиск хостов в локальной сети.
+func Browse() (*Browser, error) {
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Browser{
+		conn:    conn,
+		servers: make(map[uint64]foundServer),
+		closed:  make(chan struct{}),
+	}
+	go b.queryLoop()
+	go b.readLoop()
+	return b, nil
+}
+
+// Servers возвращает хосты, ответившие за последние несколько секунд, по имени.
+func (b *Browser) Servers() []Server {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	servers := make([]Server, 0, len(b.servers))
+	for id, found := range b.servers {
+		if time.Since(found.seen) > defaultDiscoveryExpiry {
+			delete(b.servers, id)
+			continue
+		}
+		servers = append(servers, found.server)
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].Name != servers[j].Name {
+			return servers[i].Name < servers[j].Name
+		}
+		return servers[i].Address < servers[j].Address
+	})
+	return servers
+}
+
+// Close останавливает поиск.
+func (b *Browser) Close() error {
+	var err error
+	b.closeOnce.Do(func() {
+		close(b.closed)
+		err = b.conn.Close()
+	})
+	return err
+}
+
+// queryLoop периодически рассылает запрос поиска
+func (b *Browser) queryLoop() {
+	ticker := time.NewTicker(defaultDiscoveryInterval)
+	defer ticker.Stop()
+	for {
+		for _, ip := range broadcastAddresses() {
+			_, _ = b.conn.WriteToUDP(discoveryQuery, &net.UDPAddr{IP: ip, Port: defaultDiscoveryPort})
+		}
+		select {
+		case <-b.closed:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// readLoop принимает ответы хостов
+func (b *Browser) readLoop() {
+	buf := make([]byte, maxDiscoveryPacket)
+	for {
+		n, from, err := b.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-b.closed:
+				return
+			default:
+				continue
+			}
+		}
+		payload, ok := bytes.CutPrefix(buf[:n], discoveryReply)
+		if !ok {
+			continue
+		}
+		var info serverInfo
+		if err := json.Unmarshal(payload, &info); err != nil || info.Port <= 0 {
+			continue
+		}
+
+		server := Server{
+			Name:    truncateName(info.Name),
+			Level:   info.Level,
+			Address: net.JoinHostPort(from.IP.String(), strconv.Itoa(info.Port)),
+			Open:    info.Open,
+		}
+		b.mu.Lock()
+		// Адрес в локальной сети предпочтительнее 127.0.0.1: по нему хост виден и с других компьютеров
+		if prev, ok := b.servers[info.ID]; ok && from.IP.IsLoopback() && !isLoopbackAddress(prev.server.Address) {
+			server.Address = prev.server.Address
+		}
+		b.servers[info.ID] = foundServer{server: server, seen: time.Now()}
+		b.mu.Unlock()
+	}
+}
+
+// isLoopbackAddress сообщает, указывает ли адрес ip:port на этот компьютер
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// broadcastAddresses возвращает адреса, на которые рассылается запрос:
+// общий широковещательный адрес, широковещательные адреса сетей каждого
+// интерфейса (общий адрес уходит не во все сети) и 127.0.0.1
+func broadcastAddresses() []net.IP {
+	addresses := []net.IP{net.IPv4bcast, net.IPv4(127, 0, 0, 1)}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return addresses
+	}
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagBroadcast == 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			ip, mask := ipNet.IP.To4(), ipNet.Mask
+			if len(mask) == net.IPv6len {
+				mask = mask[net.IPv6len-net.IPv4len:]
+			}
+			if ip == nil || len(mask) != net.IPv4len {
+				continue
+			}
+			broadcast := make(net.IP, net.IPv4len)
+			for i := range ip {
+				broadcast[i] = ip[i] | ^mask[i]
+			}
+			addresses = append(addresses, broadcast)
+		}
+	}
+	return addresses
+}
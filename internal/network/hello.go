@@ -0,0 +1,111 @@
+package network
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Сразу после подключения каждая сторона первым кадром отправляет рукопожатие
+// со сведениями о своем игроке. Рукопожатие идет и после переподключения,
+// поэтому другая сторона всегда знает, кто подключен сейчас.
+// Если у хоста задан пароль, он читает рукопожатие клиента еще до начала игры
+// и отключает клиента с другим паролем.
+
+// Hello — сведения об игроке, которыми стороны обмениваются при подключении
+type Hello struct {
+	Name  string // Имя игрока
+	Color int    // Цвет персонажа (индекс в палитре игры)
+	Team  int    // Команда игрока (индекс команды игры)
+	Level string // Уровень, на котором играет хост (у клиента - пустой); виден при поиске хостов
+
+	Password string // Пароль сервера, который клиент отправляет хосту (у хоста - пустой)
+}
+
+// maxHelloName — наибольшая длина имени в рукопожатии (в символах); длинное имя обрезается
+const maxHelloName = 32
+
+// truncateName обрезает имя до maxHelloName символов
+func truncateName(name string) string {
+	if runes := []rune(name); len(runes) > maxHelloName {
+		return string(runes[:maxHelloName])
+	}
+	return name
+}
+
+// SetHello заменяет рукопожатие этой стороны для следующих подключений
+// (например, после переподключения), если игрок сменил имя или цвет.
+func (m *Manager) SetHello(hello Hello) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.hello = hello
+	m.mu.Unlock()
+}
+
+// getHello возвращает рукопожатие этой стороны
+func (m *Manager) getHello() Hello {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.hello
+}
+
+// Remote возвращает рукопожатие удаленного игрока (false - соединения нет
+// или рукопожатие еще не получено).
+func (m *Manager) Remote() (Hello, bool) {
+	if m == nil {
+		return Hello{}, false
+	}
+	if peer := m.getPeer(); peer != nil {
+		return peer.getRemote()
+	}
+	return Hello{}, false
+}
+
+// getRemote возвращает рукопожатие другой стороны
+func (p *peer) getRemote() (Hello, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.remote, p.remoteKnown
+}
+
+// SetPassword задает пароль, который хост требует от клиентов (пустой - пускать всех).
+// Действует на следующие подключения.
+func (m *Manager) SetPassword(password string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.password = password
+	m.mu.Unlock()
+}
+
+// readClientHello читает рукопожатие подключившегося клиента и проверяет его пароль.
+// Клиента с другим паролем хост отключает с причиной, а ошибку показывает в ListenStatus.
+func (m *Manager) readClientHello(conn net.Conn) (Hello, error) {
+	_ = conn.SetReadDeadline(time.Now().Add(defaultHandshakeTimeout))
+	// Кадры читаются без буфера, чтобы следующие кадры клиента достались его соединению
+	reader := frameReader{r: conn}
+	frame, err := reader.read()
+	_ = conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		return Hello{}, err
+	}
+	if frame.Type != MessageHello || frame.Hello == nil {
+		return Hello{}, errors.New("network: the client did not send a handshake")
+	}
+	hello := *frame.Hello
+
+	m.mu.RLock()
+	password := m.password
+	m.mu.RUnlock()
+	if password != "" && subtle.ConstantTimeCompare([]byte(hello.Password), []byte(password)) != 1 {
+		refuse(conn, reasonPassword)
+		return Hello{}, fmt.Errorf("network: refused %s (%s): wrong password", hello.Name, remoteIP(conn))
+	}
+	hello.Password = ""
+	return hello, nil
+}
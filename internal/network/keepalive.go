@@ -0,0 +1,42 @@
+package network
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// Оборванное соединение (выдернут кабель, компьютер уснул) не всегда закрывает
+// TCP-сокет: чтение просто ждет данных вечно. Поэтому каждая сторона, которой
+// нечего отправить, шлет пустой кадр пульса, а чтение идет со сроком: если от
+// другой стороны ничего не пришло за defaultPeerTimeout, соединение считается
+// оборванным и закрывается (дальше работает восстановление, reconnect.go).
+
+const (
+	defaultHeartbeatInterval = time.Second     // Через сколько простоя отправляется пульс
+	defaultPeerTimeout       = 5 * time.Second // Через сколько тишины соединение считается оборванным
+)
+
+// errPeerTimeout возвращается, если другая сторона замолчала дольше defaultPeerTimeout
+var errPeerTimeout = errors.New("network: connection timed out")
+
+// readDeadliner — соединение, чтению которого можно задать срок
+// (net.Conn, WebSocket и WebSocket браузера)
+type readDeadliner interface {
+	SetReadDeadline(deadline time.Time) error
+}
+
+// extendReadDeadline продлевает срок чтения соединения перед следующим кадром
+func (p *peer) extendReadDeadline() {
+	if conn, ok := p.conn.(readDeadliner); ok {
+		_ = conn.SetReadDeadline(time.Now().Add(defaultPeerTimeout))
+	}
+}
+
+// readError переводит ошибку чтения в причину закрытия соединения
+func readError(err error) error {
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return errPeerTimeout
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+package network
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// Сетевая игра вмещает MaxPlayers игроков вместе с хостом, а хост может
+// ограничить их число еще сильнее (SetMaxPlayers). Пока свободного места нет,
+// хост не закрывает listener: лишний клиент проходит приветствие протокола
+// и получает кадр отключения с причиной "сервер заполнен", поэтому видит
+// понятное сообщение, а не отказ в соединении, и не пытается переподключиться.
+
+// MaxPlayers — сколько игроков вмещает сетевая игра этой сборки вместе с хостом
+const MaxPlayers = 2
+
+// ErrServerFull — на хосте нет места для этого клиента
+var ErrServerFull = errors.New("network: the server is full")
+
+// SetMaxPlayers задает, сколько игроков вмещает хост вместе с собой
+// (0 или больше MaxPlayers - MaxPlayers; 1 - хост не пускает никого).
+func (m *Manager) SetMaxPlayers(players int) {
+	if m == nil {
+		return
+	}
+	if players <= 0 || players > MaxPlayers {
+		players = MaxPlayers
+	}
+	m.mu.Lock()
+	m.maxPlayers = players
+	m.mu.Unlock()
+}
+
+// full сообщает, заняты ли на хосте все места для клиентов
+func (m *Manager) full() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	players := 1
+	if m.peer != nil {
+		// Отключившийся клиент места уже не занимает, даже если watchPeer
+		// еще не успел его убрать
+		select {
+		case <-m.peer.closed:
+		default:
+			players++
+		}
+	}
+	limit := m.maxPlayers
+	if limit == 0 {
+		limit = MaxPlayers
+	}
+	return players >= limit
+}
+
+// refuseWhileConnected принимает на listener новых клиентов, пока подключен p,
+// и отказывает им, потому что мест нет. Возвращается, когда p отключился
+// или менеджер закрыт; listener при этом закрывается.
+func (m *Manager) refuseWhileConnected(listener net.Listener, p *peer) {
+	go func() {
+		select {
+		case <-p.closed:
+		case <-m.closed:
+		}
+		_ = listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			// Клиент другой версии не поймет кадра отключения. Рукопожатие клиента
+			// дочитывается до отказа: закрытие с непрочитанными данными сбросило бы
+			// соединение раньше, чем клиент прочитает причину.
+			if handshake(conn) == nil {
+				_ = conn.SetReadDeadline(time.Now().Add(defaultHandshakeTimeout))
+				reader := frameReader{r: conn}
+				_, _ = reader.read()
+				refuse(conn, reasonFull)
+			}
+			_ = conn.Close()
+		}()
+	}
+}
@@ -1,19 +1,27 @@
 package network
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
-	defaultSendBufferSize = 8
-	defaultDialTimeout    = 5 * time.Second
-	defaultListenAddress  = ":4000"
-	defaultDialAddress    = "127.0.0.1:4000"
+	defaultSendBufferSize    = 8
+	defaultEventBufferSize   = 64
+	defaultInputQueueSize    = 8  // Сколько непрочитанных вводов клиента хранит хост
+	defaultSnapshotQueueSize = 16 // Сколько непрочитанных снимков хоста хранит клиент
+	defaultDialTimeout       = 5 * time.Second
+	defaultListenAddress     = ":4000"
+	defaultDialAddress       = "127.0.0.1:4000"
+	defaultRelistenTries     = 5               // Автоматических попыток перезапуска listener подряд
+	defaultRelistenDelay     = 2 * time.Second // Пауза перед повторной попыткой
 )
 
 // PlayerState описывает состояние игрока, которое отправляется по сети.
@@ -23,19 +31,165 @@ type PlayerState struct {
 	VelocityY   float64
 	OnGround    bool
 	FacingRight bool
+	Crouching   bool
+	Health      int
+	Weapon      int // Текущее оружие игрока
 }
 
 // BulletState описывает состояние пули, которое отправляется по сети.
 type BulletState struct {
+	ID        uint64 // Идентификатор пули на хосте
 	X         float64
 	Y         float64
 	VelocityX float64
+	VelocityY float64
+	Pierce    int // Сквозь сколько NPC пролетает пуля
 }
 
-// StateMessage содержит состояние игрока и его пуль.
-type StateMessage struct {
-	Player  PlayerState
-	Bullets []BulletState
+// NPCState описывает состояние NPC, которое отправляется по сети.
+type NPCState struct {
+	ID          uint64 // Идентификатор NPC на хосте
+	X, Y        float64
+	Width       float64
+	Height      float64
+	VelocityX   float64
+	VelocityY   float64
+	FacingRight bool
+	Health      int
+}
+
+// InputMessage — ввод клиента на одном шаге симуляции. Клиент не двигает своего
+// персонажа сам: хост применяет его ввод и присылает результат в снимке.
+type InputMessage struct {
+	Seq          uint32 // Номер ввода у клиента (растет на единицу каждый шаг)
+	ViewTick     uint32 // Шаг хоста, на котором клиент видит персонажа хоста (0 - еще не видит)
+	Left, Right  bool
+	Jump, Crouch bool
+	Sprint, Dash bool
+	Shoot        bool
+	Interact     bool
+	SwitchWeapon bool
+}
+
+// SnapshotMessage — полное состояние мира, которое хост моделирует для обоих игроков
+// и рассылает каждый шаг. Снимки могут теряться: следующий полностью заменяет предыдущий.
+type SnapshotMessage struct {
+	// Шаг симуляции хоста, на котором снят снимок. Номера шагов растут весь сеанс
+	// и служат общим временем: по ним клиент упорядочивает снимки, интерполирует
+	// персонажа хоста и сообщает, какой момент он видит.
+	Tick uint32
+
+	Host          PlayerState
+	Client        PlayerState
+	HostBullets   []BulletState
+	ClientBullets []BulletState
+	NPCs          []NPCState
+	HostScore     int // Очки хоста в текущем матче
+	ClientScore   int // Очки клиента в текущем матче
+
+	// Номер последнего ввода клиента, который хост применил к персонажу клиента
+	// до этого снимка. По нему клиент понимает, какие свои вводы надо повторить.
+	InputSeq uint32
+}
+
+// MessageType определяет тип сообщения на проводе.
+type MessageType string
+
+const (
+	MessageInput    MessageType = "input"    // Ввод клиента (каждый шаг, может теряться)
+	MessageSnapshot MessageType = "snapshot" // Снимок мира от хоста (каждый шаг, может теряться)
+	MessageEvent    MessageType = "event"    // Разовое событие (доставляется надежно)
+	MessageClock    MessageType = "clock"    // Запрос клиента или ответ хоста для синхронизации часов
+	MessageHello    MessageType = "hello"    // Рукопожатие: сведения об игроке (первый кадр соединения)
+
+	MessageHeartbeat  MessageType = "heartbeat"  // Пульс простаивающей стороны (keepalive.go)
+	MessageDisconnect MessageType = "disconnect" // Хост отключает клиента (bans.go)
+)
+
+// EventType определяет тип разового события.
+type EventType string
+
+// Event — разовое событие, которое нельзя потерять (в отличие от состояния,
+// которое сбрасывается при переполнении очереди и заменяется более новым).
+type Event struct {
+	Type    EventType       `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// NewEvent создает событие с данными payload, закодированными в JSON.
+func NewEvent(eventType EventType, payload any) (Event, error) {
+	event := Event{Type: eventType}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return Event{}, err
+		}
+		event.Payload = data
+	}
+	return event, nil
+}
+
+// Decode декодирует данные события в v.
+func (e Event) Decode(v any) error {
+	if len(e.Payload) == 0 {
+		return errors.New("network: event has no payload")
+	}
+	return json.Unmarshal(e.Payload, v)
+}
+
+// envelope — кадр, который передается по соединению (формат на проводе - в wire.go).
+type envelope struct {
+	Type     MessageType
+	Input    *InputMessage
+	Snapshot *SnapshotMessage
+	Event    *Event
+	Clock    *clockMessage
+	Hello    *Hello
+	Seq      uint32           // Номер снимка
+	Ack      uint32           // Номер последнего снимка, полученного клиентом (передается с вводом)
+	Reason   disconnectReason // Причина отключения (MessageDisconnect)
+}
+
+// ErrEventQueueFull возвращается, если очередь событий переполнена.
+var ErrEventQueueFull = errors.New("network: event queue is full")
+
+// ListenState описывает состояние ожидания подключения на стороне хоста.
+type ListenState int
+
+const (
+	ListenStarting  ListenState = iota // Listener еще не запущен
+	ListenListening                    // Хост ждет подключения клиента
+	ListenRetrying                     // Listener упал, скоро будет повторная попытка
+	ListenFailed                       // Автоматические попытки исчерпаны, нужен ручной повтор
+	ListenConnected                    // Клиент подключился
+)
+
+// String возвращает название состояния.
+func (s ListenState) String() string {
+	switch s {
+	case ListenStarting:
+		return "starting"
+	case ListenListening:
+		return "listening"
+	case ListenRetrying:
+		return "retrying"
+	case ListenFailed:
+		return "failed"
+	case ListenConnected:
+		return "connected"
+	default:
+		return "unknown"
+	}
+}
+
+// ListenStatus — текущее состояние listener хоста для отображения игроку.
+type ListenStatus struct {
+	State            ListenState
+	Address          string
+	WebSocketAddress string // Адрес для клиентов по WebSocket (пустой - выключен)
+	Attempt          int    // Номер неудачной попытки подряд (0, если ошибок не было)
+	MaxAttempts      int    // Сколько автоматических попыток делается до перехода в ListenFailed
+	Err              error  // Последняя ошибка listener или причина отказа клиенту (ListenListening)
 }
 
 // Manager управляет сетевым подключением.
@@ -44,6 +198,24 @@ type Manager struct {
 	peer     *peer
 	listener net.Listener
 
+	address   string        // Адрес, на котором хост ожидает подключения
+	wsAddress string        // Адрес для клиентов по WebSocket (пустой - только TCP)
+	status    ListenStatus  // Состояние listener (только для хоста)
+	retryCh   chan struct{} // Запрос ручного повтора
+	host      bool          // Менеджер на стороне хоста
+	hello     Hello         // Рукопожатие этой стороны
+
+	reconnectWindow time.Duration // Сколько восстанавливать оборвавшееся соединение
+	reconnect       Reconnect     // Состояние восстановления
+	reconnectUntil  time.Time     // Когда закончится окно восстановления
+
+	bans       map[string]bool // Запрещенные IP-адреса клиентов (bans.go)
+	password   string          // Пароль, который хост требует от клиентов (hello.go)
+	maxPlayers int             // Сколько игроков вмещает хост вместе с собой (0 - MaxPlayers, limit.go)
+	conditions Conditions      // Изображаемые задержки и потери сети (conditions.go)
+
+	recorder atomic.Pointer[recorder] // Запись матча (nil - не пишется, recording.go)
+
 	closeOnce sync.Once
 	closed    chan struct{}
 
@@ -53,79 +225,222 @@ type Manager struct {
 
 func newManager(initialPeer *peer) *Manager {
 	return &Manager{
-		peer:   initialPeer,
-		closed: make(chan struct{}),
+		peer:            initialPeer,
+		closed:          make(chan struct{}),
+		retryCh:         make(chan struct{}, 1),
+		reconnectWindow: defaultReconnectWindow,
 	}
 }
 
-// Host запускает сервер и ожидает подключения клиента.
-func Host(address string) (*Manager, error) {
+// Host запускает сервер и ожидает подключения клиента по TCP на address, а если
+// задан webSocketAddress, то и по WebSocket (например, из браузерной сборки игры).
+// Адрес вида relay://host:port/комната вместо TCP ждет клиента через посредника.
+// Каждому подключившемуся клиенту отправляется рукопожатие hello.
+// Если listener не удается запустить или он падает до подключения клиента
+// (порт занят, интерфейс отключен), хост автоматически повторяет попытки;
+// состояние доступно через ListenStatus, ручной повтор — через Retry.
+func Host(address, webSocketAddress string, hello Hello) (*Manager, error) {
 	if address == "" {
 		address = defaultListenAddress
 	}
 
-	listener, err := net.Listen("tcp", address)
-	if err != nil {
-		return nil, err
-	}
 	manager := newManager(nil)
-	manager.listener = listener
+	manager.host = true
+	manager.hello = hello
+	manager.address = address
+	manager.wsAddress = webSocketAddress
+	manager.status = ListenStatus{
+		State:            ListenStarting,
+		Address:          address,
+		WebSocketAddress: webSocketAddress,
+		MaxAttempts:      defaultRelistenTries,
+	}
 
-	go manager.acceptOnce()
+	go manager.listenLoop()
+	if !isRelayURL(address) {
+		// Через посредника играют не в локальной сети: искать такого хоста некому
+		go manager.discoveryLoop()
+	}
 
 	return manager, nil
 }
 
-// Join подключается к удаленному хосту.
-func Join(address string) (*Manager, error) {
+// Join подключается к удаленному хосту: по WebSocket, если address - URL
+// вида ws://host:port/ (так подключается браузерная сборка), через посредника,
+// если address вида relay://host:port/комната, иначе по TCP,
+// и отправляет хосту рукопожатие hello.
+func Join(address string, hello Hello) (*Manager, error) {
 	if address == "" {
 		address = defaultDialAddress
 	}
 
-	conn, err := net.DialTimeout("tcp", address, defaultDialTimeout)
+	conn, err := dialHandshake(address)
 	if err != nil {
 		return nil, err
 	}
 
-	return newManager(newPeer(conn)), nil
+	manager := newManager(nil)
+	manager.address = address
+	manager.hello = hello
+	manager.attach(newPeer(conn, hello))
+	return manager, nil
 }
 
-// Send отправляет состояние игры удаленному игроку.
-func (m *Manager) Send(state StateMessage) error {
+// SendInput отправляет хосту ввод клиента за один шаг.
+func (m *Manager) SendInput(input InputMessage) error {
 	if m == nil {
 		return nil
 	}
 	if peer := m.getPeer(); peer != nil {
-		return peer.send(state)
+		m.recordSent(record{Input: &input})
+		return peer.send(envelope{Type: MessageInput, Input: &input, Ack: peer.decoder.last.Load()})
 	}
 	return nil
 }
 
-// LatestState возвращает последнее состояние, полученное от удаленного игрока.
-func (m *Manager) LatestState() (StateMessage, bool) {
+// SendSnapshot отправляет клиенту снимок мира.
+func (m *Manager) SendSnapshot(snapshot SnapshotMessage) error {
 	if m == nil {
-		return StateMessage{}, false
+		return nil
 	}
 	if peer := m.getPeer(); peer != nil {
-		return peer.latestState()
+		peer.clock.sent(snapshot.Tick)
+		m.recordSent(record{Snapshot: &snapshot})
+		return peer.send(envelope{Type: MessageSnapshot, Snapshot: &snapshot, Seq: peer.snapshotSeq.Add(1)})
 	}
-	return StateMessage{}, false
+	return nil
 }
 
-// Err возвращает ошибку соединения, если она произошла.
-func (m *Manager) Err() error {
+// SendEvent отправляет удаленному игроку разовое событие.
+// В отличие от вводов и снимков, события не сбрасываются при переполнении очереди:
+// событие, которому не хватило места, не отправляется, и SendEvent возвращает
+// ErrEventQueueFull, чтобы вызывающий повторил его позже.
+func (m *Manager) SendEvent(event Event) error {
 	if m == nil {
 		return nil
 	}
-	if err := m.getErr(); err != nil {
-		return err
+	if peer := m.getPeer(); peer != nil {
+		if err := peer.sendEvent(event); err != nil {
+			return err
+		}
+		m.recordSent(record{Event: &event})
+	}
+	return nil
+}
+
+// PollEvents возвращает события, полученные от удаленного игрока с прошлого вызова.
+func (m *Manager) PollEvents() []Event {
+	if m == nil {
+		return nil
+	}
+	if peer := m.getPeer(); peer != nil {
+		events := peer.pollEvents()
+		for i := range events {
+			m.recordReceived(record{Event: &events[i]})
+		}
+		return events
+	}
+	return nil
+}
+
+// Connected сообщает, подключен ли удаленный игрок.
+func (m *Manager) Connected() bool {
+	if m == nil {
+		return false
+	}
+	return m.getPeer() != nil
+}
+
+// Clock возвращает оценку часов хоста (только для клиента; false - замеров еще нет).
+func (m *Manager) Clock() (ClockSync, bool) {
+	if m == nil {
+		return ClockSync{}, false
+	}
+	if peer := m.getPeer(); peer != nil {
+		return peer.clock.estimate()
+	}
+	return ClockSync{}, false
+}
+
+// Stats возвращает качество соединения с удаленным игроком (false - не подключен).
+func (m *Manager) Stats() (Stats, bool) {
+	if m == nil {
+		return Stats{}, false
+	}
+	if peer := m.getPeer(); peer != nil {
+		return peer.getStats(), true
+	}
+	return Stats{}, false
+}
+
+// PollSnapshots возвращает снимки мира, полученные от хоста с прошлого вызова,
+// от старых к новым. Если снимки долго не забирали, самые старые отбрасываются.
+func (m *Manager) PollSnapshots() []SnapshotMessage {
+	if m == nil {
+		return nil
+	}
+	if peer := m.getPeer(); peer != nil {
+		snapshots := peer.pollSnapshots()
+		for i := range snapshots {
+			m.recordReceived(record{Snapshot: &snapshots[i]})
+		}
+		return snapshots
+	}
+	return nil
+}
+
+// PollInputs возвращает вводы клиента, полученные с прошлого вызова, от старых к новым.
+// Если хост долго не забирал ввод, самые старые вводы отбрасываются.
+func (m *Manager) PollInputs() []InputMessage {
+	if m == nil {
+		return nil
 	}
 	if peer := m.getPeer(); peer != nil {
-		return peer.getErr()
+		inputs := peer.pollInputs()
+		for i := range inputs {
+			m.recordReceived(record{Input: &inputs[i]})
+		}
+		return inputs
 	}
 	return nil
 }
 
+// Err возвращает ошибку, после которой соединение уже не восстановится:
+// клиент не смог переподключиться за окно восстановления. Обрывы, которые
+// еще восстанавливаются, видны через Reconnect.
+func (m *Manager) Err() error {
+	if m == nil {
+		return nil
+	}
+	return m.getErr()
+}
+
+// ListenStatus возвращает состояние ожидания подключения на стороне хоста.
+func (m *Manager) ListenStatus() ListenStatus {
+	if m == nil {
+		return ListenStatus{}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+// Retry немедленно запускает повторную попытку открыть listener,
+// если хост сейчас ждет повтора или исчерпал автоматические попытки.
+func (m *Manager) Retry() {
+	if m == nil {
+		return
+	}
+	state := m.ListenStatus().State
+	if state != ListenRetrying && state != ListenFailed {
+		return
+	}
+	select {
+	case m.retryCh <- struct{}{}:
+	default:
+	}
+}
+
 // Close закрывает подключение.
 func (m *Manager) Close() error {
 	if m == nil {
@@ -143,6 +458,11 @@ func (m *Manager) Close() error {
 			}
 		}
 
+		// Дописываем запись матча
+		if err := m.stopRecording(); err != nil && result == nil {
+			result = err
+		}
+
 		// Закрываем peer, если он уже подключен.
 		if peer := m.getPeer(); peer != nil {
 			if err := peer.close(); err != nil && result == nil && !errors.Is(err, net.ErrClosed) {
@@ -155,79 +475,198 @@ func (m *Manager) Close() error {
 }
 
 type peer struct {
-	conn    net.Conn
-	sendCh  chan StateMessage
+	conn    io.ReadWriteCloser // TCP или WebSocket
+	sendCh  chan envelope      // Вводы и снимки: при переполнении старые сбрасываются
+	eventCh chan Event
+	kickCh  chan disconnectReason // Причина отключения, которую нужно отправить перед закрытием
+	kicked  atomic.Bool           // Хост отключил эту сторону намеренно
 	closed  chan struct{}
 	closeFn sync.Once
 
-	mu      sync.RWMutex
-	latest  StateMessage
-	hasData bool
+	// Разностное кодирование снимков: номера отправленных снимков, история
+	// отправленных (хост) и полученных (клиент) снимков
+	snapshotSeq atomic.Uint32
+	encoder     deltaEncoder
+	decoder     deltaDecoder
+
+	clock   peerClock              // Синхронизация часов клиента с шагами хоста
+	traffic *trafficCounter        // Кадры и байты в обе стороны
+	sim     atomic.Pointer[netSim] // Изображение плохой сети (nil - выключено, conditions.go)
+	hello   Hello                  // Рукопожатие этой стороны
+
+	mu          sync.RWMutex
+	remote      Hello        // Рукопожатие другой стороны
+	remoteKnown bool         // Рукопожатие другой стороны получено
+	stats       statsCounter // Потери и время последнего обновления
+	snapshots   []SnapshotMessage
+	inputs      []InputMessage
+	events      []Event
 
 	errMu sync.Mutex
 	err   error
 }
 
-func newPeer(conn net.Conn) *peer {
+func newPeer(conn io.ReadWriteCloser, hello Hello) *peer {
 	p := &peer{
-		conn:   conn,
-		sendCh: make(chan StateMessage, defaultSendBufferSize),
-		closed: make(chan struct{}),
+		conn:    conn,
+		hello:   hello,
+		clock:   peerClock{epoch: time.Now()},
+		stats:   newStatsCounter(time.Now()),
+		traffic: newTrafficCounter(time.Now()),
+		sendCh:  make(chan envelope, defaultSendBufferSize),
+		eventCh: make(chan Event, defaultEventBufferSize),
+		kickCh:  make(chan disconnectReason, 1),
+		closed:  make(chan struct{}),
 	}
 
 	go p.readLoop()
 	go p.writeLoop()
+	go p.clockLoop()
 
 	return p
 }
 
 func (p *peer) readLoop() {
-	decoder := json.NewDecoder(p.conn)
+	reader := frameReader{r: bufio.NewReader(p.conn), deltas: &p.decoder}
 
 	for {
-		var msg StateMessage
-		if err := decoder.Decode(&msg); err != nil {
+		p.extendReadDeadline()
+		msg, err := reader.read()
+		if err != nil {
 			if !errors.Is(err, io.EOF) {
-				p.setErr(err)
+				p.setErr(readError(err))
 			} else {
 				p.setErr(io.EOF)
 			}
 			p.close()
 			return
 		}
+		p.traffic.received(reader.size)
+		if msg.Type == MessageDisconnect {
+			p.setErr(msg.Reason.err())
+			p.close()
+			return
+		}
+
+		if sim := p.sim.Load(); sim != nil {
+			if !sim.lose(msg) {
+				sim.in.push(sim.delay(), func() { p.deliver(msg) })
+			}
+			continue
+		}
+		p.deliver(msg)
+	}
+}
+
+// deliver раскладывает полученный кадр по очередям соединения
+func (p *peer) deliver(msg envelope) {
+	p.mu.Lock()
+	switch {
+	case msg.Type == MessageSnapshot:
+		// Снимок без данных (забыт базовый снимок) считается потерянным
+		p.stats.add(msg.Seq, msg.Snapshot != nil)
+		if msg.Snapshot == nil {
+			p.traffic.decodeErrors.Add(1)
+			break
+		}
+		p.snapshots = append(p.snapshots, *msg.Snapshot)
+		if extra := len(p.snapshots) - defaultSnapshotQueueSize; extra > 0 {
+			p.snapshots = append(p.snapshots[:0], p.snapshots[extra:]...)
+		}
+	case msg.Type == MessageInput && msg.Input != nil:
+		p.stats.add(msg.Input.Seq, true)
+		p.encoder.ack(msg.Ack)
+		p.inputs = append(p.inputs, *msg.Input)
+		if extra := len(p.inputs) - defaultInputQueueSize; extra > 0 {
+			p.inputs = append(p.inputs[:0], p.inputs[extra:]...)
+		}
+	case msg.Type == MessageEvent && msg.Event != nil:
+		p.events = append(p.events, *msg.Event)
+	case msg.Type == MessageHello && msg.Hello != nil:
+		p.remote, p.remoteKnown = *msg.Hello, true
+	}
+	p.mu.Unlock()
 
-		p.mu.Lock()
-		p.latest = msg
-		p.hasData = true
-		p.mu.Unlock()
+	if msg.Type == MessageClock && msg.Clock != nil {
+		p.handleClock(*msg.Clock)
 	}
 }
 
 func (p *peer) writeLoop() {
-	encoder := json.NewEncoder(p.conn)
+	writer := frameWriter{w: p.conn, deltas: &p.encoder}
+
+	// Рукопожатие идет первым кадром соединения
+	if err := writer.write(envelope{Type: MessageHello, Hello: &p.hello}); err != nil {
+		p.setErr(err)
+		p.close()
+		return
+	}
+
+	heartbeat := time.NewTicker(defaultHeartbeatInterval)
+	defer heartbeat.Stop()
+	lastWrite := time.Now()
 
 	for {
+		var frame envelope
+
+		// Отключение и события отправляем в первую очередь, чтобы они не ждали за потоком состояний
 		select {
 		case <-p.closed:
 			return
-		case msg, ok := <-p.sendCh:
-			if !ok {
-				return
-			}
-			if err := encoder.Encode(&msg); err != nil {
-				p.setErr(err)
-				p.close()
+		case reason := <-p.kickCh:
+			frame = envelope{Type: MessageDisconnect, Reason: reason}
+		case event := <-p.eventCh:
+			frame = envelope{Type: MessageEvent, Event: &event}
+		default:
+			select {
+			case <-p.closed:
 				return
+			case reason := <-p.kickCh:
+				frame = envelope{Type: MessageDisconnect, Reason: reason}
+			case event := <-p.eventCh:
+				frame = envelope{Type: MessageEvent, Event: &event}
+			case msg := <-p.sendCh:
+				frame = msg
+			case <-heartbeat.C:
+				// Пульс нужен, только если другая сторона давно ничего не получала
+				if time.Since(lastWrite) < defaultHeartbeatInterval {
+					continue
+				}
+				frame = envelope{Type: MessageHeartbeat}
 			}
 		}
+
+		if err := writer.write(frame); err != nil {
+			p.setErr(err)
+			p.close()
+			return
+		}
+		lastWrite = time.Now()
+		p.traffic.sent(len(writer.buf))
+		if frame.Type == MessageDisconnect {
+			p.close()
+			return
+		}
 	}
 }
 
-func (p *peer) send(state StateMessage) error {
+func (p *peer) send(frame envelope) error {
+	if sim := p.sim.Load(); sim != nil {
+		// Задержанный кадр встает в очередь отправки позже, а потерянный не встает вовсе
+		if !sim.lose(frame) {
+			sim.out.push(sim.delay(), func() { _ = p.enqueue(frame) })
+		}
+		return nil
+	}
+	return p.enqueue(frame)
+}
+
+// enqueue ставит ввод, снимок или кадр часов в очередь отправки
+func (p *peer) enqueue(frame envelope) error {
 	select {
 	case <-p.closed:
 		return p.getErr()
-	case p.sendCh <- state:
+	case p.sendCh <- frame:
 		return nil
 	default:
 		// Канал переполнен — сбрасываем старые данные и отправляем новое состояние.
@@ -235,28 +674,76 @@ func (p *peer) send(state StateMessage) error {
 		case <-p.closed:
 			return p.getErr()
 		case <-p.sendCh:
+			p.traffic.dropped.Add(1)
 		default:
 		}
 		select {
 		case <-p.closed:
 			return p.getErr()
-		case p.sendCh <- state:
+		case p.sendCh <- frame:
 			return nil
 		default:
+			p.traffic.dropped.Add(1)
 			return nil
 		}
 	}
 }
 
-func (p *peer) latestState() (StateMessage, bool) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+func (p *peer) sendEvent(event Event) error {
+	if sim := p.sim.Load(); sim != nil {
+		// Задержанное событие встает в очередь позже, поэтому очередь задержек
+		// ждет в ней места: сообщить отправившему о переполнении уже некому
+		sim.out.push(sim.delay(), func() { p.waitEvent(event) })
+		return nil
+	}
+	return p.enqueueEvent(event)
+}
+
+// enqueueEvent ставит событие в очередь отправки
+func (p *peer) enqueueEvent(event Event) error {
+	select {
+	case <-p.closed:
+		return p.getErr()
+	case p.eventCh <- event:
+		return nil
+	default:
+		return ErrEventQueueFull
+	}
+}
 
-	if !p.hasData {
-		return StateMessage{}, false
+// waitEvent ставит событие в очередь отправки, дожидаясь в ней места
+func (p *peer) waitEvent(event Event) {
+	select {
+	case <-p.closed:
+	case p.eventCh <- event:
 	}
+}
+
+func (p *peer) pollEvents() []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	events := p.events
+	p.events = nil
+	return events
+}
+
+func (p *peer) pollSnapshots() []SnapshotMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	return p.latest, true
+	snapshots := p.snapshots
+	p.snapshots = nil
+	return snapshots
+}
+
+func (p *peer) pollInputs() []InputMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	inputs := p.inputs
+	p.inputs = nil
+	return inputs
 }
 
 func (p *peer) getErr() error {
@@ -281,56 +768,203 @@ func (p *peer) close() error {
 	var result error
 
 	p.closeFn.Do(func() {
+		// sendCh не закрывается: send может писать в него одновременно с закрытием,
+		// а поток записи и так завершается по p.closed
 		close(p.closed)
-		close(p.sendCh)
 		result = p.conn.Close()
 	})
 
 	return result
 }
 
-func (m *Manager) acceptOnce() {
-	listener := m.getListener()
-	if listener == nil {
-		return
+// listenLoop открывает listener и ждет клиента, перезапуская listener после сбоев.
+// После отключения клиента хост снова ждет следующего.
+func (m *Manager) listenLoop() {
+	attempt := 0
+	for {
+		err := m.listenOnce()
+		if m.isClosed() {
+			return
+		}
+		if err == nil {
+			attempt = 0
+			continue
+		}
+
+		attempt++
+		state := ListenRetrying
+		if attempt > defaultRelistenTries {
+			state = ListenFailed
+		}
+		m.setListenStatus(state, attempt, err)
+
+		if !m.waitRetry(state == ListenFailed) {
+			return
+		}
+		if state == ListenFailed {
+			// Ручной повтор начинает новую серию автоматических попыток
+			attempt = 0
+		}
+	}
+}
+
+// listenOnce открывает listener и принимает одного клиента, а пока он подключен,
+// отказывает остальным. Возвращает nil, когда клиент отключился или менеджер закрыт.
+func (m *Manager) listenOnce() error {
+	listener, err := m.listen()
+	if err != nil {
+		return err
+	}
+	if !m.setListener(listener) {
+		_ = listener.Close()
+		return nil
 	}
-	defer listener.Close()
+	defer func() {
+		if prev := m.swapListener(nil); prev != nil {
+			_ = prev.Close()
+		}
+	}()
+
+	m.setListenStatus(ListenListening, 0, nil)
 
-	conn, err := listener.Accept()
+	conn, hello, err := m.acceptClient(listener)
 	if err != nil {
-		if !errors.Is(err, net.ErrClosed) {
-			m.setErr(err)
+		if m.isClosed() {
+			return nil
 		}
-		return
+		return err
 	}
 
 	if m.isClosed() {
 		_ = conn.Close()
-		return
+		return nil
 	}
 
-	newPeer := newPeer(conn)
+	newPeer := newPeer(conn, m.getHello())
+	// Рукопожатие клиента хост уже прочитал, проверяя пароль
+	newPeer.mu.Lock()
+	newPeer.remote, newPeer.remoteKnown = hello, true
+	newPeer.mu.Unlock()
 
 	m.mu.Lock()
 	if m.peer != nil {
 		m.mu.Unlock()
 		_ = newPeer.close()
-		return
+		return nil
 	}
 	m.peer = newPeer
+	newPeer.simulate(m.conditions)
+	m.reconnect = Reconnect{}
+	m.status.State = ListenConnected
+	m.status.Attempt = 0
+	m.status.Err = nil
 	m.mu.Unlock()
+
+	go m.watchPeer(newPeer)
+	m.refuseWhileConnected(listener, newPeer)
+	return nil
 }
 
-func (m *Manager) getPeer() *peer {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	return m.peer
+// acceptClient принимает клиента с той же версией протокола и незапрещенного адреса.
+// Остальные клиенты отключаются, а причина отказа видна хосту в ListenStatus.
+func (m *Manager) acceptClient(listener net.Listener) (net.Conn, Hello, error) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return nil, Hello{}, err
+		}
+		err = handshake(conn)
+		if err == nil && m.isBanned(conn) {
+			refuse(conn, reasonBanned)
+			err = fmt.Errorf("network: refused banned address %s", remoteIP(conn))
+		}
+		var hello Hello
+		if err == nil {
+			hello, err = m.readClientHello(conn)
+		}
+		if err == nil && m.full() {
+			refuse(conn, reasonFull)
+			err = fmt.Errorf("network: refused %s: %w", hello.Name, ErrServerFull)
+		}
+		if err == nil {
+			return conn, hello, nil
+		}
+		_ = conn.Close()
+		if m.isClosed() {
+			return nil, Hello{}, net.ErrClosed
+		}
+		m.setListenStatus(ListenListening, 0, err)
+	}
+}
+
+// listen открывает listener хоста: TCP (или комнату посредника, если
+// адрес вида relay://) и, если задан адрес, WebSocket
+func (m *Manager) listen() (net.Listener, error) {
+	var listener net.Listener
+	var err error
+	if isRelayURL(m.address) {
+		listener, err = listenRelay(m.address)
+	} else {
+		listener, err = net.Listen("tcp", m.address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m.wsAddress == "" {
+		return listener, nil
+	}
+
+	ws, err := listenWebSocket(m.wsAddress)
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return joinListeners(listener, ws), nil
 }
 
-func (m *Manager) getListener() net.Listener {
+// waitRetry ждет паузы перед повтором или ручного запроса Retry.
+// Если manualOnly, ждет только ручного запроса. Возвращает false, если менеджер закрыт.
+func (m *Manager) waitRetry(manualOnly bool) bool {
+	var delay <-chan time.Time
+	if !manualOnly {
+		timer := time.NewTimer(defaultRelistenDelay)
+		defer timer.Stop()
+		delay = timer.C
+	}
+
+	select {
+	case <-m.closed:
+		return false
+	case <-m.retryCh:
+		return true
+	case <-delay:
+		return true
+	}
+}
+
+func (m *Manager) setListenStatus(state ListenState, attempt int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status.State = state
+	m.status.Attempt = attempt
+	m.status.Err = err
+}
+
+// setListener запоминает listener, если менеджер еще не закрыт.
+func (m *Manager) setListener(listener net.Listener) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.isClosed() {
+		return false
+	}
+	m.listener = listener
+	return true
+}
+
+func (m *Manager) getPeer() *peer {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.listener
+	return m.peer
 }
 
 func (m *Manager) swapListener(next net.Listener) net.Listener {
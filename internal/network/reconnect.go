@@ -0,0 +1,179 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// Оборвавшееся соединение не завершает сеанс сразу: хост снова начинает
+// принимать клиентов, а клиент переподключается к хосту с растущими паузами.
+// Попытки идут не дольше окна восстановления; если клиент за это время не
+// подключился, Err возвращает ошибку. Пока соединения нет, вводы, снимки и
+// события не отправляются, а игра продолжается локально.
+
+const (
+	defaultReconnectWindow   = 30 * time.Second       // Окно восстановления по умолчанию
+	defaultReconnectDelay    = 500 * time.Millisecond // Пауза перед второй попыткой клиента
+	defaultReconnectMaxDelay = 5 * time.Second        // Наибольшая пауза между попытками
+)
+
+// Reconnect — состояние восстановления оборвавшегося соединения
+type Reconnect struct {
+	Active    bool          // Соединение оборвалось и восстанавливается
+	Attempt   int           // Номер попытки подключения (только клиент)
+	Remaining time.Duration // Сколько еще продлятся попытки
+}
+
+// SetReconnectWindow задает, сколько пытаться восстановить оборвавшееся
+// соединение (0 - не пытаться: обрыв сразу становится ошибкой клиента).
+func (m *Manager) SetReconnectWindow(window time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.reconnectWindow = window
+	m.mu.Unlock()
+}
+
+// Reconnect возвращает состояние восстановления соединения.
+func (m *Manager) Reconnect() Reconnect {
+	if m == nil {
+		return Reconnect{}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	state := m.reconnect
+	if state.Active {
+		state.Remaining = max(time.Until(m.reconnectUntil), 0)
+	}
+	return state
+}
+
+// dial подключается к хосту: по WebSocket, если address - URL вида ws://,
+// через посредника, если вида relay://, иначе по TCP
+func dial(address string) (io.ReadWriteCloser, error) {
+	if isWebSocketURL(address) {
+		return dialWebSocket(address)
+	}
+	if isRelayURL(address) {
+		return dialRelay(address)
+	}
+	return net.DialTimeout("tcp", address, defaultDialTimeout)
+}
+
+// attach делает p текущим соединением и следит за его обрывом
+func (m *Manager) attach(p *peer) {
+	m.mu.Lock()
+	m.peer = p
+	m.reconnect = Reconnect{}
+	p.simulate(m.conditions)
+	m.mu.Unlock()
+
+	go m.watchPeer(p)
+}
+
+// watchPeer ждет закрытия соединения p и запускает его восстановление
+func (m *Manager) watchPeer(p *peer) {
+	<-p.closed
+	if m.isClosed() {
+		return
+	}
+
+	m.mu.Lock()
+	if m.peer != p {
+		m.mu.Unlock()
+		return
+	}
+	m.peer = nil
+	// Отключенного хостом клиента не ждут обратно, а сам он не переподключается;
+	// не к кому переподключаться и после конца записи матча
+	disconnected := p.kicked.Load() || isDisconnect(p.getErr()) || errors.Is(p.getErr(), ErrPlaybackFinished)
+	until := time.Now().Add(m.reconnectWindow)
+	m.reconnect = Reconnect{Active: m.reconnectWindow > 0 && !disconnected}
+	m.reconnectUntil = until
+	m.mu.Unlock()
+
+	if m.host {
+		// Следующего клиента уже ждет listenLoop
+		if !disconnected {
+			go m.expireReconnect(until)
+		}
+		return
+	}
+	if disconnected {
+		m.setErr(p.getErr())
+		return
+	}
+	go m.redialLoop(until, p.getErr())
+}
+
+// expireReconnect на хосте завершает ожидание прежнего клиента по окончании окна.
+// Хост при этом продолжает принимать клиентов, как до первого подключения.
+func (m *Manager) expireReconnect(until time.Time) {
+	timer := time.NewTimer(time.Until(until))
+	defer timer.Stop()
+	select {
+	case <-m.closed:
+		return
+	case <-timer.C:
+	}
+
+	m.mu.Lock()
+	if m.peer == nil && m.reconnectUntil.Equal(until) {
+		m.reconnect = Reconnect{}
+	}
+	m.mu.Unlock()
+}
+
+// redialLoop на клиенте переподключается к хосту, удваивая паузу между попытками,
+// пока не подключится или не закончится окно восстановления
+func (m *Manager) redialLoop(until time.Time, lost error) {
+	delay := defaultReconnectDelay
+	for attempt := 1; ; attempt++ {
+		m.mu.Lock()
+		m.reconnect.Attempt = attempt
+		m.mu.Unlock()
+
+		if time.Now().Before(until) {
+			conn, err := dialHandshake(m.address)
+			if err == nil {
+				if m.isClosed() {
+					_ = conn.Close()
+					return
+				}
+				m.attach(newPeer(conn, m.getHello()))
+				return
+			}
+			if errors.Is(err, ErrVersionMismatch) {
+				// Хост сменил сборку: повторять бесполезно
+				m.mu.Lock()
+				m.reconnect = Reconnect{}
+				m.mu.Unlock()
+				m.setErr(err)
+				return
+			}
+		}
+
+		wait := min(delay, time.Until(until))
+		if wait <= 0 {
+			m.mu.Lock()
+			m.reconnect = Reconnect{}
+			m.mu.Unlock()
+			m.setErr(fmt.Errorf("network: connection lost: %w", lost))
+			return
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-m.closed:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		delay = min(delay*2, defaultReconnectMaxDelay)
+	}
+}
@@ -0,0 +1,224 @@
+package network
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Хост может записывать матч: каждое отправленное и полученное сообщение
+// (снимки, вводы, события) попадает в файл строкой JSON вместе с шагом хоста.
+// Запись нужна, чтобы пересмотреть матч или воспроизвести рассинхронизацию:
+// Playback открывает запись как подключение к хосту, который заново присылает
+// записанные снимки и события в том же темпе, а игра показывает их как клиент.
+//
+// Первая строка файла - заголовок с версией протокола и рукопожатием хоста,
+// дальше по строке на сообщение:
+//
+//	{"version":13,"host":{...}}
+//	{"tick":120,"sent":true,"snapshot":{...}}
+//	{"tick":120,"input":{...}}
+//	{"tick":121,"sent":true,"event":{...}}
+
+// playbackTicksPerSecond — сколько шагов хоста в секунду воспроизводит Playback
+const playbackTicksPerSecond = 60
+
+// ErrPlaybackFinished возвращается, когда записанный матч закончился
+var ErrPlaybackFinished = errors.New("network: recording finished")
+
+// recordingHeader — первая строка записи матча
+type recordingHeader struct {
+	Version int   `json:"version"` // Версия протокола сборки, записавшей матч
+	Host    Hello `json:"host"`    // Рукопожатие хоста
+}
+
+// record — сообщение в записи матча
+type record struct {
+	Tick     uint32           `json:"tick"`           // Шаг хоста: номер снимка или последнего отправленного снимка
+	Sent     bool             `json:"sent,omitempty"` // Сообщение отправила записывающая сторона (иначе - получила)
+	Snapshot *SnapshotMessage `json:"snapshot,omitempty"`
+	Input    *InputMessage    `json:"input,omitempty"`
+	Event    *Event           `json:"event,omitempty"`
+}
+
+// recorder пишет сообщения в файл записи матча
+type recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	w    *bufio.Writer
+	enc  *json.Encoder
+	tick uint32
+	err  error // Первая ошибка записи: после нее запись останавливается
+}
+
+// Record начинает записывать матч в файл path (существующий файл перезаписывается).
+// Запись идет, пока менеджер не закрыт. Ошибку записи возвращает Close.
+func (m *Manager) Record(path string) error {
+	if m == nil {
+		return nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	r := &recorder{file: file, w: w, enc: json.NewEncoder(w)}
+	if err := r.enc.Encode(recordingHeader{Version: ProtocolVersion, Host: m.getHello()}); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if old := m.recorder.Swap(r); old != nil {
+		_ = old.close()
+	}
+	return nil
+}
+
+// write дописывает сообщение в запись. Шаг снимка становится шагом следующих сообщений.
+func (r *recorder) write(rec record) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	if rec.Snapshot != nil {
+		r.tick = rec.Snapshot.Tick
+	}
+	rec.Tick = r.tick
+	r.err = r.enc.Encode(rec)
+}
+
+// close дописывает буфер и закрывает файл записи
+func (r *recorder) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := r.err
+	if flushErr := r.w.Flush(); err == nil {
+		err = flushErr
+	}
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	r.err = os.ErrClosed
+	return err
+}
+
+// recordSent записывает сообщение, отправленное этой стороной
+func (m *Manager) recordSent(rec record) {
+	rec.Sent = true
+	m.recorder.Load().write(rec)
+}
+
+// recordReceived записывает сообщение, полученное от другой стороны
+func (m *Manager) recordReceived(rec record) {
+	m.recorder.Load().write(rec)
+}
+
+// Playback открывает запись матча из файла path как подключение к хосту:
+// записанные хостом снимки и события приходят в том же темпе, в каком он их
+// отправлял, а вводы и события этой стороны никуда не уходят. Рукопожатие
+// хоста из записи доступно через Remote сразу. Когда запись кончается,
+// Err возвращает ErrPlaybackFinished.
+func Playback(path string, hello Hello) (*Manager, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bufio.NewReader(file))
+	var header recordingHeader
+	if err := dec.Decode(&header); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("network: read recording %s: %w", path, err)
+	}
+	if header.Version != ProtocolVersion {
+		_ = file.Close()
+		return nil, fmt.Errorf("%w: the recording was made by v%d, this build speaks v%d",
+			ErrVersionMismatch, header.Version, ProtocolVersion)
+	}
+
+	reader, writer := io.Pipe()
+	conn := &playbackConn{PipeReader: reader}
+	p := newPeer(conn, hello)
+	p.mu.Lock()
+	p.remote, p.remoteKnown = header.Host, true
+	p.mu.Unlock()
+
+	manager := newManager(nil)
+	manager.hello = hello
+	manager.attach(p)
+	go playRecording(file, dec, header.Host, writer)
+	return manager, nil
+}
+
+// playbackConn — соединение воспроизведения: кадры приходят из записи,
+// а отправленные кадры отбрасываются
+type playbackConn struct {
+	*io.PipeReader
+}
+
+// Write отбрасывает кадр
+func (c *playbackConn) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+// playRecording передает в w кадры сообщений, которые отправил записавший матч хост,
+// выдерживая между ними время по шагам хоста
+func playRecording(file *os.File, dec *json.Decoder, host Hello, w *io.PipeWriter) {
+	defer file.Close()
+
+	writer := frameWriter{w: w, deltas: &deltaEncoder{}}
+	if err := writer.write(envelope{Type: MessageHello, Hello: &host}); err != nil {
+		return
+	}
+
+	var (
+		start     time.Time
+		firstTick uint32
+		seq       uint32
+	)
+	for {
+		var rec record
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = ErrPlaybackFinished
+			}
+			_ = w.CloseWithError(err)
+			return
+		}
+		if !rec.Sent || (rec.Snapshot == nil && rec.Event == nil) {
+			continue
+		}
+
+		// Время сообщения отсчитывается от первого записанного шага
+		if start.IsZero() {
+			start, firstTick = time.Now(), rec.Tick
+		}
+		at := start.Add(time.Duration(rec.Tick-firstTick) * time.Second / playbackTicksPerSecond)
+		time.Sleep(time.Until(at))
+
+		frame := envelope{Type: MessageEvent, Event: rec.Event}
+		if rec.Snapshot != nil {
+			seq++
+			frame = envelope{Type: MessageSnapshot, Snapshot: rec.Snapshot, Seq: seq}
+		}
+		// Соединение закрыто: воспроизведение больше никто не смотрит
+		if err := writer.write(frame); err != nil {
+			return
+		}
+	}
+}
+
+// stopRecording заканчивает запись матча
+func (m *Manager) stopRecording() error {
+	if r := m.recorder.Swap(nil); r != nil {
+		return r.close()
+	}
+	return nil
+}
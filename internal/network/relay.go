@@ -0,0 +1,140 @@
+package network
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+
+	"platformer/internal/relay"
+)
+
+// Игра через посредника (cmd/relay) для игроков за NAT: хост и клиент оба
+// подключаются к посреднику по адресу вида relay://host:port/комната, и тот
+// пересылает данные между ними. Для менеджера соединение через посредника
+// ничем не отличается от прямого TCP.
+
+// isRelayURL сообщает, задан ли адрес как адрес посредника (relay://)
+func isRelayURL(address string) bool {
+	return strings.HasPrefix(address, "relay://")
+}
+
+// parseRelayURL разбирает адрес relay://host:port/комната
+func parseRelayURL(address string) (relayAddress, room string, err error) {
+	target, err := url.Parse(address)
+	if err != nil {
+		return "", "", err
+	}
+	room = strings.Trim(target.Path, "/")
+	if target.Host == "" || room == "" || strings.ContainsAny(room, " \t\n") {
+		return "", "", errors.New("network: relay address must look like relay://host:port/room")
+	}
+	return target.Host, room, nil
+}
+
+// dialRelay подключается к комнате посредника как клиент
+func dialRelay(address string) (io.ReadWriteCloser, error) {
+	relayAddress, room, err := parseRelayURL(address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("tcp", relayAddress, defaultDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if err := relay.Connect(conn, relay.RoleJoin, room); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// relayListener принимает клиентов через посредника: Accept регистрирует
+// комнату и ждет, пока к ней подключится клиент
+type relayListener struct {
+	address string // Адрес посредника host:port
+	room    string
+
+	mu      sync.Mutex
+	pending net.Conn // Соединение, ждущее клиента в Accept
+	closed  bool
+}
+
+// listenRelay создает listener хоста для адреса relay://host:port/комната
+func listenRelay(address string) (net.Listener, error) {
+	relayAddress, room, err := parseRelayURL(address)
+	if err != nil {
+		return nil, err
+	}
+	return &relayListener{address: relayAddress, room: room}, nil
+}
+
+// Accept ждет клиента в комнате посредника
+func (l *relayListener) Accept() (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", l.address, defaultDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		_ = conn.Close()
+		return nil, net.ErrClosed
+	}
+	l.pending = conn
+	l.mu.Unlock()
+
+	err = relay.Connect(conn, relay.RoleHost, l.room)
+
+	l.mu.Lock()
+	l.pending = nil
+	closed := l.closed
+	l.mu.Unlock()
+
+	if err != nil || closed {
+		_ = conn.Close()
+		if closed {
+			return nil, net.ErrClosed
+		}
+		return nil, err
+	}
+	return relayConn{Conn: conn, remote: l.Addr()}, nil
+}
+
+// Close прерывает ожидание клиента и освобождает комнату
+func (l *relayListener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	if l.pending != nil {
+		return l.pending.Close()
+	}
+	return nil
+}
+
+// Addr возвращает адрес посредника
+func (l *relayListener) Addr() net.Addr {
+	return relayAddr("relay://" + l.address + "/" + l.room)
+}
+
+// relayConn — соединение с клиентом через посредника. Настоящий адрес клиента
+// знает только посредник, поэтому адресом другой стороны считается комната.
+type relayConn struct {
+	net.Conn
+	remote net.Addr
+}
+
+// RemoteAddr возвращает адрес комнаты посредника
+func (c relayConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// relayAddr — адрес listener посредника
+type relayAddr string
+
+func (a relayAddr) Network() string { return "relay" }
+func (a relayAddr) String() string  { return string(a) }
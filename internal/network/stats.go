@@ -0,0 +1,86 @@
+package network
+
+import "time"
+
+// defaultStatsWindow — за какое время считается доля потерь
+const defaultStatsWindow = time.Second
+
+// Stats — качество соединения для отображения игроку
+type Stats struct {
+	Ping       time.Duration // Время пути туда и обратно (0 - еще не измерено)
+	Loss       float64       // Доля потерянных снимков (у клиента) или вводов (у хоста) за последнюю секунду
+	LastUpdate time.Duration // Сколько прошло с последнего полученного снимка или ввода
+	Jitter     time.Duration // Разброс промежутков между полученными снимками или вводами
+}
+
+// statsCounter считает потери по номерам полученных снимков или вводов:
+// пропуск в номерах значит, что кадры сбросила очередь отправки или их
+// нельзя было восстановить
+type statsCounter struct {
+	last       uint32    // Последний полученный номер
+	updated    time.Time // Когда пришел последний пригодный кадр
+	windowFrom time.Time // Начало текущего окна подсчета потерь
+	received   int       // Пригодных кадров в текущем окне
+	lost       int       // Потерянных кадров в текущем окне
+	loss       float64   // Доля потерь за прошлое окно
+
+	arrived  time.Time     // Когда пришел последний кадр (пригодный или нет)
+	interval time.Duration // Промежуток перед последним кадром
+	jitter   time.Duration // Сглаженное изменение промежутков (как в RFC 3550)
+}
+
+// newStatsCounter создает счетчик для соединения, открытого в момент now
+func newStatsCounter(now time.Time) statsCounter {
+	return statsCounter{updated: now, windowFrom: now}
+}
+
+// add учитывает кадр с номером seq; usable - удалось ли его прочитать
+func (s *statsCounter) add(seq uint32, usable bool) {
+	now := time.Now()
+	if now.Sub(s.windowFrom) >= defaultStatsWindow {
+		if total := s.received + s.lost; total > 0 {
+			s.loss = float64(s.lost) / float64(total)
+		}
+		s.windowFrom, s.received, s.lost = now, 0, 0
+	}
+
+	switch {
+	case seq == 0:
+		// Кадр без номера не говорит о потерях
+	case seq <= s.last:
+		// Повтор или кадр не по порядку: он уже посчитан потерянным
+	default:
+		if s.last != 0 {
+			s.lost += int(seq - s.last - 1)
+		}
+		s.last = seq
+	}
+
+	if usable {
+		s.received++
+		s.updated = now
+	} else {
+		s.lost++
+	}
+
+	if !s.arrived.IsZero() {
+		interval := now.Sub(s.arrived)
+		change := interval - s.interval
+		if change < 0 {
+			change = -change
+		}
+		s.jitter += (change - s.jitter) / 16
+		s.interval = interval
+	}
+	s.arrived = now
+}
+
+// getStats возвращает качество соединения
+func (p *peer) getStats() Stats {
+	p.mu.RLock()
+	stats := Stats{Loss: p.stats.loss, LastUpdate: time.Since(p.stats.updated), Jitter: p.stats.jitter}
+	p.mu.RUnlock()
+
+	stats.Ping = p.clock.ping()
+	return stats
+}
@@ -0,0 +1,83 @@
+package network
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Traffic — счетчики трафика соединения для отладки синхронизации
+type Traffic struct {
+	FramesIn, FramesOut float64 // Кадров в секунду
+	BytesIn, BytesOut   float64 // Байт в секунду (вместе с заголовками кадров)
+
+	Dropped      uint64 // Вводов и снимков, сброшенных переполненной очередью отправки
+	DecodeErrors uint64 // Полученных снимков, которые не удалось восстановить (забыт базовый снимок)
+}
+
+// trafficCounter считает кадры и байты соединения. Счетчики растут в потоках
+// чтения и записи, а скорость пересчитывается при запросе раз в defaultStatsWindow.
+type trafficCounter struct {
+	framesIn, framesOut atomic.Uint64
+	bytesIn, bytesOut   atomic.Uint64
+	dropped             atomic.Uint64
+	decodeErrors        atomic.Uint64
+
+	mu         sync.Mutex
+	windowFrom time.Time  // Начало текущего окна подсчета скорости
+	base       [4]uint64  // Значения счетчиков в начале окна
+	rates      [4]float64 // Скорость за прошлое окно
+}
+
+// newTrafficCounter создает счетчик для соединения, открытого в момент now
+func newTrafficCounter(now time.Time) *trafficCounter {
+	return &trafficCounter{windowFrom: now}
+}
+
+// received учитывает полученный кадр размером size байт
+func (t *trafficCounter) received(size int) {
+	t.framesIn.Add(1)
+	t.bytesIn.Add(uint64(size))
+}
+
+// sent учитывает отправленный кадр размером size байт
+func (t *trafficCounter) sent(size int) {
+	t.framesOut.Add(1)
+	t.bytesOut.Add(uint64(size))
+}
+
+// snapshot возвращает скорость за последнее окно и накопленные счетчики
+func (t *trafficCounter) snapshot() Traffic {
+	totals := [4]uint64{t.framesIn.Load(), t.framesOut.Load(), t.bytesIn.Load(), t.bytesOut.Load()}
+
+	t.mu.Lock()
+	if elapsed := time.Since(t.windowFrom); elapsed >= defaultStatsWindow {
+		for i, total := range totals {
+			t.rates[i] = float64(total-t.base[i]) / elapsed.Seconds()
+		}
+		t.base = totals
+		t.windowFrom = time.Now()
+	}
+	rates := t.rates
+	t.mu.Unlock()
+
+	return Traffic{
+		FramesIn:     rates[0],
+		FramesOut:    rates[1],
+		BytesIn:      rates[2],
+		BytesOut:     rates[3],
+		Dropped:      t.dropped.Load(),
+		DecodeErrors: t.decodeErrors.Load(),
+	}
+}
+
+// Traffic возвращает счетчики трафика текущего соединения (false - соединения нет).
+func (m *Manager) Traffic() (Traffic, bool) {
+	if m == nil {
+		return Traffic{}, false
+	}
+	if peer := m.getPeer(); peer != nil {
+		return peer.traffic.snapshot(), true
+	}
+	return Traffic{}, false
+}
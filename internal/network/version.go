@@ -0,0 +1,84 @@
+package network
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// До первого кадра стороны обмениваются приветствием протокола: сигнатурой
+// и версией формата кадров. Сборки с разным форматом снимков не смогут
+// понять друг друга, поэтому соединение с другой версией сразу закрывается
+// с понятной ошибкой, а не разбирает чужие кадры как мусор.
+//
+//	[4]byte "PLTF"
+//	uint16  версия протокола (little-endian)
+
+// ProtocolVersion — версия формата кадров. Увеличивается при любом изменении,
+// из-за которого сборки перестают понимать друг друга (состав кадров, снимков, событий).
+const ProtocolVersion = 13
+
+// defaultHandshakeTimeout — сколько ждать приветствия другой стороны
+const defaultHandshakeTimeout = 5 * time.Second
+
+// protocolMagic — сигнатура приветствия протокола
+var protocolMagic = [4]byte{'P', 'L', 'T', 'F'}
+
+// ErrVersionMismatch возвращается, если другая сторона использует другую версию протокола
+var ErrVersionMismatch = errors.New("network: protocol version mismatch")
+
+// errHandshakeTimeout возвращается, если другая сторона не прислала приветствие вовремя
+var errHandshakeTimeout = errors.New("network: handshake timed out")
+
+// handshake отправляет приветствие протокола и проверяет приветствие другой стороны.
+// Обе стороны сначала пишут, а потом читают, поэтому ни одна не ждет другую.
+func handshake(conn io.ReadWriteCloser) error {
+	// У WebSocket-соединения нет сроков чтения, поэтому зависшее соединение закрывается
+	timer := time.AfterFunc(defaultHandshakeTimeout, func() { _ = conn.Close() })
+	err := exchangeVersion(conn)
+	if !timer.Stop() {
+		return errHandshakeTimeout
+	}
+	return err
+}
+
+// exchangeVersion выполняет обмен приветствиями протокола
+func exchangeVersion(conn io.ReadWriter) error {
+	var local [6]byte
+	copy(local[:], protocolMagic[:])
+	binary.LittleEndian.PutUint16(local[4:], ProtocolVersion)
+	if _, err := conn.Write(local[:]); err != nil {
+		return err
+	}
+
+	var remote [6]byte
+	if _, err := io.ReadFull(conn, remote[:]); err != nil {
+		return err
+	}
+	if !bytes.Equal(remote[:4], protocolMagic[:]) {
+		// Сборки до появления приветствия сразу шлют кадр
+		return fmt.Errorf("%w: the other side runs an older or unknown build (this build: v%d)",
+			ErrVersionMismatch, ProtocolVersion)
+	}
+	if version := binary.LittleEndian.Uint16(remote[4:]); version != ProtocolVersion {
+		return fmt.Errorf("%w: this build speaks v%d, the other side v%d; update both games to the same version",
+			ErrVersionMismatch, ProtocolVersion, version)
+	}
+	return nil
+}
+
+// dialHandshake подключается к хосту и обменивается с ним приветствием протокола
+func dialHandshake(address string) (io.ReadWriteCloser, error) {
+	conn, err := dial(address)
+	if err != nil {
+		return nil, err
+	}
+	if err := handshake(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
@@ -0,0 +1,393 @@
+package network
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Браузерная сборка игры не может открыть TCP-соединение, поэтому хост может
+// дополнительно принимать клиентов по WebSocket. По WebSocket идут те же кадры,
+// что и по TCP: каждый кадр уходит отдельным бинарным сообщением, а на приеме
+// сообщения склеиваются обратно в поток.
+
+// websocketGUID — строка из RFC 6455 для вычисления Sec-WebSocket-Accept
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Коды кадров WebSocket
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// wsMaxControlPayload — наибольший размер данных управляющего кадра (ping, pong, close)
+const wsMaxControlPayload = 125
+
+// errWebSocketHandshake возвращается, если сторона не выполнила рукопожатие WebSocket
+var errWebSocketHandshake = errors.New("network: websocket handshake failed")
+
+// isWebSocketURL сообщает, задан ли адрес подключения как WebSocket URL (ws:// или wss://)
+func isWebSocketURL(address string) bool {
+	return strings.HasPrefix(address, "ws://") || strings.HasPrefix(address, "wss://")
+}
+
+// websocketAccept вычисляет ответ сервера на ключ рукопожатия клиента
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// wsConn — соединение WebSocket поверх TCP. Read возвращает данные сообщений
+// одним потоком, Write отправляет каждый вызов отдельным бинарным сообщением.
+type wsConn struct {
+	net.Conn
+	reader *bufio.Reader
+	client bool // Кадры клиента маскируются (RFC 6455, раздел 5.3)
+
+	writeMu sync.Mutex // Понг отвечает поток чтения, а данные пишет поток записи
+
+	remaining int64   // Сколько байт данных текущего кадра еще не прочитано
+	masked    bool    // Данные текущего кадра замаскированы
+	mask      [4]byte // Маска текущего кадра
+	maskPos   int     // Смещение в маске для следующего байта
+}
+
+// Read читает данные сообщений, отвечая на ping и закрытие соединения
+func (c *wsConn) Read(p []byte) (int, error) {
+	for c.remaining == 0 {
+		if err := c.nextFrame(); err != nil {
+			return 0, err
+		}
+	}
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.reader.Read(p)
+	c.unmask(p[:n])
+	c.remaining -= int64(n)
+	return n, err
+}
+
+// nextFrame читает заголовок следующего кадра. Управляющие кадры обрабатываются
+// целиком, а у кадров с данными заголовок только запоминается для Read.
+func (c *wsConn) nextFrame() error {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return err
+	}
+	opcode := header[0] & 0x0f
+	c.masked = header[1]&0x80 != 0
+	c.maskPos = 0
+
+	length := int64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+		if length < 0 {
+			return fmt.Errorf("network: websocket frame too large")
+		}
+	}
+	if c.masked {
+		if _, err := io.ReadFull(c.reader, c.mask[:]); err != nil {
+			return err
+		}
+	}
+
+	switch opcode {
+	case wsOpContinuation, wsOpText, wsOpBinary:
+		c.remaining = length
+		return nil
+	}
+
+	if length > wsMaxControlPayload {
+		return fmt.Errorf("network: websocket control frame too large")
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return err
+	}
+	c.unmask(payload)
+
+	switch opcode {
+	case wsOpPing:
+		return c.writeFrame(wsOpPong, payload)
+	case wsOpPong:
+		return nil
+	case wsOpClose:
+		// Подтверждаем закрытие, как требует протокол; ошибка уже не важна
+		_ = c.writeFrame(wsOpClose, nil)
+		return io.EOF
+	default:
+		return fmt.Errorf("network: unknown websocket opcode %#x", opcode)
+	}
+}
+
+// unmask снимает маску текущего кадра с прочитанных данных
+func (c *wsConn) unmask(data []byte) {
+	if !c.masked {
+		return
+	}
+	for i := range data {
+		data[i] ^= c.mask[c.maskPos%4]
+		c.maskPos++
+	}
+}
+
+// Write отправляет p одним бинарным сообщением
+func (c *wsConn) Write(p []byte) (int, error) {
+	if err := c.writeFrame(wsOpBinary, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeFrame отправляет один кадр с кодом opcode
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|opcode) // FIN: сообщение из одного кадра
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n <= wsMaxControlPayload:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xffff:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	if !c.client {
+		frame = append(frame, payload...)
+	} else {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.Conn.Write(frame)
+	return err
+}
+
+// Close сообщает другой стороне о закрытии и закрывает соединение
+func (c *wsConn) Close() error {
+	_ = c.writeFrame(wsOpClose, nil)
+	return c.Conn.Close()
+}
+
+// wsListener принимает клиентов по WebSocket: HTTP-сервер выполняет рукопожатие
+// и передает соединения в Accept, как обычный net.Listener.
+type wsListener struct {
+	listener net.Listener
+	server   *http.Server
+	conns    chan net.Conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// listenWebSocket запускает прием клиентов по WebSocket на address (любой путь URL)
+func listenWebSocket(address string) (*wsListener, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	ws := &wsListener{
+		listener: listener,
+		conns:    make(chan net.Conn),
+		closed:   make(chan struct{}),
+	}
+	ws.server = &http.Server{
+		Handler:           http.HandlerFunc(ws.upgrade),
+		ReadHeaderTimeout: defaultDialTimeout,
+	}
+	go func() {
+		// Serve завершается с ошибкой при закрытии listener
+		_ = ws.server.Serve(listener)
+	}()
+
+	return ws, nil
+}
+
+// upgrade выполняет рукопожатие WebSocket и передает соединение в Accept
+func (l *wsListener) upgrade(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || key == "" ||
+		!strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		!headerContains(r.Header, "Connection", "upgrade") {
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket upgrade unsupported", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + websocketAccept(key) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(response)); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	ws := &wsConn{Conn: conn, reader: rw.Reader}
+	select {
+	case l.conns <- ws:
+	case <-l.closed:
+		// Хост уже не ждет клиентов (подключился другой или хост закрыт)
+		_ = ws.Close()
+	}
+}
+
+// Accept ждет следующего клиента, выполнившего рукопожатие
+func (l *wsListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close прекращает прием клиентов. Уже принятые соединения не закрываются.
+func (l *wsListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		err = l.server.Close()
+	})
+	return err
+}
+
+// Addr возвращает адрес, на котором принимаются клиенты
+func (l *wsListener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// headerContains сообщает, есть ли в списке через запятую заголовка name значение token
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// multiListener принимает клиентов сразу с нескольких listener (TCP и WebSocket)
+type multiListener struct {
+	listeners []net.Listener
+	conns     chan net.Conn
+	errs      chan error
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// joinListeners объединяет listeners в один: Accept возвращает первого подключившегося
+// клиента с любого из них, а ошибка любого listener завершает прием
+func joinListeners(listeners ...net.Listener) net.Listener {
+	m := &multiListener{
+		listeners: listeners,
+		conns:     make(chan net.Conn),
+		errs:      make(chan error, len(listeners)),
+		closed:    make(chan struct{}),
+	}
+	for _, listener := range listeners {
+		go m.acceptLoop(listener)
+	}
+	return m
+}
+
+// acceptLoop передает клиентов одного listener в общий Accept
+func (m *multiListener) acceptLoop(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			m.errs <- err
+			return
+		}
+		select {
+		case m.conns <- conn:
+		case <-m.closed:
+			_ = conn.Close()
+			return
+		}
+	}
+}
+
+// Accept ждет клиента с любого из listener
+func (m *multiListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-m.conns:
+		return conn, nil
+	case err := <-m.errs:
+		return nil, err
+	case <-m.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close закрывает все listener
+func (m *multiListener) Close() error {
+	var result error
+	m.closeOnce.Do(func() {
+		close(m.closed)
+		for _, listener := range m.listeners {
+			if err := listener.Close(); err != nil && result == nil {
+				result = err
+			}
+		}
+	})
+	return result
+}
+
+// Addr возвращает адрес первого listener (TCP)
+func (m *multiListener) Addr() net.Addr {
+	return m.listeners[0].Addr()
+}
@@ -0,0 +1,94 @@
+//go:build !js
+
+package network
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// dialWebSocket подключается к хосту по WebSocket URL. В собранной для браузера
+// игре используется WebSocket браузера, а здесь рукопожатие выполняется вручную
+// (например, чтобы проверить WebSocket-вход хоста из обычной сборки).
+func dialWebSocket(address string) (io.ReadWriteCloser, error) {
+	target, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+
+	host := target.Host
+	if target.Port() == "" {
+		port := "80"
+		if target.Scheme == "wss" {
+			port = "443"
+		}
+		host = net.JoinHostPort(target.Hostname(), port)
+	}
+
+	dialer := &net.Dialer{Timeout: defaultDialTimeout}
+	var conn net.Conn
+	if target.Scheme == "wss" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: target.Hostname()})
+	} else {
+		conn, err = dialer.Dial("tcp", host)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ws, err := websocketHandshake(conn, target)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+// websocketHandshake отправляет запрос на переход к WebSocket и проверяет ответ хоста
+func websocketHandshake(conn net.Conn, target *url.URL) (*wsConn, error) {
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+
+	path := target.RequestURI()
+	request := "GET " + path + " HTTP/1.1\r\n" +
+		"Host: " + target.Host + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+
+	// Рукопожатие ограничено тем же временем, что и подключение
+	if err := conn.SetDeadline(time.Now().Add(defaultDialTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write([]byte(request)); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, &http.Request{Method: http.MethodGet})
+	if err != nil {
+		return nil, err
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusSwitchingProtocols ||
+		response.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+		return nil, fmt.Errorf("%w: %s", errWebSocketHandshake, response.Status)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	return &wsConn{Conn: conn, reader: reader, client: true}, nil
+}
@@ -0,0 +1,198 @@
+//go:build js && wasm
+
+package network
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"syscall/js"
+	"time"
+)
+
+// jsConn — соединение через WebSocket браузера
+type jsConn struct {
+	ws        js.Value
+	listeners []jsListener // Обработчики событий WebSocket, которые нужно снять при закрытии
+
+	mu      sync.Mutex
+	queue   [][]byte      // Полученные сообщения, которые еще не прочитаны
+	pending []byte        // Непрочитанный остаток текущего сообщения
+	err     error         // Причина закрытия соединения
+	notify  chan struct{} // Сигнал о новом сообщении, закрытии или смене срока чтения
+
+	readDeadline time.Time // Срок, после которого Read возвращает ошибку (нулевой - без срока)
+
+	closeOnce sync.Once
+}
+
+// jsListener — обработчик события WebSocket
+type jsListener struct {
+	event string
+	fn    js.Func
+}
+
+// dialWebSocket подключается к хосту через WebSocket браузера
+func dialWebSocket(address string) (io.ReadWriteCloser, error) {
+	constructor := js.Global().Get("WebSocket")
+	if constructor.IsUndefined() {
+		return nil, errors.New("network: websocket is not supported by the browser")
+	}
+
+	c := &jsConn{
+		ws:     constructor.New(address),
+		notify: make(chan struct{}, 1),
+	}
+	c.ws.Set("binaryType", "arraybuffer")
+
+	// Обработчики вызываются из цикла событий браузера и не должны блокироваться
+	opened := make(chan error, 1)
+	c.on("open", func(js.Value) {
+		select {
+		case opened <- nil:
+		default:
+		}
+	})
+	c.on("error", func(js.Value) {
+		select {
+		case opened <- errors.New("network: websocket connection failed"):
+		default:
+		}
+	})
+	c.on("close", func(js.Value) {
+		c.fail(io.EOF)
+	})
+	c.on("message", func(event js.Value) {
+		array := js.Global().Get("Uint8Array").New(event.Get("data"))
+		data := make([]byte, array.Length())
+		js.CopyBytesToGo(data, array)
+
+		c.mu.Lock()
+		c.queue = append(c.queue, data)
+		c.mu.Unlock()
+		c.signal()
+	})
+
+	timer := time.NewTimer(defaultDialTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-opened:
+		if err != nil {
+			_ = c.Close()
+			return nil, err
+		}
+	case <-timer.C:
+		_ = c.Close()
+		return nil, errors.New("network: websocket connection timed out")
+	}
+	return c, nil
+}
+
+// on подписывается на событие WebSocket
+func (c *jsConn) on(event string, handler func(js.Value)) {
+	fn := js.FuncOf(func(this js.Value, args []js.Value) any {
+		var arg js.Value
+		if len(args) > 0 {
+			arg = args[0]
+		}
+		handler(arg)
+		return nil
+	})
+	c.listeners = append(c.listeners, jsListener{event: event, fn: fn})
+	c.ws.Call("addEventListener", event, fn)
+}
+
+// signal будит ожидающий Read
+func (c *jsConn) signal() {
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+// fail запоминает причину закрытия соединения
+func (c *jsConn) fail(err error) {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = err
+	}
+	c.mu.Unlock()
+	c.signal()
+}
+
+// Read читает данные полученных сообщений одним потоком
+func (c *jsConn) Read(p []byte) (int, error) {
+	for {
+		c.mu.Lock()
+		if len(c.pending) == 0 && len(c.queue) > 0 {
+			c.pending = c.queue[0]
+			c.queue = c.queue[1:]
+		}
+		if len(c.pending) > 0 {
+			n := copy(p, c.pending)
+			c.pending = c.pending[n:]
+			c.mu.Unlock()
+			return n, nil
+		}
+		err, deadline := c.err, c.readDeadline
+		c.mu.Unlock()
+		if err != nil {
+			return 0, err
+		}
+		if deadline.IsZero() {
+			<-c.notify
+			continue
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return 0, os.ErrDeadlineExceeded
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-c.notify:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// SetReadDeadline задает срок для Read, как у net.Conn
+func (c *jsConn) SetReadDeadline(deadline time.Time) error {
+	c.mu.Lock()
+	c.readDeadline = deadline
+	c.mu.Unlock()
+	// Ожидающий Read пересчитывает срок
+	c.signal()
+	return nil
+}
+
+// Write отправляет p одним бинарным сообщением
+func (c *jsConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	err := c.err
+	c.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	array := js.Global().Get("Uint8Array").New(len(p))
+	js.CopyBytesToJS(array, p)
+	c.ws.Call("send", array)
+	return len(p), nil
+}
+
+// Close закрывает WebSocket и освобождает обработчики событий.
+// Обработчики снимаются до освобождения: событие close придет уже после Close.
+func (c *jsConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.fail(io.EOF)
+		for _, l := range c.listeners {
+			c.ws.Call("removeEventListener", l.event, l.fn)
+			l.fn.Release()
+		}
+		c.ws.Call("close")
+	})
+	return nil
+}
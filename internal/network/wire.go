@@ -0,0 +1,527 @@
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Формат кадра на проводе:
+//
+//	uint32 длина (little-endian, без учета самого поля длины)
+//	uint8  тип кадра (wireInput, wireSnapshot, wireEvent, wireClock, wireHello, wireHeartbeat, wireDisconnect)
+//	...    данные кадра
+//
+// Ввод несет номер последнего полученного клиентом снимка, а снимок - свой номер
+// и номер базового снимка, от которого закодирована разность (0 - полный снимок,
+// подробнее в delta.go). Целые числа (идентификаторы, счетчики, здоровье, длины списков) записываются
+// как varint, координаты и скорости - как float32, флаги собираются в битовые маски.
+// Данные событий остаются JSON: события редкие, и их содержимое задает игра.
+// Кадр часов несет признак ответа, время запроса, шаг хоста и время с его отправки (clock.go).
+// Кадр рукопожатия несет имя, цвет и команду игрока, уровень хоста и пароль сервера (hello.go).
+// Кадр пульса пустой: его отправляет простаивающая сторона (keepalive.go).
+// Кадр отключения несет причину, по которой хост отключает клиента (bans.go).
+// Перед первым кадром стороны обмениваются версией протокола (version.go).
+
+// Типы кадров на проводе
+const (
+	wireInput      byte = 1
+	wireSnapshot   byte = 2
+	wireEvent      byte = 3
+	wireClock      byte = 4
+	wireHello      byte = 5
+	wireHeartbeat  byte = 6
+	wireDisconnect byte = 7
+)
+
+// maxFrameSize — наибольший допустимый размер кадра; кадр больше считается ошибкой
+// протокола, чтобы испорченная длина не заставила выделить гигабайты памяти
+const maxFrameSize = 1 << 20
+
+// Биты флагов ввода
+const (
+	inputLeft uint16 = 1 << iota
+	inputRight
+	inputJump
+	inputCrouch
+	inputSprint
+	inputDash
+	inputShoot
+	inputInteract
+	inputSwitchWeapon
+)
+
+// Биты флагов игрока
+const (
+	playerOnGround byte = 1 << iota
+	playerFacingRight
+	playerCrouching
+)
+
+// errFrameTooLarge возвращается при чтении кадра больше maxFrameSize
+var errFrameTooLarge = errors.New("network: frame too large")
+
+// frameWriter кодирует кадры в переиспользуемый буфер
+type frameWriter struct {
+	w      io.Writer
+	buf    []byte
+	deltas *deltaEncoder // Базовые снимки для разностей
+}
+
+// write кодирует frame и отправляет его одним вызовом Write
+func (fw *frameWriter) write(frame envelope) error {
+	buf := append(fw.buf[:0], 0, 0, 0, 0)
+	buf, err := fw.appendFrame(buf, frame)
+	if err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint32(buf, uint32(len(buf)-4))
+	fw.buf = buf
+	_, err = fw.w.Write(buf)
+	return err
+}
+
+// frameReader читает кадры в переиспользуемый буфер
+type frameReader struct {
+	r      io.Reader
+	buf    []byte
+	size   int           // Размер последнего прочитанного кадра вместе с заголовком
+	deltas *deltaDecoder // Полученные снимки для восстановления разностей
+}
+
+// read читает и декодирует следующий кадр
+func (fr *frameReader) read() (envelope, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(fr.r, header[:]); err != nil {
+		return envelope{}, err
+	}
+	size := binary.LittleEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return envelope{}, errFrameTooLarge
+	}
+	if cap(fr.buf) < int(size) {
+		fr.buf = make([]byte, size)
+	}
+	data := fr.buf[:size]
+	if _, err := io.ReadFull(fr.r, data); err != nil {
+		return envelope{}, err
+	}
+	fr.size = len(header) + int(size)
+	return fr.parseFrame(data)
+}
+
+// appendFrame дописывает к buf тип и данные кадра
+func (fw *frameWriter) appendFrame(buf []byte, frame envelope) ([]byte, error) {
+	switch {
+	case frame.Type == MessageInput && frame.Input != nil:
+		buf = append(buf, wireInput)
+		buf = appendInput(buf, *frame.Input)
+		return binary.AppendUvarint(buf, uint64(frame.Ack)), nil
+	case frame.Type == MessageSnapshot && frame.Snapshot != nil:
+		buf = append(buf, wireSnapshot)
+		return fw.deltas.appendSnapshot(buf, frame.Seq, frame.Snapshot), nil
+	case frame.Type == MessageEvent && frame.Event != nil:
+		buf = append(buf, wireEvent)
+		buf = appendString(buf, string(frame.Event.Type))
+		return appendBytes(buf, frame.Event.Payload), nil
+	case frame.Type == MessageClock && frame.Clock != nil:
+		buf = append(buf, wireClock)
+		buf = appendBool(buf, frame.Clock.Reply)
+		buf = binary.AppendVarint(buf, frame.Clock.Time)
+		buf = binary.AppendUvarint(buf, uint64(frame.Clock.Tick))
+		return binary.AppendVarint(buf, frame.Clock.SinceTick), nil
+	case frame.Type == MessageHello && frame.Hello != nil:
+		buf = append(buf, wireHello)
+		buf = appendString(buf, frame.Hello.Name)
+		buf = binary.AppendUvarint(buf, uint64(frame.Hello.Color))
+		buf = binary.AppendUvarint(buf, uint64(frame.Hello.Team))
+		buf = appendString(buf, frame.Hello.Level)
+		return appendString(buf, frame.Hello.Password), nil
+	case frame.Type == MessageHeartbeat:
+		return append(buf, wireHeartbeat), nil
+	case frame.Type == MessageDisconnect:
+		return append(buf, wireDisconnect, byte(frame.Reason)), nil
+	default:
+		return nil, fmt.Errorf("network: cannot encode %q frame", frame.Type)
+	}
+}
+
+// parseFrame декодирует кадр из data. Данные кадра копируются,
+// поэтому буфер можно переиспользовать для следующего кадра. Снимок, который
+// нельзя восстановить (базовый снимок уже забыт), возвращается без данных.
+func (fr *frameReader) parseFrame(data []byte) (envelope, error) {
+	if len(data) == 0 {
+		return envelope{}, errors.New("network: empty frame")
+	}
+	d := decoder{data: data[1:]}
+	var frame envelope
+	switch data[0] {
+	case wireInput:
+		input := d.input()
+		frame = envelope{Type: MessageInput, Input: &input, Ack: uint32(d.uvarint())}
+	case wireSnapshot:
+		frame = envelope{Type: MessageSnapshot}
+		snapshot, seq, ok := fr.deltas.snapshot(&d)
+		if ok {
+			frame.Snapshot = &snapshot
+		}
+		frame.Seq = seq
+	case wireEvent:
+		event := Event{Type: EventType(d.string())}
+		if payload := d.bytes(); len(payload) > 0 {
+			event.Payload = append([]byte(nil), payload...)
+		}
+		frame = envelope{Type: MessageEvent, Event: &event}
+	case wireClock:
+		clock := clockMessage{Reply: d.byte1() != 0, Time: d.varint64(), Tick: uint32(d.uvarint()), SinceTick: d.varint64()}
+		frame = envelope{Type: MessageClock, Clock: &clock}
+	case wireHello:
+		hello := Hello{Name: truncateName(d.string()), Color: int(d.uvarint()), Team: int(d.uvarint()), Level: d.string(), Password: d.string()}
+		frame = envelope{Type: MessageHello, Hello: &hello}
+	case wireHeartbeat:
+		frame = envelope{Type: MessageHeartbeat}
+	case wireDisconnect:
+		frame = envelope{Type: MessageDisconnect, Reason: disconnectReason(d.byte1())}
+	default:
+		return envelope{}, fmt.Errorf("network: unknown frame type %d", data[0])
+	}
+	if d.err != nil {
+		return envelope{}, d.err
+	}
+	return frame, nil
+}
+
+// appendInput кодирует ввод битовой маской
+func appendInput(buf []byte, input InputMessage) []byte {
+	var flags uint16
+	if input.Left {
+		flags |= inputLeft
+	}
+	if input.Right {
+		flags |= inputRight
+	}
+	if input.Jump {
+		flags |= inputJump
+	}
+	if input.Crouch {
+		flags |= inputCrouch
+	}
+	if input.Sprint {
+		flags |= inputSprint
+	}
+	if input.Dash {
+		flags |= inputDash
+	}
+	if input.Shoot {
+		flags |= inputShoot
+	}
+	if input.Interact {
+		flags |= inputInteract
+	}
+	if input.SwitchWeapon {
+		flags |= inputSwitchWeapon
+	}
+	buf = binary.LittleEndian.AppendUint16(buf, flags)
+	buf = binary.AppendUvarint(buf, uint64(input.Seq))
+	return binary.AppendUvarint(buf, uint64(input.ViewTick))
+}
+
+// appendSnapshot кодирует снимок мира
+func appendSnapshot(buf []byte, s *SnapshotMessage) []byte {
+	buf = binary.AppendUvarint(buf, uint64(s.Tick))
+	buf = appendPlayer(buf, s.Host)
+	buf = appendPlayer(buf, s.Client)
+	buf = appendBullets(buf, s.HostBullets)
+	buf = appendBullets(buf, s.ClientBullets)
+	buf = appendNPCs(buf, s.NPCs)
+	buf = binary.AppendVarint(buf, int64(s.HostScore))
+	buf = binary.AppendVarint(buf, int64(s.ClientScore))
+	return binary.AppendUvarint(buf, uint64(s.InputSeq))
+}
+
+// appendPlayer кодирует состояние игрока
+func appendPlayer(buf []byte, p PlayerState) []byte {
+	buf = appendFloats(buf, p.X, p.Y, p.VelocityX, p.VelocityY)
+	buf = append(buf, playerFlags(p))
+	buf = binary.AppendVarint(buf, int64(p.Health))
+	return binary.AppendVarint(buf, int64(p.Weapon))
+}
+
+// playerFlags собирает флаги игрока в байт
+func playerFlags(p PlayerState) byte {
+	var flags byte
+	if p.OnGround {
+		flags |= playerOnGround
+	}
+	if p.FacingRight {
+		flags |= playerFacingRight
+	}
+	if p.Crouching {
+		flags |= playerCrouching
+	}
+	return flags
+}
+
+// setPlayerFlags переносит флаги из байта на игрока
+func setPlayerFlags(p *PlayerState, flags byte) {
+	p.OnGround = flags&playerOnGround != 0
+	p.FacingRight = flags&playerFacingRight != 0
+	p.Crouching = flags&playerCrouching != 0
+}
+
+// appendBullets кодирует список пуль
+func appendBullets(buf []byte, bullets []BulletState) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(bullets)))
+	for _, b := range bullets {
+		buf = appendBullet(buf, b)
+	}
+	return buf
+}
+
+// appendBullet кодирует пулю
+func appendBullet(buf []byte, b BulletState) []byte {
+	buf = binary.AppendUvarint(buf, b.ID)
+	buf = appendFloats(buf, b.X, b.Y, b.VelocityX, b.VelocityY)
+	return binary.AppendVarint(buf, int64(b.Pierce))
+}
+
+// appendNPCs кодирует список NPC
+func appendNPCs(buf []byte, npcs []NPCState) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(npcs)))
+	for _, npc := range npcs {
+		buf = appendNPC(buf, npc)
+	}
+	return buf
+}
+
+// appendNPC кодирует NPC
+func appendNPC(buf []byte, npc NPCState) []byte {
+	buf = binary.AppendUvarint(buf, npc.ID)
+	buf = appendFloats(buf, npc.X, npc.Y, npc.Width, npc.Height, npc.VelocityX, npc.VelocityY)
+	buf = appendBool(buf, npc.FacingRight)
+	return binary.AppendVarint(buf, int64(npc.Health))
+}
+
+// appendFloats кодирует числа как float32
+func appendFloats(buf []byte, values ...float64) []byte {
+	for _, v := range values {
+		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(float32(v)))
+	}
+	return buf
+}
+
+// appendBool кодирует флаг одним байтом
+func appendBool(buf []byte, v bool) []byte {
+	if v {
+		return append(buf, 1)
+	}
+	return append(buf, 0)
+}
+
+// appendBytes кодирует данные с длиной
+func appendBytes(buf []byte, data []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(data)))
+	return append(buf, data...)
+}
+
+// appendString кодирует строку с длиной
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// decoder читает значения из кадра. После первой ошибки все чтения возвращают
+// нули, а ошибка проверяется один раз в конце.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+// errShortFrame возвращается, если кадр закончился раньше данных
+var errShortFrame = errors.New("network: truncated frame")
+
+// fail запоминает первую ошибку декодирования
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+	d.data = nil
+}
+
+// take возвращает следующие n байт кадра
+func (d *decoder) take(n int) []byte {
+	if d.err != nil || n < 0 || len(d.data) < n {
+		d.fail(errShortFrame)
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+// uvarint читает беззнаковое число
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail(errShortFrame)
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// varint читает целое число со знаком
+func (d *decoder) varint() int {
+	return int(d.varint64())
+}
+
+// varint64 читает целое число со знаком, которое может не поместиться в int
+// на 32-битных платформах (например, время в микросекундах)
+func (d *decoder) varint64() int64 {
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail(errShortFrame)
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// count читает длину списка, элементы которого занимают не меньше minSize байт.
+// Длина больше оставшихся данных - ошибка, а не повод выделить огромный срез.
+func (d *decoder) count(minSize int) int {
+	n := d.uvarint()
+	if n > uint64(len(d.data)/minSize) {
+		d.fail(errShortFrame)
+		return 0
+	}
+	return int(n)
+}
+
+// float читает число float32
+func (d *decoder) float() float64 {
+	b := d.take(4)
+	if b == nil {
+		return 0
+	}
+	return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+}
+
+// byte1 читает один байт
+func (d *decoder) byte1() byte {
+	b := d.take(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+// bytes читает данные с длиной; результат ссылается на буфер кадра
+func (d *decoder) bytes() []byte {
+	return d.take(d.count(1))
+}
+
+// string читает строку с длиной
+func (d *decoder) string() string {
+	return string(d.bytes())
+}
+
+// input читает ввод
+func (d *decoder) input() InputMessage {
+	b := d.take(2)
+	if b == nil {
+		return InputMessage{}
+	}
+	flags := binary.LittleEndian.Uint16(b)
+	return InputMessage{
+		Seq:          uint32(d.uvarint()),
+		ViewTick:     uint32(d.uvarint()),
+		Left:         flags&inputLeft != 0,
+		Right:        flags&inputRight != 0,
+		Jump:         flags&inputJump != 0,
+		Crouch:       flags&inputCrouch != 0,
+		Sprint:       flags&inputSprint != 0,
+		Dash:         flags&inputDash != 0,
+		Shoot:        flags&inputShoot != 0,
+		Interact:     flags&inputInteract != 0,
+		SwitchWeapon: flags&inputSwitchWeapon != 0,
+	}
+}
+
+// snapshot читает снимок мира
+func (d *decoder) snapshot() SnapshotMessage {
+	return SnapshotMessage{
+		Tick:          uint32(d.uvarint()),
+		Host:          d.player(),
+		Client:        d.player(),
+		HostBullets:   d.bullets(),
+		ClientBullets: d.bullets(),
+		NPCs:          d.npcs(),
+		HostScore:     d.varint(),
+		ClientScore:   d.varint(),
+		InputSeq:      uint32(d.uvarint()),
+	}
+}
+
+// player читает состояние игрока
+func (d *decoder) player() PlayerState {
+	p := PlayerState{X: d.float(), Y: d.float(), VelocityX: d.float(), VelocityY: d.float()}
+	setPlayerFlags(&p, d.byte1())
+	p.Health = d.varint()
+	p.Weapon = d.varint()
+	return p
+}
+
+// Наименьший размер закодированных пули (ID, четыре float32, пробивание)
+// и NPC (ID, шесть float32, флаг, здоровье)
+const (
+	minBulletSize = 18
+	minNPCSize    = 27
+)
+
+// bullets читает список пуль
+func (d *decoder) bullets() []BulletState {
+	bullets := make([]BulletState, d.count(minBulletSize))
+	for i := range bullets {
+		bullets[i] = d.bullet()
+	}
+	return bullets
+}
+
+// bullet читает пулю
+func (d *decoder) bullet() BulletState {
+	return BulletState{
+		ID:        d.uvarint(),
+		X:         d.float(),
+		Y:         d.float(),
+		VelocityX: d.float(),
+		VelocityY: d.float(),
+		Pierce:    d.varint(),
+	}
+}
+
+// npcs читает список NPC
+func (d *decoder) npcs() []NPCState {
+	npcs := make([]NPCState, d.count(minNPCSize))
+	for i := range npcs {
+		npcs[i] = d.npc()
+	}
+	return npcs
+}
+
+// npc читает NPC
+func (d *decoder) npc() NPCState {
+	return NPCState{
+		ID:          d.uvarint(),
+		X:           d.float(),
+		Y:           d.float(),
+		Width:       d.float(),
+		Height:      d.float(),
+		VelocityX:   d.float(),
+		VelocityY:   d.float(),
+		FacingRight: d.byte1() != 0,
+		Health:      d.varint(),
+	}
+}
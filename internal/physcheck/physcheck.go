@@ -0,0 +1,359 @@
+package physcheck
+
+import (
+	"fmt"
+	"math"
+
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/game"
+	"platformer/internal/level"
+)
+
+// Проверки физики персонажа на маленьких уровнях, собранных в коде: каждая
+// ставит персонажа, прогоняет сценарий ввода в game.Simulation и сверяет,
+// где он оказался. Набор ловит возвращение старых ошибок столкновений:
+// застревание на стыке плиток и углах, проскакивание тонких стен
+// на большой скорости, расхождение повторов. Набор прогоняет go test
+// (physcheck_test.go), а без исходников - флаг -physics-check. Пакет game
+// не зависит от ebiten (окно подключает пакет window), поэтому проверки
+// собираются и идут на любой машине с Go, без графики и звука.
+
+// floorY — верх пола проверочных уровней: выше нижней границы уровня
+// (config.WorldHeight), за которой персонаж возвращается на контрольную точку
+const floorY = 700
+
+// Case — одна проверка физики
+type Case struct {
+	Name   string
+	Level  func() *level.Level            // Уровень проверки (новый на каждый запуск)
+	Setup  func(p *entities.Player)       // Подготовка персонажа перед сценарием (nil - как появился)
+	Script []game.ScriptStep              // Ввод по шагам
+	Check  func(s *game.Simulation) error // Проверка состояния после сценария
+}
+
+// Result — итог проверки: Err пустая, если проверка прошла
+type Result struct {
+	Name string
+	Err  error
+}
+
+// Run выполняет проверки по очереди
+func Run(cases []Case) []Result {
+	results := make([]Result, 0, len(cases))
+	for _, c := range cases {
+		results = append(results, Result{Name: c.Name, Err: c.run()})
+	}
+	return results
+}
+
+// run выполняет проверку на новой симуляции
+func (c Case) run() error {
+	s, err := simulate(c.Level(), c.Setup, c.Script)
+	if err != nil {
+		return err
+	}
+	return c.Check(s)
+}
+
+// simulate запускает симуляцию уровня lvl и прогоняет сценарий
+func simulate(lvl *level.Level, setup func(*entities.Player), script []game.ScriptStep) (*game.Simulation, error) {
+	s, err := game.NewSimulation(lvl, 1)
+	if err != nil {
+		return nil, err
+	}
+	if setup != nil {
+		setup(s.Player())
+	}
+	if err := s.Run(script); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Cases — набор проверок столкновений
+var Cases = []Case{
+	{
+		// Пол из плиток встык: на стыке персонаж не должен упираться в край следующей
+		Name:   "walk across tile seams",
+		Level:  func() *level.Level { return tiledFloor(40, 32) },
+		Script: []game.ScriptStep{{Ticks: 120, Input: hold(func(in *game.Input) { in.Right = true })}},
+		Check: func(s *game.Simulation) error {
+			return all(reachedX(s, 100+120*config.MoveSpeed*0.8), onGround(s))
+		},
+	},
+	{
+		// Бег по стыкам: скорость выше, и шаг чаще попадает близко к краю плитки
+		Name:   "sprint across tile seams",
+		Level:  func() *level.Level { return tiledFloor(40, 32) },
+		Script: []game.ScriptStep{{Ticks: 120, Input: hold(func(in *game.Input) { in.Right, in.Sprint = true, true })}},
+		Check: func(s *game.Simulation) error {
+			return all(reachedX(s, 100+120*config.MoveSpeed), onGround(s))
+		},
+	},
+	{
+		// Падение на угол платформы с маленьким перекрытием: персонаж встает
+		// на платформу, а не выталкивается в сторону
+		Name: "land on a platform corner",
+		Level: func() *level.Level {
+			return withPlatforms(level.Rect{X: 400, Y: 500, Width: 200, Height: 20})
+		},
+		Setup:  place(400-config.PlayerWidth+4, 380),
+		Script: []game.ScriptStep{{Ticks: 60, Input: game.NoInput}},
+		Check: func(s *game.Simulation) error {
+			return all(standsAt(s, 500), onGround(s))
+		},
+	},
+	{
+		// Прыжок вдоль стены: касание боковой грани не должно тормозить подъем
+		Name: "jump along a wall",
+		Level: func() *level.Level {
+			return withPlatforms(level.Rect{X: 300, Y: 300, Width: 40, Height: floorY - 300})
+		},
+		Setup: place(300-config.PlayerWidth, floorY-config.PlayerHeight),
+		Script: []game.ScriptStep{
+			{Ticks: 1, Input: game.NoInput},
+			{Ticks: 12, Input: hold(func(in *game.Input) { in.Jump, in.Right = true, true })},
+		},
+		Check: func(s *game.Simulation) error {
+			p := s.Player()
+			if p.Y > floorY-config.PlayerHeight-40 {
+				return fmt.Errorf("jumped only to y=%.1f along the wall", p.Y)
+			}
+			if p.Right() > 300+0.01 {
+				return fmt.Errorf("entered the wall: right side at x=%.1f", p.Right())
+			}
+			return nil
+		},
+	},
+	{
+		// Удар головой о потолок: подъем останавливается, а персонаж остается под потолком
+		Name: "bump the ceiling",
+		Level: func() *level.Level {
+			return withPlatforms(level.Rect{X: 0, Y: floorY - config.PlayerHeight - 60, Width: 400, Height: 10})
+		},
+		Setup: place(100, floorY-config.PlayerHeight),
+		Script: []game.ScriptStep{
+			{Ticks: 1, Input: game.NoInput},
+			{Ticks: 20, Input: hold(func(in *game.Input) { in.Jump = true })},
+			{Ticks: 60, Input: game.NoInput},
+		},
+		Check: func(s *game.Simulation) error {
+			return all(standsAt(s, floorY), onGround(s))
+		},
+	},
+	{
+		// Полет вбок быстрее половины ширины персонажа за шаг: тонкая стена останавливает его
+		Name: "fast horizontal hit on a thin wall",
+		Level: func() *level.Level {
+			return withPlatforms(level.Rect{X: 600, Y: 400, Width: 6, Height: floorY - 400})
+		},
+		Setup: func(p *entities.Player) {
+			place(400, floorY-config.PlayerHeight)(p)
+			p.VelocityX = 60
+		},
+		Script: []game.ScriptStep{{Ticks: 30, Input: game.NoInput}},
+		Check: func(s *game.Simulation) error {
+			if p := s.Player(); p.Right() > 600+0.01 {
+				return fmt.Errorf("tunneled through the wall: right side at x=%.1f", p.Right())
+			}
+			return nil
+		},
+	},
+	{
+		// Быстрое падение на тонкую платформу: персонаж приземляется, а не пролетает ее
+		Name: "fast fall onto a thin platform",
+		Level: func() *level.Level {
+			return withPlatforms(level.Rect{X: 200, Y: 400, Width: 300, Height: 4})
+		},
+		Setup: func(p *entities.Player) {
+			place(300, 0)(p)
+			p.VelocityY = config.MaxFallSpeed
+		},
+		Script: []game.ScriptStep{{Ticks: 90, Input: game.NoInput}},
+		Check: func(s *game.Simulation) error {
+			return all(standsAt(s, 400), onGround(s))
+		},
+	},
+	{
+		// Рывок в тонкую стену: скорость рывка велика, но стена его останавливает
+		Name: "dash into a thin wall",
+		Level: func() *level.Level {
+			return withPlatforms(level.Rect{X: 500, Y: 400, Width: 6, Height: floorY - 400})
+		},
+		Setup: place(380, floorY-config.PlayerHeight),
+		Script: []game.ScriptStep{
+			{Ticks: 1, Input: game.NoInput},
+			{Ticks: 30, Input: hold(func(in *game.Input) { in.Right, in.Dash, in.Sprint = true, true, true })},
+		},
+		Check: func(s *game.Simulation) error {
+			if p := s.Player(); p.Right() > 500+0.01 {
+				return fmt.Errorf("dashed through the wall: right side at x=%.1f", p.Right())
+			}
+			return nil
+		},
+	},
+	{
+		// Рывок влево в край уровня: после рывка персонаж не отъезжает от края сам
+		Name:  "dash into the level edge",
+		Level: flatLevel,
+		Setup: place(40, floorY-config.PlayerHeight),
+		Script: []game.ScriptStep{
+			{Ticks: 1, Input: hold(func(in *game.Input) { in.Left = true })},
+			{Ticks: 1, Input: hold(func(in *game.Input) { in.Dash = true })},
+			{Ticks: 60, Input: game.NoInput},
+		},
+		Check: func(s *game.Simulation) error {
+			if p := s.Player(); p.X > 0.01 {
+				return fmt.Errorf("slid away from the edge to x=%.1f", p.X)
+			}
+			return nil
+		},
+	},
+	{
+		// Край платформы: персонаж, стоящий на ней парой пикселей, не соскальзывает
+		Name: "stand on a ledge edge",
+		Level: func() *level.Level {
+			return withPlatforms(level.Rect{X: 400, Y: 500, Width: 200, Height: 20})
+		},
+		Setup:  place(600-2, 500-config.PlayerHeight),
+		Script: []game.ScriptStep{{Ticks: 60, Input: game.NoInput}},
+		Check: func(s *game.Simulation) error {
+			return all(standsAt(s, 500), onGround(s))
+		},
+	},
+	{
+		// Зона гибели в пропасти возвращает персонажа на точку появления,
+		// хотя до нижней границы уровня он не долетел
+		Name: "fall into a kill zone",
+		Level: func() *level.Level {
+			lvl := flatLevel()
+			lvl.Height = 3 * config.WorldHeight // Нижняя граница далеко: персонажа возвращает только зона
+			lvl.Platforms = []level.Platform{
+				{Rect: level.Rect{X: 0, Y: floorY, Width: 600, Height: 40}},
+				{Rect: level.Rect{X: 800, Y: floorY, Width: 1200, Height: 40}},
+			}
+			lvl.Triggers = []level.Trigger{{Kind: level.TriggerKill, Bounds: level.Rect{X: 600, Y: floorY + 40, Width: 200, Height: 40}}}
+			return lvl
+		},
+		Script: []game.ScriptStep{{Ticks: 130, Input: hold(func(in *game.Input) { in.Right = true })}},
+		Check: func(s *game.Simulation) error {
+			if p := s.Player(); p.X >= 600 {
+				return fmt.Errorf("not respawned: at (%.1f, %.1f)", p.X, p.Y)
+			}
+			return nil
+		},
+	},
+	{
+		// Одинаковые уровень, зерно и ввод дают одинаковое состояние
+		Name: "replay is deterministic",
+		Level: func() *level.Level {
+			return withPlatforms(level.Rect{X: 500, Y: 560, Width: 200, Height: 20})
+		},
+		Script: replayScript,
+		Check: func(s *game.Simulation) error {
+			again, err := simulate(withPlatforms(level.Rect{X: 500, Y: 560, Width: 200, Height: 20}), nil, replayScript)
+			if err != nil {
+				return err
+			}
+			if a, b := s.StateHash(), again.StateHash(); a != b {
+				return fmt.Errorf("state hashes differ: %016x and %016x", a, b)
+			}
+			return nil
+		},
+	},
+}
+
+// replayScript — сценарий с ходьбой, прыжками, рывком и стрельбой для проверки повторов
+var replayScript = []game.ScriptStep{
+	{Ticks: 30, Input: hold(func(in *game.Input) { in.Right = true })},
+	{Ticks: 15, Input: hold(func(in *game.Input) { in.Right, in.Jump = true, true })},
+	{Ticks: 20, Input: hold(func(in *game.Input) { in.Shoot = true })},
+	{Ticks: 10, Input: hold(func(in *game.Input) { in.Left, in.Dash = true, true })},
+	{Ticks: 60, Input: game.NoInput},
+}
+
+// hold возвращает ввод без нажатий, измененный fn
+func hold(fn func(in *game.Input)) game.Input {
+	in := game.NoInput
+	fn(&in)
+	return in
+}
+
+// place ставит персонажа левым верхним углом в (x, y) без скорости
+func place(x, y float64) func(p *entities.Player) {
+	return func(p *entities.Player) {
+		p.X, p.Y = x, y
+		p.VelocityX, p.VelocityY = 0, 0
+		p.OnGround = false
+	}
+}
+
+// withPlatforms возвращает уровень со сплошным полом и платформами extra
+func withPlatforms(extra ...level.Rect) *level.Level {
+	lvl := flatLevel()
+	for _, rect := range extra {
+		lvl.Platforms = append(lvl.Platforms, level.Platform{Rect: rect})
+	}
+	return lvl
+}
+
+// tiledFloor возвращает уровень, пол которого собран из count плиток шириной width встык
+func tiledFloor(count int, width float64) *level.Level {
+	lvl := flatLevel()
+	lvl.Platforms = lvl.Platforms[:0]
+	for i := 0; i < count; i++ {
+		lvl.Platforms = append(lvl.Platforms, level.Platform{Rect: level.Rect{X: float64(i) * width, Y: floorY, Width: width, Height: 40}})
+	}
+	// Дальше плиток персонаж идет по сплошному полу
+	end := float64(count) * width
+	lvl.Platforms = append(lvl.Platforms, level.Platform{Rect: level.Rect{X: end, Y: floorY, Width: lvl.Width - end, Height: 40}})
+	return lvl
+}
+
+// flatLevel возвращает пустой уровень со сплошным полом, персонаж появляется на полу слева
+func flatLevel() *level.Level {
+	return &level.Level{
+		Name:   "physics check",
+		Width:  2000,
+		Height: config.WorldHeight,
+		Spawn:  level.Point{X: 100, Y: floorY - config.PlayerHeight},
+		Platforms: []level.Platform{
+			{Rect: level.Rect{X: 0, Y: floorY, Width: 2000, Height: 40}},
+		},
+	}
+}
+
+// all возвращает первую ошибку проверок
+func all(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reachedX проверяет, что левый край персонажа дошел до x
+func reachedX(s *game.Simulation, x float64) error {
+	if p := s.Player(); p.X < x {
+		return fmt.Errorf("stopped at x=%.1f, expected at least %.1f", p.X, x)
+	}
+	return nil
+}
+
+// standsAt проверяет, что ноги персонажа на высоте y
+func standsAt(s *game.Simulation, y float64) error {
+	if p := s.Player(); math.Abs(p.Bottom()-y) > 0.01 {
+		return fmt.Errorf("feet at y=%.2f, expected y=%.0f", p.Bottom(), y)
+	}
+	return nil
+}
+
+// onGround проверяет, что персонаж стоит на платформе
+func onGround(s *game.Simulation) error {
+	if !s.Player().OnGround {
+		return fmt.Errorf("not on ground at (%.1f, %.1f)", s.Player().X, s.Player().Y)
+	}
+	return nil
+}
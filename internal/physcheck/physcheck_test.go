@@ -0,0 +1,15 @@
+package physcheck
+
+import "testing"
+
+// TestPhysics прогоняет набор проверок столкновений (Cases) на новых симуляциях
+func TestPhysics(t *testing.T) {
+	for _, c := range Cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			if err := c.run(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
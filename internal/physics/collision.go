@@ -2,27 +2,32 @@ package physics
 
 import "platformer/internal/entities"
 
-// IsColliding проверяет, пересекается ли персонаж с платформой
-// Используется алгоритм AABB (Axis-Aligned Bounding Box) для проверки коллизий
-func IsColliding(player *entities.Player, platform *entities.Platform, playerWidth, playerHeight float64) bool {
-	// Проверяем, не пересекаются ли прямоугольники
-	// Два прямоугольника пересекаются, если:
-	// - левая сторона одного не правее правой стороны другого
-	// - правая сторона одного не левее левой стороны другого
-	// - верхняя сторона одного не ниже нижней стороны другого
-	// - нижняя сторона одного не выше верхней стороны другого
+// Overlaps проверяет, пересекаются ли хитбоксы двух сущностей.
+// Используется алгоритм AABB (Axis-Aligned Bounding Box): два прямоугольника
+// пересекаются, если они перекрываются и по горизонтали, и по вертикали.
+// Размеры берутся из текущего хитбокса (у присевшего персонажа он меньше).
+func Overlaps(a, b entities.Collider) bool {
+	return a.Bounds().Intersects(b.Bounds())
+}
+
+// Penetration возвращает глубину взаимного проникновения хитбоксов по осям
+// и направление от b к a (знак dx и dy). Нужна для выталкивания сущности из препятствия
+// по оси наименьшего перекрытия.
+func Penetration(a, b entities.Collider) (overlapX, overlapY, dx, dy float64) {
+	boxA, boxB := a.Bounds(), b.Bounds()
+	centerAX, centerAY := boxA.Center()
+	centerBX, centerBY := boxB.Center()
 
-	return player.X < platform.X+platform.Width &&
-		player.X+playerWidth > platform.X &&
-		player.Y < platform.Y+platform.Height &&
-		player.Y+playerHeight > platform.Y
+	dx = centerAX - centerBX
+	dy = centerAY - centerBY
+	overlapX = (boxA.Width+boxB.Width)/2 - abs(dx)
+	overlapY = (boxA.Height+boxB.Height)/2 - abs(dy)
+	return overlapX, overlapY, dx, dy
 }
 
-// IsBulletColliding проверяет, пересекается ли пуля с платформой
-func IsBulletColliding(bullet *entities.Bullet, platform *entities.Platform) bool {
-	// Используем тот же алгоритм AABB, что и для персонажа
-	return bullet.X < platform.X+platform.Width &&
-		bullet.X+bullet.Width > platform.X &&
-		bullet.Y < platform.Y+platform.Height &&
-		bullet.Y+bullet.Height > platform.Y
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
 }
@@ -0,0 +1,187 @@
+package physics
+
+import (
+	"math"
+	"sort"
+
+	"platformer/internal/entities"
+)
+
+// DefaultCellSize — размер ячейки сетки по умолчанию (порядка размера персонажа и платформ)
+const DefaultCellSize = 128
+
+// cell — координаты ячейки сетки
+type cell struct {
+	X, Y int
+}
+
+// gridItem — платформа в сетке вместе с порядком добавления
+type gridItem struct {
+	platform *entities.Platform
+	order    int    // Порядок добавления: результаты запроса возвращаются в этом порядке
+	stamp    uint64 // Номер последнего запроса, вернувшего платформу (для удаления повторов)
+}
+
+// Grid — равномерная сетка для быстрого поиска платформ рядом с объектом.
+// Платформа заносится во все ячейки, которые она покрывает, а запрос проверяет
+// только ячейки вокруг объекта, поэтому стоимость проверки столкновений не растет
+// с числом платформ на уровне.
+type Grid struct {
+	cellSize float64
+	cells    map[cell][]*gridItem
+	items    map[*entities.Platform]*gridItem
+	nextID   int
+	stamp    uint64
+}
+
+// NewGrid создает пустую сетку с ячейками размера cellSize (0 - размер по умолчанию)
+func NewGrid(cellSize float64) *Grid {
+	if cellSize <= 0 {
+		cellSize = DefaultCellSize
+	}
+	return &Grid{
+		cellSize: cellSize,
+		cells:    make(map[cell][]*gridItem),
+		items:    make(map[*entities.Platform]*gridItem),
+	}
+}
+
+// Rebuild заново заполняет сетку списком платформ
+func (g *Grid) Rebuild(platforms []*entities.Platform) {
+	g.cells = make(map[cell][]*gridItem)
+	g.items = make(map[*entities.Platform]*gridItem, len(platforms))
+	g.nextID = 0
+	for _, platform := range platforms {
+		g.Insert(platform)
+	}
+}
+
+// Insert добавляет платформу в сетку
+func (g *Grid) Insert(platform *entities.Platform) {
+	if _, ok := g.items[platform]; ok {
+		return
+	}
+
+	item := &gridItem{platform: platform, order: g.nextID}
+	g.nextID++
+	g.items[platform] = item
+	g.addCells(item)
+}
+
+// InsertAt добавляет платформу с заданным порядком в результатах запросов.
+// Так платформа, убранная из сетки и возвращенная позже (например,
+// при подгрузке частей уровня), встает на прежнее место среди остальных.
+func (g *Grid) InsertAt(platform *entities.Platform, order int) {
+	if _, ok := g.items[platform]; ok {
+		return
+	}
+
+	item := &gridItem{platform: platform, order: order}
+	g.nextID = max(g.nextID, order+1)
+	g.items[platform] = item
+	g.addCells(item)
+}
+
+// Remove удаляет платформу из сетки
+func (g *Grid) Remove(platform *entities.Platform) {
+	item, ok := g.items[platform]
+	if !ok {
+		return
+	}
+	delete(g.items, platform)
+	g.removeCells(item)
+}
+
+// Move переносит платформу в точку (x, y) и обновляет ее ячейки.
+// Порядок платформы в результатах запросов не меняется.
+// Платформа, которой нет в сетке, просто перемещается.
+func (g *Grid) Move(platform *entities.Platform, x, y float64) {
+	item, ok := g.items[platform]
+	if !ok {
+		platform.X, platform.Y = x, y
+		return
+	}
+
+	g.removeCells(item)
+	platform.X, platform.Y = x, y
+	g.addCells(item)
+}
+
+// addCells заносит платформу во все ячейки, которые она покрывает
+func (g *Grid) addCells(item *gridItem) {
+	platform := item.platform
+	g.forEachCell(platform.X, platform.Y, platform.Width, platform.Height, func(c cell) {
+		g.cells[c] = append(g.cells[c], item)
+	})
+}
+
+// removeCells удаляет платформу из ячеек, которые она покрывает
+func (g *Grid) removeCells(item *gridItem) {
+	platform := item.platform
+	g.forEachCell(platform.X, platform.Y, platform.Width, platform.Height, func(c cell) {
+		items := g.cells[c]
+		for i, other := range items {
+			if other == item {
+				items = append(items[:i], items[i+1:]...)
+				break
+			}
+		}
+		if len(items) == 0 {
+			delete(g.cells, c)
+		} else {
+			g.cells[c] = items
+		}
+	})
+}
+
+// Len возвращает количество платформ в сетке
+func (g *Grid) Len() int {
+	return len(g.items)
+}
+
+// Query возвращает платформы из ячеек, которые пересекает прямоугольник (x, y, width, height).
+// Это кандидаты на столкновение: точную проверку нужно выполнить отдельно.
+// Результат дописывается в out и идет в порядке добавления платформ,
+// чтобы столкновения разрешались так же, как при полном переборе.
+func (g *Grid) Query(x, y, width, height float64, out []*entities.Platform) []*entities.Platform {
+	g.stamp++
+
+	var found []*gridItem
+	g.forEachCell(x, y, width, height, func(c cell) {
+		for _, item := range g.cells[c] {
+			if item.stamp == g.stamp {
+				continue
+			}
+			item.stamp = g.stamp
+			found = append(found, item)
+		}
+	})
+
+	sort.Slice(found, func(i, j int) bool { return found[i].order < found[j].order })
+	for _, item := range found {
+		out = append(out, item.platform)
+	}
+	return out
+}
+
+// QuerySwept возвращает кандидатов на столкновение для прямоугольника,
+// который за кадр сдвигается на (dx, dy): запрашивается вся пройденная область
+func (g *Grid) QuerySwept(x, y, width, height, dx, dy float64, out []*entities.Platform) []*entities.Platform {
+	minX := math.Min(x, x+dx)
+	minY := math.Min(y, y+dy)
+	return g.Query(minX, minY, width+math.Abs(dx), height+math.Abs(dy), out)
+}
+
+// forEachCell вызывает fn для каждой ячейки, которую покрывает прямоугольник
+func (g *Grid) forEachCell(x, y, width, height float64, fn func(cell)) {
+	minX := int(math.Floor(x / g.cellSize))
+	minY := int(math.Floor(y / g.cellSize))
+	maxX := int(math.Floor((x + width) / g.cellSize))
+	maxY := int(math.Floor((y + height) / g.cellSize))
+
+	for cy := minY; cy <= maxY; cy++ {
+		for cx := minX; cx <= maxX; cx++ {
+			fn(cell{X: cx, Y: cy})
+		}
+	}
+}
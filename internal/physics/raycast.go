@@ -0,0 +1,146 @@
+package physics
+
+import (
+	"math"
+
+	"platformer/internal/entities"
+)
+
+// Vec — точка или направление на плоскости
+type Vec struct {
+	X, Y float64
+}
+
+// Length возвращает длину вектора
+func (v Vec) Length() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Normalized возвращает вектор единичной длины того же направления (нулевой вектор не меняется)
+func (v Vec) Normalized() Vec {
+	length := v.Length()
+	if length == 0 {
+		return v
+	}
+	return Vec{X: v.X / length, Y: v.Y / length}
+}
+
+// Layer — слой столкновений. Слои объединяются в маску, чтобы луч
+// проверял только нужные объекты (например, только платформы для проверки земли).
+type Layer uint32
+
+const (
+	LayerPlatform Layer = 1 << iota // Платформы уровня
+	LayerPlayer                     // Игроки
+	LayerNPC                        // NPC
+	LayerBullet                     // Пули
+
+	LayerAll Layer = ^Layer(0) // Все слои
+)
+
+// Body — объект, который может задеть луч: хитбокс и слой
+type Body struct {
+	Collider entities.Collider
+	Layer    Layer
+}
+
+// PlatformBodies оборачивает платформы в объекты слоя LayerPlatform
+func PlatformBodies(platforms []*entities.Platform, out []Body) []Body {
+	for _, platform := range platforms {
+		out = append(out, Body{Collider: platform, Layer: LayerPlatform})
+	}
+	return out
+}
+
+// RayHit описывает первое попадание луча
+type RayHit struct {
+	Body     Body    // Задетый объект
+	Point    Vec     // Точка попадания
+	Distance float64 // Расстояние от начала луча до точки попадания
+	Normal   Vec     // Нормаль задетой стороны
+}
+
+// Raycast выпускает луч из origin в направлении direction на расстояние maxDist
+// и возвращает первый задетый объект из bodies, слой которого входит в mask.
+// Используется для оружия мгновенного действия, проверки прямой видимости
+// и поиска земли под персонажем. Если начало луча внутри объекта, попадание
+// считается на расстоянии 0.
+func Raycast(origin, direction Vec, maxDist float64, mask Layer, bodies []Body) (RayHit, bool) {
+	dir := direction.Normalized()
+	if dir.X == 0 && dir.Y == 0 || maxDist <= 0 {
+		return RayHit{}, false
+	}
+
+	best := RayHit{Distance: maxDist}
+	found := false
+	for _, body := range bodies {
+		if body.Layer&mask == 0 {
+			continue
+		}
+		distance, normal, ok := RayAABB(origin, dir, body.Collider.Bounds())
+		if !ok || distance > best.Distance || found && distance == best.Distance {
+			continue
+		}
+		best = RayHit{
+			Body:     body,
+			Point:    Vec{X: origin.X + dir.X*distance, Y: origin.Y + dir.Y*distance},
+			Distance: distance,
+			Normal:   normal,
+		}
+		found = true
+	}
+	if !found {
+		return RayHit{}, false
+	}
+	return best, true
+}
+
+// RayAABB находит пересечение луча (origin, dir) с прямоугольником методом плит:
+// луч входит в прямоугольник, когда оказывается между плитами сторон по обеим осям.
+// dir должен быть единичным; возвращается расстояние до точки входа и нормаль стороны.
+func RayAABB(origin, dir Vec, box entities.AABB) (float64, Vec, bool) {
+	entryX, exitX := rayAxis(origin.X, dir.X, box.X, box.X+box.Width)
+	entryY, exitY := rayAxis(origin.Y, dir.Y, box.Y, box.Y+box.Height)
+
+	entry := math.Max(entryX, entryY)
+	exit := math.Min(exitX, exitY)
+	if entry > exit || exit < 0 {
+		return 0, Vec{}, false
+	}
+
+	// Начало луча внутри прямоугольника
+	if entry < 0 {
+		return 0, Vec{}, true
+	}
+
+	if entryX > entryY {
+		return entry, Vec{X: -sign(dir.X)}, true
+	}
+	return entry, Vec{Y: -sign(dir.Y)}, true
+}
+
+// rayAxis возвращает расстояния вдоль луча, на которых он входит в плиту [min, max] и выходит из нее
+func rayAxis(origin, dir, min, max float64) (float64, float64) {
+	if dir == 0 {
+		// Луч параллелен плите: он либо всегда внутри нее, либо никогда
+		if origin < min || origin > max {
+			return math.Inf(1), math.Inf(-1)
+		}
+		return math.Inf(-1), math.Inf(1)
+	}
+
+	near := (min - origin) / dir
+	far := (max - origin) / dir
+	if near > far {
+		near, far = far, near
+	}
+	return near, far
+}
+
+// QueryRay возвращает платформы из ячеек вокруг отрезка луча — кандидатов для Raycast
+func (g *Grid) QueryRay(origin, direction Vec, maxDist float64, out []*entities.Platform) []*entities.Platform {
+	dir := direction.Normalized()
+	end := Vec{X: origin.X + dir.X*maxDist, Y: origin.Y + dir.Y*maxDist}
+	return g.Query(math.Min(origin.X, end.X), math.Min(origin.Y, end.Y),
+		math.Abs(end.X-origin.X), math.Abs(end.Y-origin.Y), out)
+}
@@ -0,0 +1,88 @@
+package physics
+
+import (
+	"math"
+
+	"platformer/internal/entities"
+)
+
+// Contact описывает столкновение движущегося прямоугольника с препятствием
+type Contact struct {
+	Time    float64 // Доля пути за кадр (от 0 до 1), пройденная до касания
+	NormalX float64 // Нормаль поверхности, в которую произошел удар (-1, 0 или 1)
+	NormalY float64
+}
+
+// SweepAABB проверяет непрерывное столкновение прямоугольника box,
+// который за кадр сдвигается на (dx, dy), с неподвижным прямоугольником target.
+// В отличие от проверки пересечения в конечной точке, быстрый объект не может
+// проскочить тонкое препятствие насквозь. Прямоугольники, которые уже пересекаются
+// в начале кадра, не считаются столкновением — их разрешает обычная проверка AABB.
+func SweepAABB(box entities.AABB, dx, dy float64, target entities.AABB) (Contact, bool) {
+	entryX, exitX, ok := sweepAxis(box.X, box.Width, dx, target.X, target.Width)
+	if !ok {
+		return Contact{}, false
+	}
+	entryY, exitY, ok := sweepAxis(box.Y, box.Height, dy, target.Y, target.Height)
+	if !ok {
+		return Contact{}, false
+	}
+
+	// Столкновение начинается, когда перекрытие появилось по обеим осям,
+	// и заканчивается, когда оно пропало хотя бы по одной
+	entry := math.Max(entryX, entryY)
+	exit := math.Min(exitX, exitY)
+	if entry >= exit || entry < 0 || entry > 1 {
+		return Contact{}, false
+	}
+
+	contact := Contact{Time: entry}
+	if entryX > entryY {
+		contact.NormalX = -sign(dx)
+	} else {
+		contact.NormalY = -sign(dy)
+	}
+	return contact, true
+}
+
+// sweepAxis возвращает доли пути, на которых отрезок [pos, pos+size), сдвигаясь на delta,
+// начинает и перестает перекрывать отрезок [target, target+targetSize).
+// ok == false, если перекрытия по этой оси не будет никогда.
+func sweepAxis(pos, size, delta, target, targetSize float64) (entry, exit float64, ok bool) {
+	if delta == 0 {
+		// Без движения по оси перекрытие либо есть все время, либо его нет вовсе
+		if pos+size <= target || pos >= target+targetSize {
+			return 0, 0, false
+		}
+		return math.Inf(-1), math.Inf(1), true
+	}
+
+	if delta > 0 {
+		return (target - (pos + size)) / delta, (target + targetSize - pos) / delta, true
+	}
+	return (target + targetSize - pos) / delta, (target - (pos + size)) / delta, true
+}
+
+// Sweep проверяет, заденет ли сущность mover препятствие target при сдвиге на (dx, dy)
+func Sweep(mover entities.Collider, dx, dy float64, target entities.Collider) (Contact, bool) {
+	return SweepAABB(mover.Bounds(), dx, dy, target.Bounds())
+}
+
+// NeedsSweep сообщает, может ли сущность проскочить препятствие за один кадр
+// при скорости (dx, dy): это возможно, когда за кадр она сдвигается больше
+// чем на половину своего размера
+func NeedsSweep(mover entities.Collider, dx, dy float64) bool {
+	box := mover.Bounds()
+	return math.Abs(dx) > box.Width/2 || math.Abs(dy) > box.Height/2
+}
+
+func sign(v float64) float64 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
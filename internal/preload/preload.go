@@ -0,0 +1,116 @@
+package preload
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Task — одна задача загрузки (например, спрайты или уровень)
+type Task struct {
+	Name string       // Название для экрана загрузки
+	Load func() error // Функция загрузки; вызывается в фоновой горутине
+}
+
+// Loader выполняет задачи загрузки по очереди в фоне и сообщает о прогрессе,
+// чтобы игровой цикл мог рисовать экран загрузки, пока данные читаются с диска.
+type Loader struct {
+	tasks []Task
+
+	mu       sync.Mutex
+	started  bool
+	done     int    // Сколько задач выполнено
+	current  string // Название выполняемой задачи
+	finished bool
+	err      error
+	wait     chan struct{} // Закрывается по окончании загрузки
+}
+
+// New создает загрузчик с заданным списком задач
+func New(tasks ...Task) *Loader {
+	return &Loader{tasks: tasks}
+}
+
+// Add добавляет задачу. Задачи нельзя добавлять после запуска загрузки.
+func (l *Loader) Add(name string, load func() error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.started {
+		panic("preload: Add called after Start")
+	}
+	l.tasks = append(l.tasks, Task{Name: name, Load: load})
+}
+
+// Start запускает загрузку в фоновой горутине. Повторные вызовы ничего не делают.
+func (l *Loader) Start() {
+	l.mu.Lock()
+	if l.started {
+		l.mu.Unlock()
+		return
+	}
+	l.started = true
+	l.wait = make(chan struct{})
+	l.mu.Unlock()
+
+	go l.run()
+}
+
+func (l *Loader) run() {
+	defer close(l.wait)
+	for _, task := range l.tasks {
+		l.mu.Lock()
+		l.current = task.Name
+		l.mu.Unlock()
+
+		if err := task.Load(); err != nil {
+			l.mu.Lock()
+			l.err = fmt.Errorf("load %s: %w", task.Name, err)
+			l.finished = true
+			l.mu.Unlock()
+			return
+		}
+
+		l.mu.Lock()
+		l.done++
+		l.mu.Unlock()
+	}
+
+	l.mu.Lock()
+	l.current = ""
+	l.finished = true
+	l.mu.Unlock()
+}
+
+// Progress возвращает долю выполненных задач (от 0 до 1) и название текущей задачи
+func (l *Loader) Progress() (float64, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.tasks) == 0 {
+		return 1, ""
+	}
+	return float64(l.done) / float64(len(l.tasks)), l.current
+}
+
+// Done сообщает, закончена ли загрузка (успешно или с ошибкой)
+func (l *Loader) Done() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.finished
+}
+
+// Err возвращает ошибку загрузки, если она произошла
+func (l *Loader) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Wait блокируется до окончания загрузки; используется там, где нечего показывать
+// на экране загрузки (например, при запуске без окна). Загрузка должна быть запущена.
+func (l *Loader) Wait() {
+	l.mu.Lock()
+	wait := l.wait
+	l.mu.Unlock()
+	<-wait
+}
@@ -0,0 +1,171 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+)
+
+// Имена файлов в каталоге профиля
+const (
+	profileFileName = "profile.json"
+	saveFileName    = "save.json"
+)
+
+// MaxNameLength — наибольшая длина имени профиля в символах
+const MaxNameLength = 16
+
+// DefaultRoot возвращает каталог профилей по умолчанию
+// (например, ~/.config/platformer/profiles)
+func DefaultRoot() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("profile: %w", err)
+	}
+	return filepath.Join(dir, "platformer", "profiles"), nil
+}
+
+// Settings — настройки игры, которые у каждого профиля свои
+type Settings struct {
+	Renderer  string `json:"renderer,omitempty"`   // Имя бэкенда отрисовки (пустое - по умолчанию)
+	DebugPage string `json:"debug_page,omitempty"` // Страница отладочной информации (пустая - по умолчанию для сборки)
+	Skin      string `json:"skin,omitempty"`       // PNG-файл скина персонажа (пустой - встроенный спрайт)
+	Language  string `json:"language,omitempty"`   // Язык интерфейса (пустой - язык по умолчанию)
+
+	// Громкость от 0 до 1
+	MasterVolume  float64 `json:"master_volume"`
+	MusicVolume   float64 `json:"music_volume"`
+	EffectsVolume float64 `json:"effects_volume"`
+	Muted         bool    `json:"muted,omitempty"`
+}
+
+// DefaultSettings возвращает настройки нового профиля
+func DefaultSettings() Settings {
+	return Settings{
+		MasterVolume:  config.MasterVolume,
+		MusicVolume:   config.MusicVolume,
+		EffectsVolume: config.EffectsVolume,
+	}
+}
+
+// Stats — накопленная статистика профиля
+type Stats struct {
+	LevelsCompleted int `json:"levels_completed"`
+	BestScore       int `json:"best_score"`
+	Deaths          int `json:"deaths"`
+	ShotsFired      int `json:"shots_fired"`
+	PlayFrames      int `json:"play_frames"` // Сколько шагов симуляции длилась игра
+}
+
+// PlayTime возвращает общее время игры; step — длительность шага симуляции
+func (s Stats) PlayTime(step time.Duration) time.Duration {
+	return time.Duration(s.PlayFrames) * step
+}
+
+// Profile — слот игрока: имя, настройки, статистика и сохранение прохождения.
+// Каждый профиль хранится в своем каталоге slotN.
+type Profile struct {
+	Name     string   `json:"name"`
+	Settings Settings `json:"settings"`
+	Stats    Stats    `json:"stats"`
+
+	slot int
+	dir  string
+}
+
+// Load загружает профиль слота slot (нумерация с 1) из каталога root.
+// Если профиль еще не создан, возвращается новый профиль с именем по умолчанию.
+func Load(root string, slot int) (*Profile, error) {
+	// Поля, которых нет в файле (например, из старой версии), остаются по умолчанию
+	p := &Profile{
+		Name:     i18n.T("profile.default_name", slot),
+		Settings: DefaultSettings(),
+		slot:     slot,
+		dir:      filepath.Join(root, fmt.Sprintf("slot%d", slot)),
+	}
+
+	path := filepath.Join(p.dir, profileFileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return p, fmt.Errorf("profile: read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return p, fmt.Errorf("profile: parse %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// LoadAll загружает профили слотов от 1 до count. Профили, которые не удалось
+// прочитать, заменяются новыми; первая ошибка возвращается для журнала.
+func LoadAll(root string, count int) ([]*Profile, error) {
+	profiles := make([]*Profile, 0, count)
+	var firstErr error
+	for slot := 1; slot <= count; slot++ {
+		p, err := Load(root, slot)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, firstErr
+}
+
+// Slot возвращает номер слота профиля
+func (p *Profile) Slot() int {
+	return p.slot
+}
+
+// SavePath возвращает путь к сохранению прохождения этого профиля
+func (p *Profile) SavePath() string {
+	return filepath.Join(p.dir, saveFileName)
+}
+
+// HasSave сообщает, есть ли у профиля сохраненное прохождение
+func (p *Profile) HasSave() bool {
+	_, err := os.Stat(p.SavePath())
+	return err == nil
+}
+
+// Rename меняет имя профиля. Пустое имя не принимается, длинное обрезается.
+func (p *Profile) Rename(name string) bool {
+	runes := []rune(name)
+	if len(runes) == 0 {
+		return false
+	}
+	if len(runes) > MaxNameLength {
+		runes = runes[:MaxNameLength]
+	}
+	p.Name = string(runes)
+	return true
+}
+
+// Save записывает профиль в его каталог
+func (p *Profile) Save() error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("profile: encode: %w", err)
+	}
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+
+	path := filepath.Join(p.dir, profileFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("profile: write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("profile: save %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package rcon
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Удаленное управление нужно выделенному серверу без окна: администратор
+// подключается к хосту по TCP (хотя бы через nc или telnet), вводит пароль
+// и выполняет команды консоли игры. Команды не выполняются в потоке
+// соединения: игра забирает их через Poll в своем шаге и отвечает через Reply.
+//
+// Протокол строковый:
+//
+//	администратор -> хост: пароль первой строкой, затем по команде на строку
+//	хост -> администратор: "ok" после верного пароля или "error <причина>"
+//	                       и закрытие соединения; на каждую команду - ответ
+//	                       и пустая строка, которой ответ заканчивается
+//
+// Команды quit и exit закрывают соединение.
+
+const (
+	maxLineLength  = 1024             // Наибольшая длина строки команды
+	maxConnections = 4                // Сколько администраторов подключено одновременно
+	authTimeout    = 10 * time.Second // Сколько ждать пароль от подключившегося
+	idleTimeout    = 10 * time.Minute // Через сколько тишины соединение закрывается
+	failDelay      = time.Second      // Пауза перед отказом в неверном пароле, чтобы пароль не перебирали
+)
+
+// ErrNoPassword возвращается, если удаленное управление запускают без пароля
+var ErrNoPassword = errors.New("rcon: a password is required")
+
+// Request — команда администратора, которую должна выполнить игра
+type Request struct {
+	Line   string // Командная строка без перевода строки
+	Remote string // Адрес администратора
+	reply  chan string
+}
+
+// Reply отправляет администратору ответ на команду. Вызывается ровно один раз.
+func (r Request) Reply(text string) {
+	r.reply <- text
+}
+
+// Server — удаленное управление хостом
+type Server struct {
+	listener net.Listener
+	password string
+	requests chan Request
+	slots    chan struct{} // Свободные места для подключений
+	closed   chan struct{}
+	closeFn  sync.Once
+}
+
+// Listen начинает принимать администраторов на address. Ошибка занятого
+// адреса возвращается сразу, а подключения принимаются в фоне до Close.
+func Listen(address, password string) (*Server, error) {
+	if password == "" {
+		return nil, ErrNoPassword
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("rcon: %w", err)
+	}
+	s := &Server{
+		listener: listener,
+		password: password,
+		requests: make(chan Request, maxConnections),
+		slots:    make(chan struct{}, maxConnections),
+		closed:   make(chan struct{}),
+	}
+	go s.acceptLoop()
+	return s, nil
+}
+
+// Addr возвращает адрес, на котором сервер принимает администраторов
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Poll возвращает команды, пришедшие с прошлого вызова. Не блокируется.
+func (s *Server) Poll() []Request {
+	if s == nil {
+		return nil
+	}
+	var requests []Request
+	for {
+		select {
+		case r := <-s.requests:
+			requests = append(requests, r)
+		default:
+			return requests
+		}
+	}
+}
+
+// Close перестает принимать администраторов и отключает подключенных
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+	var err error
+	s.closeFn.Do(func() {
+		close(s.closed)
+		err = s.listener.Close()
+	})
+	return err
+}
+
+func (s *Server) acceptLoop() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.closed:
+			default:
+				log.Printf("rcon: %v", err)
+			}
+			return
+		}
+		select {
+		case s.slots <- struct{}{}:
+			go s.handle(conn)
+		default:
+			reject(conn, "too many connections")
+		}
+	}
+}
+
+// handle проверяет пароль администратора и передает игре его команды
+func (s *Server) handle(conn net.Conn) {
+	defer func() { <-s.slots }()
+	defer conn.Close()
+
+	// Соединение закрывается и вместе с сервером
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-s.closed:
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	remote := conn.RemoteAddr().String()
+	reader := bufio.NewReaderSize(conn, maxLineLength)
+	_ = conn.SetDeadline(time.Now().Add(authTimeout))
+	password, err := readLine(reader)
+	if err != nil {
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(password), []byte(s.password)) != 1 {
+		log.Printf("rcon: wrong password from %s", remote)
+		time.Sleep(failDelay)
+		reject(conn, "wrong password")
+		return
+	}
+	if _, err := io.WriteString(conn, "ok\n"); err != nil {
+		return
+	}
+	log.Printf("rcon: %s connected", remote)
+
+	for {
+		_ = conn.SetDeadline(time.Now().Add(idleTimeout))
+		line, err := readLine(reader)
+		if err != nil {
+			log.Printf("rcon: %s disconnected", remote)
+			return
+		}
+		line = strings.TrimSpace(line)
+		switch strings.ToLower(line) {
+		case "":
+			continue
+		case "quit", "exit":
+			return
+		}
+
+		request := Request{Line: line, Remote: remote, reply: make(chan string, 1)}
+		select {
+		case s.requests <- request:
+		case <-s.closed:
+			return
+		}
+		var text string
+		select {
+		case text = <-request.reply:
+		case <-s.closed:
+			return
+		}
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		if _, err := io.WriteString(conn, text+"\n"); err != nil {
+			return
+		}
+	}
+}
+
+// readLine читает строку без перевода строки (и без \r от telnet)
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return "", errors.New("rcon: line too long")
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// reject отправляет администратору причину отказа и закрывает соединение
+func reject(conn net.Conn, reason string) {
+	_, _ = io.WriteString(conn, "error "+reason+"\n")
+	_ = conn.Close()
+}
@@ -0,0 +1,253 @@
+package relay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Посредник нужен игрокам за NAT: хост и клиент не могут подключиться друг
+// к другу напрямую, но оба могут подключиться к посреднику. Хост регистрирует
+// комнату и ждет, клиент подключается к комнате по ее имени, после чего
+// посредник пересылает данные между ними в обе стороны, не разбирая их.
+//
+// Протокол - одна строка запроса и одна строка ответа:
+//
+//	игрок -> посредник: "platformer-relay host <комната>\n" или "platformer-relay join <комната>\n"
+//	посредник -> игрок: "ok\n", когда пара составлена, или "error <причина>\n"
+//
+// Хост получает ответ только после подключения клиента. Пока хост ждет,
+// он ничего не отправляет, поэтому любые данные от него значат обрыв.
+
+const (
+	requestPrefix  = "platformer-relay"
+	maxLineLength  = 256              // Наибольшая длина строки запроса или ответа
+	maxRoomLength  = 64               // Наибольшая длина имени комнаты
+	requestTimeout = 10 * time.Second // Сколько ждать строку запроса от подключившегося
+)
+
+// Role — роль игрока в комнате посредника
+type Role string
+
+const (
+	RoleHost Role = "host" // Хост регистрирует комнату и ждет клиента
+	RoleJoin Role = "join" // Клиент подключается к хосту комнаты
+)
+
+// Connect выполняет рукопожатие с посредником по соединению conn и возвращается,
+// когда посредник соединил игрока с другой стороной: для хоста - когда к комнате
+// подключился клиент. Дальше по conn идут данные другой стороны.
+func Connect(conn io.ReadWriter, role Role, room string) error {
+	if _, err := fmt.Fprintf(conn, "%s %s %s\n", requestPrefix, role, room); err != nil {
+		return err
+	}
+	line, err := readLine(conn)
+	if err != nil {
+		return err
+	}
+	if line == "ok" {
+		return nil
+	}
+	if reason, ok := strings.CutPrefix(line, "error "); ok {
+		return fmt.Errorf("relay: %s", reason)
+	}
+	return fmt.Errorf("relay: unexpected reply %q", line)
+}
+
+// readLine читает строку до '\n' по одному байту, чтобы не прочитать
+// лишнего из данных, которые пойдут следом
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	var b [1]byte
+	for len(line) < maxLineLength {
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return "", err
+		}
+		if b[0] == '\n' {
+			return string(line), nil
+		}
+		line = append(line, b[0])
+	}
+	return "", errors.New("relay: line too long")
+}
+
+// Server — посредник, соединяющий хостов и клиентов по именам комнат
+type Server struct {
+	mu       sync.Mutex
+	rooms    map[string]*waitingHost
+	listener net.Listener
+	closed   bool
+}
+
+// waitingHost — хост, ждущий клиента в комнате
+type waitingHost struct {
+	conn net.Conn
+	done chan struct{} // Закрывается, когда перестал ждать обрыва (watch)
+	gone bool          // Хост отключился, не дождавшись клиента
+}
+
+// NewServer создает посредника
+func NewServer() *Server {
+	return &Server{rooms: make(map[string]*waitingHost)}
+}
+
+// Serve принимает игроков на listener, пока посредник не закрыт.
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return net.ErrClosed
+	}
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// Close закрывает посредника и отключает ждущих хостов.
+// Уже соединенные пары доигрывают до разрыва.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for room, host := range s.rooms {
+		_ = host.conn.Close()
+		delete(s.rooms, room)
+	}
+	if s.listener != nil {
+		return s.listener.Close()
+	}
+	return nil
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// handle читает запрос игрока и регистрирует хоста или соединяет клиента с хостом
+func (s *Server) handle(conn net.Conn) {
+	_ = conn.SetDeadline(time.Now().Add(requestTimeout))
+	line, err := readLine(conn)
+	_ = conn.SetDeadline(time.Time{})
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) != 3 || fields[0] != requestPrefix || len(fields[2]) > maxRoomLength {
+		reject(conn, "bad request")
+		return
+	}
+	role, room := Role(fields[1]), fields[2]
+
+	switch role {
+	case RoleHost:
+		host := &waitingHost{conn: conn, done: make(chan struct{})}
+		s.mu.Lock()
+		if _, taken := s.rooms[room]; taken || s.closed {
+			s.mu.Unlock()
+			reject(conn, "room is taken")
+			return
+		}
+		s.rooms[room] = host
+		s.mu.Unlock()
+		log.Printf("relay: host waiting in %q (%s)", room, conn.RemoteAddr())
+		go s.watch(room, host)
+
+	case RoleJoin:
+		s.mu.Lock()
+		host := s.rooms[room]
+		delete(s.rooms, room)
+		s.mu.Unlock()
+		if host == nil || !host.stopWatching() {
+			reject(conn, "no host in room")
+			return
+		}
+		if _, err := io.WriteString(host.conn, "ok\n"); err != nil {
+			_ = host.conn.Close()
+			reject(conn, "host is gone")
+			return
+		}
+		if _, err := io.WriteString(conn, "ok\n"); err != nil {
+			_ = host.conn.Close()
+			_ = conn.Close()
+			return
+		}
+		log.Printf("relay: %s joined %q", conn.RemoteAddr(), room)
+		pipe(host.conn, conn)
+		log.Printf("relay: room %q closed", room)
+
+	default:
+		reject(conn, "bad request")
+	}
+}
+
+// watch ждет обрыва соединения хоста, пока тот ждет клиента. Чтение
+// прерывается сроком, который ставит stopWatching, когда пришел клиент.
+func (s *Server) watch(room string, host *waitingHost) {
+	defer close(host.done)
+
+	var b [1]byte
+	_, err := host.conn.Read(b[:])
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return
+	}
+
+	s.mu.Lock()
+	if s.rooms[room] == host {
+		delete(s.rooms, room)
+	}
+	host.gone = true
+	s.mu.Unlock()
+	_ = host.conn.Close()
+}
+
+// stopWatching прерывает watch; false - хост уже отключился
+func (h *waitingHost) stopWatching() bool {
+	_ = h.conn.SetReadDeadline(time.Now())
+	<-h.done
+	_ = h.conn.SetReadDeadline(time.Time{})
+	return !h.gone
+}
+
+// reject отправляет игроку причину отказа и закрывает соединение
+func reject(conn net.Conn, reason string) {
+	_, _ = io.WriteString(conn, "error "+reason+"\n")
+	_ = conn.Close()
+}
+
+// pipe пересылает данные между a и b в обе стороны, пока одна из сторон
+// не отключится, и закрывает оба соединения
+func pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	forward := func(dst, src net.Conn) {
+		_, _ = io.Copy(dst, src)
+		done <- struct{}{}
+	}
+	go forward(a, b)
+	go forward(b, a)
+
+	<-done
+	_ = a.Close()
+	_ = b.Close()
+	<-done
+}
@@ -0,0 +1,81 @@
+package renderer
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"platformer/internal/entities"
+	"platformer/internal/transform"
+)
+
+// Renderer — бэкенд отрисовки игрового мира.
+// Игра рисует все объекты через этот интерфейс, поэтому бэкенд можно
+// переключать во время игры: если ошибка видна в обоих бэкендах, она в логике,
+// а если только в одном — в отрисовке.
+type Renderer interface {
+	// Name возвращает название бэкенда для отладочного вывода
+	Name() string
+
+	// DrawBackground заливает экран фоном
+	DrawBackground(screen *ebiten.Image)
+
+	// Методы отрисовки объектов мира; view переводит мировые координаты в экранные.
+	// tint - цвет игрока в сетевой игре (nil - персонаж рисуется без оттенка).
+	DrawPlatform(screen *ebiten.Image, platform *entities.Platform, view transform.View)
+	DrawPlayer(screen *ebiten.Image, player *entities.Player, view transform.View, tint color.Color)
+	DrawBullet(screen *ebiten.Image, bullet *entities.Bullet, view transform.View)
+	DrawNPC(screen *ebiten.Image, npc *entities.NPC, view transform.View)
+	DrawExit(screen *ebiten.Image, x, y, width, height float64, view transform.View)
+}
+
+// SpriteRenderer — основной бэкенд: спрайты и залитые цветом объекты
+type SpriteRenderer struct{}
+
+// NewSpriteRenderer создает основной бэкенд отрисовки
+func NewSpriteRenderer() *SpriteRenderer {
+	return &SpriteRenderer{}
+}
+
+// Name возвращает название бэкенда
+func (r *SpriteRenderer) Name() string {
+	return "sprites"
+}
+
+// DrawBackground заливает экран цветом неба
+func (r *SpriteRenderer) DrawBackground(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{R: 135, G: 206, B: 235, A: 255}) // Светло-голубой цвет
+}
+
+// DrawPlatform рисует платформу
+func (r *SpriteRenderer) DrawPlatform(screen *ebiten.Image, platform *entities.Platform, view transform.View) {
+	DrawPlatformWithCamera(screen, platform, view)
+}
+
+// DrawPlayer рисует персонажа, окрашенного в цвет игрока
+func (r *SpriteRenderer) DrawPlayer(screen *ebiten.Image, player *entities.Player, view transform.View, tint color.Color) {
+	DrawTintedPlayerWithCamera(screen, player, view, tint)
+}
+
+// DrawBullet рисует пулю
+func (r *SpriteRenderer) DrawBullet(screen *ebiten.Image, bullet *entities.Bullet, view transform.View) {
+	DrawBulletWithCamera(screen, bullet, view)
+}
+
+// DrawNPC рисует NPC
+func (r *SpriteRenderer) DrawNPC(screen *ebiten.Image, npc *entities.NPC, view transform.View) {
+	DrawNPCWithCamera(screen, npc, view)
+}
+
+// DrawExit рисует выход с уровня
+func (r *SpriteRenderer) DrawExit(screen *ebiten.Image, x, y, width, height float64, view transform.View) {
+	DrawExitWithCamera(screen, x, y, width, height, view)
+}
+
+// Backends возвращает все доступные бэкенды в порядке переключения
+func Backends() []Renderer {
+	return []Renderer{
+		NewSpriteRenderer(),
+		NewWireframeRenderer(),
+	}
+}
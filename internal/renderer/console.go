@@ -0,0 +1,33 @@
+package renderer
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"platformer/internal/config"
+)
+
+// Цвета отладочной консоли
+var (
+	consolePanelColor = color.RGBA{R: 10, G: 10, B: 20, A: 210}
+	consoleInputColor = color.RGBA{R: 255, G: 220, B: 120, A: 255}
+	consoleStyle      = TextStyle{Size: config.FontSizeHUD, Mono: true}
+)
+
+// DrawConsole рисует отладочную консоль в верхней части экрана:
+// журнал команд и ответов, а под ним строку ввода с курсором
+func DrawConsole(screen *ebiten.Image, lines []string, input string) {
+	const padding = 8
+	spacing := consoleStyle.lineSpacing()
+	height := padding*2 + spacing*float64(config.ConsoleLines+1)
+	vector.DrawFilledRect(screen, 0, 0, config.ScreenWidth, float32(height), consolePanelColor, false)
+
+	DrawText(screen, strings.Join(lines, "\n"), padding, padding, consoleStyle)
+
+	inputStyle := consoleStyle
+	inputStyle.Color = consoleInputColor
+	DrawText(screen, "> "+input+"_", padding, padding+spacing*float64(config.ConsoleLines), inputStyle)
+}
@@ -0,0 +1,48 @@
+package renderer
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+	"platformer/internal/transform"
+)
+
+// DrawBaseWithCamera рисует базу команды team: полупрозрачную площадку с рамкой ее цвета
+func DrawBaseWithCamera(screen *ebiten.Image, x, y, width, height float64, team int, view transform.View) {
+	if !view.Visible(x, y, width, height) {
+		return
+	}
+
+	screenX, screenY, screenWidth, screenHeight := view.RectToScreen(x, y, width, height)
+	clr := canvas.TeamColor(team)
+	vector.DrawFilledRect(screen, float32(screenX), float32(screenY), float32(screenWidth), float32(screenHeight), fade(clr, 0.2), false)
+	vector.StrokeRect(screen, float32(screenX), float32(screenY), float32(screenWidth), float32(screenHeight), float32(2*view.Scale()), clr, false)
+}
+
+// DrawFlagWithCamera рисует флаг команды team: древко по левому краю и полотнище в его верхней части
+func DrawFlagWithCamera(screen *ebiten.Image, x, y, width, height float64, team int, view transform.View) {
+	if !view.Visible(x, y, width, height) {
+		return
+	}
+
+	screenX, screenY, screenWidth, screenHeight := view.RectToScreen(x, y, width, height)
+	pole := 3 * view.Scale()
+	// Древко
+	vector.DrawFilledRect(screen, float32(screenX), float32(screenY), float32(pole), float32(screenHeight), color.RGBA{R: 200, G: 200, B: 200, A: 255}, false)
+	// Полотнище
+	vector.DrawFilledRect(screen, float32(screenX+pole), float32(screenY), float32(screenWidth-pole), float32(screenHeight/2), canvas.TeamColor(team), false)
+}
+
+// DrawCTFInfo выводит в верхней части экрана счет захватов команд, их лимит и оставшееся время
+func DrawCTFInfo(screen *ebiten.Image, red, blue, limit, secondsLeft int) {
+	if secondsLeft < 0 {
+		secondsLeft = 0
+	}
+	DrawText(screen, i18n.T("ctf.info", red, blue, limit, secondsLeft/60, secondsLeft%60),
+		config.ScreenWidth/2, 10, TextStyle{Size: config.FontSizeHUD, Align: AlignCenter})
+}
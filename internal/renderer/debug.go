@@ -0,0 +1,80 @@
+package renderer
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"platformer/internal/canvas"
+	"platformer/internal/i18n"
+	"platformer/internal/transform"
+)
+
+// Цвета отладочного слоя физики
+var debugBoxColors = map[canvas.DebugBoxKind]color.RGBA{
+	canvas.DebugPlatform: {R: 255, G: 255, B: 255, A: 255},
+	canvas.DebugPlayer:   {R: 80, G: 160, B: 255, A: 255},
+	canvas.DebugNPC:      {R: 80, G: 255, B: 80, A: 255},
+	canvas.DebugBullet:   {R: 255, G: 220, B: 0, A: 255},
+	canvas.DebugTrigger:  {R: 255, G: 80, B: 255, A: 255},
+}
+
+var (
+	debugVelocityColor = color.RGBA{R: 255, G: 140, B: 0, A: 255}
+	debugNormalColor   = color.RGBA{R: 255, G: 40, B: 40, A: 255}
+)
+
+// Длина линий скорости и нормалей в пикселях мира
+const (
+	debugVelocityScale = 4  // Скорость в пикселях за кадр умножается на этот коэффициент
+	debugNormalLength  = 16 // Длина линии нормали
+)
+
+// DrawPhysicsDebug рисует поверх мира хитбоксы, скорости, нормали контактов и триггеры
+func DrawPhysicsDebug(screen *ebiten.Image, view transform.View, debug canvas.PhysicsDebug) {
+	for _, item := range debug.Boxes {
+		box := item.Box
+		if !view.Visible(box.X, box.Y, box.Width, box.Height) {
+			continue
+		}
+		clr := debugBoxColors[item.Kind]
+		x, y, width, height := view.RectToScreen(box.X, box.Y, box.Width, box.Height)
+
+		if item.Kind == canvas.DebugTrigger {
+			// Триггеры несплошные - рисуем их полупрозрачной заливкой
+			fill := clr
+			fill.A = 40
+			vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), fill, false)
+		}
+		vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(height), 1, clr, false)
+		if item.Label != "" {
+			DrawText(screen, item.Label, x+2, y+2, hudStyle)
+		}
+
+		// Скорость - линия из центра хитбокса
+		if item.VelocityX != 0 || item.VelocityY != 0 {
+			centerX, centerY := box.Center()
+			drawWorldLine(screen, view, centerX, centerY,
+				centerX+item.VelocityX*debugVelocityScale, centerY+item.VelocityY*debugVelocityScale,
+				debugVelocityColor)
+		}
+	}
+
+	for _, contact := range debug.Contacts {
+		drawWorldLine(screen, view, contact.X, contact.Y,
+			contact.X+contact.NormalX*debugNormalLength, contact.Y+contact.NormalY*debugNormalLength,
+			debugNormalColor)
+		screenX, screenY := view.WorldToScreen(contact.X, contact.Y)
+		vector.DrawFilledRect(screen, float32(screenX)-2, float32(screenY)-2, 4, 4, debugNormalColor, false)
+	}
+
+	DrawText(screen, i18n.T("debug.physics"), 0, 260, hudStyle)
+}
+
+// drawWorldLine рисует линию между двумя точками мира
+func drawWorldLine(screen *ebiten.Image, view transform.View, x1, y1, x2, y2 float64, clr color.Color) {
+	fromX, fromY := view.WorldToScreen(x1, y1)
+	toX, toY := view.WorldToScreen(x2, y2)
+	vector.StrokeLine(screen, float32(fromX), float32(fromY), float32(toX), float32(toY), 1, clr, false)
+}
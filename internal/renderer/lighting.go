@@ -0,0 +1,84 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"platformer/internal/config"
+	"platformer/internal/level"
+	"platformer/internal/transform"
+)
+
+// lightTextureRadius — радиус текстуры пятна света в пикселях; пятна нужного радиуса
+// получаются ее масштабированием
+const lightTextureRadius = 128
+
+var lightTexture *ebiten.Image // Круглое пятно света: непрозрачное в центре и прозрачное к краю
+
+// Lightmap — карта освещения размером с экран. Карта заливается темнотой, источники
+// света стирают в ней пятна, а затем карта накладывается поверх нарисованного мира.
+type Lightmap struct {
+	image *ebiten.Image
+}
+
+// NewLightmap создает карту освещения размером с экран
+func NewLightmap() *Lightmap {
+	return &Lightmap{image: ebiten.NewImage(config.ScreenWidth, config.ScreenHeight)}
+}
+
+// Draw затемняет экран до яркости ambient (от 0 до 1) и высветляет пятна источников
+// света lights, заданных в координатах мира
+func (m *Lightmap) Draw(screen *ebiten.Image, view transform.View, ambient float64, lights []level.Light) {
+	if ambient >= 1 {
+		return
+	}
+	m.image.Fill(color.RGBA{A: uint8(math.Round((1 - ambient) * 255))})
+
+	texture := lightSpot()
+	for _, light := range lights {
+		radius := light.Radius * view.Scale()
+		x, y := view.WorldToScreen(light.X, light.Y)
+
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(-lightTextureRadius, -lightTextureRadius)
+		op.GeoM.Scale(radius/lightTextureRadius, radius/lightTextureRadius)
+		op.GeoM.Translate(x, y)
+		if light.Intensity > 0 {
+			op.ColorScale.ScaleAlpha(float32(light.Intensity))
+		}
+		// Пятно уменьшает непрозрачность темноты под собой; пересекающиеся пятна складываются
+		op.Blend = ebiten.BlendDestinationOut
+		op.Filter = ebiten.FilterLinear
+		m.image.DrawImage(texture, op)
+	}
+
+	screen.DrawImage(m.image, nil)
+}
+
+// lightSpot возвращает текстуру пятна света, создавая ее при первом вызове
+func lightSpot() *ebiten.Image {
+	if lightTexture != nil {
+		return lightTexture
+	}
+
+	const size = lightTextureRadius * 2
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx := float64(x) + 0.5 - lightTextureRadius
+			dy := float64(y) + 0.5 - lightTextureRadius
+			d := math.Hypot(dx, dy) / lightTextureRadius
+			if d >= 1 {
+				continue
+			}
+			// Плавный спад к краю пятна, без резкой границы
+			a := uint8(math.Round((1 - d*d) * (1 - d*d) * 255))
+			img.SetRGBA(x, y, color.RGBA{R: a, G: a, B: a, A: a})
+		}
+	}
+	lightTexture = ebiten.NewImageFromImage(img)
+	return lightTexture
+}
@@ -0,0 +1,60 @@
+package renderer
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+)
+
+// Цвета отметки готовности игрока
+var (
+	lobbyReadyColor    = color.RGBA{R: 120, G: 230, B: 120, A: 255}
+	lobbyNotReadyColor = color.RGBA{R: 170, G: 170, B: 170, A: 255}
+)
+
+// DrawLobby рисует лобби сетевой игры: игроков с их цветами, командами и готовностью,
+// правила матча, строку состояния и подсказку по клавишам
+func DrawLobby(screen *ebiten.Image, players []canvas.LobbyPlayer, rules, status, hint string) {
+	screen.Fill(menuBackgroundColor)
+
+	x := float64(config.ScreenWidth/2 - 200)
+	y := float64(config.ScreenHeight / 3)
+	DrawText(screen, i18n.T("lobby.title"), x, y, titleStyle)
+
+	for i, player := range players {
+		rowY := y + 50 + float64(i)*36
+		if !player.Present {
+			DrawText(screen, i18n.T("lobby.empty"), x+34, rowY, TextStyle{Size: config.FontSizeMenu, Color: lobbyNotReadyColor})
+			continue
+		}
+
+		vector.DrawFilledRect(screen, float32(x), float32(rowY+2), 22, 22, canvas.PlayerColor(player.Color), false)
+
+		name := player.Name
+		if player.Host {
+			name = i18n.T("lobby.host_name", name)
+		}
+		style := menuStyle
+		if player.Local {
+			style.Color = menuSelectedColor
+		}
+		DrawText(screen, name, x+34, rowY, style)
+		DrawText(screen, player.TeamName, x+290, rowY, TextStyle{Size: config.FontSizeMenu, Align: AlignRight, Color: canvas.TeamColor(player.Team)})
+
+		ready, readyColor := i18n.T("lobby.not_ready"), lobbyNotReadyColor
+		if player.Ready {
+			ready, readyColor = i18n.T("lobby.ready"), lobbyReadyColor
+		}
+		DrawText(screen, ready, x+400, rowY, TextStyle{Size: config.FontSizeMenu, Align: AlignRight, Color: readyColor})
+	}
+
+	bottom := y + 70 + float64(len(players))*36
+	DrawText(screen, rules, x, bottom, hudStyle)
+	DrawText(screen, status, x, bottom+30, menuStyle)
+	DrawText(screen, hint, x, bottom+66, hudStyle)
+}
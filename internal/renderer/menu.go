@@ -0,0 +1,102 @@
+package renderer
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+	"platformer/internal/speedrun"
+)
+
+// Цвета экранов меню: фон и выбранный пункт
+var (
+	menuBackgroundColor = color.RGBA{R: 30, G: 30, B: 50, A: 255}
+	menuSelectedColor   = color.RGBA{R: 255, G: 220, B: 100, A: 255}
+)
+
+// DrawMenu рисует меню с заголовком и списком пунктов, выделяя выбранный
+func DrawMenu(screen *ebiten.Image, title string, items []string, selected int) {
+	DrawMenuWithHint(screen, title, items, selected, i18n.T("menu.hint"))
+}
+
+// DrawMenuWithHint рисует меню, как DrawMenu, но со своей подсказкой по клавишам под пунктами
+func DrawMenuWithHint(screen *ebiten.Image, title string, items []string, selected int, hint string) {
+	screen.Fill(menuBackgroundColor)
+
+	x := float64(config.ScreenWidth/2 - 200)
+	y := float64(config.ScreenHeight / 3)
+	DrawText(screen, title, x, y, titleStyle)
+
+	for i, item := range items {
+		style := menuStyle
+		prefix := "  "
+		if i == selected {
+			prefix = "> "
+			style.Color = menuSelectedColor
+		}
+		DrawText(screen, prefix+item, x, y+50+float64(i)*28, style)
+	}
+
+	DrawText(screen, hint, x, y+70+float64(len(items))*28, hudStyle)
+}
+
+// DrawLeaderboard рисует таблицы рекордов и строку состояния
+func DrawLeaderboard(screen *ebiten.Image, sections []canvas.LeaderboardSection, status string) {
+	screen.Fill(menuBackgroundColor)
+
+	x := 100.0
+	y := 60.0
+	DrawText(screen, i18n.T("leaderboard.title"), x, y, titleStyle)
+	y += 50
+
+	for _, section := range sections {
+		DrawText(screen, section.Title, x, y, menuStyle)
+		y += 26
+
+		if len(section.Entries) == 0 {
+			DrawText(screen, i18n.T("leaderboard.empty"), x, y, tableStyle)
+			y += 24
+		}
+		for i, entry := range section.Entries {
+			elapsed := time.Duration(entry.TimeMs) * time.Millisecond
+			line := fmt.Sprintf("%2d. %-16s %-10s %8d  %s", i+1, entry.Name, entry.Level, entry.Score, formatDuration(elapsed))
+			DrawText(screen, line, x, y, tableStyle)
+			y += 24
+		}
+		y += 20
+	}
+
+	DrawText(screen, status, x, config.ScreenHeight-40, hudStyle)
+}
+
+// formatDuration форматирует время в виде минуты:секунды.миллисекунды
+func formatDuration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	seconds := int(d % time.Minute / time.Second)
+	millis := int(d % time.Second / time.Millisecond)
+	return fmt.Sprintf("%d:%02d.%03d", minutes, seconds, millis)
+}
+
+// DrawSpeedrun выводит в правом верхнем углу таймер спидрана и времена пройденных участков.
+// Рядом с каждым участком показывается его собственная длительность.
+func DrawSpeedrun(screen *ebiten.Image, elapsed time.Duration, splits []speedrun.Split, finished bool) {
+	x := float64(config.ScreenWidth - 10)
+	style := TextStyle{Size: config.FontSizeHUD, Align: AlignRight, Mono: true}
+	label := i18n.T("speedrun.running")
+	if finished {
+		label = i18n.T("speedrun.finished")
+	}
+	DrawText(screen, label+formatDuration(elapsed), x, 10, style)
+
+	var previous time.Duration
+	for i, split := range splits {
+		line := fmt.Sprintf("%-10s %s (+%s)", split.Name, formatDuration(split.Time), formatDuration(split.Time-previous))
+		DrawText(screen, line, x, float64(30+i*20), style)
+		previous = split.Time
+	}
+}
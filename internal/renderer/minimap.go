@@ -0,0 +1,99 @@
+package renderer
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/level"
+	"platformer/internal/transform"
+)
+
+// markerColors — цвета отметок по видам
+var markerColors = map[canvas.MarkerKind]color.RGBA{
+	canvas.MarkerPlayer: {R: 255, G: 60, B: 60, A: 255},
+	canvas.MarkerRemote: {R: 80, G: 160, B: 255, A: 255},
+	canvas.MarkerNPC:    {R: 255, G: 220, B: 80, A: 255},
+}
+
+// Цвета миникарты
+var (
+	minimapBackgroundColor = color.RGBA{R: 10, G: 10, B: 20, A: 180}
+	minimapPlatformColor   = color.RGBA{R: 0, G: 200, B: 0, A: 255}
+	minimapViewColor       = color.RGBA{R: 255, G: 255, B: 255, A: 160}
+)
+
+// Minimap — уменьшенная карта области уровня. Неподвижные платформы рисуются
+// в изображение один раз, а отметки сущностей и движущиеся платформы - каждый кадр.
+type Minimap struct {
+	terrain *ebiten.Image
+	bounds  level.Rect // Показываемая область мира
+	scale   float64    // Пикселей миникарты на единицу мира
+}
+
+// NewMinimap строит миникарту области bounds с неподвижными платформами platforms.
+// Размер миникарты подбирается под пропорции области в пределах config.MinimapWidth
+// на config.MinimapMaxHeight.
+func NewMinimap(bounds level.Rect, platforms []*entities.Platform) *Minimap {
+	scale := math.Min(config.MinimapWidth/bounds.Width, config.MinimapMaxHeight/bounds.Height)
+	width := max(1, int(math.Ceil(bounds.Width*scale)))
+	height := max(1, int(math.Ceil(bounds.Height*scale)))
+
+	m := &Minimap{terrain: ebiten.NewImage(width, height), bounds: bounds, scale: scale}
+	m.terrain.Fill(minimapBackgroundColor)
+	for _, platform := range platforms {
+		m.drawPlatform(m.terrain, platform, 0, 0)
+	}
+	return m
+}
+
+// Dispose освобождает изображение миникарты
+func (m *Minimap) Dispose() {
+	m.terrain.Dispose()
+}
+
+// drawPlatform рисует платформу на изображении dst со смещением (offsetX, offsetY).
+// Даже тонкая платформа занимает хотя бы пиксель, чтобы не пропасть с карты.
+func (m *Minimap) drawPlatform(dst *ebiten.Image, platform *entities.Platform, offsetX, offsetY float32) {
+	x, y := m.toMap(platform.X, platform.Y)
+	width := float32(math.Max(1, platform.Width*m.scale))
+	height := float32(math.Max(1, platform.Height*m.scale))
+	vector.DrawFilledRect(dst, offsetX+x, offsetY+y, width, height, minimapPlatformColor, false)
+}
+
+// toMap переводит точку мира в координаты изображения миникарты
+func (m *Minimap) toMap(x, y float64) (float32, float32) {
+	return float32((x - m.bounds.X) * m.scale), float32((y - m.bounds.Y) * m.scale)
+}
+
+// Draw рисует миникарту в правом нижнем углу экрана: закешированные платформы,
+// движущиеся платформы moving, рамку видимой области view и отметки markers
+func (m *Minimap) Draw(screen *ebiten.Image, view transform.View, moving []*entities.Platform, markers []canvas.MinimapMarker) {
+	bounds := m.terrain.Bounds()
+	left := float32(config.ScreenWidth - bounds.Dx() - config.MinimapMargin)
+	top := float32(config.ScreenHeight - bounds.Dy() - config.MinimapMargin)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(left), float64(top))
+	screen.DrawImage(m.terrain, op)
+	vector.StrokeRect(screen, left, top, float32(bounds.Dx()), float32(bounds.Dy()), 1, minimapViewColor, false)
+
+	for _, platform := range moving {
+		m.drawPlatform(screen, platform, left, top)
+	}
+
+	// Видимая на экране часть области
+	viewWidth, viewHeight := view.WorldSize()
+	viewX, viewY := m.toMap(view.X, view.Y)
+	vector.StrokeRect(screen, left+viewX, top+viewY, float32(viewWidth*m.scale), float32(viewHeight*m.scale), 1, minimapViewColor, false)
+
+	for _, marker := range markers {
+		x, y := m.toMap(marker.X, marker.Y)
+		vector.DrawFilledCircle(screen, left+x, top+y, config.MinimapMarkerRadius, markerColors[marker.Kind], true)
+	}
+}
@@ -1,14 +1,20 @@
 package renderer
 
 import (
-	"fmt"
 	"image/color"
+	"math"
+	"sync"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 
+	"platformer/internal/assets"
+	"platformer/internal/canvas"
 	"platformer/internal/config"
 	"platformer/internal/entities"
+	"platformer/internal/i18n"
+	"platformer/internal/level"
+	"platformer/internal/transform"
 )
 
 var (
@@ -16,109 +22,83 @@ var (
 	npcSprite    *ebiten.Image // Кэшированный спрайт NPC
 )
 
-// init инициализирует спрайты при загрузке пакета
-func init() {
-	// Создаем спрайт персонажа (простой пиксельный арт)
-	playerSprite = createPlayerSprite()
-	// Создаем спрайт NPC
-	npcSprite = createNPCSprite()
-}
-
-// createPlayerSprite создает простой спрайт персонажа программно
-func createPlayerSprite() *ebiten.Image {
-	img := ebiten.NewImage(config.PlayerWidth, config.PlayerHeight)
-
-	// Рисуем простой спрайт персонажа
-	// Голова (верхняя часть)
-	headColor := color.RGBA{R: 255, G: 200, B: 150, A: 255} // Цвет кожи
-	for y := 0; y < 12; y++ {
-		for x := 8; x < 32; x++ {
-			img.Set(x, y, headColor)
-		}
-	}
-
-	// Глаза
-	eyeColor := color.RGBA{R: 0, G: 0, B: 0, A: 255} // Черный
-	img.Set(14, 6, eyeColor)
-	img.Set(25, 6, eyeColor)
+// Спрайты ресурсов, перенесенные в изображения ebiten, по именам. Спрайты
+// загружаются и из фоновой загрузки, и из отрисовки, поэтому доступ защищен мьютексом.
+var (
+	spriteMu     sync.Mutex
+	spriteImages = make(map[string]*ebiten.Image)
+)
 
-	// Тело (средняя часть)
-	bodyColor := color.RGBA{R: 0, G: 100, B: 255, A: 255} // Синий
-	for y := 12; y < 28; y++ {
-		for x := 6; x < 34; x++ {
-			img.Set(x, y, bodyColor)
-		}
+// LoadSprites загружает спрайты из встроенных ресурсов заранее, во время загрузки игры.
+// Если спрайты не были загружены, они загружаются при первой отрисовке.
+func LoadSprites() error {
+	var err error
+	if playerSprite, err = spriteImage("player"); err != nil {
+		return err
 	}
-
-	// Руки
-	armColor := color.RGBA{R: 255, G: 200, B: 150, A: 255} // Цвет кожи
-	for y := 14; y < 26; y++ {
-		img.Set(4, y, armColor)
-		img.Set(5, y, armColor)
-		img.Set(34, y, armColor)
-		img.Set(35, y, armColor)
+	if npcSprite, err = spriteImage("npc"); err != nil {
+		return err
 	}
+	return nil
+}
 
-	// Ноги (нижняя часть)
-	legColor := color.RGBA{R: 100, G: 50, B: 0, A: 255} // Коричневый
-	for y := 28; y < 40; y++ {
-		for x := 10; x < 18; x++ {
-			img.Set(x, y, legColor)
+// ReloadSprites заново переносит в изображения спрайты, которые обновил assets.Poll
+// (режим разработки). Спрайт того же размера перерисовывается на месте, а спрайт
+// нового размера заменяется в кэше, поэтому спрайты персонажа и NPC запрашиваются заново.
+func ReloadSprites() error {
+	spriteMu.Lock()
+	var firstErr error
+	for name, img := range spriteImages {
+		decoded, err := assets.GetImage(name)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
 		}
-		for x := 22; x < 30; x++ {
-			img.Set(x, y, legColor)
+		fresh := ebiten.NewImageFromImage(decoded)
+		if img.Bounds() != fresh.Bounds() {
+			spriteImages[name] = fresh
+			continue
 		}
+		img.Clear()
+		img.DrawImage(fresh, nil)
+		fresh.Dispose()
 	}
+	spriteMu.Unlock()
 
-	return img
-}
-
-// createNPCSprite создает простой спрайт NPC программно
-func createNPCSprite() *ebiten.Image {
-	img := ebiten.NewImage(40, 40)
-
-	// Рисуем простой спрайт NPC (зеленый персонаж)
-	// Голова
-	headColor := color.RGBA{R: 150, G: 255, B: 150, A: 255} // Светло-зеленый
-	for y := 0; y < 12; y++ {
-		for x := 8; x < 32; x++ {
-			img.Set(x, y, headColor)
-		}
+	if firstErr != nil {
+		return firstErr
 	}
+	return LoadSprites()
+}
 
-	// Глаза
-	eyeColor := color.RGBA{R: 0, G: 0, B: 0, A: 255} // Черный
-	img.Set(14, 6, eyeColor)
-	img.Set(25, 6, eyeColor)
+// spriteImage возвращает изображение спрайта name; спрайт переносится
+// в изображение один раз, повторные вызовы возвращают то же изображение
+func spriteImage(name string) (*ebiten.Image, error) {
+	spriteMu.Lock()
+	defer spriteMu.Unlock()
 
-	// Тело
-	bodyColor := color.RGBA{R: 0, G: 200, B: 0, A: 255} // Зеленый
-	for y := 12; y < 28; y++ {
-		for x := 6; x < 34; x++ {
-			img.Set(x, y, bodyColor)
-		}
+	if img, ok := spriteImages[name]; ok {
+		return img, nil
 	}
-
-	// Руки
-	armColor := color.RGBA{R: 150, G: 255, B: 150, A: 255} // Светло-зеленый
-	for y := 14; y < 26; y++ {
-		img.Set(4, y, armColor)
-		img.Set(5, y, armColor)
-		img.Set(34, y, armColor)
-		img.Set(35, y, armColor)
+	decoded, err := assets.GetImage(name)
+	if err != nil {
+		return nil, err
 	}
+	img := ebiten.NewImageFromImage(decoded)
+	spriteImages[name] = img
+	return img, nil
+}
 
-	// Ноги
-	legColor := color.RGBA{R: 0, G: 150, B: 0, A: 255} // Темно-зеленый
-	for y := 28; y < 40; y++ {
-		for x := 10; x < 18; x++ {
-			img.Set(x, y, legColor)
-		}
-		for x := 22; x < 30; x++ {
-			img.Set(x, y, legColor)
-		}
+// sprite возвращает спрайт name, а если его не удалось загрузить - заглушку
+// заданного размера, чтобы отсутствующий ресурс был заметен, но не ронял игру
+func sprite(name string, width, height int) *ebiten.Image {
+	img, err := spriteImage(name)
+	if err != nil {
+		img = ebiten.NewImage(width, height)
+		img.Fill(color.RGBA{R: 255, G: 0, B: 255, A: 255})
 	}
-
 	return img
 }
 
@@ -141,11 +121,24 @@ func DrawPlayer(screen *ebiten.Image, player *entities.Player) {
 }
 
 // DrawPlayerWithCamera рисует персонажа на экране с учетом позиции камеры
-func DrawPlayerWithCamera(screen *ebiten.Image, player *entities.Player, cameraX, cameraY float64) {
+func DrawPlayerWithCamera(screen *ebiten.Image, player *entities.Player, view transform.View) {
+	DrawTintedPlayerWithCamera(screen, player, view, nil)
+}
+
+// DrawTintedPlayerWithCamera рисует персонажа, как DrawPlayerWithCamera, окрашивая
+// спрайт в цвет tint (nil - без оттенка). Оттенок смешивается с исходными цветами
+// спрайта с силой PlayerTintStrength, поэтому детали спрайта остаются видны.
+func DrawTintedPlayerWithCamera(screen *ebiten.Image, player *entities.Player, view transform.View, tint color.Color) {
 	// Используем предзагруженный спрайт персонажа
 	if playerSprite == nil {
-		// Если спрайт не загружен, создаем его
-		playerSprite = createPlayerSprite()
+		// Если спрайт не загружен заранее, загружаем его сейчас
+		playerSprite = sprite("player", config.PlayerWidth, config.PlayerHeight)
+	}
+
+	// Скин игрока заменяет встроенный спрайт
+	img := playerSprite
+	if playerSkin != nil {
+		img = playerSkin
 	}
 
 	// Создаем опции для позиционирования
@@ -157,16 +150,33 @@ func DrawPlayerWithCamera(screen *ebiten.Image, player *entities.Player, cameraX
 		op.GeoM.Translate(config.PlayerWidth, 0) // Смещаем после отражения
 	}
 
-	// Вычисляем позицию на экране с учетом камеры
-	// Вычитаем позицию камеры, чтобы объект отображался в правильном месте на экране
-	screenX := player.X - cameraX
-	screenY := player.Y - cameraY
+	// При перевернутой гравитации персонаж стоит вверх ногами - отражаем спрайт по вертикали
+	if player.GravityFlipped {
+		op.GeoM.Scale(1, -1)
+		op.GeoM.Translate(0, config.PlayerHeight)
+	}
 
-	// Устанавливаем позицию, где нужно нарисовать персонажа
-	op.GeoM.Translate(screenX, screenY)
+	// Присевшего персонажа сплющиваем до высоты его хитбокса
+	if player.Height != config.PlayerHeight {
+		op.GeoM.Scale(player.Width/config.PlayerWidth, player.Height/config.PlayerHeight)
+	}
+
+	// Переводим позицию персонажа из мира на экран с учетом камеры
+	placeInView(&op.GeoM, view, player.X, player.Y)
+
+	// Окрашиваем спрайт в цвет игрока
+	if tint != nil {
+		r, g, b, _ := tint.RGBA()
+		op.ColorScale.Scale(tintChannel(r), tintChannel(g), tintChannel(b), 1)
+	}
 
 	// Рисуем спрайт персонажа на экране
-	screen.DrawImage(playerSprite, op)
+	screen.DrawImage(img, op)
+}
+
+// tintChannel возвращает множитель канала цвета для оттенка со значением value (0-0xffff)
+func tintChannel(value uint32) float32 {
+	return float32(1 - config.PlayerTintStrength*(1-float64(value)/0xffff))
 }
 
 // DrawPlatform рисует платформу на экране
@@ -187,24 +197,33 @@ func DrawPlatform(screen *ebiten.Image, platform *entities.Platform) {
 	screen.DrawImage(platformImg, op)
 }
 
+// Цвета платформ из особых материалов (обычные платформы коричневые)
+var platformMaterialColors = map[string]color.RGBA{
+	level.MaterialIce: {R: 170, G: 220, B: 255, A: 255},
+	level.MaterialMud: {R: 80, G: 50, B: 20, A: 255},
+}
+
 // DrawPlatformWithCamera рисует платформу на экране с учетом позиции камеры
-func DrawPlatformWithCamera(screen *ebiten.Image, platform *entities.Platform, cameraX, cameraY float64) {
+func DrawPlatformWithCamera(screen *ebiten.Image, platform *entities.Platform, view transform.View) {
 	// Создаем изображение для платформы
 	platformImg := ebiten.NewImage(int(platform.Width), int(platform.Height))
 
-	// Заливаем платформу коричневым цветом
-	platformImg.Fill(color.RGBA{R: 139, G: 69, B: 19, A: 255})
+	// Заливаем платформу коричневым цветом, цветом ее материала или серым для конвейера
+	clr, ok := platformMaterialColors[platform.Material]
+	switch {
+	case ok:
+	case platform.Conveyor != 0:
+		clr = color.RGBA{R: 110, G: 110, B: 120, A: 255}
+	default:
+		clr = color.RGBA{R: 139, G: 69, B: 19, A: 255}
+	}
+	platformImg.Fill(clr)
 
 	// Создаем опции для позиционирования
 	op := &ebiten.DrawImageOptions{}
 
-	// Вычисляем позицию на экране с учетом камеры
-	// Вычитаем позицию камеры, чтобы объект отображался в правильном месте на экране
-	screenX := platform.X - cameraX
-	screenY := platform.Y - cameraY
-
-	// Устанавливаем позицию платформы
-	op.GeoM.Translate(screenX, screenY)
+	// Переводим позицию платформы из мира на экран с учетом камеры
+	placeInView(&op.GeoM, view, platform.X, platform.Y)
 
 	// Рисуем платформу на экране
 	screen.DrawImage(platformImg, op)
@@ -229,7 +248,7 @@ func DrawBullet(screen *ebiten.Image, bullet *entities.Bullet) {
 }
 
 // DrawBulletWithCamera рисует пулю на экране с учетом позиции камеры
-func DrawBulletWithCamera(screen *ebiten.Image, bullet *entities.Bullet, cameraX, cameraY float64) {
+func DrawBulletWithCamera(screen *ebiten.Image, bullet *entities.Bullet, view transform.View) {
 	// Создаем изображение для пули
 	bulletImg := ebiten.NewImage(int(bullet.Width), int(bullet.Height))
 
@@ -239,48 +258,35 @@ func DrawBulletWithCamera(screen *ebiten.Image, bullet *entities.Bullet, cameraX
 	// Создаем опции для позиционирования
 	op := &ebiten.DrawImageOptions{}
 
-	// Вычисляем позицию на экране с учетом камеры
-	// Вычитаем позицию камеры, чтобы объект отображался в правильном месте на экране
-	screenX := bullet.X - cameraX
-	screenY := bullet.Y - cameraY
-
-	// Устанавливаем позицию пули
-	op.GeoM.Translate(screenX, screenY)
+	// Переводим позицию пули из мира на экран с учетом камеры
+	placeInView(&op.GeoM, view, bullet.X, bullet.Y)
 
 	// Рисуем пулю на экране
 	screen.DrawImage(bulletImg, op)
 }
 
-// DrawDebugInfo выводит отладочную информацию на экран
-func DrawDebugInfo(screen *ebiten.Image, player *entities.Player, bulletCount int) {
-	// Выводим информацию для отладки (FPS, позиция персонажа)
-	ebitenutil.DebugPrint(screen, "Платформер на Go!")
-	ebitenutil.DebugPrintAt(screen,
-		"Управление: Стрелки/WASD - движение, Пробел - прыжок, J/Enter - стрельба",
-		0, 20)
-	ebitenutil.DebugPrintAt(screen,
-		"Позиция: X="+formatFloat(player.X)+" Y="+formatFloat(player.Y),
-		0, 40)
-	ebitenutil.DebugPrintAt(screen,
-		"Скорость: VX="+formatFloat(player.VelocityX)+" VY="+formatFloat(player.VelocityY),
-		0, 60)
-	if player.OnGround {
-		ebitenutil.DebugPrintAt(screen, "На земле: Да", 0, 80)
-	} else {
-		ebitenutil.DebugPrintAt(screen, "На земле: Нет", 0, 80)
+// DrawHUD выводит название игры, подсказку по управлению и здоровье персонажа
+func DrawHUD(screen *ebiten.Image, player *entities.Player) {
+	DrawText(screen, i18n.T("hud.title"), 0, 0, hudStyle)
+	DrawText(screen, i18n.T("hud.controls"), 0, 20, hudStyle)
+	DrawText(screen, i18n.T("hud.health", player.Health, player.MaxHealth), 0, 40, hudStyle)
+}
+
+// DrawDebugPage выводит под подсказками страницу отладочной информации:
+// заголовок и строки по одной на строку экрана
+func DrawDebugPage(screen *ebiten.Image, title string, lines []string) {
+	DrawText(screen, title, 0, 70, hudStyle)
+	for i, line := range lines {
+		DrawText(screen, line, 0, float64(90+i*20), hudStyle)
 	}
-	// Выводим количество активных пуль
-	ebitenutil.DebugPrintAt(screen,
-		fmt.Sprintf("Пули: %d", bulletCount),
-		0, 100)
 }
 
 // DrawNPCWithCamera рисует NPC на экране с учетом позиции камеры
-func DrawNPCWithCamera(screen *ebiten.Image, npc *entities.NPC, cameraX, cameraY float64) {
+func DrawNPCWithCamera(screen *ebiten.Image, npc *entities.NPC, view transform.View) {
 	// Используем предзагруженный спрайт NPC
 	if npcSprite == nil {
-		// Если спрайт не загружен, создаем его
-		npcSprite = createNPCSprite()
+		// Если спрайт не загружен заранее, загружаем его сейчас
+		npcSprite = sprite("npc", 40, 40)
 	}
 
 	// Создаем опции для позиционирования
@@ -292,19 +298,254 @@ func DrawNPCWithCamera(screen *ebiten.Image, npc *entities.NPC, cameraX, cameraY
 		op.GeoM.Translate(npc.Width, 0) // Смещаем после отражения
 	}
 
-	// Вычисляем позицию на экране с учетом камеры
-	// Вычитаем позицию камеры, чтобы объект отображался в правильном месте на экране
-	screenX := npc.X - cameraX
-	screenY := npc.Y - cameraY
-
-	// Устанавливаем позицию NPC
-	op.GeoM.Translate(screenX, screenY)
+	// Переводим позицию NPC из мира на экран с учетом камеры
+	placeInView(&op.GeoM, view, npc.X, npc.Y)
 
 	// Рисуем спрайт NPC на экране
 	screen.DrawImage(npcSprite, op)
 }
 
-// formatFloat форматирует число с плавающей точкой для вывода
-func formatFloat(f float64) string {
-	return fmt.Sprintf("%.1f", f)
+// placeInView добавляет к преобразованию спрайта переход из мира на экран:
+// спрайт, нарисованный от своего левого верхнего угла, ставится в точку (x, y) мира
+func placeInView(geom *ebiten.GeoM, view transform.View, x, y float64) {
+	geom.Translate(x-view.X, y-view.Y)
+	scale := view.Scale()
+	geom.Scale(scale, scale)
+	geom.Translate(view.ShakeX, view.ShakeY)
+}
+
+// DrawExitWithCamera рисует зону выхода с уровня (дверь) с учетом позиции камеры
+func DrawExitWithCamera(screen *ebiten.Image, x, y, width, height float64, view transform.View) {
+	// Проверяем, видна ли дверь на экране
+	if !view.Visible(x, y, width, height) {
+		return
+	}
+
+	screenX, screenY, screenWidth, screenHeight := view.RectToScreen(x, y, width, height)
+	frame := 6 * view.Scale()
+
+	// Рамка двери
+	vector.DrawFilledRect(screen, float32(screenX), float32(screenY), float32(screenWidth), float32(screenHeight), color.RGBA{R: 90, G: 60, B: 30, A: 255}, false)
+	// Проем двери
+	vector.DrawFilledRect(screen, float32(screenX+frame), float32(screenY+frame), float32(screenWidth-2*frame), float32(screenHeight-frame), color.RGBA{R: 30, G: 20, B: 10, A: 255}, false)
+}
+
+// DrawSpeechWithCamera выводит реплику над персонажем с учетом позиции камеры
+func DrawSpeechWithCamera(screen *ebiten.Image, text string, x, y float64, view transform.View) {
+	screenX, screenY := view.WorldToScreen(x, y)
+	DrawText(screen, text, screenX, screenY-20, TextStyle{Size: config.FontSizeDialogue, Align: AlignCenter})
+}
+
+// DrawNameTag выводит имя игрока цветом clr (nil - белым) над персонажем
+// (над полоской здоровья) с учетом позиции камеры
+func DrawNameTag(screen *ebiten.Image, name string, player *entities.Player, view transform.View, clr color.Color) {
+	screenX, screenY := view.WorldToScreen(player.X+player.Width/2, player.Y)
+	DrawText(screen, name, screenX, screenY-28, TextStyle{Size: config.FontSizeHUD, Align: AlignCenter, Color: clr})
+}
+
+// DrawBanner выводит крупное сообщение в центре экрана (например, итог уровня)
+func DrawBanner(screen *ebiten.Image, text string) {
+	// Полупрозрачная подложка под текстом шириной по надписи, но не уже 320 пикселей
+	width, height := MeasureText(text, bannerStyle)
+	width = math.Max(320, width+40)
+	vector.DrawFilledRect(screen, float32(config.ScreenWidth/2-width/2), config.ScreenHeight/2-20, float32(width), 40, color.RGBA{A: 160}, false)
+	DrawText(screen, text, config.ScreenWidth/2, config.ScreenHeight/2-height/2, bannerStyle)
+}
+
+// DrawCollapseFront рисует у левого края экрана красное марево обрушения,
+// от которого игрок должен убегать
+func DrawCollapseFront(screen *ebiten.Image) {
+	const width = 48
+	for i := 0; i < width; i += 4 {
+		alpha := uint8(200 - i*4)
+		vector.DrawFilledRect(screen, float32(i), 0, 4, config.ScreenHeight, color.RGBA{R: 200, G: 40, B: 20, A: alpha}, false)
+	}
+	DrawText(screen, i18n.T("escape.run"), 60, config.ScreenHeight/2, TextStyle{Size: config.FontSizeBanner, Color: color.RGBA{R: 255, G: 220, B: 200, A: 255}})
+}
+
+// DrawSplitDivider рисует черту между половинами разделенного экрана
+func DrawSplitDivider(screen *ebiten.Image) {
+	const width = 4
+	vector.DrawFilledRect(screen, config.ScreenWidth/2-width/2, 0, width, config.ScreenHeight, color.RGBA{R: 20, G: 20, B: 30, A: 255}, false)
+}
+
+// DrawNetworkStatus выводит строку состояния сетевого подключения под верхним краем экрана
+func DrawNetworkStatus(screen *ebiten.Image, text string) {
+	DrawText(screen, text, config.ScreenWidth/2, 30, TextStyle{Size: config.FontSizeHUD, Align: AlignCenter})
+}
+
+// connectionColors — цвета индикатора для каждого качества соединения
+var connectionColors = [...]color.RGBA{
+	canvas.ConnectionGood: {R: 80, G: 220, B: 90, A: 255},
+	canvas.ConnectionFair: {R: 240, G: 200, B: 60, A: 255},
+	canvas.ConnectionPoor: {R: 230, G: 70, B: 60, A: 255},
+}
+
+// DrawConnectionInfo выводит в правом верхнем углу пинг, долю потерь и время
+// с последнего обновления, а рядом - индикатор качества из трех делений:
+// чем хуже соединение, тем меньше горящих делений
+func DrawConnectionInfo(screen *ebiten.Image, pingMs, lossPercent, ageMs int, quality canvas.ConnectionQuality) {
+	const (
+		barWidth = 4
+		barGap   = 2
+		bars     = 3
+		top      = 10
+		right    = config.ScreenWidth - 10
+	)
+	clr := connectionColors[quality]
+	lit := bars - int(quality)
+	for i := 0; i < bars; i++ {
+		height := float32(4 + 4*i)
+		x := float32(right - (bars-i)*(barWidth+barGap))
+		barColor := color.RGBA{R: 90, G: 90, B: 90, A: 255}
+		if i < lit {
+			barColor = clr
+		}
+		vector.DrawFilledRect(screen, x, top+12-height, barWidth, height, barColor, false)
+	}
+
+	text := i18n.T("net.stats", pingMs, lossPercent, ageMs)
+	DrawText(screen, text, right-bars*(barWidth+barGap)-6, top, TextStyle{Size: config.FontSizeHUD, Align: AlignRight, Color: clr})
+}
+
+// DrawMatchInfo выводит счет и оставшееся время сетевого матча
+func DrawMatchInfo(screen *ebiten.Image, localScore, remoteScore, secondsLeft int, isHost bool) {
+	if secondsLeft < 0 {
+		secondsLeft = 0
+	}
+	role := i18n.T("match.role_client")
+	if isHost {
+		role = i18n.T("match.role_host")
+	}
+	DrawText(screen, i18n.T("match.info", role, localScore, remoteScore, secondsLeft/60, secondsLeft%60),
+		config.ScreenWidth/2, 10, TextStyle{Size: config.FontSizeHUD, Align: AlignCenter})
+}
+
+// DrawCoopInfo выводит в верхней части экрана номер уровня кооперативной кампании
+func DrawCoopInfo(screen *ebiten.Image, stage, stages int) {
+	DrawText(screen, i18n.T("coop.info", stage, stages),
+		config.ScreenWidth/2, 10, TextStyle{Size: config.FontSizeHUD, Align: AlignCenter})
+}
+
+// DrawDeathmatchInfo выводит в верхней части экрана номер раунда дезматча, счет и лимит убийств
+func DrawDeathmatchInfo(screen *ebiten.Image, round, localKills, remoteKills, limit int) {
+	DrawText(screen, i18n.T("deathmatch.info", round, localKills, remoteKills, limit),
+		config.ScreenWidth/2, 10, TextStyle{Size: config.FontSizeHUD, Align: AlignCenter})
+}
+
+// DrawTracer рисует след выстрела между двумя точками мира; alpha от 0 до 1 задает яркость
+func DrawTracer(screen *ebiten.Image, view transform.View, x1, y1, x2, y2, alpha float64) {
+	clr := fade(color.RGBA{R: 255, G: 255, B: 200, A: 255}, alpha)
+	fromX, fromY := view.WorldToScreen(x1, y1)
+	toX, toY := view.WorldToScreen(x2, y2)
+	vector.StrokeLine(screen, float32(fromX), float32(fromY), float32(toX), float32(toY), 2, clr, false)
+}
+
+// DrawWeaponInfo выводит текущее оружие в нижнем левом углу экрана
+func DrawWeaponInfo(screen *ebiten.Image, name string) {
+	DrawText(screen, i18n.T("hud.weapon", name), 0, config.ScreenHeight-20, hudStyle)
+}
+
+// floatingTextImages кэширует изображения всплывающих надписей, чтобы не рисовать текст заново каждый кадр
+var floatingTextImages = make(map[string]*ebiten.Image)
+
+// DrawFloatingText рисует надпись по центру над точкой (x, y) мира; alpha от 0 до 1 задает непрозрачность
+func DrawFloatingText(screen *ebiten.Image, view transform.View, text string, x, y, alpha float64) {
+	img, ok := floatingTextImages[text]
+	if !ok {
+		width, height := MeasureText(text, hudStyle)
+		img = ebiten.NewImage(int(math.Ceil(width))+2, int(math.Ceil(height)))
+		DrawText(img, text, 1, 0, hudStyle)
+		floatingTextImages[text] = img
+	}
+
+	screenX, screenY := view.WorldToScreen(x, y)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(screenX-float64(img.Bounds().Dx())/2, screenY-float64(img.Bounds().Dy()))
+	op.ColorScale.ScaleAlpha(float32(math.Max(0, math.Min(1, alpha))))
+	screen.DrawImage(img, op)
+}
+
+// DrawHitMarker рисует крестик отметки попадания в точке (x, y) мира
+func DrawHitMarker(screen *ebiten.Image, view transform.View, x, y, alpha float64) {
+	clr := fade(color.RGBA{R: 255, G: 255, B: 255, A: 255}, alpha)
+	screenX, screenY := view.WorldToScreen(x, y)
+	centerX, centerY := float32(screenX), float32(screenY)
+	vector.StrokeLine(screen, centerX-6, centerY-6, centerX-2, centerY-2, 2, clr, false)
+	vector.StrokeLine(screen, centerX+6, centerY-6, centerX+2, centerY-2, 2, clr, false)
+	vector.StrokeLine(screen, centerX-6, centerY+6, centerX-2, centerY+2, 2, clr, false)
+	vector.StrokeLine(screen, centerX+6, centerY+6, centerX+2, centerY+2, 2, clr, false)
+}
+
+// fade возвращает цвет clr с непрозрачностью alpha от 0 до 1.
+// Цвета ebiten хранятся с предумноженной альфой, поэтому масштабируются все каналы.
+func fade(clr color.RGBA, alpha float64) color.RGBA {
+	alpha = math.Max(0, math.Min(1, alpha))
+	return color.RGBA{
+		R: uint8(float64(clr.R) * alpha),
+		G: uint8(float64(clr.G) * alpha),
+		B: uint8(float64(clr.B) * alpha),
+		A: uint8(float64(clr.A) * alpha),
+	}
+}
+
+// DrawHealthBar рисует полоску здоровья над персонажем
+func DrawHealthBar(screen *ebiten.Image, player *entities.Player, view transform.View) {
+	if player.MaxHealth <= 0 {
+		return
+	}
+	fraction := float64(player.Health) / float64(player.MaxHealth)
+	fraction = math.Max(0, math.Min(1, fraction))
+
+	x, y, width, _ := view.RectToScreen(player.X, player.Y-8, player.Width, 4)
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), 4, color.RGBA{R: 80, A: 255}, false)
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width*fraction), 4, color.RGBA{R: 220, G: 40, B: 40, A: 255}, false)
+}
+
+// DrawEmoteBubble рисует облачко с эмоцией над точкой (x, y) мира.
+// progress от 0 до 1 задает фазу анимации: облачко подпрыгивает при появлении
+// и поднимается вверх перед исчезновением.
+func DrawEmoteBubble(screen *ebiten.Image, text string, x, y float64, view transform.View, progress float64) {
+	textWidth, textHeight := MeasureText(text, dialogueStyle)
+	width := float32(textWidth + 12)
+	height := float32(textHeight + 6)
+
+	offset := 0.0
+	if progress < 0.15 {
+		// Подпрыгивание при появлении
+		offset = math.Sin(progress/0.15*math.Pi) * 8
+	} else if progress > 0.8 {
+		// Уплывание вверх в конце
+		offset = (progress - 0.8) / 0.2 * 12
+	}
+
+	anchorX, anchorY := view.WorldToScreen(x, y)
+	screenX := float32(anchorX) - width/2
+	screenY := float32(anchorY) - height - 12 - float32(offset)
+
+	vector.DrawFilledRect(screen, screenX, screenY, width, height, color.RGBA{R: 255, G: 255, B: 255, A: 230}, false)
+	vector.StrokeRect(screen, screenX, screenY, width, height, 1, color.RGBA{A: 255}, false)
+	// Хвостик облачка
+	vector.DrawFilledRect(screen, screenX+width/2-3, screenY+height, 6, 6, color.RGBA{R: 255, G: 255, B: 255, A: 230}, false)
+
+	DrawText(screen, text, float64(screenX+width/2), float64(screenY)+3, dialogueStyle)
+}
+
+// DrawLoading рисует экран загрузки с полосой прогресса
+func DrawLoading(screen *ebiten.Image, progress float64, current string) {
+	screen.Fill(menuBackgroundColor)
+
+	const barWidth, barHeight = 400, 20
+	x := float32(config.ScreenWidth/2 - barWidth/2)
+	y := float32(config.ScreenHeight/2 - barHeight/2)
+
+	DrawText(screen, i18n.T("loading.title"), float64(x), float64(y)-28, menuStyle)
+
+	// Рамка и заполненная часть полосы
+	vector.StrokeRect(screen, x, y, barWidth, barHeight, 2, color.RGBA{R: 200, G: 200, B: 200, A: 255}, false)
+	vector.DrawFilledRect(screen, x+2, y+2, float32(progress)*(barWidth-4), barHeight-4, color.RGBA{R: 80, G: 200, B: 80, A: 255}, false)
+
+	if current != "" {
+		DrawText(screen, current, float64(x), float64(y)+barHeight+8, hudStyle)
+	}
 }
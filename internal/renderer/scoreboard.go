@@ -0,0 +1,69 @@
+package renderer
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/i18n"
+)
+
+// Ширина таблицы счета и отступы ее столбцов от левого края
+const (
+	scoreboardWidth   = 460
+	scoreboardKills   = 250
+	scoreboardDeaths  = 340
+	scoreboardRounds  = 430
+	scoreboardRowStep = 32
+)
+
+// scoreboardBackground — полупрозрачная подложка таблицы счета, сквозь которую видна игра
+var scoreboardBackground = color.RGBA{R: 30, G: 30, B: 50, A: 220}
+
+// DrawScoreboard рисует поверх игры таблицу счета: заголовок и строки игроков
+// с убийствами, гибелями и выигранными раундами
+func DrawScoreboard(screen *ebiten.Image, title string, rows []canvas.ScoreboardRow) {
+	height := float64(90 + len(rows)*scoreboardRowStep)
+	x := float64(config.ScreenWidth/2 - scoreboardWidth/2)
+	y := float64(config.ScreenHeight/2) - height/2
+	vector.DrawFilledRect(screen, float32(x-20), float32(y-20), scoreboardWidth+40, float32(height+20), scoreboardBackground, false)
+
+	DrawText(screen, title, x, y, menuStyle)
+	headerY := y + 40
+	DrawText(screen, i18n.T("scoreboard.player"), x+34, headerY, hudStyle)
+	for _, column := range []struct {
+		key string
+		x   float64
+	}{
+		{"scoreboard.kills", scoreboardKills},
+		{"scoreboard.deaths", scoreboardDeaths},
+		{"scoreboard.rounds", scoreboardRounds},
+	} {
+		DrawText(screen, i18n.T(column.key), x+column.x, headerY, TextStyle{Size: config.FontSizeHUD, Align: AlignRight})
+	}
+
+	for i, row := range rows {
+		rowY := headerY + 30 + float64(i*scoreboardRowStep)
+		vector.DrawFilledRect(screen, float32(x), float32(rowY+2), 18, 18, canvas.PlayerColor(row.Color), false)
+
+		style := hudStyle
+		if row.Local {
+			style.Color = menuSelectedColor
+		}
+		DrawText(screen, row.Name, x+34, rowY, style)
+		for _, value := range []struct {
+			n int
+			x float64
+		}{
+			{row.Kills, scoreboardKills},
+			{row.Deaths, scoreboardDeaths},
+			{row.Rounds, scoreboardRounds},
+		} {
+			DrawText(screen, fmt.Sprint(value.n), x+value.x, rowY, TextStyle{Size: config.FontSizeHUD, Align: AlignRight, Color: style.Color})
+		}
+	}
+}
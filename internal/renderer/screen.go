@@ -0,0 +1,327 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"platformer/internal/canvas"
+	"platformer/internal/config"
+	"platformer/internal/entities"
+	"platformer/internal/level"
+	"platformer/internal/speedrun"
+	"platformer/internal/transform"
+	"platformer/internal/weather"
+)
+
+// Screen — кадр ebiten, на котором рисует игра (canvas.Canvas).
+// Изображение кадра подставляет окно перед каждой отрисовкой (Bind), а бэкенды
+// и закешированные изображения живут, пока живет Screen, и общие с половинами
+// разделенного экрана.
+type Screen struct {
+	image *ebiten.Image
+	cache *screenCache
+}
+
+// screenCache — состояние кадра, которое переживает отдельные кадры
+type screenCache struct {
+	backends        []Renderer
+	backendIndex    int
+	lightmap        *Lightmap     // Карта освещения темных комнат (nil - еще не нужна)
+	minimap         *Minimap      // Миникарта текущей комнаты (nil - еще не построена)
+	minimapRevision int           // Версия платформ, по которой построена миникарта
+	halves          [2]*Screen    // Половины разделенного экрана (nil - еще не созданы)
+	scaled          *ebiten.Image // Уменьшенный кадр перед чтением пикселей
+}
+
+var _ canvas.Canvas = (*Screen)(nil)
+
+// NewScreen создает кадр со всеми бэкендами отрисовки; рисует первый из них
+func NewScreen() *Screen {
+	return &Screen{cache: &screenCache{backends: Backends()}}
+}
+
+// Bind подставляет изображение, на котором рисуется следующий кадр
+func (s *Screen) Bind(image *ebiten.Image) {
+	s.image = image
+}
+
+// NextBackend переключает отрисовку мира на следующий бэкенд по кругу
+func (s *Screen) NextBackend() {
+	c := s.cache
+	c.backendIndex = (c.backendIndex + 1) % len(c.backends)
+}
+
+// SetBackend переключает отрисовку мира на бэкенд с названием name;
+// при неизвестном названии бэкенд не меняется
+func (s *Screen) SetBackend(name string) {
+	for i, backend := range s.cache.backends {
+		if backend.Name() == name {
+			s.cache.backendIndex = i
+		}
+	}
+}
+
+// Backend возвращает название текущего бэкенда отрисовки
+func (s *Screen) Backend() string {
+	return s.backend().Name()
+}
+
+// backend возвращает текущий бэкенд отрисовки
+func (s *Screen) backend() Renderer {
+	return s.cache.backends[s.cache.backendIndex]
+}
+
+// DrawBackground заливает кадр фоном текущего бэкенда
+func (s *Screen) DrawBackground() {
+	s.backend().DrawBackground(s.image)
+}
+
+// DrawPlatform рисует платформу текущим бэкендом
+func (s *Screen) DrawPlatform(platform *entities.Platform, view transform.View) {
+	s.backend().DrawPlatform(s.image, platform, view)
+}
+
+// DrawPlayer рисует персонажа текущим бэкендом
+func (s *Screen) DrawPlayer(player *entities.Player, view transform.View, tint color.Color) {
+	s.backend().DrawPlayer(s.image, player, view, tint)
+}
+
+// DrawBullet рисует пулю текущим бэкендом
+func (s *Screen) DrawBullet(bullet *entities.Bullet, view transform.View) {
+	s.backend().DrawBullet(s.image, bullet, view)
+}
+
+// DrawNPC рисует NPC текущим бэкендом
+func (s *Screen) DrawNPC(npc *entities.NPC, view transform.View) {
+	s.backend().DrawNPC(s.image, npc, view)
+}
+
+// DrawExit рисует выход с уровня текущим бэкендом
+func (s *Screen) DrawExit(x, y, width, height float64, view transform.View) {
+	s.backend().DrawExit(s.image, x, y, width, height, view)
+}
+
+// DrawHealthBar рисует полоску здоровья над персонажем
+func (s *Screen) DrawHealthBar(player *entities.Player, view transform.View) {
+	DrawHealthBar(s.image, player, view)
+}
+
+// DrawBase рисует базу команды
+func (s *Screen) DrawBase(x, y, width, height float64, team int, view transform.View) {
+	DrawBaseWithCamera(s.image, x, y, width, height, team, view)
+}
+
+// DrawFlag рисует флаг команды
+func (s *Screen) DrawFlag(x, y, width, height float64, team int, view transform.View) {
+	DrawFlagWithCamera(s.image, x, y, width, height, team, view)
+}
+
+// DrawSpeech рисует реплику над точкой мира
+func (s *Screen) DrawSpeech(text string, x, y float64, view transform.View) {
+	DrawSpeechWithCamera(s.image, text, x, y, view)
+}
+
+// DrawNameTag рисует имя над персонажем
+func (s *Screen) DrawNameTag(name string, player *entities.Player, view transform.View, clr color.Color) {
+	DrawNameTag(s.image, name, player, view, clr)
+}
+
+// DrawEmoteBubble рисует облачко эмоции
+func (s *Screen) DrawEmoteBubble(text string, x, y float64, view transform.View, progress float64) {
+	DrawEmoteBubble(s.image, text, x, y, view, progress)
+}
+
+// DrawTracer рисует след выстрела
+func (s *Screen) DrawTracer(view transform.View, x1, y1, x2, y2, alpha float64) {
+	DrawTracer(s.image, view, x1, y1, x2, y2, alpha)
+}
+
+// DrawFloatingText рисует всплывающую надпись
+func (s *Screen) DrawFloatingText(view transform.View, text string, x, y, alpha float64) {
+	DrawFloatingText(s.image, view, text, x, y, alpha)
+}
+
+// DrawHitMarker рисует отметку попадания
+func (s *Screen) DrawHitMarker(view transform.View, x, y, alpha float64) {
+	DrawHitMarker(s.image, view, x, y, alpha)
+}
+
+// DrawWeather рисует осадки
+func (s *Screen) DrawWeather(system *weather.System, view transform.View) {
+	DrawWeather(s.image, system, view)
+}
+
+// DrawPhysicsDebug рисует отладочный слой физики
+func (s *Screen) DrawPhysicsDebug(view transform.View, debug canvas.PhysicsDebug) {
+	DrawPhysicsDebug(s.image, view, debug)
+}
+
+// DrawLighting накладывает карту освещения, создавая ее при первой темной комнате
+func (s *Screen) DrawLighting(view transform.View, ambient float64, lights []level.Light) {
+	if s.cache.lightmap == nil {
+		s.cache.lightmap = NewLightmap()
+	}
+	s.cache.lightmap.Draw(s.image, view, ambient, lights)
+}
+
+// DrawMinimap рисует миникарту, перестраивая ее изображение при смене revision
+func (s *Screen) DrawMinimap(revision int, bounds level.Rect, platforms, moving []*entities.Platform, view transform.View, markers []canvas.MinimapMarker) {
+	c := s.cache
+	if c.minimap != nil && c.minimapRevision != revision {
+		c.minimap.Dispose()
+		c.minimap = nil
+	}
+	if c.minimap == nil {
+		isMoving := make(map[*entities.Platform]bool, len(moving))
+		for _, platform := range moving {
+			isMoving[platform] = true
+		}
+		static := make([]*entities.Platform, 0, len(platforms))
+		for _, platform := range platforms {
+			if !isMoving[platform] {
+				static = append(static, platform)
+			}
+		}
+		c.minimap = NewMinimap(bounds, static)
+		c.minimapRevision = revision
+	}
+	c.minimap.Draw(s.image, view, moving, markers)
+}
+
+// DrawBanner рисует крупную надпись по центру
+func (s *Screen) DrawBanner(text string) {
+	DrawBanner(s.image, text)
+}
+
+// DrawCollapseFront рисует границу обрушения
+func (s *Screen) DrawCollapseFront() {
+	DrawCollapseFront(s.image)
+}
+
+// DrawConsole рисует отладочную консоль
+func (s *Screen) DrawConsole(lines []string, input string) {
+	DrawConsole(s.image, lines, input)
+}
+
+// DrawHUD рисует подсказки и здоровье
+func (s *Screen) DrawHUD(player *entities.Player) {
+	DrawHUD(s.image, player)
+}
+
+// DrawWeaponInfo рисует текущее оружие
+func (s *Screen) DrawWeaponInfo(name string) {
+	DrawWeaponInfo(s.image, name)
+}
+
+// DrawDebugPage рисует страницу отладочной информации
+func (s *Screen) DrawDebugPage(title string, lines []string) {
+	DrawDebugPage(s.image, title, lines)
+}
+
+// DrawNetworkStatus рисует строку состояния сети
+func (s *Screen) DrawNetworkStatus(text string) {
+	DrawNetworkStatus(s.image, text)
+}
+
+// DrawConnectionInfo рисует пинг и индикатор качества соединения
+func (s *Screen) DrawConnectionInfo(pingMs, lossPercent, ageMs int, quality canvas.ConnectionQuality) {
+	DrawConnectionInfo(s.image, pingMs, lossPercent, ageMs, quality)
+}
+
+// DrawMatchInfo рисует счет сетевого матча
+func (s *Screen) DrawMatchInfo(localScore, remoteScore, secondsLeft int, isHost bool) {
+	DrawMatchInfo(s.image, localScore, remoteScore, secondsLeft, isHost)
+}
+
+// DrawCoopInfo рисует этап кооперативной кампании
+func (s *Screen) DrawCoopInfo(stage, stages int) {
+	DrawCoopInfo(s.image, stage, stages)
+}
+
+// DrawDeathmatchInfo рисует счет раунда дезматча
+func (s *Screen) DrawDeathmatchInfo(round, localKills, remoteKills, limit int) {
+	DrawDeathmatchInfo(s.image, round, localKills, remoteKills, limit)
+}
+
+// DrawCTFInfo рисует счет захвата флага
+func (s *Screen) DrawCTFInfo(red, blue, limit, secondsLeft int) {
+	DrawCTFInfo(s.image, red, blue, limit, secondsLeft)
+}
+
+// DrawScoreboard рисует таблицу счета
+func (s *Screen) DrawScoreboard(title string, rows []canvas.ScoreboardRow) {
+	DrawScoreboard(s.image, title, rows)
+}
+
+// DrawSpeedrun рисует таймер спидрана
+func (s *Screen) DrawSpeedrun(elapsed time.Duration, splits []speedrun.Split, finished bool) {
+	DrawSpeedrun(s.image, elapsed, splits, finished)
+}
+
+// DrawSplitDivider рисует границу половин разделенного экрана
+func (s *Screen) DrawSplitDivider() {
+	DrawSplitDivider(s.image)
+}
+
+// DrawLoading рисует экран загрузки
+func (s *Screen) DrawLoading(progress float64, current string) {
+	DrawLoading(s.image, progress, current)
+}
+
+// DrawMenu рисует меню с подсказкой по умолчанию
+func (s *Screen) DrawMenu(title string, items []string, selected int) {
+	DrawMenu(s.image, title, items, selected)
+}
+
+// DrawMenuWithHint рисует меню со своей подсказкой
+func (s *Screen) DrawMenuWithHint(title string, items []string, selected int, hint string) {
+	DrawMenuWithHint(s.image, title, items, selected, hint)
+}
+
+// DrawLeaderboard рисует таблицы рекордов
+func (s *Screen) DrawLeaderboard(sections []canvas.LeaderboardSection, status string) {
+	DrawLeaderboard(s.image, sections, status)
+}
+
+// DrawLobby рисует лобби сетевой игры
+func (s *Screen) DrawLobby(players []canvas.LobbyPlayer, rules, status, hint string) {
+	DrawLobby(s.image, players, rules, status, hint)
+}
+
+// DrawHalf рисует draw в изображение половины index и накладывает его на кадр
+func (s *Screen) DrawHalf(index int, draw canvas.DrawFunc) {
+	half := s.cache.halves[index]
+	if half == nil {
+		half = &Screen{image: ebiten.NewImage(config.ScreenWidth/2, config.ScreenHeight), cache: s.cache}
+		s.cache.halves[index] = half
+	}
+	half.image.Clear()
+	draw(half)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(index)*config.ScreenWidth/2, 0)
+	s.image.DrawImage(half.image, op)
+}
+
+// ReadPixels копирует кадр в dst; для меньшего dst кадр сначала уменьшается
+func (s *Screen) ReadPixels(dst *image.RGBA) {
+	if dst.Bounds().Size() == s.image.Bounds().Size() {
+		s.image.ReadPixels(dst.Pix)
+		return
+	}
+
+	width, height := dst.Bounds().Dx(), dst.Bounds().Dy()
+	c := s.cache
+	if c.scaled == nil || c.scaled.Bounds().Dx() != width || c.scaled.Bounds().Dy() != height {
+		c.scaled = ebiten.NewImage(width, height)
+	}
+	bounds := s.image.Bounds()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(width)/float64(bounds.Dx()), float64(height)/float64(bounds.Dy()))
+	op.Filter = ebiten.FilterLinear
+	c.scaled.DrawImage(s.image, op)
+	c.scaled.ReadPixels(dst.Pix)
+}
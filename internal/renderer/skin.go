@@ -0,0 +1,43 @@
+package renderer
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"platformer/internal/config"
+)
+
+// playerSkin — пользовательский скин персонажа (nil - встроенный спрайт)
+var playerSkin *ebiten.Image
+
+// LoadSkin загружает скин персонажа из PNG-файла path. Размер скина должен совпадать
+// с размером персонажа, иначе спрайт не совпадет с хитбоксом.
+func LoadSkin(path string) (*ebiten.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("skin: %w", err)
+	}
+	defer file.Close()
+
+	decoded, err := png.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("skin %s: %w", path, err)
+	}
+	size := decoded.Bounds().Size()
+	if size.X != config.PlayerWidth || size.Y != config.PlayerHeight {
+		return nil, fmt.Errorf("skin %s: size %dx%d, want %dx%d",
+			path, size.X, size.Y, config.PlayerWidth, config.PlayerHeight)
+	}
+	return ebiten.NewImageFromImage(decoded), nil
+}
+
+// SetPlayerSkin задает скин персонажа; nil возвращает встроенный спрайт
+func SetPlayerSkin(skin *ebiten.Image) {
+	if playerSkin != nil && playerSkin != skin {
+		playerSkin.Dispose()
+	}
+	playerSkin = skin
+}
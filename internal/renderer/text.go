@@ -0,0 +1,140 @@
+package renderer
+
+import (
+	"bytes"
+	"image/color"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+
+	"platformer/internal/assets"
+	"platformer/internal/config"
+)
+
+// Align — выравнивание надписи по горизонтали относительно точки привязки
+type Align int
+
+const (
+	AlignLeft   Align = iota // Точка привязки - левый край надписи
+	AlignCenter              // Точка привязки - середина надписи
+	AlignRight               // Точка привязки - правый край надписи
+)
+
+// TextStyle — оформление надписи
+type TextStyle struct {
+	Size  float64     // Кегль в пикселях (0 - config.FontSizeHUD)
+	Align Align       // Выравнивание по горизонтали
+	Color color.Color // Цвет текста (nil - белый)
+	Mono  bool        // Моноширинный шрифт: для таблиц и таймеров, где столбцы должны совпадать
+}
+
+// Оформление надписей интерфейса
+var (
+	hudStyle      = TextStyle{Size: config.FontSizeHUD}
+	menuStyle     = TextStyle{Size: config.FontSizeMenu}
+	titleStyle    = TextStyle{Size: config.FontSizeTitle}
+	tableStyle    = TextStyle{Size: config.FontSizeMenu, Mono: true}
+	dialogueStyle = TextStyle{Size: config.FontSizeDialogue, Align: AlignCenter, Color: color.Black}
+	bannerStyle   = TextStyle{Size: config.FontSizeBanner, Align: AlignCenter}
+)
+
+// Шрифты загружаются один раз: из шрифта создаются начертания нужных размеров
+var (
+	fontsOnce   sync.Once
+	regularFont *text.GoTextFaceSource // Основной шрифт (nil - не загрузился)
+	monoFont    *text.GoTextFaceSource // Моноширинный шрифт (nil - не загрузился)
+	fontsErr    error
+)
+
+// LoadFonts загружает встроенные шрифты заранее, во время загрузки игры.
+// Если шрифты не загрузились, текст выводится отладочным шрифтом ebiten.
+func LoadFonts() error {
+	fontsOnce.Do(func() {
+		if regularFont, fontsErr = loadFont("go-regular"); fontsErr != nil {
+			return
+		}
+		monoFont, fontsErr = loadFont("go-mono")
+	})
+	return fontsErr
+}
+
+// loadFont создает источник начертаний из встроенного TTF-шрифта name
+func loadFont(name string) (*text.GoTextFaceSource, error) {
+	data, err := assets.GetFont(name)
+	if err != nil {
+		return nil, err
+	}
+	return text.NewGoTextFaceSource(bytes.NewReader(data))
+}
+
+// face возвращает начертание для стиля или nil, если шрифты недоступны
+func (s TextStyle) face() text.Face {
+	if LoadFonts() != nil {
+		return nil
+	}
+	source := regularFont
+	if s.Mono {
+		source = monoFont
+	}
+	size := s.Size
+	if size == 0 {
+		size = config.FontSizeHUD
+	}
+	return &text.GoTextFace{Source: source, Size: size}
+}
+
+// lineSpacing возвращает расстояние между строками многострочной надписи
+func (s TextStyle) lineSpacing() float64 {
+	if s.Size == 0 {
+		return config.FontSizeHUD * 1.25
+	}
+	return s.Size * 1.25
+}
+
+// DrawText выводит надпись str; (x, y) - верх строки, по горизонтали - точка привязки
+// по выравниванию стиля. Строки разделяются символом '\n'.
+func DrawText(screen *ebiten.Image, str string, x, y float64, style TextStyle) {
+	face := style.face()
+	if face == nil {
+		// Запасной вариант без шрифтов: отладочный шрифт ebiten
+		width, _ := MeasureText(str, style)
+		switch style.Align {
+		case AlignCenter:
+			x -= width / 2
+		case AlignRight:
+			x -= width
+		}
+		ebitenutil.DebugPrintAt(screen, str, int(x), int(y))
+		return
+	}
+
+	op := &text.DrawOptions{}
+	op.GeoM.Translate(x, y)
+	op.LineSpacing = style.lineSpacing()
+	switch style.Align {
+	case AlignCenter:
+		op.PrimaryAlign = text.AlignCenter
+	case AlignRight:
+		op.PrimaryAlign = text.AlignEnd
+	}
+	if style.Color != nil {
+		op.ColorScale.ScaleWithColor(style.Color)
+	}
+	text.Draw(screen, str, face, op)
+}
+
+// MeasureText возвращает ширину и высоту надписи str в пикселях
+func MeasureText(str string, style TextStyle) (width, height float64) {
+	face := style.face()
+	if face == nil {
+		// Ширина символа отладочного шрифта - 6 пикселей, высота строки - 16
+		lines := bytes.Split([]byte(str), []byte("\n"))
+		for _, line := range lines {
+			width = max(width, float64(len(bytes.Runes(line))*6))
+		}
+		return width, float64(len(lines) * 16)
+	}
+	return text.Measure(str, face, style.lineSpacing())
+}
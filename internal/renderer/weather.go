@@ -0,0 +1,50 @@
+package renderer
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"platformer/internal/config"
+	"platformer/internal/level"
+	"platformer/internal/transform"
+	"platformer/internal/weather"
+)
+
+// Цвета частиц погоды
+var (
+	rainColor = color.RGBA{R: 150, G: 170, B: 210, A: 170}
+	snowColor = color.RGBA{R: 245, G: 245, B: 255, A: 230}
+)
+
+// DrawWeather рисует частицы погоды. Плитка с частицами привязана к миру и повторяется,
+// поэтому при движении камеры дождь и снег смещаются вместе с уровнем.
+func DrawWeather(screen *ebiten.Image, system *weather.System, view transform.View) {
+	if system == nil {
+		return
+	}
+	scale := view.Scale()
+	for _, p := range system.Particles() {
+		x := float32(tileOffset(p.X-view.X, config.ScreenWidth) * scale)
+		y := float32(tileOffset(p.Y-view.Y, config.ScreenHeight) * scale)
+		switch system.Kind() {
+		case level.WeatherRain:
+			// Капля - короткий штрих вдоль направления падения
+			length := float32(2 * scale)
+			vector.StrokeLine(screen, x, y, x-float32(p.VX)*length, y-float32(p.VY)*length, float32(p.Size*scale), rainColor, false)
+		case level.WeatherSnow:
+			vector.DrawFilledCircle(screen, x, y, float32(p.Size*scale), snowColor, true)
+		}
+	}
+}
+
+// tileOffset возвращает положение частицы внутри повторяющейся плитки размером size
+func tileOffset(v, size float64) float64 {
+	v = math.Mod(v, size)
+	if v < 0 {
+		v += size
+	}
+	return v
+}
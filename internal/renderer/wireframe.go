@@ -0,0 +1,91 @@
+package renderer
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"platformer/internal/entities"
+	"platformer/internal/transform"
+)
+
+// Цвета отладочного бэкенда: у каждого типа объектов свой плоский цвет
+var (
+	wireBackgroundColor = color.RGBA{R: 20, G: 20, B: 28, A: 255}
+	wirePlatformColor   = color.RGBA{R: 200, G: 200, B: 200, A: 255}
+	wirePlayerColor     = color.RGBA{R: 80, G: 160, B: 255, A: 255}
+	wireBulletColor     = color.RGBA{R: 255, G: 220, B: 0, A: 255}
+	wireNPCColor        = color.RGBA{R: 80, G: 255, B: 80, A: 255}
+	wireExitColor       = color.RGBA{R: 255, G: 80, B: 255, A: 255}
+)
+
+// WireframeRenderer — отладочный бэкенд: рисует только контуры хитбоксов
+// плоскими цветами и линию направления взгляда, без спрайтов
+type WireframeRenderer struct{}
+
+// NewWireframeRenderer создает отладочный бэкенд отрисовки
+func NewWireframeRenderer() *WireframeRenderer {
+	return &WireframeRenderer{}
+}
+
+// Name возвращает название бэкенда
+func (r *WireframeRenderer) Name() string {
+	return "wireframe"
+}
+
+// DrawBackground заливает экран темным фоном, чтобы контуры были хорошо видны
+func (r *WireframeRenderer) DrawBackground(screen *ebiten.Image) {
+	screen.Fill(wireBackgroundColor)
+}
+
+// DrawPlatform рисует контур платформы
+func (r *WireframeRenderer) DrawPlatform(screen *ebiten.Image, platform *entities.Platform, view transform.View) {
+	strokeBox(screen, view, platform.X, platform.Y, platform.Width, platform.Height, wirePlatformColor)
+}
+
+// DrawPlayer рисует хитбокс персонажа и направление взгляда; в сетевой игре - цветом игрока
+func (r *WireframeRenderer) DrawPlayer(screen *ebiten.Image, player *entities.Player, view transform.View, tint color.Color) {
+	var clr color.Color = wirePlayerColor
+	if tint != nil {
+		clr = tint
+	}
+	strokeBox(screen, view, player.X, player.Y, player.Width, player.Height, clr)
+	drawFacing(screen, view, player.X, player.Y, player.Width, player.Height, player.FacingRight, clr)
+}
+
+// DrawBullet рисует пулю залитым прямоугольником
+func (r *WireframeRenderer) DrawBullet(screen *ebiten.Image, bullet *entities.Bullet, view transform.View) {
+	x, y, width, height := view.RectToScreen(bullet.X, bullet.Y, bullet.Width, bullet.Height)
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), wireBulletColor, false)
+}
+
+// DrawNPC рисует хитбокс NPC и направление взгляда
+func (r *WireframeRenderer) DrawNPC(screen *ebiten.Image, npc *entities.NPC, view transform.View) {
+	strokeBox(screen, view, npc.X, npc.Y, npc.Width, npc.Height, wireNPCColor)
+	drawFacing(screen, view, npc.X, npc.Y, npc.Width, npc.Height, npc.FacingRight, wireNPCColor)
+}
+
+// DrawExit рисует контур зоны выхода
+func (r *WireframeRenderer) DrawExit(screen *ebiten.Image, x, y, width, height float64, view transform.View) {
+	strokeBox(screen, view, x, y, width, height, wireExitColor)
+}
+
+// strokeBox рисует контур прямоугольника, заданного в мировых координатах
+func strokeBox(screen *ebiten.Image, view transform.View, x, y, width, height float64, clr color.Color) {
+	x, y, width, height = view.RectToScreen(x, y, width, height)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(height), 1, clr, false)
+}
+
+// drawFacing рисует линию от центра объекта в сторону его взгляда
+func drawFacing(screen *ebiten.Image, view transform.View, x, y, width, height float64, facingRight bool, clr color.Color) {
+	centerX := x + width/2
+	centerY := y + height/2
+	endX := centerX + width/2
+	if !facingRight {
+		endX = centerX - width/2
+	}
+	fromX, fromY := view.WorldToScreen(centerX, centerY)
+	toX, toY := view.WorldToScreen(endX, centerY)
+	vector.StrokeLine(screen, float32(fromX), float32(fromY), float32(toX), float32(toY), 1, clr, false)
+}
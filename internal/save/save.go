@@ -0,0 +1,92 @@
+package save
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"platformer/internal/entities"
+	"platformer/internal/level"
+)
+
+// Version — версия формата файла сохранения. Файлы другой версии не загружаются.
+const Version = 1
+
+// DefaultQuickSave — имя файла быстрого сохранения по умолчанию
+const DefaultQuickSave = "quicksave.json"
+
+// State — сохраненное состояние прохождения уровня.
+// Сущности хранятся в общей кодировке entities.Record.
+type State struct {
+	Version   int    `json:"version"`
+	LevelPath string `json:"level_path,omitempty"` // Файл уровня (пустой - встроенный уровень)
+	LevelName string `json:"level_name"`
+	Frames    int    `json:"frames"` // Сколько кадров шло прохождение
+	Score     int    `json:"score"`  // Очки на момент сохранения (для справки)
+	Weapon    int    `json:"weapon"`
+
+	Player     entities.Record   `json:"player"`
+	NPCs       []entities.Record `json:"npcs,omitempty"`   // Живые NPC текущей комнаты
+	Rooms      []Room            `json:"rooms,omitempty"`  // NPC комнат, из которых ушел игрок
+	Escort     *Escort           `json:"escort,omitempty"` // Задание сопровождения
+	Checkpoint *level.Point      `json:"checkpoint,omitempty"`
+
+	SpentTriggers []string `json:"spent_triggers,omitempty"` // Уже сработавшие одноразовые триггеры
+}
+
+// Room — живые NPC комнаты, в которой игрок уже побывал: при возвращении
+// в нее появляются они, а не NPC из данных уровня
+type Room struct {
+	Index int               `json:"index"` // Индекс комнаты в уровне
+	NPCs  []entities.Record `json:"npcs"`
+}
+
+// Escort — состояние задания сопровождения
+type Escort struct {
+	NPC   entities.Record `json:"npc"`
+	State int             `json:"state"`
+}
+
+// Write записывает состояние в файл path. Данные сначала пишутся во временный файл,
+// чтобы прерванная запись не испортила предыдущее сохранение.
+func Write(path string, state State) error {
+	state.Version = Version
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("save: encode: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("save: %w", err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("save: write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("save: write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Read загружает состояние из файла path
+func Read(path string) (State, error) {
+	var state State
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, fmt.Errorf("save: read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("save: parse %s: %w", path, err)
+	}
+	if state.Version != Version {
+		return state, fmt.Errorf("save: %s has version %d, expected %d", path, state.Version, Version)
+	}
+	if state.Player.Kind != entities.KindPlayer {
+		return state, fmt.Errorf("save: %s has no player", path)
+	}
+	return state, nil
+}
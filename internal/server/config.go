@@ -0,0 +1,136 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Выделенный сервер удобнее настраивать файлом, чем длинной командной строкой:
+// -server server.json задает порт, пароль, смену уровней, частоту снимков
+// и правила матча. Каждое поле файла - значение одноименного флага командной
+// строки, поэтому флаг, указанный явно, имеет приоритет над файлом.
+//
+//	{
+//	  "port": 4000,
+//	  "name": "Arena",
+//	  "max_players": 2,
+//	  "password": "secret",
+//	  "levels": ["rooms", "ctf"],
+//	  "snapshot_rate": 30,
+//	  "match": "ctf",
+//	  "score_limit": 5,
+//	  "friendly_fire": false
+//	}
+
+// MaxSnapshotRate — наибольшая частота снимков: хост делает 60 шагов в секунду,
+// и снимок чаще шага не отправить. Частота самой симуляции не настраивается.
+const MaxSnapshotRate = 60
+
+// Config — настройки сервера из файла
+type Config struct {
+	Address      string   `json:"address,omitempty"`       // Адрес для клиентов, как у -addr (например, ":4000" или relay://...)
+	Port         int      `json:"port,omitempty"`          // Порт TCP на всех интерфейсах, если адрес не задан
+	WebSocket    string   `json:"websocket,omitempty"`     // Адрес для клиентов по WebSocket, как у -ws
+	Name         string   `json:"name,omitempty"`          // Имя хоста, которое видят клиенты
+	MaxPlayers   int      `json:"max_players,omitempty"`   // Сколько игроков вмещает сервер вместе с хостом
+	Password     string   `json:"password,omitempty"`      // Пароль, без которого клиентов не пускают
+	Levels       []string `json:"levels,omitempty"`        // Уровни по очереди: первый в начале, следующий - после каждого матча
+	SnapshotRate int      `json:"snapshot_rate,omitempty"` // Сколько снимков в секунду хост отправляет клиентам (0 - каждый шаг)
+	Match        string   `json:"match,omitempty"`         // Вид сетевой игры, как у -match
+	ScoreLimit   int      `json:"score_limit,omitempty"`   // Сколько убийств или захватов нужно для победы
+	FriendlyFire bool     `json:"friendly_fire,omitempty"` // Пули ранят игроков своей команды
+	Rcon         string   `json:"rcon,omitempty"`          // Адрес удаленного управления, как у -rcon
+	RconPassword string   `json:"rcon_password,omitempty"` // Пароль удаленного управления
+}
+
+// Load читает настройки сервера из JSON-файла path и проверяет их
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	return Parse(data)
+}
+
+// Parse разбирает настройки сервера из JSON. Неизвестные поля считаются
+// ошибкой, чтобы опечатка в имени не оставила настройку без действия.
+func Parse(data []byte) (Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("parse server config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate проверяет, что настройки согласованы
+func (c Config) Validate() error {
+	if c.Address != "" && c.Port != 0 {
+		return errors.New("server: set either address or port, not both")
+	}
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("server: port %d is out of range", c.Port)
+	}
+	if c.MaxPlayers < 0 {
+		return errors.New("server: max_players must not be negative")
+	}
+	if c.SnapshotRate < 0 || c.SnapshotRate > MaxSnapshotRate {
+		return fmt.Errorf("server: snapshot_rate must be between 0 (every step) and %d", MaxSnapshotRate)
+	}
+	if c.ScoreLimit < 0 {
+		return errors.New("server: score_limit must not be negative")
+	}
+	for _, lvl := range c.Levels {
+		if strings.TrimSpace(lvl) == "" || strings.Contains(lvl, ",") {
+			return fmt.Errorf("server: bad level name %q", lvl)
+		}
+	}
+	if c.Rcon != "" && c.RconPassword == "" {
+		return errors.New("server: rcon needs rcon_password")
+	}
+	return nil
+}
+
+// Flags возвращает заданные в файле настройки как значения флагов командной строки
+func (c Config) Flags() map[string]string {
+	flags := make(map[string]string)
+	set := func(name, value string) {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+	address := c.Address
+	if c.Port != 0 {
+		address = ":" + strconv.Itoa(c.Port)
+	}
+	set("addr", address)
+	set("ws", c.WebSocket)
+	set("name", c.Name)
+	set("password", c.Password)
+	set("match", c.Match)
+	set("rcon", c.Rcon)
+	set("rcon-password", c.RconPassword)
+	set("rotation", strings.Join(c.Levels, ","))
+	if c.MaxPlayers != 0 {
+		set("max-players", strconv.Itoa(c.MaxPlayers))
+	}
+	if c.SnapshotRate != 0 {
+		set("snapshot-rate", strconv.Itoa(c.SnapshotRate))
+	}
+	if c.ScoreLimit != 0 {
+		set("score-limit", strconv.Itoa(c.ScoreLimit))
+	}
+	if c.FriendlyFire {
+		set("friendly-fire", "true")
+	}
+	return flags
+}
@@ -0,0 +1,43 @@
+package sound
+
+import "platformer/internal/config"
+
+// Effect — короткий звуковой эффект игрового события
+type Effect string
+
+const (
+	EffectJump   Effect = "jump"   // Прыжок
+	EffectShoot  Effect = "shoot"  // Выстрел
+	EffectHit    Effect = "hit"    // Попадание по персонажу или NPC
+	EffectLand   Effect = "land"   // Приземление
+	EffectPickup Effect = "pickup" // Подбор предмета или контрольной точки
+)
+
+// Volume — настройки громкости: общая, музыки и эффектов (от 0 до 1) и выключение звука
+type Volume struct {
+	Master  float64
+	Music   float64
+	Effects float64
+	Muted   bool
+}
+
+// DefaultVolume возвращает громкость по умолчанию
+func DefaultVolume() Volume {
+	return Volume{Master: config.MasterVolume, Music: config.MusicVolume, Effects: config.EffectsVolume}
+}
+
+// MusicLevel возвращает итоговую громкость музыки
+func (v Volume) MusicLevel() float64 {
+	if v.Muted {
+		return 0
+	}
+	return v.Master * v.Music
+}
+
+// EffectsLevel возвращает итоговую громкость эффектов
+func (v Volume) EffectsLevel() float64 {
+	if v.Muted {
+		return 0
+	}
+	return v.Master * v.Effects
+}
@@ -0,0 +1,127 @@
+package speedrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Split — отметка времени забега: сколько прошло от старта до конца участка
+type Split struct {
+	Name   string        // Название участка (комната или уровень)
+	Frames int           // Шагов симуляции от старта забега
+	Time   time.Duration // То же время в единицах времени
+}
+
+// Run — таймер спидрана. Время считается в шагах симуляции, поэтому
+// пауза и заморозка игры (например, переход между комнатами) его не двигают,
+// если в это время не вызывается Tick.
+type Run struct {
+	level    string
+	step     time.Duration // Длительность одного шага симуляции
+	frames   int
+	splits   []Split
+	finished bool
+}
+
+// NewRun создает таймер забега по уровню level; step — длительность шага симуляции
+func NewRun(level string, step time.Duration) *Run {
+	return &Run{level: level, step: step}
+}
+
+// Tick продвигает таймер на один шаг симуляции
+func (r *Run) Tick() {
+	if r.finished {
+		return
+	}
+	r.frames++
+}
+
+// Elapsed возвращает время от старта забега
+func (r *Run) Elapsed() time.Duration {
+	return time.Duration(r.frames) * r.step
+}
+
+// Split отмечает конец участка name
+func (r *Run) Split(name string) {
+	if r.finished {
+		return
+	}
+	r.splits = append(r.splits, Split{Name: name, Frames: r.frames, Time: r.Elapsed()})
+}
+
+// Finish отмечает последний участок и останавливает таймер.
+// Возвращает false, если забег уже был закончен.
+func (r *Run) Finish(name string) bool {
+	if r.finished {
+		return false
+	}
+	r.Split(name)
+	r.finished = true
+	return true
+}
+
+// Finished сообщает, закончен ли забег
+func (r *Run) Finished() bool {
+	return r.finished
+}
+
+// Splits возвращает отмеченные участки в порядке прохождения
+func (r *Run) Splits() []Split {
+	return r.splits
+}
+
+// result — запись о забеге в файле результатов
+type result struct {
+	Level    string        `json:"level"`
+	Finished time.Time     `json:"finished"`
+	TotalMs  int64         `json:"total_ms"`
+	Frames   int           `json:"frames"`
+	Splits   []splitResult `json:"splits"`
+}
+
+// splitResult — участок забега в файле результатов
+type splitResult struct {
+	Name      string `json:"name"`
+	ElapsedMs int64  `json:"elapsed_ms"` // От старта забега
+	SegmentMs int64  `json:"segment_ms"` // Длительность самого участка
+}
+
+// Append дописывает забег строкой JSON в конец файла path,
+// чтобы в файле накапливалась история всех забегов
+func (r *Run) Append(path string, now time.Time) error {
+	record := result{
+		Level:    r.level,
+		Finished: now.UTC(),
+		TotalMs:  r.Elapsed().Milliseconds(),
+		Frames:   r.frames,
+		Splits:   make([]splitResult, 0, len(r.splits)),
+	}
+	var previous time.Duration
+	for _, split := range r.splits {
+		record.Splits = append(record.Splits, splitResult{
+			Name:      split.Name,
+			ElapsedMs: split.Time.Milliseconds(),
+			SegmentMs: (split.Time - previous).Milliseconds(),
+		})
+		previous = split.Time
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("speedrun: encode: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("speedrun: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("speedrun: write %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("speedrun: write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package timer
+
+// ID идентифицирует таймер внутри Manager.
+type ID uint64
+
+// entry описывает один зарегистрированный таймер.
+type entry struct {
+	id        ID
+	remaining int    // Сколько кадров осталось до срабатывания
+	interval  int    // Интервал повторения (для повторяющихся таймеров)
+	repeat    bool   // Повторяется ли таймер после срабатывания
+	callback  func() // Функция, вызываемая при срабатывании
+	cancelled bool   // Таймер отменен и будет удален при следующем Update
+}
+
+// Manager управляет таймерами, которые отсчитывают время в кадрах (тиках).
+// Manager не зависит от ebiten и реального времени, поэтому его можно
+// использовать и проверять без окна: каждый вызов Update — это один тик.
+type Manager struct {
+	nextID   ID
+	timers   []*entry
+	updating bool // Идет Update: Clear только помечает таймеры отмененными
+}
+
+// NewManager создает пустой менеджер таймеров.
+func NewManager() *Manager {
+	return &Manager{
+		timers: make([]*entry, 0),
+	}
+}
+
+// After регистрирует одноразовый таймер, который вызовет callback через ticks кадров.
+// Значение ticks меньше 1 трактуется как 1 (срабатывание в ближайшем Update).
+func (m *Manager) After(ticks int, callback func()) ID {
+	return m.add(ticks, false, callback)
+}
+
+// Every регистрирует повторяющийся таймер, который вызывает callback каждые ticks кадров.
+func (m *Manager) Every(ticks int, callback func()) ID {
+	return m.add(ticks, true, callback)
+}
+
+func (m *Manager) add(ticks int, repeat bool, callback func()) ID {
+	if ticks < 1 {
+		ticks = 1
+	}
+
+	m.nextID++
+	m.timers = append(m.timers, &entry{
+		id:        m.nextID,
+		remaining: ticks,
+		interval:  ticks,
+		repeat:    repeat,
+		callback:  callback,
+	})
+
+	return m.nextID
+}
+
+// Cancel отменяет таймер. Возвращает false, если таймер не найден или уже завершен.
+func (m *Manager) Cancel(id ID) bool {
+	if t := m.find(id); t != nil {
+		t.cancelled = true
+		return true
+	}
+	return false
+}
+
+// Active сообщает, ожидает ли таймер срабатывания.
+func (m *Manager) Active(id ID) bool {
+	return m.find(id) != nil
+}
+
+// Remaining возвращает количество кадров до срабатывания таймера (0, если таймер не активен).
+func (m *Manager) Remaining(id ID) int {
+	if t := m.find(id); t != nil {
+		return t.remaining
+	}
+	return 0
+}
+
+// Len возвращает количество активных таймеров.
+func (m *Manager) Len() int {
+	count := 0
+	for _, t := range m.timers {
+		if !t.cancelled {
+			count++
+		}
+	}
+	return count
+}
+
+// Clear отменяет все таймеры. Внутри callback таймеры только помечаются
+// отмененными, и до конца Update ни один из них уже не сработает.
+func (m *Manager) Clear() {
+	for _, t := range m.timers {
+		t.cancelled = true
+	}
+	if !m.updating {
+		m.timers = m.timers[:0]
+	}
+}
+
+// Update продвигает все таймеры на один кадр и вызывает сработавшие callback-функции.
+// Таймеры, созданные внутри callback, начинают отсчет со следующего Update.
+// Callback может отменить любой таймер через Cancel или Clear: отмена только
+// помечает запись, поэтому таймер, до которого очередь еще не дошла, не сработает.
+func (m *Manager) Update() {
+	m.updating = true
+	defer func() { m.updating = false }()
+
+	// Таймеры, добавленные в callback, попадают в конец списка после count
+	count := len(m.timers)
+	for i := 0; i < count; i++ {
+		t := m.timers[i]
+		if t.cancelled {
+			continue
+		}
+
+		t.remaining--
+		if t.remaining > 0 {
+			continue
+		}
+
+		if t.repeat {
+			// Повторяющийся таймер перезапускается до вызова callback,
+			// чтобы callback мог отменить его через Cancel
+			t.remaining = t.interval
+		} else {
+			t.cancelled = true
+		}
+
+		if t.callback != nil {
+			t.callback()
+		}
+	}
+
+	// Убираем сработавшие и отмененные таймеры
+	active := m.timers[:0]
+	for _, t := range m.timers {
+		if !t.cancelled {
+			active = append(active, t)
+		}
+	}
+	for i := len(active); i < len(m.timers); i++ {
+		m.timers[i] = nil // Не держим удаленные записи в хвосте массива
+	}
+	m.timers = active
+}
+
+func (m *Manager) find(id ID) *entry {
+	for _, t := range m.timers {
+		if t.id == id && !t.cancelled {
+			return t
+		}
+	}
+	return nil
+}
+
+// Cooldown — простой кадровый счетчик перезарядки без callback-функций.
+// Подходит для перезарядки оружия, окон неуязвимости и длительности эффектов,
+// которые хранятся прямо в сущности.
+type Cooldown struct {
+	Duration  int // Длительность перезарядки в кадрах
+	remaining int // Сколько кадров осталось до готовности
+}
+
+// NewCooldown создает перезарядку заданной длительности в состоянии "готово".
+func NewCooldown(duration int) Cooldown {
+	return Cooldown{Duration: duration}
+}
+
+// Ready сообщает, закончилась ли перезарядка.
+func (c *Cooldown) Ready() bool {
+	return c.remaining <= 0
+}
+
+// Trigger запускает перезарядку, если она готова. Возвращает true, если действие разрешено.
+func (c *Cooldown) Trigger() bool {
+	if !c.Ready() {
+		return false
+	}
+	c.remaining = c.Duration
+	return true
+}
+
+// Start запускает перезарядку независимо от текущего состояния.
+func (c *Cooldown) Start() {
+	c.remaining = c.Duration
+}
+
+// Tick уменьшает оставшееся время перезарядки на один кадр.
+func (c *Cooldown) Tick() {
+	if c.remaining > 0 {
+		c.remaining--
+	}
+}
+
+// Remaining возвращает количество кадров до готовности.
+func (c *Cooldown) Remaining() int {
+	return c.remaining
+}
+
+// Reset сбрасывает перезарядку в состояние "готово".
+func (c *Cooldown) Reset() {
+	c.remaining = 0
+}
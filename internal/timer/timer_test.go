@@ -0,0 +1,111 @@
+package timer
+
+import "testing"
+
+// TestAfter проверяет, что одноразовый таймер срабатывает ровно через заданное число тиков
+func TestAfter(t *testing.T) {
+	m := NewManager()
+	fired := 0
+	id := m.After(3, func() { fired++ })
+
+	for i := 0; i < 2; i++ {
+		m.Update()
+	}
+	if fired != 0 || !m.Active(id) || m.Remaining(id) != 1 {
+		t.Fatalf("after 2 ticks: fired=%d active=%v remaining=%d", fired, m.Active(id), m.Remaining(id))
+	}
+	m.Update()
+	m.Update()
+	if fired != 1 || m.Active(id) || m.Len() != 0 {
+		t.Fatalf("after 4 ticks: fired=%d active=%v len=%d", fired, m.Active(id), m.Len())
+	}
+}
+
+// TestEvery проверяет повторяющийся таймер и его отмену из собственного callback
+func TestEvery(t *testing.T) {
+	m := NewManager()
+	fired := 0
+	var id ID
+	id = m.Every(2, func() {
+		fired++
+		if fired == 3 {
+			m.Cancel(id)
+		}
+	})
+
+	for i := 0; i < 10; i++ {
+		m.Update()
+	}
+	if fired != 3 || m.Active(id) {
+		t.Fatalf("fired=%d active=%v, want 3 and inactive", fired, m.Active(id))
+	}
+}
+
+// TestCancelFromCallback проверяет, что callback отменяет таймер,
+// до которого очередь на этом тике еще не дошла
+func TestCancelFromCallback(t *testing.T) {
+	m := NewManager()
+	var later ID
+	laterFired := false
+	m.After(1, func() {
+		if !m.Active(later) {
+			t.Error("pending timer is not active inside a callback")
+		}
+		if !m.Cancel(later) {
+			t.Error("cancel of a pending timer returned false")
+		}
+	})
+	later = m.After(1, func() { laterFired = true })
+
+	m.Update()
+	if laterFired {
+		t.Fatal("cancelled timer fired")
+	}
+	if m.Len() != 0 {
+		t.Fatalf("len=%d after cancel, want 0", m.Len())
+	}
+}
+
+// TestClearFromCallback проверяет, что Clear внутри callback останавливает
+// оставшиеся таймеры тика, а таймеры, созданные после Clear, продолжают работать
+func TestClearFromCallback(t *testing.T) {
+	m := NewManager()
+	rest := 0
+	added := 0
+	m.After(1, func() {
+		m.Clear()
+		m.After(1, func() { added++ })
+	})
+	m.After(1, func() { rest++ })
+	m.Every(1, func() { rest++ })
+
+	m.Update()
+	if rest != 0 {
+		t.Fatalf("%d cleared timers fired", rest)
+	}
+	if m.Len() != 1 {
+		t.Fatalf("len=%d after clear, want the timer added by the callback", m.Len())
+	}
+	m.Update()
+	if added != 1 || rest != 0 || m.Len() != 0 {
+		t.Fatalf("added=%d rest=%d len=%d, want 1, 0, 0", added, rest, m.Len())
+	}
+}
+
+// TestAddFromCallback проверяет, что таймер, созданный в callback, начинает отсчет со следующего тика
+func TestAddFromCallback(t *testing.T) {
+	m := NewManager()
+	fired := 0
+	m.After(1, func() {
+		m.After(1, func() { fired++ })
+	})
+
+	m.Update()
+	if fired != 0 {
+		t.Fatal("timer added in a callback fired on the same tick")
+	}
+	m.Update()
+	if fired != 1 {
+		t.Fatalf("fired=%d, want 1", fired)
+	}
+}
@@ -0,0 +1,69 @@
+package transform
+
+import "platformer/internal/config"
+
+// View описывает, какая часть мира видна на экране: позицию камеры, масштаб и тряску.
+// Перевод мировых координат в экранные выполняется через View, чтобы камера,
+// отрисовка и рамка видимой области на миникарте считали их одинаково.
+type View struct {
+	X, Y float64 // Мировые координаты левого верхнего угла видимой области
+	Zoom float64 // Масштаб (1 - без увеличения; 0 считается за 1)
+
+	// Смещение тряски камеры в экранных пикселях
+	ShakeX, ShakeY float64
+
+	// Размер экрана в пикселях (0 - размер окна игры из конфигурации)
+	ScreenWidth, ScreenHeight float64
+}
+
+// NewView создает вид без масштаба и тряски с левым верхним углом в (x, y)
+func NewView(x, y float64) View {
+	return View{X: x, Y: y, Zoom: 1}
+}
+
+// Scale возвращает масштаб вида
+func (v View) Scale() float64 {
+	if v.Zoom <= 0 {
+		return 1
+	}
+	return v.Zoom
+}
+
+// screenSize возвращает размер экрана в пикселях
+func (v View) screenSize() (float64, float64) {
+	width, height := v.ScreenWidth, v.ScreenHeight
+	if width <= 0 {
+		width = config.ScreenWidth
+	}
+	if height <= 0 {
+		height = config.ScreenHeight
+	}
+	return width, height
+}
+
+// WorldSize возвращает размер видимой области в мировых координатах
+func (v View) WorldSize() (float64, float64) {
+	width, height := v.screenSize()
+	scale := v.Scale()
+	return width / scale, height / scale
+}
+
+// WorldToScreen переводит точку мира в координаты экрана
+func (v View) WorldToScreen(x, y float64) (float64, float64) {
+	scale := v.Scale()
+	return (x-v.X)*scale + v.ShakeX, (y-v.Y)*scale + v.ShakeY
+}
+
+// RectToScreen переводит прямоугольник мира в прямоугольник экрана
+func (v View) RectToScreen(x, y, width, height float64) (float64, float64, float64, float64) {
+	screenX, screenY := v.WorldToScreen(x, y)
+	scale := v.Scale()
+	return screenX, screenY, width * scale, height * scale
+}
+
+// Visible проверяет, попадает ли прямоугольник мира хотя бы частично в видимую область
+func (v View) Visible(x, y, width, height float64) bool {
+	viewWidth, viewHeight := v.WorldSize()
+	return x+width > v.X && x < v.X+viewWidth &&
+		y+height > v.Y && y < v.Y+viewHeight
+}
@@ -0,0 +1,122 @@
+package trigger
+
+import "platformer/internal/entities"
+
+// Phase — момент срабатывания триггера
+type Phase int
+
+const (
+	Enter Phase = iota // Объект вошел в область триггера
+	Exit               // Объект вышел из области триггера
+)
+
+// String возвращает название фазы
+func (p Phase) String() string {
+	switch p {
+	case Enter:
+		return "enter"
+	case Exit:
+		return "exit"
+	default:
+		return "unknown"
+	}
+}
+
+// Volume — несплошная область уровня, которая сообщает о входе и выходе объекта.
+// В отличие от платформ, через триггер можно свободно проходить.
+type Volume struct {
+	ID     string        // Идентификатор из данных уровня (может быть пустым)
+	Kind   string        // Вид триггера: по нему выбираются обработчики
+	Bounds entities.AABB // Область триггера
+	Once   bool          // Сработать только при первом входе (и последующем выходе)
+	Data   any           // Дополнительные данные уровня для обработчика
+
+	inside bool // Объект сейчас внутри области
+	spent  bool // Одноразовый триггер уже сработал
+}
+
+// Inside сообщает, находится ли объект внутри области
+func (v *Volume) Inside() bool {
+	return v.inside
+}
+
+// Spent сообщает, что одноразовый триггер уже сработал
+func (v *Volume) Spent() bool {
+	return v.spent
+}
+
+// Spend отключает одноразовый триггер (например, при загрузке сохранения)
+func (v *Volume) Spend() {
+	v.spent = true
+}
+
+// Handler обрабатывает вход в триггер или выход из него
+type Handler func(volume *Volume, phase Phase)
+
+// Set хранит триггеры уровня и рассылает их события обработчикам по виду триггера
+type Set struct {
+	volumes  []*Volume
+	handlers map[string][]Handler
+}
+
+// NewSet создает пустой набор триггеров
+func NewSet() *Set {
+	return &Set{handlers: make(map[string][]Handler)}
+}
+
+// Add добавляет триггер
+func (s *Set) Add(volume *Volume) {
+	s.volumes = append(s.volumes, volume)
+}
+
+// Clear удаляет все триггеры; обработчики остаются
+func (s *Set) Clear() {
+	s.volumes = s.volumes[:0]
+}
+
+// Volumes возвращает все триггеры (например, для отладочной отрисовки)
+func (s *Set) Volumes() []*Volume {
+	return s.volumes
+}
+
+// On регистрирует обработчик для триггеров вида kind
+func (s *Set) On(kind string, handler Handler) {
+	s.handlers[kind] = append(s.handlers[kind], handler)
+}
+
+// Update проверяет положение объекта и вызывает обработчики для тех триггеров,
+// в которые он вошел или из которых вышел с прошлого вызова
+func (s *Set) Update(actor entities.Collider) {
+	bounds := actor.Bounds()
+
+	// Обработчик может очистить набор (например, при перезапуске уровня),
+	// поэтому перебираем копию списка
+	volumes := append([]*Volume(nil), s.volumes...)
+	for _, volume := range volumes {
+		inside := volume.Bounds.Intersects(bounds)
+		if inside == volume.inside {
+			continue
+		}
+		volume.inside = inside
+
+		if volume.spent {
+			continue
+		}
+		if inside {
+			s.dispatch(volume, Enter)
+		} else {
+			s.dispatch(volume, Exit)
+			// Одноразовый триггер отключается после выхода, чтобы обработчик
+			// получил пару вход/выход (например, урон по таймеру прекращается)
+			if volume.Once {
+				volume.spent = true
+			}
+		}
+	}
+}
+
+func (s *Set) dispatch(volume *Volume, phase Phase) {
+	for _, handler := range s.handlers[volume.Kind] {
+		handler(volume, phase)
+	}
+}
@@ -0,0 +1,108 @@
+package weather
+
+import (
+	"math"
+	"math/rand"
+
+	"platformer/internal/budget"
+	"platformer/internal/config"
+	"platformer/internal/level"
+)
+
+// Particle — капля дождя или снежинка. Координаты лежат в пределах плитки
+// размером с экран, которая повторяется по всему миру.
+type Particle struct {
+	X, Y   float64
+	VX, VY float64 // Скорость за кадр
+	Size   float64
+	phase  float64 // Фаза покачивания снежинки
+}
+
+// System — частицы погоды одного вида
+type System struct {
+	kind      string
+	particles []Particle
+	rng       *rand.Rand
+	frame     int
+}
+
+// New создает частицы погоды w; для ясной погоды возвращает nil.
+// Количество частиц пропорционально интенсивности погоды.
+func New(w *level.Weather) *System {
+	if w == nil || w.Kind == level.WeatherNone {
+		return nil
+	}
+
+	// Фиксированное зерно: осадки выглядят одинаково при каждом входе в комнату
+	s := &System{kind: w.Kind, rng: rand.New(rand.NewSource(1))}
+	count := int(math.Round(config.WeatherMaxParticles * w.Intensity))
+	s.particles = make([]Particle, count)
+	for i := range s.particles {
+		p := &s.particles[i]
+		p.X = s.rng.Float64() * config.ScreenWidth
+		p.Y = s.rng.Float64() * config.ScreenHeight
+		p.phase = s.rng.Float64() * 2 * math.Pi
+		switch w.Kind {
+		case level.WeatherRain:
+			// Дальние капли мельче и медленнее - так дождь выглядит объемным
+			depth := 0.6 + s.rng.Float64()*0.4
+			p.VX = w.Wind * depth
+			p.VY = config.RainSpeed * depth
+			p.Size = depth
+		case level.WeatherSnow:
+			p.VX = w.Wind * 0.5
+			p.VY = config.SnowSpeed * (0.5 + s.rng.Float64()*0.5)
+			p.Size = 1 + s.rng.Float64()*2
+		}
+	}
+	return s
+}
+
+// Limit применяет к частицам ограничение budget.KindParticles трекера t:
+// лишние частицы отбрасываются, а трекер учитывает их в статистике.
+// Для ясной погоды (nil) трекер узнает, что частиц нет.
+func (s *System) Limit(t *budget.Tracker) {
+	if s == nil {
+		t.Observe(budget.KindParticles, 0, 0)
+		return
+	}
+	s.particles = budget.Apply(t, budget.KindParticles, s.particles)
+}
+
+// Kind возвращает вид погоды (level.WeatherRain или level.WeatherSnow)
+func (s *System) Kind() string {
+	return s.kind
+}
+
+// Particles возвращает частицы для отрисовки
+func (s *System) Particles() []Particle {
+	return s.particles
+}
+
+// Update сдвигает частицы на один кадр и переносит вылетевшие за плитку на другую сторону
+func (s *System) Update() {
+	if s == nil {
+		return
+	}
+	s.frame++
+	for i := range s.particles {
+		p := &s.particles[i]
+		p.X += p.VX
+		p.Y += p.VY
+		if s.kind == level.WeatherSnow {
+			// Снежинки покачиваются из стороны в сторону
+			p.X += math.Sin(float64(s.frame)*0.03+p.phase) * 0.5
+		}
+		p.X = wrap(p.X, config.ScreenWidth)
+		p.Y = wrap(p.Y, config.ScreenHeight)
+	}
+}
+
+// wrap возвращает v по модулю size в диапазоне [0, size)
+func wrap(v, size float64) float64 {
+	v = math.Mod(v, size)
+	if v < 0 {
+		v += size
+	}
+	return v
+}
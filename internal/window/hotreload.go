@@ -0,0 +1,40 @@
+package window
+
+import (
+	"log"
+
+	"platformer/internal/assets"
+	"platformer/internal/config"
+	"platformer/internal/renderer"
+)
+
+// pollAssets в режиме -dev периодически проверяет файлы ресурсов на диске
+// и подхватывает измененные спрайты и звуки без перезапуска игры
+func (w *Window) pollAssets() {
+	if !w.dev {
+		return
+	}
+	w.assetPollFrames++
+	if w.assetPollFrames < config.AssetPollFrames {
+		return
+	}
+	w.assetPollFrames = 0
+
+	changes, err := assets.Poll()
+	if err != nil {
+		log.Printf("hot reload: %v", err)
+	}
+	if changes.Images {
+		if err := renderer.ReloadSprites(); err != nil {
+			log.Printf("hot reload: %v", err)
+		}
+		log.Printf("hot reload: sprites updated")
+	}
+	if changes.Sounds {
+		if err := w.sounds.Reload(); err != nil {
+			log.Printf("hot reload: %v", err)
+		} else {
+			log.Printf("hot reload: sounds updated")
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package window
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"platformer/internal/config"
+	"platformer/internal/game"
+)
+
+// keySet — клавиши управления одного игрока
+type keySet struct {
+	Left, Right, Jump, Crouch []ebiten.Key
+	Sprint                    []ebiten.Key // Бег и рывок
+	Shoot, Interact, Switch   []ebiten.Key
+	Extras                    bool // Эмоции и голосование за реванш (только у первого игрока)
+}
+
+var (
+	// keysAll — клавиши единственного игрока: стрелки и WASD вместе
+	keysAll = keySet{
+		Left:     []ebiten.Key{ebiten.KeyArrowLeft, ebiten.KeyA},
+		Right:    []ebiten.Key{ebiten.KeyArrowRight, ebiten.KeyD},
+		Jump:     []ebiten.Key{ebiten.KeySpace, ebiten.KeyArrowUp, ebiten.KeyW},
+		Crouch:   []ebiten.Key{ebiten.KeyArrowDown, ebiten.KeyS},
+		Sprint:   []ebiten.Key{ebiten.KeyShiftLeft, ebiten.KeyShiftRight},
+		Shoot:    []ebiten.Key{ebiten.KeyJ, ebiten.KeyEnter},
+		Interact: []ebiten.Key{ebiten.KeyE},
+		Switch:   []ebiten.Key{ebiten.KeyQ},
+		Extras:   true,
+	}
+
+	// keysLeft — клавиши первого игрока, когда второй играет на той же клавиатуре
+	keysLeft = keySet{
+		Left:     []ebiten.Key{ebiten.KeyA},
+		Right:    []ebiten.Key{ebiten.KeyD},
+		Jump:     []ebiten.Key{ebiten.KeySpace, ebiten.KeyW},
+		Crouch:   []ebiten.Key{ebiten.KeyS},
+		Sprint:   []ebiten.Key{ebiten.KeyShiftLeft},
+		Shoot:    []ebiten.Key{ebiten.KeyJ},
+		Interact: []ebiten.Key{ebiten.KeyE},
+		Switch:   []ebiten.Key{ebiten.KeyQ},
+		Extras:   true,
+	}
+
+	// keysRight — клавиши второго игрока на той же клавиатуре
+	keysRight = keySet{
+		Left:     []ebiten.Key{ebiten.KeyArrowLeft},
+		Right:    []ebiten.Key{ebiten.KeyArrowRight},
+		Jump:     []ebiten.Key{ebiten.KeyArrowUp},
+		Crouch:   []ebiten.Key{ebiten.KeyArrowDown},
+		Sprint:   []ebiten.Key{ebiten.KeyShiftRight},
+		Shoot:    []ebiten.Key{ebiten.KeyEnter, ebiten.KeyNumpadEnter},
+		Interact: []ebiten.Key{ebiten.KeySlash},
+		Switch:   []ebiten.Key{ebiten.KeyPeriod},
+	}
+)
+
+// emoteKeys — клавиши эмоций: клавиша i+1 вызывает эмоцию с индексом i
+var emoteKeys = []ebiten.Key{ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4}
+
+// keyboardInput читает управление с клавиатуры через ebiten
+type keyboardInput struct {
+	keys *keySet
+}
+
+// Input возвращает зажатые сейчас клавиши управления
+func (k keyboardInput) Input() game.Input {
+	keys := k.keys
+	sprint := anyPressed(keys.Sprint)
+	input := game.Input{
+		Left:         anyPressed(keys.Left),
+		Right:        anyPressed(keys.Right),
+		Jump:         anyPressed(keys.Jump),
+		Crouch:       anyPressed(keys.Crouch),
+		Sprint:       sprint,
+		Dash:         sprint,
+		Shoot:        anyPressed(keys.Shoot),
+		Interact:     anyPressed(keys.Interact),
+		SwitchWeapon: anyPressed(keys.Switch),
+		Emote:        -1,
+	}
+	if !keys.Extras {
+		return input
+	}
+	input.VoteYes = ebiten.IsKeyPressed(ebiten.KeyY)
+	input.VoteNo = ebiten.IsKeyPressed(ebiten.KeyN)
+	for i, key := range emoteKeys {
+		if ebiten.IsKeyPressed(key) {
+			input.Emote = i
+			break
+		}
+	}
+	return input
+}
+
+// anyPressed сообщает, зажата ли хотя бы одна из клавиш
+func anyPressed(keys []ebiten.Key) bool {
+	for _, key := range keys {
+		if ebiten.IsKeyPressed(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// gamepadInput читает управление с первого подключенного геймпада
+// со стандартной раскладкой; без геймпада персонаж стоит
+type gamepadInput struct {
+	ids []ebiten.GamepadID // Буфер списка геймпадов
+}
+
+// Input возвращает зажатые сейчас кнопки и наклон левого стика
+func (g *gamepadInput) Input() game.Input {
+	g.ids = ebiten.AppendGamepadIDs(g.ids[:0])
+	if len(g.ids) == 0 || !ebiten.IsStandardGamepadLayoutAvailable(g.ids[0]) {
+		return game.NoInput
+	}
+	id := g.ids[0]
+	pressed := func(button ebiten.StandardGamepadButton) bool {
+		return ebiten.IsStandardGamepadButtonPressed(id, button)
+	}
+	stickX := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)
+	stickY := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)
+
+	return game.Input{
+		Left:         pressed(ebiten.StandardGamepadButtonLeftLeft) || stickX < -config.GamepadDeadZone,
+		Right:        pressed(ebiten.StandardGamepadButtonLeftRight) || stickX > config.GamepadDeadZone,
+		Jump:         pressed(ebiten.StandardGamepadButtonRightBottom),
+		Crouch:       pressed(ebiten.StandardGamepadButtonLeftBottom) || stickY > config.GamepadDeadZone,
+		Sprint:       pressed(ebiten.StandardGamepadButtonFrontTopRight),
+		Dash:         pressed(ebiten.StandardGamepadButtonRightRight),
+		Shoot:        pressed(ebiten.StandardGamepadButtonRightLeft),
+		Interact:     pressed(ebiten.StandardGamepadButtonFrontTopLeft),
+		SwitchWeapon: pressed(ebiten.StandardGamepadButtonRightTop),
+		Emote:        -1,
+	}
+}
+
+// playerInputs возвращает источники ввода первого и второго игрока за этим
+// компьютером: при втором игроке на клавиатуре она делится пополам,
+// и первому игроку остаются WASD
+func playerInputs(controls game.Controls) (first, second game.InputSource) {
+	switch controls {
+	case game.ControlsKeyboard:
+		return keyboardInput{keys: &keysLeft}, keyboardInput{keys: &keysRight}
+	case game.ControlsGamepad:
+		return keyboardInput{keys: &keysAll}, &gamepadInput{}
+	default:
+		// Боту клавиатура не нужна, а неизвестное управление отклонит игра
+		return keyboardInput{keys: &keysAll}, nil
+	}
+}
+
+// keyCodes — клавиши ebiten для клавиш, которые читает игра
+var keyCodes = [...]ebiten.Key{
+	game.KeyArrowLeft:  ebiten.KeyArrowLeft,
+	game.KeyArrowRight: ebiten.KeyArrowRight,
+	game.KeyArrowUp:    ebiten.KeyArrowUp,
+	game.KeyArrowDown:  ebiten.KeyArrowDown,
+	game.KeyA:          ebiten.KeyA,
+	game.KeyB:          ebiten.KeyB,
+	game.KeyD:          ebiten.KeyD,
+	game.KeyF:          ebiten.KeyF,
+	game.KeyK:          ebiten.KeyK,
+	game.KeyM:          ebiten.KeyM,
+	game.KeyN:          ebiten.KeyN,
+	game.KeyP:          ebiten.KeyP,
+	game.KeyR:          ebiten.KeyR,
+	game.KeyS:          ebiten.KeyS,
+	game.KeyT:          ebiten.KeyT,
+	game.KeyW:          ebiten.KeyW,
+	game.KeyEnter:      ebiten.KeyEnter,
+	game.KeySpace:      ebiten.KeySpace,
+	game.KeyEscape:     ebiten.KeyEscape,
+	game.KeyBackspace:  ebiten.KeyBackspace,
+	game.KeyBackquote:  ebiten.KeyBackquote,
+	game.KeyTab:        ebiten.KeyTab,
+	game.KeyShift:      ebiten.KeyShift,
+	game.KeyF2:         ebiten.KeyF2,
+	game.KeyF3:         ebiten.KeyF3,
+	game.KeyF5:         ebiten.KeyF5,
+	game.KeyF9:         ebiten.KeyF9,
+	game.KeyF10:        ebiten.KeyF10,
+	game.KeyF12:        ebiten.KeyF12,
+}
+
+// Pressed сообщает, зажата ли клавиша
+func (w *Window) Pressed(key game.Key) bool {
+	return ebiten.IsKeyPressed(keyCodes[key])
+}
+
+// JustPressed сообщает, нажата ли клавиша на этом кадре
+func (w *Window) JustPressed(key game.Key) bool {
+	return inpututil.IsKeyJustPressed(keyCodes[key])
+}
+
+// AppendChars дописывает к chars символы, введенные на этом кадре
+func (w *Window) AppendChars(chars []rune) []rune {
+	return ebiten.AppendInputChars(chars)
+}
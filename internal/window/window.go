@@ -0,0 +1,182 @@
+package window
+
+import (
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"platformer/internal/config"
+	"platformer/internal/game"
+	"platformer/internal/i18n"
+	"platformer/internal/mixer"
+	"platformer/internal/preload"
+	"platformer/internal/renderer"
+	"platformer/internal/sound"
+)
+
+// Window — окно ebiten, в котором идет игра (ebiten.Game). Окно читает
+// клавиатуру и геймпад, играет звук, загружает спрайты и шрифты и рисует
+// кадр игры через renderer.Screen; сама игра (пакет game) ebiten не знает.
+type Window struct {
+	game   *game.Game
+	screen *renderer.Screen
+
+	sounds *mixer.Manager // Звуковые эффекты и музыка (nil - звук недоступен)
+	volume sound.Volume   // Громкость, которую задала игра
+
+	dev             bool      // Режим разработки: ресурсы обновляются при изменении файлов
+	assetPollFrames int       // Кадров с прошлой проверки файлов ресурсов (режим -dev)
+	lastUpdate      time.Time // Время прошлого вызова Update (нужно, только если TPS не фиксирован)
+}
+
+var (
+	_ ebiten.Game = (*Window)(nil)
+	_ game.Window = (*Window)(nil)
+)
+
+// New создает окно и игру в нем с опциями opts: управление первого игрока
+// идет с клавиатуры (кроме просмотра записи), а второго - с его половины
+// клавиатуры или с геймпада
+func New(opts game.Options) (*Window, error) {
+	w := &Window{screen: renderer.NewScreen(), volume: sound.DefaultVolume(), dev: opts.Dev}
+
+	first, second := playerInputs(opts.SecondPlayer)
+	if opts.Input == nil && opts.PlaybackPath == "" {
+		opts.Input = first
+	}
+	if opts.SecondInput == nil {
+		opts.SecondInput = second
+	}
+	opts.Window = w
+
+	g, err := game.NewGameWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	w.game = g
+	return w, nil
+}
+
+// Update продвигает игру на время, прошедшее с прошлого кадра
+func (w *Window) Update() error {
+	// При закрытии окна сохраняем настройки и статистику профиля
+	if ebiten.IsWindowBeingClosed() {
+		w.game.SaveProfile()
+		return ebiten.Termination
+	}
+
+	w.pollAssets()
+	return w.game.Update(w.elapsed())
+}
+
+// elapsed возвращает время, прошедшее с прошлого вызова Update.
+// При фиксированном TPS ebiten вызывает Update ровно TPS раз в секунду игрового времени,
+// поэтому берем 1/TPS; в режиме SyncWithFPS измеряем реальное время.
+func (w *Window) elapsed() time.Duration {
+	if tps := ebiten.TPS(); tps > 0 {
+		return time.Second / time.Duration(tps)
+	}
+
+	now := time.Now()
+	elapsed := time.Second / ebiten.DefaultTPS
+	if !w.lastUpdate.IsZero() {
+		elapsed = now.Sub(w.lastUpdate)
+	}
+	w.lastUpdate = now
+	return elapsed
+}
+
+// Draw рисует кадр игры на экране окна
+func (w *Window) Draw(screen *ebiten.Image) {
+	w.screen.Bind(screen)
+	w.game.Draw(w.screen)
+}
+
+// Layout возвращает размеры игрового экрана
+// Эта функция требуется интерфейсом ebiten.Game
+func (w *Window) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return config.ScreenWidth, config.ScreenHeight
+}
+
+// Preload добавляет в загрузку спрайты, шрифты и звук
+func (w *Window) Preload(loader *preload.Loader) {
+	loader.Add(i18n.T("loading.sprites"), renderer.LoadSprites)
+	loader.Add(i18n.T("loading.fonts"), renderer.LoadFonts)
+	loader.Add(i18n.T("loading.sounds"), func() error {
+		// Без звуковой карты игра продолжает работать молча
+		manager, err := mixer.NewManager()
+		if err != nil {
+			log.Printf("sound disabled: %v", err)
+			return nil
+		}
+		manager.SetVolume(w.volume)
+		w.sounds = manager
+		return nil
+	})
+}
+
+// SetSkin заменяет спрайт персонажа скином из файла path; пустой путь возвращает встроенный спрайт
+func (w *Window) SetSkin(path string) error {
+	if path == "" {
+		renderer.SetPlayerSkin(nil)
+		return nil
+	}
+	skin, err := renderer.LoadSkin(path)
+	if err != nil {
+		return err
+	}
+	renderer.SetPlayerSkin(skin)
+	return nil
+}
+
+// SetTitle меняет заголовок окна
+func (w *Window) SetTitle(title string) {
+	ebiten.SetWindowTitle(title)
+}
+
+// Renderer возвращает название текущего бэкенда отрисовки
+func (w *Window) Renderer() string {
+	return w.screen.Backend()
+}
+
+// SetRenderer переключает отрисовку на бэкенд name
+func (w *Window) SetRenderer(name string) {
+	w.screen.SetBackend(name)
+}
+
+// NextRenderer переключает отрисовку на следующий бэкенд
+func (w *Window) NextRenderer() {
+	w.screen.NextBackend()
+}
+
+// FrameRate возвращает частоту кадров и обновлений, которую измерил ebiten
+func (w *Window) FrameRate() (fps, tps float64) {
+	return ebiten.ActualFPS(), ebiten.ActualTPS()
+}
+
+// Play проигрывает звуковой эффект
+func (w *Window) Play(effect sound.Effect) {
+	w.sounds.Play(effect)
+}
+
+// PlayMusic плавно переключает музыку на трек name
+func (w *Window) PlayMusic(name string) {
+	w.sounds.PlayMusic(name)
+}
+
+// DuckMusic приглушает музыку на время диалога
+func (w *Window) DuckMusic(ducked bool) {
+	w.sounds.DuckMusic(ducked)
+}
+
+// UpdateMusic продвигает плавные переходы громкости музыки
+func (w *Window) UpdateMusic() {
+	w.sounds.UpdateMusic()
+}
+
+// SetVolume запоминает громкость и применяет ее, когда звук загружен
+func (w *Window) SetVolume(volume sound.Volume) {
+	w.volume = volume
+	w.sounds.SetVolume(volume)
+}
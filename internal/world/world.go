@@ -0,0 +1,193 @@
+package world
+
+import (
+	"platformer/internal/budget"
+	"platformer/internal/canvas"
+	"platformer/internal/entities"
+	"platformer/internal/transform"
+)
+
+// Object — объект мира (подбираемый предмет, частица, опасность и т.п.): сущность,
+// которую хранит реестр мира, а не сама игра.
+// Update вызывается каждый шаг симуляции; объект, вернувший false, удаляется из мира.
+// Draw вызывается, только если хитбокс объекта попадает в вид камеры.
+type Object interface {
+	entities.Entity
+}
+
+// Budgeted — объект, количество которого ограничено бюджетом (например, частицы).
+// При превышении лимита самые старые объекты этого вида удаляются.
+type Budgeted interface {
+	BudgetKind() budget.Kind
+}
+
+// Layered — объект, который рисуется не в слое сущностей (например, эффект или снаряд)
+type Layered interface {
+	DrawLayer() canvas.Layer
+}
+
+// Registry хранит объекты мира и обновляет и рисует их через общие интерфейсы,
+// чтобы новые виды объектов не требовали правок в Game.Update и Game.Draw
+type Registry struct {
+	objects  []Object
+	pending  []Object // Объекты, созданные во время обновления
+	updating bool
+	cleared  bool // Реестр очищен во время обновления
+	budget   *budget.Tracker
+	observed map[budget.Kind]bool // Виды, о которых реестр уже сообщал трекеру
+}
+
+// NewRegistry создает пустой реестр. Если tracker не nil, объекты Budgeted ограничиваются его лимитами.
+func NewRegistry(tracker *budget.Tracker) *Registry {
+	return &Registry{budget: tracker, observed: make(map[budget.Kind]bool)}
+}
+
+// Add добавляет объект в мир
+func (r *Registry) Add(object Object) {
+	if r.updating {
+		r.pending = append(r.pending, object)
+		return
+	}
+	r.objects = append(r.objects, object)
+}
+
+// Len возвращает количество объектов в мире
+func (r *Registry) Len() int {
+	return len(r.objects) + len(r.pending)
+}
+
+// Each вызывает fn для каждого объекта мира в порядке добавления
+func (r *Registry) Each(fn func(Object)) {
+	for _, object := range r.objects {
+		fn(object)
+	}
+}
+
+// Clear удаляет все объекты (например, при перезапуске уровня)
+func (r *Registry) Clear() {
+	for i := range r.objects {
+		r.objects[i] = nil
+	}
+	r.objects = r.objects[:0]
+	r.pending = r.pending[:0]
+	if r.updating {
+		r.cleared = true
+	}
+}
+
+// Update обновляет все объекты, удаляет отжившие и применяет лимиты бюджета
+func (r *Registry) Update(ctx entities.Context) {
+	r.updating = true
+	r.cleared = false
+	objects := r.objects
+	alive := make([]Object, 0, len(objects))
+	for _, object := range objects {
+		if object.Update(ctx) {
+			alive = append(alive, object)
+		}
+		// Объект мог перезапустить уровень: остальные объекты старого уровня не нужны
+		if r.cleared {
+			break
+		}
+	}
+	r.updating = false
+
+	if r.cleared {
+		// Оставляем только объекты, созданные после очистки
+		r.objects = append(r.objects[:0], r.pending...)
+		r.pending = r.pending[:0]
+		r.enforceBudget()
+		return
+	}
+
+	r.objects = append(alive, r.pending...)
+	r.pending = r.pending[:0]
+
+	r.enforceBudget()
+}
+
+// Draw добавляет в очередь отрисовки объекты мира, видимые камерой. Объект рисуется
+// в своем слое (Layered), а без него - в слое сущностей.
+func (r *Registry) Draw(queue *canvas.DrawQueue, view transform.View) {
+	for _, object := range r.objects {
+		layer := canvas.LayerEntities
+		if l, ok := object.(Layered); ok {
+			layer = l.DrawLayer()
+		}
+		QueueDraw(queue, layer, 0, object, view)
+	}
+}
+
+// Step выполняет шаг сущностей list и возвращает те, что остались в мире,
+// в прежнем порядке. Так игра обновляет собственные списки сущностей (пули).
+func Step[T entities.Entity](ctx entities.Context, list []T) []T {
+	alive := make([]T, 0, len(list))
+	for _, entity := range list {
+		if entity.Update(ctx) {
+			alive = append(alive, entity)
+		}
+	}
+	return alive
+}
+
+// QueueDraw добавляет в очередь отрисовку сущности entity в слое layer с глубиной depth,
+// если ее хитбокс виден через view
+func QueueDraw(queue *canvas.DrawQueue, layer canvas.Layer, depth float64, entity entities.Entity, view transform.View) {
+	bounds := entity.Bounds()
+	if !view.Visible(bounds.X, bounds.Y, bounds.Width, bounds.Height) {
+		return
+	}
+	queue.Add(layer, depth, func(screen canvas.Canvas) { entity.Draw(screen, view) })
+}
+
+// enforceBudget удаляет самые старые объекты тех видов, которые превысили лимит
+func (r *Registry) enforceBudget() {
+	if r.budget == nil {
+		return
+	}
+
+	// Виды, объекты которых закончились, тоже сообщаем трекеру - с нулевым количеством
+	counts := make(map[budget.Kind]int, len(r.observed))
+	for kind := range r.observed {
+		counts[kind] = 0
+	}
+	for _, object := range r.objects {
+		if b, ok := object.(Budgeted); ok {
+			counts[b.BudgetKind()]++
+		}
+	}
+	if len(counts) == 0 {
+		return
+	}
+
+	// Сколько объектов каждого вида нужно вытеснить (объекты идут от старых к новым)
+	excess := make(map[budget.Kind]int, len(counts))
+	for kind, count := range counts {
+		if limit := r.budget.Cap(kind); limit > 0 && count > limit {
+			excess[kind] = count - limit
+		}
+	}
+
+	kept := r.objects[:0]
+	for _, object := range r.objects {
+		if b, ok := object.(Budgeted); ok && excess[b.BudgetKind()] > 0 {
+			excess[b.BudgetKind()]--
+			continue
+		}
+		kept = append(kept, object)
+	}
+	for i := len(kept); i < len(r.objects); i++ {
+		r.objects[i] = nil
+	}
+
+	for kind, count := range counts {
+		live := count
+		evicted := 0
+		if limit := r.budget.Cap(kind); limit > 0 && count > limit {
+			live, evicted = limit, count-limit
+		}
+		r.budget.Observe(kind, live, evicted)
+		r.observed[kind] = true
+	}
+	r.objects = kept
+}
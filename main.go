@@ -2,20 +2,79 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
+	"os"
 	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
 
 	"platformer/internal/config"
 	"platformer/internal/game"
+	"platformer/internal/i18n"
+	"platformer/internal/network"
+	"platformer/internal/physcheck"
+	"platformer/internal/server"
+	"platformer/internal/window"
 )
 
 // main - точка входа в программу
 func main() {
 	modeFlag := flag.String("mode", string(game.ModeLocal), "Game mode: local, host, client")
-	addrFlag := flag.String("addr", "", "Address for host or client connection (e.g. :4000 or 192.168.0.5:4000)")
-	flag.Parse()
+	addrFlag := flag.String("addr", "", "Address for host or client connection (e.g. :4000, 192.168.0.5:4000, ws://192.168.0.5:4001/ or relay://relay.example.com:4100/room)")
+	wsFlag := flag.String("ws", "", "Address the host also accepts WebSocket clients on, e.g. browser builds (e.g. :4001, empty disables it)")
+	levelFlag := flag.String("level", "", "Path to a JSON level file or name of an embedded level (empty uses the built-in level)")
+	leaderboardFlag := flag.String("leaderboard", "", "Leaderboard server URL (empty disables online scores)")
+	leaderboardKeyFlag := flag.String("leaderboard-key", "", "Secret key used to sign leaderboard results")
+	speedrunFlag := flag.Bool("speedrun", false, "Show a speedrun timer with per-room splits")
+	speedrunOutFlag := flag.String("speedrun-out", "speedrun.jsonl", "File that finished speedrun results are appended to")
+	loadFlag := flag.String("load", "", "Path to a save file to continue from (empty starts a new game)")
+	dailyFlag := flag.Bool("daily", false, "Play today's daily challenge: the level and seed are picked by the UTC date, and results go to the daily leaderboard")
+	saveFlag := flag.String("save", "", "Path to the quicksave file used by F5/F9 (empty uses quicksave.json)")
+	profileDirFlag := flag.String("profiles", "", "Directory with player profiles (empty uses the user config directory)")
+	profileFlag := flag.Int("profile", 1, "Profile slot to start with")
+	langFlag := flag.String("lang", "", "Interface language: ru or en (empty uses the profile setting)")
+	nameFlag := flag.String("name", "", "Player name shown to the other player and on leaderboards (empty uses the profile name)")
+	skinFlag := flag.String("skin", "", "PNG file with a custom player skin (must match the player size)")
+	clipFlag := flag.Int("clip-seconds", config.ClipSeconds, "Length of the F10 GIF clip in seconds (0 disables clip recording)")
+	reconnectFlag := flag.Int("reconnect-seconds", config.ReconnectSeconds, "How long to keep retrying a dropped network connection in seconds (0 gives up at once)")
+	matchmakingFlag := flag.String("matchmaking", "", "Matchmaking server URL a host registers its room with and a client looks -room codes up on (e.g. http://example.com:4200)")
+	matchFlag := flag.String("match", string(game.MatchVersus), "Network game the host runs: versus (players fight each other on time), coop (players go through the campaign levels together), deathmatch (rounds to -score-limit kills) or ctf (capture the flag to -score-limit captures)")
+	scoreLimitFlag := flag.Int("score-limit", 0, fmt.Sprintf("Kills needed to win a deathmatch round or flag captures needed to win a ctf match (0 - %d kills, %d captures)", config.DeathmatchScoreLimit, config.CTFCaptureLimit))
+	friendlyFireFlag := flag.Bool("friendly-fire", false, "Let bullets hurt players on the same team (the host can toggle it in the lobby)")
+	netLagFlag := flag.Duration("net-lag", 0, "Developer option: delay every network frame sent and received by this long (e.g. 100ms) to test prediction on a slow network")
+	netJitterFlag := flag.Duration("net-jitter", 0, "Developer option: add a random delay of up to this long to every network frame on top of -net-lag")
+	netLossFlag := flag.Float64("net-loss", 0, "Developer option: fraction of inputs and snapshots to drop in each direction, from 0 to 1 (e.g. 0.05)")
+	recordFlag := flag.String("record", "", "With -mode host, record every network message of the match to this file")
+	playbackFlag := flag.String("playback", "", "Play back a match recorded with -record as the client saw it instead of connecting to a host")
+	serverFlag := flag.String("server", "", "With -mode host, read the port, password, level rotation, snapshot rate and match rules from this JSON file (flags given explicitly take priority)")
+	passwordFlag := flag.String("password", "", "Server password: the host only lets in clients that send it, a client sends it to the host")
+	maxPlayersFlag := flag.Int("max-players", 0, "With -mode host, how many players the server holds including the host (0 - as many as this build supports)")
+	rotationFlag := flag.String("rotation", "", "With -mode host, comma-separated levels played in turn, switching after every match (the first one starts unless -level is set)")
+	snapshotRateFlag := flag.Int("snapshot-rate", 0, "With -mode host, snapshots per second sent to clients (0 - every simulation step, 60/s)")
+	rconFlag := flag.String("rcon", "", "With -mode host, accept remote admin console connections on this TCP address (e.g. :4002, empty disables it)")
+	rconPasswordFlag := flag.String("rcon-password", "", "Password remote admins must send first (required with -rcon)")
+	roomFlag := flag.String("room", "", "With -mode client, join the room with this code on the -matchmaking server instead of -addr")
+	devFlag := flag.Bool("dev", false, "Load assets from disk and reload them when files change")
+	assetDirFlag := flag.String("assets", "internal/assets", "Asset directory used with -dev")
+	pprofFlag := flag.String("pprof", "", "Address for the pprof and runtime metrics HTTP server (e.g. :6060, empty disables it)")
+	headlessFlag := flag.Bool("headless", false, "Run the simulation without a window, audio or rendering (dedicated server, CI)")
+	seedFlag := flag.Int64("seed", 1, "Seed of the simulation random numbers: the same seed and inputs play out the same way")
+	physicsCheckFlag := flag.Bool("physics-check", false, "Run the collision regression checks without a window and exit (non-zero exit code if any fails)")
+	ticksFlag := flag.Int("ticks", 0, "With -headless, run this many ticks as fast as possible and exit (0 runs in real time until an error)")
+	player2Flag := flag.String("player2", "", "Add a second local player: keys (arrows, right Shift, Enter; player one keeps WASD) or gamepad (the first connected gamepad), or bot (a computer rival for practice); combine with -split-screen")
+	botFlag := flag.Bool("bot", false, "Let a bot play this player's character; with -mode client -headless it fills a free slot on a dedicated server")
+	splitScreenFlag := flag.Bool("split-screen", false, "Split the screen into two side-by-side views, each following its own player, whenever there is a second player")
+	highScoresFlag := flag.String("highscores", "", "Path to the local high score file (empty uses the user config directory)")
+	// В браузере аргументов командной строки нет, и флаги берутся из адреса страницы
+	if err := flag.CommandLine.Parse(commandLineArgs()); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if path := strings.TrimSpace(*serverFlag); path != "" {
+		if err := applyServerConfig(path); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
 
 	modeValue := strings.ToLower(strings.TrimSpace(*modeFlag))
 	if modeValue == "" {
@@ -29,21 +88,165 @@ func main() {
 		log.Fatalf("unknown mode %q, expected local, host or client", modeValue)
 	}
 
-	gameInstance, err := game.NewGameWithOptions(game.Options{
-		Mode:    mode,
-		Address: strings.TrimSpace(*addrFlag),
-	})
+	match := game.MatchMode(strings.ToLower(strings.TrimSpace(*matchFlag)))
+	switch match {
+	case game.MatchVersus, game.MatchCoop, game.MatchDeathmatch, game.MatchCTF:
+	default:
+		log.Fatalf("unknown match %q, expected versus, coop, deathmatch or ctf", match)
+	}
+
+	if *netLagFlag < 0 || *netJitterFlag < 0 {
+		log.Fatalf("-net-lag and -net-jitter must not be negative")
+	}
+	if *netLossFlag < 0 || *netLossFlag > 1 {
+		log.Fatalf("-net-loss must be between 0 and 1, got %v", *netLossFlag)
+	}
+
+	lang := strings.ToLower(strings.TrimSpace(*langFlag))
+	if lang != "" {
+		if err := i18n.SetLanguage(lang); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+
+	if *physicsCheckFlag {
+		if !runPhysicsCheck() {
+			os.Exit(1)
+		}
+		return
+	}
+
+	opts := game.Options{
+		Mode:      mode,
+		Address:   strings.TrimSpace(*addrFlag),
+		LevelPath: strings.TrimSpace(*levelFlag),
+
+		WebSocketAddress: strings.TrimSpace(*wsFlag),
+
+		PlayerName:        strings.TrimSpace(*nameFlag),
+		LeaderboardURL:    strings.TrimSpace(*leaderboardFlag),
+		LeaderboardSecret: *leaderboardKeyFlag,
+
+		HighScorePath: strings.TrimSpace(*highScoresFlag),
+
+		SavePath: strings.TrimSpace(*saveFlag),
+		LoadPath: strings.TrimSpace(*loadFlag),
+		Daily:    *dailyFlag,
+
+		ProfileDir: strings.TrimSpace(*profileDirFlag),
+		Profile:    *profileFlag,
+
+		Skin:     strings.TrimSpace(*skinFlag),
+		Language: lang,
+
+		ClipSeconds:  *clipFlag,
+		SplitScreen:  *splitScreenFlag,
+		SecondPlayer: game.Controls(strings.TrimSpace(*player2Flag)),
+		Bot:          *botFlag,
+
+		ReconnectSeconds: *reconnectFlag,
+
+		NetConditions: network.Conditions{Lag: *netLagFlag, Jitter: *netJitterFlag, Loss: *netLossFlag},
+
+		RecordPath:   strings.TrimSpace(*recordFlag),
+		PlaybackPath: strings.TrimSpace(*playbackFlag),
+
+		Match:        match,
+		ScoreLimit:   *scoreLimitFlag,
+		FriendlyFire: *friendlyFireFlag,
+
+		Password:      *passwordFlag,
+		MaxPlayers:    *maxPlayersFlag,
+		LevelRotation: splitList(*rotationFlag),
+		SnapshotRate:  *snapshotRateFlag,
+
+		RconAddress:  strings.TrimSpace(*rconFlag),
+		RconPassword: *rconPasswordFlag,
+
+		MatchmakingURL: strings.TrimSpace(*matchmakingFlag),
+		RoomCode:       strings.TrimSpace(*roomFlag),
+
+		Dev:      *devFlag,
+		AssetDir: strings.TrimSpace(*assetDirFlag),
+
+		PprofAddr: strings.TrimSpace(*pprofFlag),
+		Headless:  *headlessFlag,
+		Seed:      *seedFlag,
+
+		Speedrun:     *speedrunFlag,
+		SpeedrunPath: strings.TrimSpace(*speedrunOutFlag),
+	}
+
+	if *headlessFlag {
+		gameInstance, err := game.NewGameWithOptions(opts)
+		if err != nil {
+			log.Fatalf("failed to start game: %v", err)
+		}
+		if err := gameInstance.RunHeadless(*ticksFlag); err != nil {
+			log.Fatalf("headless error: %v", err)
+		}
+		return
+	}
+
+	gameWindow, err := window.New(opts)
 	if err != nil {
 		log.Fatalf("failed to start game: %v", err)
 	}
 
 	// Настраиваем параметры окна
 	ebiten.SetWindowSize(config.ScreenWidth, config.ScreenHeight)
-	ebiten.SetWindowTitle("Платформер на Go")
+	ebiten.SetWindowTitle(i18n.T("window.title"))
+	// Игра сама завершает работу при закрытии окна, чтобы успеть сохранить профиль
+	ebiten.SetWindowClosingHandled(true)
 
 	// Запускаем игровой цикл
 	// RunGame будет вызывать Update и Draw в цикле до тех пор, пока игра не завершится
-	if err := ebiten.RunGame(gameInstance); err != nil {
+	if err := ebiten.RunGame(gameWindow); err != nil {
 		log.Fatalf("game error: %v", err)
 	}
 }
+
+// applyServerConfig подставляет настройки из файла сервера path во флаги,
+// которые не заданы в командной строке явно
+func applyServerConfig(path string) error {
+	cfg, err := server.Load(path)
+	if err != nil {
+		return err
+	}
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	for name, value := range cfg.Flags() {
+		if explicit[name] {
+			continue
+		}
+		if err := flag.Set(name, value); err != nil {
+			return fmt.Errorf("server config %s: %s: %w", path, name, err)
+		}
+	}
+	return nil
+}
+
+// runPhysicsCheck выполняет проверки физики и сообщает, прошли ли все
+func runPhysicsCheck() bool {
+	passed := true
+	for _, result := range physcheck.Run(physcheck.Cases) {
+		if result.Err != nil {
+			passed = false
+			fmt.Printf("FAIL %s: %v\n", result.Name, result.Err)
+			continue
+		}
+		fmt.Printf("ok   %s\n", result.Name)
+	}
+	return passed
+}
+
+// splitList разбирает список через запятую, пропуская пустые элементы
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}